@@ -0,0 +1,134 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// AreNeighbors reports whether the cells i and j share a Voronoi edge.
+// The lookup uses a hash set of all neighbor pairs that is built on first use.
+// It panics if either index is out of range.
+func (d *Diagram) AreNeighbors(i, j int) bool {
+	d.checkCellIndex(i)
+	d.checkCellIndex(j)
+
+	c := d.caches()
+	c.neighborsOnce.Do(func() {
+		c.neighbors = make(map[uint64]struct{}, len(d.CellNeighbors)/2)
+		for i := range d.NumCells() {
+			for _, j := range d.Cell(i).NeighborIndices() {
+				c.neighbors[edgeKey(i, j)] = struct{}{}
+			}
+		}
+	})
+	_, ok := c.neighbors[edgeKey(i, j)]
+	return ok
+}
+
+// IsNeighbor reports whether the cells i and j share a Voronoi edge. Unlike AreNeighbors it
+// builds no lookup structure and scans the neighbors of the cell with fewer of them, ties going
+// to the smaller index, so its cost is O(min(deg i, deg j)) and its result is symmetric even
+// for a diagram whose neighbor lists are not; Validate rejects such diagrams.
+// It panics if either index is out of range.
+func (d *Diagram) IsNeighbor(i, j int) bool {
+	d.checkCellIndex(i)
+	d.checkCellIndex(j)
+
+	ni, nj := d.Cell(i).NumNeighbors(), d.Cell(j).NumNeighbors()
+	if nj < ni || (nj == ni && j < i) {
+		i, j = j, i
+	}
+	return slices.Contains(d.Cell(i).NeighborIndices(), j)
+}
+
+// PaddedNeighbors returns the neighbor indices of all cells as a dense row-major
+// n×maxDegree matrix, where row i holds the NeighborIndices of cell i followed by padValue.
+// It also returns the actual maximum number of neighbors. If maxDegree is 0, the actual
+// maximum is used as the row width. It returns an error naming the first offending cell if
+// maxDegree is smaller than the number of neighbors of some cell.
+func (d *Diagram) PaddedNeighbors(maxDegree int, padValue int) ([]int32, int, error) {
+	if maxDegree < 0 {
+		return nil, 0, fmt.Errorf("s2voronoi: maxDegree must be non-negative, got %d", maxDegree)
+	}
+	if d.NumCells() > math.MaxInt32 || padValue < math.MinInt32 || padValue > math.MaxInt32 {
+		return nil, 0, errors.New("s2voronoi: indices do not fit into int32")
+	}
+
+	degree := 0
+	for i := range d.NumCells() {
+		degree = max(degree, d.Cell(i).NumNeighbors())
+	}
+	if maxDegree == 0 {
+		maxDegree = degree
+	}
+
+	out := make([]int32, d.NumCells()*maxDegree)
+	for i := range d.NumCells() {
+		neighbors := d.Cell(i).NeighborIndices()
+		if len(neighbors) > maxDegree {
+			return nil, degree, fmt.Errorf("s2voronoi: cell %d has %d neighbors, exceeds maxDegree %d",
+				i, len(neighbors), maxDegree)
+		}
+		row := out[i*maxDegree : (i+1)*maxDegree]
+		for k, nIdx := range neighbors {
+			row[k] = int32(nIdx)
+		}
+		for k := len(neighbors); k < maxDegree; k++ {
+			row[k] = int32(padValue)
+		}
+	}
+	return out, degree, nil
+}
+
+// Edge is a Voronoi edge, the boundary between two neighboring cells.
+type Edge struct {
+	// Vertices are the indices of the endpoints in the Diagram's Vertices, in the order of the
+	// ring of the cell Sites[0].
+	Vertices [2]int
+	// Sites are the indices of the two cells sharing the edge, the smaller one first.
+	Sites [2]int
+}
+
+// Edges returns every Voronoi edge of the diagram exactly once, ordered by the cell Sites[0] and
+// the position of the edge in its ring. The edges of a diagram of n sites built by NewDiagram
+// are the duals of the Delaunay edges, so there are 3n-6 of them.
+func (d *Diagram) Edges() []Edge {
+	edges := make([]Edge, 0, len(d.CellNeighbors)/2)
+	for i := range d.NumCells() {
+		c := d.Cell(i)
+		vertices := c.VertexIndices()
+		for k, j := range c.NeighborIndices() {
+			if j < i {
+				continue
+			}
+			edges = append(edges, Edge{
+				Vertices: [2]int{vertices[k], vertices[(k+1)%len(vertices)]},
+				Sites:    [2]int{i, j},
+			})
+		}
+	}
+	return edges
+}
+
+// SharedEdge returns the edge of cell i that separates it from cell j, as returned by
+// Cell.Edge, and reports whether the cells are adjacent. The edge runs in the order of the
+// ring of cell i; cell j has the same edge reversed. It scans the neighbors of cell i only.
+// It panics if either index is out of range.
+func (d *Diagram) SharedEdge(i, j int) (CellEdge, bool) {
+	d.checkCellIndex(i)
+	d.checkCellIndex(j)
+
+	c := d.Cell(i)
+	for k, n := range c.NeighborIndices() {
+		if n == j {
+			return c.Edge(k), true
+		}
+	}
+	return CellEdge{}, false
+}
@@ -0,0 +1,285 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/internal/fixtures"
+	"github.com/2dChan/s2voronoi/utils"
+)
+
+func TestDiagram_AreNeighbors(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	for i := range vd.NumCells() {
+		for j := range vd.NumCells() {
+			want := slices.Contains(vd.Cell(i).NeighborIndices(), j)
+			if got := vd.AreNeighbors(i, j); got != want {
+				t.Errorf("vd.AreNeighbors(%d, %d) = %v, want %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestDiagram_AreNeighbors_Panic(t *testing.T) {
+	vd := mustNewDiagram(t, 10)
+	for _, in := range []int{-1, vd.NumCells()} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("vd.AreNeighbors(0, %d) did not panic, want panic", in)
+				}
+			}()
+			vd.AreNeighbors(0, in)
+		}()
+	}
+}
+
+func TestDiagram_IsNeighbor(t *testing.T) {
+	for _, name := range []string{"octahedron", "cocircular-rings", "antipodal-pairs"} {
+		t.Run(name, func(t *testing.T) {
+			vd, err := NewDiagram(fixtures.Load(name))
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			assertNeighborsSymmetric(t, vd)
+		})
+	}
+	t.Run("random", func(t *testing.T) {
+		assertNeighborsSymmetric(t, mustNewDiagram(t, 200))
+	})
+}
+
+func TestDiagram_IsNeighbor_Asymmetric(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	// Listing a non-adjacent cell as a neighbor of cell 0 breaks the symmetry of the lists.
+	j := -1
+	for k := 1; k < vd.NumCells(); k++ {
+		if !vd.AreNeighbors(0, k) {
+			j = k
+			break
+		}
+	}
+	vd.CellNeighbors[vd.CellOffsets[0]] = j
+	if got, rev := vd.IsNeighbor(0, j), vd.IsNeighbor(j, 0); got != rev {
+		t.Errorf("vd.IsNeighbor(0, %d) = %v, vd.IsNeighbor(%d, 0) = %v, want equal", j, got, j, rev)
+	}
+	if err := vd.Validate(); err == nil {
+		t.Errorf("vd.Validate() error = nil, want non-nil for asymmetric neighbors")
+	}
+}
+
+func TestDiagram_IsNeighbor_Panic(t *testing.T) {
+	vd := mustNewDiagram(t, 10)
+	for _, in := range []int{-1, vd.NumCells()} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("vd.IsNeighbor(0, %d) did not panic, want panic", in)
+				}
+			}()
+			vd.IsNeighbor(0, in)
+		}()
+	}
+}
+
+func TestDiagram_PaddedNeighbors(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	for _, maxDegree := range []int{0, 20} {
+		got, degree, err := vd.PaddedNeighbors(maxDegree, -1)
+		if err != nil {
+			t.Fatalf("vd.PaddedNeighbors(%d, -1) error = %v, want nil", maxDegree, err)
+		}
+		width := maxDegree
+		if width == 0 {
+			width = degree
+		}
+		if len(got) != vd.NumCells()*width {
+			t.Fatalf("vd.PaddedNeighbors(%d, -1) len = %d, want %d", maxDegree, len(got),
+				vd.NumCells()*width)
+		}
+
+		wantDegree := 0
+		for i := range vd.NumCells() {
+			neighbors := vd.Cell(i).NeighborIndices()
+			wantDegree = max(wantDegree, len(neighbors))
+			row := got[i*width : (i+1)*width]
+			for k := range row {
+				want := int32(-1)
+				if k < len(neighbors) {
+					want = int32(neighbors[k])
+				}
+				if row[k] != want {
+					t.Errorf("vd.PaddedNeighbors(%d, -1) row %d[%d] = %d, want %d", maxDegree, i,
+						k, row[k], want)
+				}
+			}
+		}
+		if degree != wantDegree {
+			t.Errorf("vd.PaddedNeighbors(%d, -1) degree = %d, want %d", maxDegree, degree,
+				wantDegree)
+		}
+	}
+}
+
+func TestDiagram_PaddedNeighbors_Error(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	_, degree, err := vd.PaddedNeighbors(0, -1)
+	if err != nil {
+		t.Fatalf("vd.PaddedNeighbors(0, -1) error = %v, want nil", err)
+	}
+	first := 0
+	for vd.Cell(first).NumNeighbors() < degree {
+		first++
+	}
+
+	_, _, err = vd.PaddedNeighbors(degree-1, -1)
+	if err == nil {
+		t.Fatalf("vd.PaddedNeighbors(%d, -1) error = nil, want non-nil", degree-1)
+	}
+	if want := fmt.Sprintf("cell %d ", first); !strings.Contains(err.Error(), want) {
+		t.Errorf("vd.PaddedNeighbors(%d, -1) error = %v, want mention of %q", degree-1, err, want)
+	}
+
+	if _, _, err := vd.PaddedNeighbors(-1, -1); err == nil {
+		t.Errorf("vd.PaddedNeighbors(-1, -1) error = nil, want non-nil")
+	}
+}
+
+func TestDiagram_Edges(t *testing.T) {
+	for _, n := range []int{4, 100, 1000} {
+		t.Run(fmt.Sprintf("%d sites", n), func(t *testing.T) {
+			vd := mustNewDiagram(t, n)
+			edges := vd.Edges()
+			if got, want := len(edges), 3*n-6; got != want {
+				t.Errorf("len(vd.Edges()) = %v, want %v", got, want)
+			}
+
+			seen := make(map[[2]int]bool, len(edges))
+			for _, e := range edges {
+				i, j := e.Sites[0], e.Sites[1]
+				if i >= j || seen[e.Sites] {
+					t.Errorf("vd.Edges() has edge %+v, want sites increasing and unique", e)
+				}
+				seen[e.Sites] = true
+				if !vd.AreNeighbors(i, j) {
+					t.Errorf("vd.Edges() has edge %+v between non-neighbors", e)
+				}
+				if !hasReversedEdge(vd.Cell(i), j, e.Vertices[1], e.Vertices[0]) ||
+					!hasReversedEdge(vd.Cell(j), i, e.Vertices[0], e.Vertices[1]) {
+					t.Errorf("vd.Edges() has edge %+v not matching the cell rings", e)
+				}
+			}
+		})
+	}
+}
+
+func TestDiagram_SharedEdge(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	for i := range vd.NumCells() {
+		for j := range vd.NumCells() {
+			e, ok := vd.SharedEdge(i, j)
+			if want := slices.Contains(vd.Cell(i).NeighborIndices(), j); ok != want {
+				t.Fatalf("vd.SharedEdge(%d, %d) ok = %v, want %v", i, j, ok, want)
+			}
+			if !ok {
+				if e != (CellEdge{}) {
+					t.Errorf("vd.SharedEdge(%d, %d) = %+v, want zero edge", i, j, e)
+				}
+				continue
+			}
+			if e.Neighbor != j || vd.Vertices[e.Vertices[0]] != e.V0 ||
+				vd.Vertices[e.Vertices[1]] != e.V1 {
+				t.Errorf("vd.SharedEdge(%d, %d) = %+v, inconsistent edge", i, j, e)
+			}
+			for _, c := range []Cell{vd.Cell(i), vd.Cell(j)} {
+				for _, v := range e.Vertices {
+					if !slices.Contains(c.VertexIndices(), v) {
+						t.Errorf("vd.SharedEdge(%d, %d) vertex %d is not in the ring of cell %d",
+							i, j, v, c.SiteIndex())
+					}
+				}
+			}
+			if !hasReversedEdge(vd.Cell(j), i, e.Vertices[0], e.Vertices[1]) {
+				t.Errorf("vd.SharedEdge(%d, %d) = %+v, want reversed in cell %d", i, j, e, j)
+			}
+		}
+	}
+}
+
+func TestDiagram_SharedEdge_Panic(t *testing.T) {
+	vd := mustNewDiagram(t, 10)
+	for _, tt := range [][2]int{{-1, 0}, {0, -1}, {10, 0}, {0, 10}} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("vd.SharedEdge(%d, %d) did not panic, want panic", tt[0], tt[1])
+				}
+			}()
+			vd.SharedEdge(tt[0], tt[1])
+		}()
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDiagram_IsNeighbor(b *testing.B) {
+	vd, err := NewDiagram(utils.GenerateRandomPoints(1e+4, 0))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	for i := 0; b.Loop(); i++ {
+		vd.IsNeighbor(i%vd.NumCells(), (i*7919)%vd.NumCells())
+	}
+}
+
+func BenchmarkDiagram_AreNeighbors(b *testing.B) {
+	points := utils.GenerateRandomPoints(1e+4, 0)
+	vd, err := NewDiagram(points)
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+
+	b.Run("HashSet", func(b *testing.B) {
+		vd.AreNeighbors(0, 1)
+		i := 0
+		for b.Loop() {
+			vd.AreNeighbors(i%vd.NumCells(), (i*7919)%vd.NumCells())
+			i++
+		}
+	})
+	b.Run("LinearScan", func(b *testing.B) {
+		i := 0
+		for b.Loop() {
+			_ = slices.Contains(vd.Cell(i%vd.NumCells()).NeighborIndices(), (i*7919)%vd.NumCells())
+			i++
+		}
+	})
+}
+
+// Helpers
+
+// assertNeighborsSymmetric checks that the neighbor lists of the diagram are symmetric and that
+// IsNeighbor agrees with them and with AreNeighbors.
+func assertNeighborsSymmetric(t *testing.T, vd *Diagram) {
+	t.Helper()
+	for i := range vd.NumCells() {
+		for j := range vd.NumCells() {
+			want := slices.Contains(vd.Cell(i).NeighborIndices(), j)
+			if rev := slices.Contains(vd.Cell(j).NeighborIndices(), i); rev != want {
+				t.Errorf("cell %d lists cell %d as neighbor = %v, reverse = %v", i, j, want, rev)
+			}
+			if got := vd.IsNeighbor(i, j); got != want {
+				t.Errorf("vd.IsNeighbor(%d, %d) = %v, want %v", i, j, got, want)
+			}
+			if got := vd.AreNeighbors(i, j); got != want {
+				t.Errorf("vd.AreNeighbors(%d, %d) = %v, want %v", i, j, got, want)
+			}
+		}
+	}
+}
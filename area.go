@@ -0,0 +1,58 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"math"
+)
+
+// CellAreas returns the areas of all cells in steradians, equal to Cell.Area up to rounding.
+// It makes one pass over the CSR arrays and visits every Voronoi edge once, from the cell with
+// the smaller index, sharing the cross and dot products of the edge endpoints between the fan
+// triangles of both cells sharing the edge. The fan triangle areas are computed with
+// Eriksson's formula and accumulated with compensated summation. The diagram must be
+// consistent, see Validate.
+func (d *Diagram) CellAreas() []float64 {
+	sums := make([]compensatedSum, d.NumCells())
+	for i := range d.NumCells() {
+		start, end := d.CellOffsets[i], d.CellOffsets[i+1]
+		si := d.Sites[i]
+		for k := start; k < end; k++ {
+			j := d.CellNeighbors[k]
+			if j < i {
+				continue
+			}
+			next := k + 1
+			if next == end {
+				next = start
+			}
+			a, b := d.Vertices[d.CellVertices[k]], d.Vertices[d.CellVertices[next]]
+
+			// x is b×a, computed in the form that stays accurate for nearby a and b.
+			x := b.Add(a.Vector).Cross(a.Sub(b.Vector)).Mul(0.5)
+			ab := a.Dot(b.Vector)
+			// The fan triangle of cell i is (si, b, a), and the neighbor traverses the edge
+			// reversed, so its fan triangle is (sj, a, b).
+			sj := d.Sites[j]
+			sums[i].Add(2 * math.Atan2(si.Dot(x), 1+si.Dot(a.Vector)+si.Dot(b.Vector)+ab))
+			sums[j].Add(2 * math.Atan2(-sj.Dot(x), 1+sj.Dot(a.Vector)+sj.Dot(b.Vector)+ab))
+		}
+	}
+
+	areas := make([]float64, len(sums))
+	for i := range sums {
+		areas[i] = sums[i].Value()
+	}
+	return areas
+}
+
+// TotalArea returns the sum of CellAreas, which is 4π for a diagram covering the sphere.
+func (d *Diagram) TotalArea() float64 {
+	var total compensatedSum
+	for _, a := range d.CellAreas() {
+		total.Add(a)
+	}
+	return total.Value()
+}
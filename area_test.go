@@ -0,0 +1,78 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"math"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/internal/fixtures"
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s2"
+)
+
+func TestDiagram_CellAreas(t *testing.T) {
+	tests := []struct {
+		name  string
+		sites s2.PointVector
+	}{
+		{"octahedron", fixtures.Load("octahedron")},
+		{"cocircular rings", fixtures.Load("cocircular-rings")},
+		{"random", utils.GenerateRandomPoints(1000, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd, err := NewDiagram(tt.sites)
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			areas := vd.CellAreas()
+			if len(areas) != vd.NumCells() {
+				t.Fatalf("len(vd.CellAreas()) = %v, want %v", len(areas), vd.NumCells())
+			}
+			for i, got := range areas {
+				if want := vd.Cell(i).Area(); math.Abs(got-want) > 1e-12 {
+					t.Errorf("vd.CellAreas()[%d] = %v, want %v", i, got, want)
+				}
+			}
+			if got := vd.TotalArea(); math.Abs(got-4*math.Pi) > 1e-12 {
+				t.Errorf("vd.TotalArea() = %v, want 4π", got)
+			}
+		})
+	}
+}
+
+func TestDiagram_CellAreas_EmptyCell(t *testing.T) {
+	vd, empty := mustNewEmptyCellDiagram(t)
+	if got := vd.CellAreas()[empty]; got != 0 {
+		t.Errorf("vd.CellAreas()[%d] = %v, want 0", empty, got)
+	}
+	if got := vd.TotalArea(); math.Abs(got-4*math.Pi) > 1e-12 {
+		t.Errorf("vd.TotalArea() = %v, want 4π", got)
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDiagram_CellAreas(b *testing.B) {
+	vd, err := NewDiagram(utils.GenerateRandomPoints(100000, 0))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+
+	b.Run("Batch", func(b *testing.B) {
+		for b.Loop() {
+			vd.CellAreas()
+		}
+	})
+	b.Run("PerCell", func(b *testing.B) {
+		for b.Loop() {
+			areas := make([]float64, vd.NumCells())
+			for i := range areas {
+				areas[i] = vd.Cell(i).Area()
+			}
+		}
+	})
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// Bisector returns the great circle of points equidistant from the sites i and j, or of equal
+// power in a power diagram. The circle is described as a pole and a colatitude, which is always
+// π/2. The pole points towards site i, so the hemisphere around the pole holds the points
+// closer to i. It returns an error if i equals j or the two sites coincide within eps.
+// It panics if either index is out of range.
+func (d *Diagram) Bisector(i, j int) (s2.Point, s1.Angle, error) {
+	d.checkCellIndex(i)
+	d.checkCellIndex(j)
+	if i == j {
+		return s2.Point{}, 0, fmt.Errorf("s2voronoi: bisector of site %d with itself", i)
+	}
+
+	diff := d.generator(i).Sub(d.generator(j))
+	if diff.Norm() <= d.eps {
+		return s2.Point{}, 0, errorf(ErrDuplicateSites, "s2voronoi: sites %d and %d coincide", i, j)
+	}
+
+	return s2.Point{Vector: diff.Normalize()}, s1.Angle(math.Pi / 2), nil
+}
+
+// BisectorEdge returns the Voronoi edge shared by the cells i and j, i.e. the part of
+// their bisector that is clipped by the diagram. The endpoints are returned in the CCW
+// order of cell i. It returns ok == false if the cells are not adjacent.
+// It panics if either index is out of range.
+func (d *Diagram) BisectorEdge(i, j int) (a, b s2.Point, ok bool) {
+	d.checkCellIndex(j)
+	c := d.Cell(i)
+	for k, nIdx := range c.NeighborIndices() {
+		if nIdx == j {
+			return c.Vertex(k), c.Vertex((k + 1) % c.NumVertices()), true
+		}
+	}
+	return s2.Point{}, s2.Point{}, false
+}
+
+// Portal returns the point where the geodesic between the sites i and j crosses the Voronoi
+// edge shared by their cells, and the length of that geodesic. The geodesic crosses the
+// bisector at the midpoint of the sites, or in a power diagram at the point of the great circle
+// through the sites with equal power, see Bisector. If the Delaunay triangles on both sides of
+// the geodesic are obtuse enough, or the weights differ enough, that point lies beyond an
+// endpoint of the shared edge, and the crossing is clamped to the closest point of the edge,
+// which is that endpoint; this also absorbs rounding when the geodesic grazes an endpoint.
+// It returns an error if the cells are not adjacent. It panics if either index is out of range.
+func (d *Diagram) Portal(i, j int) (crossing s2.Point, siteDist s1.Angle, err error) {
+	a, b, ok := d.BisectorEdge(i, j)
+	if !ok {
+		return s2.Point{}, 0, fmt.Errorf("s2voronoi: cells %d and %d are not adjacent", i, j)
+	}
+
+	// The bisector plane and the plane of the geodesic meet in the line through the crossing,
+	// which is taken on the side of the geodesic. Without weights it is the midpoint.
+	si, sj := d.Sites[i], d.Sites[j]
+	v := d.generator(i).Sub(d.generator(j)).Cross(si.Cross(sj.Vector))
+	if v.Dot(si.Add(sj.Vector)) < 0 {
+		v = v.Mul(-1)
+	}
+	return s2.Project(s2.Point{Vector: v.Normalize()}, a, b), si.Distance(sj), nil
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s2"
+)
+
+func TestDiagram_Bisector(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	for i := range vd.NumCells() {
+		for _, j := range []int{(i + 1) % vd.NumCells(), (i + 37) % vd.NumCells()} {
+			pole, colat, err := vd.Bisector(i, j)
+			if err != nil {
+				t.Fatalf("vd.Bisector(%d, %d) error = %v, want nil", i, j, err)
+			}
+			if colat.Radians() != math.Pi/2 {
+				t.Errorf("vd.Bisector(%d, %d) colatitude = %v, want π/2", i, j, colat)
+			}
+			if pole.Dot(vd.Sites[i].Vector) <= pole.Dot(vd.Sites[j].Vector) {
+				t.Errorf("vd.Bisector(%d, %d) pole does not point towards site %d", i, j, i)
+			}
+
+			u := s2.Ortho(pole)
+			w := pole.Cross(u.Vector)
+			for k := range 16 {
+				theta := 2 * math.Pi * float64(k) / 16
+				p := s2.Point{Vector: u.Mul(math.Cos(theta)).Add(w.Mul(math.Sin(theta)))}
+				di := p.Distance(vd.Sites[i])
+				dj := p.Distance(vd.Sites[j])
+				if math.Abs((di - dj).Radians()) > vd.eps {
+					t.Errorf("vd.Bisector(%d, %d) point %v distances %v, %v, want equal", i, j, p,
+						di, dj)
+				}
+			}
+		}
+	}
+}
+
+func TestDiagram_Bisector_Error(t *testing.T) {
+	vd := mustNewDiagram(t, 10)
+	if _, _, err := vd.Bisector(1, 1); err == nil {
+		t.Errorf("vd.Bisector(1, 1) error = nil, want non-nil")
+	}
+
+	vd.Sites[2] = vd.Sites[3]
+	if _, _, err := vd.Bisector(2, 3); err == nil {
+		t.Errorf("vd.Bisector(2, 3) error = nil, want non-nil for coincident sites")
+	}
+}
+
+func TestDiagram_Bisector_Panic(t *testing.T) {
+	vd := mustNewDiagram(t, 10)
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("vd.Bisector(0, %d) did not panic, want panic", vd.NumCells())
+		}
+	}()
+	_, _, _ = vd.Bisector(0, vd.NumCells())
+}
+
+func TestDiagram_BisectorEdge(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	for i := range vd.NumCells() {
+		c := vd.Cell(i)
+		for k, j := range c.NeighborIndices() {
+			a, b, ok := vd.BisectorEdge(i, j)
+			if !ok {
+				t.Fatalf("vd.BisectorEdge(%d, %d) ok = false, want true", i, j)
+			}
+			if a != c.Vertex(k) || b != c.Vertex((k+1)%c.NumVertices()) {
+				t.Errorf("vd.BisectorEdge(%d, %d) = %v, %v, want cell edge %d", i, j, a, b, k)
+			}
+			for _, p := range []s2.Point{a, b} {
+				di := p.Distance(vd.Sites[i])
+				dj := p.Distance(vd.Sites[j])
+				if math.Abs((di - dj).Radians()) > vd.eps {
+					t.Errorf("vd.BisectorEdge(%d, %d) endpoint %v distances %v, %v, want equal",
+						i, j, p, di, dj)
+				}
+			}
+		}
+	}
+}
+
+func TestDiagram_BisectorEdge_NotAdjacent(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	c := vd.Cell(0)
+	for j := 1; j < vd.NumCells(); j++ {
+		adjacent := false
+		for _, nIdx := range c.NeighborIndices() {
+			adjacent = adjacent || nIdx == j
+		}
+		if _, _, ok := vd.BisectorEdge(0, j); ok != adjacent {
+			t.Errorf("vd.BisectorEdge(0, %d) ok = %v, want %v", j, ok, adjacent)
+		}
+	}
+}
+
+func TestDiagram_Portal(t *testing.T) {
+	sites := utils.GenerateRandomPoints(100, 0)
+	weights := make([]float64, len(sites))
+	r := rand.New(rand.NewSource(0))
+	for i := range weights {
+		weights[i] = r.Float64() * 0.05
+	}
+	power, err := NewPowerDiagram(sites, weights)
+	if err != nil {
+		t.Fatalf("NewPowerDiagram(...) error = %v, want nil", err)
+	}
+	tests := []struct {
+		name string
+		vd   *Diagram
+	}{
+		{"voronoi", mustNewDiagram(t, 100)},
+		{"power", power},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd := tt.vd
+			clamped := 0
+			for i := range vd.NumCells() {
+				for _, j := range vd.Cell(i).NeighborIndices() {
+					crossing, siteDist, err := vd.Portal(i, j)
+					if err != nil {
+						t.Fatalf("vd.Portal(%d, %d) error = %v, want nil", i, j, err)
+					}
+					if want := vd.Sites[i].Distance(vd.Sites[j]); siteDist != want {
+						t.Errorf("vd.Portal(%d, %d) siteDist = %v, want %v", i, j, siteDist, want)
+					}
+
+					a, b, _ := vd.BisectorEdge(i, j)
+					if dist := s2.DistanceFromSegment(crossing, a, b); dist.Radians() > vd.eps {
+						t.Errorf("vd.Portal(%d, %d) crossing %v is %v away from the shared edge",
+							i, j, crossing, dist)
+					}
+					// The crossing has equal power, which is equal distance without weights.
+					diff := vd.generator(i).Sub(vd.generator(j))
+					if pow := crossing.Dot(diff); math.Abs(pow) > vd.eps {
+						t.Errorf("vd.Portal(%d, %d) crossing power difference %v, want 0", i, j,
+							pow)
+					}
+
+					// With weights the point of equal power may lie beyond a site, so the
+					// crossing is checked against the great circle through the sites.
+					normal := vd.Sites[i].Cross(vd.Sites[j].Vector).Normalize()
+					if math.Abs(crossing.Dot(normal)) > vd.eps {
+						clamped++
+						if crossing != a && crossing != b {
+							t.Errorf("vd.Portal(%d, %d) crossing %v is off the geodesic but not "+
+								"an endpoint", i, j, crossing)
+						}
+					}
+				}
+			}
+			if clamped == len(vd.CellNeighbors) {
+				t.Errorf("vd.Portal(...) clamped all %d crossings, want most on the geodesic",
+					clamped)
+			}
+		})
+	}
+}
+
+func TestDiagram_Portal_NotAdjacent(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	for j := 1; j < vd.NumCells(); j++ {
+		if vd.AreNeighbors(0, j) {
+			continue
+		}
+		if _, _, err := vd.Portal(0, j); err == nil {
+			t.Errorf("vd.Portal(0, %d) error = nil, want non-nil", j)
+		}
+	}
+	if _, _, err := vd.Portal(0, 0); err == nil {
+		t.Errorf("vd.Portal(0, 0) error = nil, want non-nil")
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"math"
+
+	"github.com/golang/geo/r1"
+	"github.com/golang/geo/r3"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// boundError is the angle by which bounds are expanded to absorb the rounding error of the
+// points computed on geodesic edges.
+const boundError = s1.Angle(1e-14)
+
+// CellCapBounds returns a bounding cap for every cell, see Cell.CapBound. The caps are computed
+// once on first use and shared by all callers, so the returned slice must not be modified.
+func (d *Diagram) CellCapBounds() []s2.Cap {
+	c := d.caches()
+	c.capBoundsOnce.Do(func() {
+		c.capBounds = make([]s2.Cap, d.NumCells())
+		for i := range d.NumCells() {
+			c.capBounds[i] = d.Cell(i).CapBound()
+		}
+	})
+	return c.capBounds
+}
+
+// CellsIntersectingRegion returns the indices of the cells whose bounding caps intersect the
+// bounding cap of the region, in increasing order. It is a conservative filter: every cell
+// intersecting the region is reported, but some reported cells may not intersect it.
+func (d *Diagram) CellsIntersectingRegion(r s2.Region) []int {
+	bound := r.CapBound()
+	var out []int
+	for i, cp := range d.CellCapBounds() {
+		if cp.Intersects(bound) {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// CapBound returns a cap centered at the site that contains the whole cell, analogous to
+// s2.Region.CapBound. The interior of a geodesic edge can be farther from the site than both of
+// its endpoints, so besides the vertices the cap contains the point of every edge farthest from
+// the site, and it is expanded to cover rounding errors. Every point of the cell, including the
+// edge interiors, is therefore contained in the cap. The cap of an empty cell contains only the
+// site.
+func (c Cell) CapBound() s2.Cap {
+	site := c.Site()
+	cp := s2.CapFromPoint(site)
+	num := c.NumVertices()
+	for i := range num {
+		a, b := c.Vertex(i), c.Vertex((i+1)%num)
+		cp = cp.AddPoint(a)
+		if f, ok := farthestEdgePoint(site, a, b); ok {
+			cp = cp.AddPoint(f)
+		}
+	}
+	return cp.Expanded(boundError)
+}
+
+// RectBound returns a latitude-longitude rectangle that contains the whole cell, analogous to
+// s2.Region.RectBound. It is computed as s2.Loop computes its bound: the vertices are fed to
+// an s2.RectBounder, which accounts for edges bulging beyond the latitude of their endpoints
+// and for cells crossing the antimeridian, whose longitude interval is inverted, and the result
+// is expanded to cover rounding errors. A cell
+// containing a pole, including one with the pole on its boundary, extends to that pole and
+// spans all longitudes. The rectangle of an empty cell contains only the site.
+func (c Cell) RectBound() s2.Rect {
+	num := c.NumVertices()
+	if num == 0 {
+		return s2.RectFromLatLng(s2.LatLngFromPoint(c.Site()))
+	}
+
+	// Vertex 0 is added twice to close the ring.
+	bounder := s2.NewRectBounder()
+	for i := range num + 1 {
+		bounder.AddPoint(c.Vertex(i % num))
+	}
+	// The bounder only expands the latitudes, while the points computed on meridian edges may
+	// also round to longitudes beyond those of the vertices.
+	b := bounder.RectBound()
+	b.Lat = b.Lat.Expanded(boundError.Radians()).Intersection(
+		r1.Interval{Lo: -math.Pi / 2, Hi: math.Pi / 2})
+	b.Lng = b.Lng.Expanded(boundError.Radians())
+	b = b.PolarClosure()
+
+	if c.containsClosed(s2.Point{Vector: r3.Vector{X: 0, Y: 0, Z: 1}}) {
+		b = s2.Rect{Lat: r1.Interval{Lo: b.Lat.Lo, Hi: math.Pi / 2}, Lng: s1.FullInterval()}
+	}
+	if c.containsClosed(s2.Point{Vector: r3.Vector{X: 0, Y: 0, Z: -1}}) {
+		b = s2.Rect{Lat: r1.Interval{Lo: -math.Pi / 2, Hi: b.Lat.Hi}, Lng: s1.FullInterval()}
+	}
+	return b
+}
+
+// containsClosed reports whether p lies in the cell or on its boundary, i.e. whether p is at
+// least as close to the site as to the site of every neighbor, see ContainsPoint.
+func (c Cell) containsClosed(p s2.Point) bool {
+	dot := p.Dot(c.d.generator(c.idx))
+	for _, j := range c.NeighborIndices() {
+		if p.Dot(c.d.generator(j)) > dot {
+			return false
+		}
+	}
+	return true
+}
+
+// farthestEdgePoint returns the point of the edge AB farthest from p if it lies in the interior
+// of the edge. Otherwise the farthest point is one of the endpoints and ok is false.
+func farthestEdgePoint(p, a, b s2.Point) (s2.Point, bool) {
+	n := a.PointCross(b).Normalize()
+	f := p.Sub(n.Mul(p.Dot(n))).Mul(-1)
+	if f.Norm2() == 0 {
+		return s2.Point{}, false
+	}
+	if n.Dot(a.Cross(f)) <= 0 || n.Dot(f.Cross(b.Vector)) <= 0 {
+		return s2.Point{}, false
+	}
+	return s2.Point{Vector: f.Normalize()}, true
+}
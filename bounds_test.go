@@ -0,0 +1,240 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/internal/fixtures"
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+func TestCell_CapBound(t *testing.T) {
+	tests := []struct {
+		name  string
+		sites s2.PointVector
+	}{
+		{"octahedron", fixtures.Load("octahedron")},
+		{"tetrahedron", s2.PointVector{
+			s2.PointFromCoords(1, 1, 1), s2.PointFromCoords(1, -1, -1),
+			s2.PointFromCoords(-1, 1, -1), s2.PointFromCoords(-1, -1, 1),
+		}},
+		{"random", utils.GenerateRandomPoints(100, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd, err := NewDiagram(tt.sites)
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			for i := range vd.NumCells() {
+				c := vd.Cell(i)
+				cp := c.CapBound()
+				if cp.Center() != c.Site() {
+					t.Errorf("vd.Cell(%d).CapBound().Center() = %v, want the site %v", i,
+						cp.Center(), c.Site())
+				}
+				for j := range c.NumVertices() {
+					e := c.Edge(j)
+					for k := range 65 {
+						p := s2.Interpolate(float64(k)/64, e.V0, e.V1)
+						if !cp.ContainsPoint(p) {
+							t.Errorf("vd.Cell(%d).CapBound() does not contain %v on edge %d", i, p, j)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCell_RectBound(t *testing.T) {
+	latLng := func(lat, lng float64) s2.Point {
+		return s2.PointFromLatLng(s2.LatLngFromDegrees(lat, lng))
+	}
+	dateline := s2.PointVector{
+		latLng(0, 180), latLng(0, 170), latLng(0, -170), latLng(20, 180), latLng(-20, 180),
+		latLng(0, 0), latLng(0, 90), latLng(0, -90), latLng(89, 45), latLng(-89, -45),
+	}
+	octahedron := fixtures.Load("octahedron")
+
+	tests := []struct {
+		name  string
+		sites s2.PointVector
+		cell  int
+		check func(r s2.Rect) bool
+	}{
+		{"dateline", dateline, 0, func(r s2.Rect) bool {
+			return r.Lng.IsInverted() && r.Lng.Contains(math.Pi) && !r.Lng.Contains(0)
+		}},
+		{"near north pole", dateline, 8, func(r s2.Rect) bool {
+			return r.Lat.Hi == math.Pi/2 && r.Lng.IsFull()
+		}},
+		{"near south pole", dateline, 9, func(r s2.Rect) bool {
+			return r.Lat.Lo == -math.Pi/2 && r.Lng.IsFull()
+		}},
+		{"antimeridian site", octahedron, 1, func(r s2.Rect) bool {
+			return r.Lng.IsInverted() && r.Lng.Contains(math.Pi)
+		}},
+		{"north pole site", octahedron, 4, func(r s2.Rect) bool {
+			return r.Lat.Hi == math.Pi/2 && r.Lng.IsFull()
+		}},
+		{"south pole site", octahedron, 5, func(r s2.Rect) bool {
+			return r.Lat.Lo == -math.Pi/2 && r.Lng.IsFull()
+		}},
+		// The vertices are at latitude ±35.26°, and the edges between them bulge to ±45°.
+		{"edge bulge", octahedron, 0, func(r s2.Rect) bool {
+			return r.Lat.Hi >= math.Pi/4 && r.Lat.Lo <= -math.Pi/4 && r.Lng.Hi < math.Pi/2
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd, err := NewDiagram(tt.sites)
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			r := vd.Cell(tt.cell).RectBound()
+			if !tt.check(r) {
+				t.Errorf("vd.Cell(%d).RectBound() = %v, unexpected bound", tt.cell, r)
+			}
+			for i := range vd.NumCells() {
+				assertRectContainsCell(t, vd.Cell(i))
+			}
+		})
+	}
+
+	t.Run("random", func(t *testing.T) {
+		vd := mustNewDiagram(t, 100)
+		for i := range vd.NumCells() {
+			assertRectContainsCell(t, vd.Cell(i))
+		}
+	})
+}
+
+func TestDiagram_CellCapBounds(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	caps := vd.CellCapBounds()
+	if len(caps) != vd.NumCells() {
+		t.Fatalf("len(vd.CellCapBounds()) = %v, want %v", len(caps), vd.NumCells())
+	}
+	for i, cp := range caps {
+		c := vd.Cell(i)
+		if !cp.ContainsPoint(c.Site()) {
+			t.Errorf("vd.CellCapBounds()[%d] does not contain the site", i)
+		}
+		for j := range c.NumVertices() {
+			a, b := c.Vertex(j), c.Vertex((j+1)%c.NumVertices())
+			for k := range 17 {
+				p := s2.Interpolate(float64(k)/16, a, b)
+				if !cp.ContainsPoint(p) {
+					t.Errorf("vd.CellCapBounds()[%d] does not contain %v on edge %d", i, p, j)
+				}
+			}
+		}
+	}
+	if again := vd.CellCapBounds(); &again[0] != &caps[0] {
+		t.Errorf("vd.CellCapBounds() was recomputed, want cached")
+	}
+}
+
+func TestDiagram_CellCapBounds_Relax(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	before := vd.CellCapBounds()[0]
+	if _, err := vd.Relax(1); err != nil {
+		t.Fatalf("vd.Relax(1) error = %v, want nil", err)
+	}
+	if want := vd.Cell(0).CapBound(); vd.CellCapBounds()[0] != want {
+		t.Errorf("vd.CellCapBounds()[0] = %v after Relax, want %v (was %v)",
+			vd.CellCapBounds()[0], want, before)
+	}
+}
+
+func TestDiagram_CellsIntersectingRegion(t *testing.T) {
+	vd := mustNewDiagram(t, 200)
+	random := rand.New(rand.NewSource(0))
+	for _, p := range utils.GenerateRandomPoints(100, 1) {
+		cp := s2.CapFromCenterAngle(p, s1.Angle(random.Float64()*0.2))
+		got := vd.CellsIntersectingRegion(cp)
+		if !slices.IsSorted(got) {
+			t.Errorf("vd.CellsIntersectingRegion(%v) = %v, want sorted", cp, got)
+		}
+
+		// The cell owning the center and all cells containing a sampled boundary point of
+		// the cap must be reported.
+		edge := s2.InterpolateAtDistance(cp.Radius(), p, s2.Ortho(p))
+		for k := range 8 {
+			q := p
+			if k > 0 {
+				q = s2.Rotate(edge, p, s1.Angle(k))
+			}
+			owner := nearestSite(vd, q)
+			if !slices.Contains(got, owner) {
+				t.Errorf("vd.CellsIntersectingRegion(%v) = %v, missing cell %d owning %v", cp,
+					got, owner, q)
+			}
+		}
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDiagram_CellsIntersectingRegion(b *testing.B) {
+	vd, err := NewDiagram(utils.GenerateRandomPoints(10000, 0))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	cp := s2.CapFromCenterAngle(vd.Sites[0], s1.Angle(0.05))
+
+	b.Run("Cached", func(b *testing.B) {
+		vd.CellCapBounds()
+		b.ResetTimer()
+		for b.Loop() {
+			vd.CellsIntersectingRegion(cp)
+		}
+	})
+	b.Run("Uncached", func(b *testing.B) {
+		for b.Loop() {
+			vd.invalidateCaches()
+			vd.CellsIntersectingRegion(cp)
+		}
+	})
+}
+
+// Helpers
+
+// assertRectContainsCell checks that the RectBound of the cell contains points sampled on its
+// boundary and on the segments from the site to the boundary.
+func assertRectContainsCell(t *testing.T, c Cell) {
+	t.Helper()
+	r := c.RectBound()
+	for j := range c.NumVertices() {
+		e := c.Edge(j)
+		for k := range 33 {
+			b := s2.Interpolate(float64(k)/32, e.V0, e.V1)
+			for _, f := range []float64{0, 0.5, 0.99, 1} {
+				if p := s2.Interpolate(f, c.Site(), b); !r.ContainsPoint(p) {
+					t.Errorf("vd.Cell(%d).RectBound() = %v does not contain %v", c.SiteIndex(), r,
+						s2.LatLngFromPoint(p))
+					return
+				}
+			}
+		}
+	}
+}
+
+func nearestSite(vd *Diagram, p s2.Point) int {
+	best := 0
+	for i, s := range vd.Sites {
+		if p.Distance(s) < p.Distance(vd.Sites[best]) {
+			best = i
+		}
+	}
+	return best
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"sync"
+
+	"github.com/2dChan/s2voronoi/s2delaunay"
+	"github.com/golang/geo/s2"
+)
+
+// diagramCache holds lazily built structures derived from the diagram data.
+// Every operation that modifies the diagram must discard it via invalidateCaches.
+type diagramCache struct {
+	neighborsOnce sync.Once
+	neighbors     map[uint64]struct{}
+
+	capBoundsOnce sync.Once
+	capBounds     []s2.Cap
+
+	hintsOnce  sync.Once
+	hintsLevel int
+	hints      []int
+
+	vertexCellsOnce   sync.Once
+	vertexCellOffsets []int
+	vertexCells       []int
+
+	vertexNeighborsOnce sync.Once
+	vertexNeighbors     []int
+
+	shapeIndexOnce sync.Once
+	shapeIndex     *s2.ShapeIndex
+
+	// triangulation is the triangulation the diagram was built from if it was retained, see
+	// WithRetainedTriangulation.
+	triangulation *s2delaunay.Triangulation
+}
+
+// caches returns the cache of the diagram. Diagrams created by the constructors always have
+// one; for diagrams assembled by hand it is allocated on first use, which is not safe for
+// concurrent use.
+func (d *Diagram) caches() *diagramCache {
+	if d.cache == nil {
+		d.cache = new(diagramCache)
+	}
+	return d.cache
+}
+
+// invalidateCaches discards all lazily built structures of the diagram.
+func (d *Diagram) invalidateCaches() {
+	d.cache = new(diagramCache)
+}
+
+// edgeKey returns a key identifying the undirected edge between a and b.
+func edgeKey(a, b int) uint64 {
+	if a > b {
+		a, b = b, a
+	}
+	return uint64(a)<<32 | uint64(uint32(b))
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/golang/geo/s2"
+)
+
+// WithOrderIndependentOutput makes the diagram independent of the order of the sites: any
+// permutation of the same sites yields the same Vertices and, for every site, the same cell
+// ring and neighbor sites. The sites are sorted canonically by their s2.CellID before the
+// diagram is built, and all cell indices are mapped back to the order of the given sites. The
+// option is kept by Relax.
+func WithOrderIndependentOutput() DiagramOption {
+	return func(o *DiagramOptions) error {
+		o.OrderIndependent = true
+		return nil
+	}
+}
+
+// newOrderIndependentDiagram builds the diagram of the canonically sorted sites and remaps its
+// cells to the order of the given sites.
+func newOrderIndependentDiagram(ctx context.Context, sites s2.PointVector,
+	opts *DiagramOptions) (*Diagram, error) {
+	n := len(sites)
+	perm := canonicalSiteOrder(sites)
+	sorted := make(s2.PointVector, n)
+	for k, i := range perm {
+		sorted[k] = sites[i]
+	}
+
+	sd, err := newDiagram(ctx, sorted, opts)
+	if err != nil {
+		var derr *DuplicateSitesError
+		if errors.As(err, &derr) {
+			return nil, permuteDuplicates(derr, perm)
+		}
+		return nil, err
+	}
+
+	rank := make([]int, n)
+	for k, i := range perm {
+		rank[i] = k
+	}
+	d := &Diagram{
+		Sites:         sites,
+		Vertices:      sd.Vertices,
+		CellVertices:  make([]int, 0, len(sd.CellVertices)),
+		CellNeighbors: make([]int, 0, len(sd.CellNeighbors)),
+		CellOffsets:   make([]int, 1, n+1),
+
+		eps:              opts.Eps,
+		orderIndependent: true,
+		mergeTol:         sd.mergeTol,
+		cache:            new(diagramCache),
+	}
+	for i := range n {
+		c := sd.Cell(rank[i])
+		d.CellVertices = append(d.CellVertices, c.VertexIndices()...)
+		for _, nIdx := range c.NeighborIndices() {
+			d.CellNeighbors = append(d.CellNeighbors, perm[nIdx])
+		}
+		d.CellOffsets = append(d.CellOffsets, len(d.CellVertices))
+	}
+	return d, nil
+}
+
+// permuteDuplicates returns the error e about the sorted sites for the given sites, where the
+// sorted site k is the site perm[k].
+func permuteDuplicates(e *DuplicateSitesError, perm []int) *DuplicateSitesError {
+	pe := &DuplicateSitesError{Indices: make([]int, len(e.Indices))}
+	for k, i := range e.Indices {
+		pe.Indices[k] = perm[i]
+	}
+	slices.Sort(pe.Indices)
+	if e.Pairs == nil {
+		return pe
+	}
+
+	// The later site of every pair changes with the order, so the indices follow the pairs.
+	pe.Pairs = make([][2]int, len(e.Pairs))
+	pe.Indices = pe.Indices[:0]
+	for k, p := range e.Pairs {
+		i, j := perm[p[0]], perm[p[1]]
+		pe.Pairs[k] = [2]int{min(i, j), max(i, j)}
+	}
+	slices.SortFunc(pe.Pairs, func(a, b [2]int) int {
+		return cmp.Or(cmp.Compare(a[1], b[1]), cmp.Compare(a[0], b[0]))
+	})
+	for _, p := range pe.Pairs {
+		pe.Indices = append(pe.Indices, p[1])
+	}
+	pe.Indices = slices.Compact(pe.Indices)
+	return pe
+}
+
+// canonicalSiteOrder returns the indices of the sites sorted by s2.CellID, with ties broken
+// by the coordinates.
+func canonicalSiteOrder(sites s2.PointVector) []int {
+	ids := make([]s2.CellID, len(sites))
+	perm := make([]int, len(sites))
+	for i, p := range sites {
+		ids[i] = s2.CellFromPoint(p).ID()
+		perm[i] = i
+	}
+	slices.SortStableFunc(perm, func(a, b int) int {
+		if c := cmp.Compare(ids[a], ids[b]); c != 0 {
+			return c
+		}
+		return sites[a].Cmp(sites[b].Vector)
+	})
+	return perm
+}
+
+// Canonicalize rotates the ring of every cell, together with its neighbors, to start with its
+// smallest vertex index, preserving the CCW order. Diagrams with the same vertex numbering, e.g.
+// built twice from the same sites or with WithOrderIndependentOutput from permuted sites, then
+// have identical CSR arrays, whatever the starting points chosen when they were built, e.g. for
+// golden files or hashing. See s2delaunay.Triangulation.Canonicalize for the triangulation.
+func (d *Diagram) Canonicalize() {
+	for i := range d.NumCells() {
+		start, end := d.CellOffsets[i], d.CellOffsets[i+1]
+		ring := d.CellVertices[start:end]
+		if len(ring) == 0 {
+			continue
+		}
+		k := slices.Index(ring, slices.Min(ring))
+		for _, s := range [][]int{ring, d.CellNeighbors[start:end]} {
+			slices.Reverse(s[:k])
+			slices.Reverse(s[k:])
+			slices.Reverse(s)
+		}
+	}
+	d.invalidateCaches()
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWithOrderIndependentOutput(t *testing.T) {
+	points := utils.GenerateRandomPoints(200, 0)
+	random := rand.New(rand.NewSource(0))
+	for range 5 {
+		want, err := NewDiagram(slices.Clone(points), WithOrderIndependentOutput())
+		if err != nil {
+			t.Fatalf("NewDiagram(..., WithOrderIndependentOutput()) error = %v, want nil", err)
+		}
+		if err := want.Validate(); err != nil {
+			t.Fatalf("want.Validate() error = %v, want nil", err)
+		}
+
+		perm := random.Perm(len(points))
+		sites := make(s2.PointVector, len(points))
+		for i, p := range perm {
+			sites[i] = points[p]
+		}
+		got, err := NewDiagram(sites, WithOrderIndependentOutput())
+		if err != nil {
+			t.Fatalf("NewDiagram(permuted, WithOrderIndependentOutput()) error = %v, want nil", err)
+		}
+		assertSameDiagram(t, want, got, perm)
+
+		if _, err := want.Relax(2); err != nil {
+			t.Fatalf("want.Relax(2) error = %v, want nil", err)
+		}
+		if _, err := got.Relax(2); err != nil {
+			t.Fatalf("got.Relax(2) error = %v, want nil", err)
+		}
+		assertSameDiagram(t, want, got, perm)
+	}
+}
+
+func TestDiagram_Canonicalize(t *testing.T) {
+	points := utils.GenerateRandomPoints(200, 0)
+	perm := rand.New(rand.NewSource(0)).Perm(len(points))
+	permuted := make(s2.PointVector, len(points))
+	for i, p := range perm {
+		permuted[i] = points[p]
+	}
+	build := func(t *testing.T, sites s2.PointVector, setters ...DiagramOption) *Diagram {
+		vd, err := NewDiagram(slices.Clone(sites), setters...)
+		if err != nil {
+			t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+		}
+		vd.Canonicalize()
+		return vd
+	}
+
+	t.Run("rotated rings", func(t *testing.T) {
+		want := build(t, points)
+		vd, err := NewDiagram(slices.Clone(points))
+		if err != nil {
+			t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+		}
+		for i := range vd.NumCells() {
+			start, end := vd.CellOffsets[i], vd.CellOffsets[i+1]
+			k := i % (end - start)
+			rotateLeft(vd.CellVertices[start:end], k)
+			rotateLeft(vd.CellNeighbors[start:end], k)
+		}
+		vd.Canonicalize()
+		if err := vd.Validate(); err != nil {
+			t.Errorf("vd.Validate() = %v, want nil", err)
+		}
+		if !vd.Equal(want) {
+			t.Errorf("vd.Canonicalize() of rotated rings differs from the canonical diagram")
+		}
+		for i, c := range vd.Cells() {
+			if ring := c.VertexIndices(); ring[0] != slices.Min(ring) {
+				t.Errorf("Cell(%d).VertexIndices() = %v, want to start with the smallest", i, ring)
+			}
+		}
+	})
+	t.Run("built twice", func(t *testing.T) {
+		if !build(t, points).Equal(build(t, points)) {
+			t.Errorf("canonical diagrams of the same sites differ")
+		}
+	})
+	t.Run("permuted order independent", func(t *testing.T) {
+		want := build(t, points, WithOrderIndependentOutput())
+		got := build(t, permuted, WithOrderIndependentOutput())
+		assertSameDiagram(t, want, got, perm)
+	})
+}
+
+// Helpers
+
+// rotateLeft rotates s in place by k positions to the left.
+func rotateLeft(s []int, k int) {
+	slices.Reverse(s[:k])
+	slices.Reverse(s[k:])
+	slices.Reverse(s)
+}
+
+// assertSameDiagram checks that got is the diagram want with its sites permuted, such that
+// site i of got is site perm[i] of want.
+func assertSameDiagram(t *testing.T, want, got *Diagram, perm []int) {
+	t.Helper()
+	if diff := cmp.Diff(want.Vertices, got.Vertices); diff != "" {
+		t.Fatalf("Vertices mismatch (-want +got):\n%s", diff)
+	}
+	for i, p := range perm {
+		if got.Sites[i] != want.Sites[p] {
+			t.Fatalf("Sites[%d] = %v, want %v", i, got.Sites[i], want.Sites[p])
+		}
+		gc, wc := got.Cell(i), want.Cell(p)
+		if diff := cmp.Diff(wc.VertexIndices(), gc.VertexIndices()); diff != "" {
+			t.Errorf("Cell(%d).VertexIndices() mismatch (-want +got):\n%s", i, diff)
+		}
+		for k, n := range gc.NeighborIndices() {
+			if got.Sites[n] != want.Sites[wc.NeighborIndices()[k]] {
+				t.Errorf("Cell(%d).Neighbor(%d) site = %v, want %v", i, k, got.Sites[n],
+					want.Sites[wc.NeighborIndices()[k]])
+			}
+		}
+	}
+}
@@ -8,13 +8,22 @@ package s2voronoi
 
 import (
 	"fmt"
+	"iter"
+	"math"
 
 	"github.com/golang/geo/r3"
+	"github.com/golang/geo/s1"
 	"github.com/golang/geo/s2"
 )
 
 // Cell represents a Voronoi cell. It is a view structure for accessing a cell in a Diagram.
 // The cell's index corresponds to the index of its site in the Diagram's Sites.
+//
+// A cell may be empty, i.e. have no vertices and no neighbors. NewDiagram never produces empty
+// cells; they occur in diagrams assembled by hand, in diagrams whose cells were pruned or
+// clipped away, and in power diagrams, see NewPowerDiagram. Empty cells have a zero Area,
+// Moments centered at the site, and empty vertex and neighbor lists; Relax leaves their sites
+// in place.
 type Cell struct {
 	idx int
 	d   *Diagram
@@ -30,6 +39,21 @@ func (c Cell) Site() s2.Point {
 	return c.d.Sites[c.idx]
 }
 
+// fanCenter returns the point from which the cell is split into fan triangles over its edges.
+// It is the site, which lies in its cell except in power diagrams, where the vertex average is
+// used instead. It is the site for empty cells.
+func (c Cell) fanCenter() s2.Point {
+	if c.d.weights == nil || c.IsEmpty() {
+		return c.Site()
+	}
+	return s2.Point{Vector: c.centroid().Normalize()}
+}
+
+// IsEmpty reports whether the cell has no vertices.
+func (c Cell) IsEmpty() bool {
+	return c.NumVertices() == 0
+}
+
 // NumVertices returns the number of vertices in the cell.
 // This equals the number of neighbors.
 func (c Cell) NumVertices() int {
@@ -53,6 +77,16 @@ func (c Cell) Vertex(i int) s2.Point {
 	return c.d.Vertices[c.d.CellVertices[start+i]]
 }
 
+// VertexAt returns the vertex at the specified index, like Vertex, or an error if the index is
+// out of range.
+func (c Cell) VertexAt(i int) (s2.Point, error) {
+	num := c.NumVertices()
+	if i < 0 || i >= num {
+		return s2.Point{}, fmt.Errorf("s2voronoi: vertex index %d out of range [0 %d)", i, num)
+	}
+	return c.d.Vertices[c.VertexIndices()[i]], nil
+}
+
 // NumNeighbors returns the number of neighboring cells.
 // This equals the number of vertices.
 func (c Cell) NumNeighbors() int {
@@ -60,7 +94,8 @@ func (c Cell) NumNeighbors() int {
 }
 
 // NeighborIndices returns the indices of the neighboring cells in the Diagram,
-// sorted in counter-clockwise order when looking out of the sphere.
+// sorted in counter-clockwise order when looking out of the sphere. The k-th neighbor lies
+// across the edge from Vertex(k) to Vertex((k+1)%n), see Edge.
 func (c Cell) NeighborIndices() []int {
 	return c.d.CellNeighbors[c.d.CellOffsets[c.idx]:c.d.CellOffsets[c.idx+1]]
 }
@@ -77,17 +112,397 @@ func (c Cell) Neighbor(i int) Cell {
 	return nc
 }
 
+// NeighborAt returns the neighboring cell at the specified index, like Neighbor, or an error if
+// the index is out of range.
+func (c Cell) NeighborAt(i int) (Cell, error) {
+	num := c.NumNeighbors()
+	if i < 0 || i >= num {
+		return Cell{}, fmt.Errorf("s2voronoi: neighbor index %d out of range [0 %d)", i, num)
+	}
+	return Cell{idx: c.NeighborIndices()[i], d: c.d}, nil
+}
+
+// VertexPoints returns an iterator over the vertices of the cell and their ring positions, in
+// the order of Vertex.
+func (c Cell) VertexPoints() iter.Seq2[int, s2.Point] {
+	return func(yield func(int, s2.Point) bool) {
+		for k, v := range c.VertexIndices() {
+			if !yield(k, c.d.Vertices[v]) {
+				return
+			}
+		}
+	}
+}
+
+// Neighbors returns an iterator over the neighboring cells and their ring positions, in the
+// order of Neighbor.
+func (c Cell) Neighbors() iter.Seq2[int, Cell] {
+	return func(yield func(int, Cell) bool) {
+		for k, n := range c.NeighborIndices() {
+			if !yield(k, Cell{idx: n, d: c.d}) {
+				return
+			}
+		}
+	}
+}
+
+// CellEdge is an edge of the ring of a cell.
+type CellEdge struct {
+	// V0 and V1 are the endpoints of the edge in the order of the ring.
+	V0, V1 s2.Point
+	// Vertices are the indices of V0 and V1 in the Diagram's Vertices.
+	Vertices [2]int
+	// Neighbor is the index of the cell on the other side of the edge, which has the same edge
+	// from V1 to V0 in its ring.
+	Neighbor int
+}
+
+// Edge returns the edge from Vertex(i) to Vertex((i+1)%n) of the cell with n vertices, whose
+// neighbor is NeighborIndices()[i].
+// It panics if the index is out of range.
+func (c Cell) Edge(i int) CellEdge {
+	num := c.NumVertices()
+	if i < 0 || i >= num {
+		panic(fmt.Sprintf("s2voronoi: edge index %d out of range [0 %d)", i, num))
+	}
+	vertices := c.VertexIndices()
+	a, b := vertices[i], vertices[(i+1)%num]
+	return CellEdge{
+		V0:       c.d.Vertices[a],
+		V1:       c.d.Vertices[b],
+		Vertices: [2]int{a, b},
+		Neighbor: c.NeighborIndices()[i],
+	}
+}
+
 // centroid returns the centroid of the cell by averaging its vertex vectors on the unit sphere.
+// The vertex vectors are accumulated with compensated summation.
+// It panics if the cell is empty.
 func (c Cell) centroid() s2.Point {
 	num := c.NumVertices()
 	if num == 0 {
 		panic("s2voronoi: centroid: cell has no vertices")
 	}
 
-	sum := r3.Vector{X: 0, Y: 0, Z: 0}
+	var sum compensatedVector
 	for i := range num {
-		sum = sum.Add(c.Vertex(i).Vector)
+		sum.Add(c.Vertex(i).Vector)
+	}
+
+	return s2.Point{Vector: sum.Value().Mul(1.0 / float64(num))}
+}
+
+// Centroid returns the area centroid of the cell projected onto the unit sphere. Unlike the
+// average of the vertices used by Relax by default, it does not depend on how the vertices are
+// spread along the boundary, so it coincides with the center of symmetry of a symmetric cell.
+// It is the sum of the true centroids of the fan triangles from the site to the edges, each
+// weighted by its signed area, and equals the Centroid of Moments. It is the site for empty
+// cells.
+func (c Cell) Centroid() s2.Point {
+	first := c.firstMoment()
+	if first.Norm2() == 0 {
+		return c.Site()
+	}
+	return s2.Point{Vector: first.Normalize()}
+}
+
+// firstMoment returns the integral of the position vector over the cell, accumulated over the
+// fan triangles with compensated summation. It is the zero vector for empty cells.
+func (c Cell) firstMoment() r3.Vector {
+	site := c.Site()
+	num := c.NumVertices()
+
+	// The fan triangles are built from the reversed edges, see Area.
+	var sum compensatedVector
+	for i := range num {
+		sum.Add(s2.TrueCentroid(site, c.Vertex((i+1)%num), c.Vertex(i)).Vector)
+	}
+	return sum.Value()
+}
+
+// WeightedCentroid returns the centroid of the cell weighted by the density f, projected onto
+// the unit sphere. It is computed by quadrature: every fan triangle from the site, or from the
+// vertex average in a power diagram, to an edge is split into k² triangles on a barycentric
+// grid, with k chosen so that f is evaluated at about the given number of samples in total, and
+// each of them contributes its area times the density at its center. The result is
+// deterministic, and with a constant density it approximates the area centroid of the cell.
+// f must be non-negative; if it vanishes on the whole cell, the site is returned.
+// It panics if the cell is empty or samples is not positive.
+func (c Cell) WeightedCentroid(f func(s2.Point) float64, samples int) s2.Point {
+	num := c.NumVertices()
+	if num == 0 {
+		panic("s2voronoi: WeightedCentroid: cell has no vertices")
+	}
+	if samples <= 0 {
+		panic(fmt.Sprintf("s2voronoi: WeightedCentroid: samples must be positive, got %d", samples))
 	}
+	k := max(1, int(math.Sqrt(float64(samples)/float64(num))))
 
-	return s2.Point{Vector: sum.Mul(1.0 / float64(num))}
+	center := c.fanCenter()
+	grid := make([]s2.Point, (k+1)*(k+2)/2)
+	var sum compensatedVector
+	var weight compensatedSum
+	add := func(p0, p1, p2 s2.Point) {
+		center := s2.Point{Vector: p0.Add(p1.Vector).Add(p2.Vector).Normalize()}
+		w := f(center) * s2.PointArea(p0, p1, p2)
+		sum.Add(center.Mul(w))
+		weight.Add(w)
+	}
+	for e := range num {
+		a, b := c.Vertex(e), c.Vertex((e+1)%num)
+		// Row i of the grid holds the points at i/k of the way from the center to the edge.
+		at := func(i, j int) s2.Point { return grid[i*(i+1)/2+j] }
+		for i := range k + 1 {
+			for j := range i + 1 {
+				v := center.Mul(float64(k - i)).Add(a.Mul(float64(i - j))).Add(b.Mul(float64(j)))
+				grid[i*(i+1)/2+j] = s2.Point{Vector: v.Normalize()}
+			}
+		}
+		for i := range k {
+			for j := range i + 1 {
+				add(at(i, j), at(i+1, j), at(i+1, j+1))
+				if j < i {
+					add(at(i, j), at(i+1, j+1), at(i, j+1))
+				}
+			}
+		}
+	}
+	if weight.Value() <= 0 {
+		return c.Site()
+	}
+	return s2.Point{Vector: sum.Value().Normalize()}
+}
+
+// Area returns the area of the cell on the unit sphere in steradians, which is 0 for empty
+// cells. The fan triangle areas are accumulated with compensated summation.
+func (c Cell) Area() float64 {
+	site := c.Site()
+	num := c.NumVertices()
+
+	// The ring is CCW when looking out of the sphere, which is clockwise in the s2 convention,
+	// so the fan triangles are built from the reversed edges.
+	var area compensatedSum
+	for i := range num {
+		area.Add(s2.SignedArea(site, c.Vertex((i+1)%num), c.Vertex(i)))
+	}
+	return area.Value()
+}
+
+// Perimeter returns the geodesic length of the cell boundary, the sum of the great-circle arcs
+// between consecutive vertices including the closing one. It is 0 for empty cells, and a
+// degenerate ring of two vertices yields twice the arc between them. The arc lengths are
+// accumulated with compensated summation.
+func (c Cell) Perimeter() s1.Angle {
+	num := c.NumVertices()
+
+	var perimeter compensatedSum
+	for i := range num {
+		perimeter.Add(c.Vertex(i).Distance(c.Vertex((i + 1) % num)).Radians())
+	}
+	return s1.Angle(perimeter.Value())
+}
+
+// Compactness returns the spherical isoperimetric quotient A(4π-A)/P² of the cell, where A is
+// its Area and P its Perimeter. By the isoperimetric inequality on the unit sphere, which
+// reduces to the planar 4πA/P² for small cells, it is 1 for a spherical cap and less for any
+// other shape, approaching 0 for elongated cells. It is 0 for empty cells.
+func (c Cell) Compactness() float64 {
+	p := c.Perimeter().Radians()
+	if p == 0 {
+		return 0
+	}
+	a := c.Area()
+	return a * (4*math.Pi - a) / (p * p)
+}
+
+// InteriorAngles returns the interior angle of the cell at every vertex, aligned with
+// VertexIndices. The angle at a vertex is measured in its tangent plane between the boundary
+// arcs to the previous and the next vertex, so by the Gauss-Bonnet theorem the angles of a cell
+// with n vertices sum to its Area plus (n-2)π. Vertices closer than eps to the previous vertex
+// are treated as lying on a straight boundary and get the angle π, and the angle of the vertex
+// before them is measured to the next distinct vertex, which keeps the sum intact and the
+// result free of NaNs. It returns nil for empty cells.
+func (c Cell) InteriorAngles() []s1.Angle {
+	num := c.NumVertices()
+	if num == 0 {
+		return nil
+	}
+	eps := c.d.eps
+	coincident := func(a, b s2.Point) bool { return a.Sub(b.Vector).Norm() <= eps }
+
+	angles := make([]s1.Angle, num)
+	for k := range num {
+		b := c.Vertex(k)
+		a := c.Vertex((k + num - 1) % num)
+		if coincident(a, b) {
+			angles[k] = math.Pi
+			continue
+		}
+		next := -1
+		for m := 1; m < num; m++ {
+			if v := c.Vertex((k + m) % num); !coincident(v, b) {
+				next = (k + m) % num
+				break
+			}
+		}
+		if next < 0 {
+			angles[k] = math.Pi
+			continue
+		}
+		// The ring is CCW when looking out of the sphere, which is clockwise in the s2
+		// convention, so the boundary turns right at every convex vertex.
+		angles[k] = math.Pi + s2.TurnAngle(a, b, c.Vertex(next))
+	}
+	return angles
+}
+
+// ContainsPoint reports whether p lies in the cell. The edges of a Voronoi cell lie on the
+// bisector planes between its site and its neighbors, so p is tested against each of them by
+// comparing its dot products with the two sites, scaled by the weights in a power diagram. A
+// point on an edge belongs to the cell with the smaller site index, which makes every point
+// belong to exactly one of two adjacent cells, and the cells partition the sphere as the
+// nearest-site rule does. Empty cells contain no points.
+func (c Cell) ContainsPoint(p s2.Point) bool {
+	if c.IsEmpty() {
+		return false
+	}
+	dot := p.Dot(c.d.generator(c.idx))
+	for _, j := range c.NeighborIndices() {
+		nDot := p.Dot(c.d.generator(j))
+		if nDot > dot || (nDot == dot && j < c.idx) {
+			return false
+		}
+	}
+	return true
+}
+
+// DistanceToBoundary returns the geodesic distance from p to the boundary of the cell and the
+// boundary point achieving it. p is projected onto the great-circle segment of every edge, and
+// the nearest projection wins, with ties broken by the smaller edge index. For a point outside
+// the cell this is the distance to the cell. Empty cells have no boundary, so they yield an
+// infinite distance and the site.
+func (c Cell) DistanceToBoundary(p s2.Point) (s1.Angle, s2.Point) {
+	num := c.NumVertices()
+	best, closest := s1.InfAngle(), c.Site()
+	for i := range num {
+		q := s2.Project(p, c.Vertex(i), c.Vertex((i+1)%num))
+		if dist := p.Distance(q); dist < best {
+			best, closest = dist, q
+		}
+	}
+	return best, closest
+}
+
+// CellMoments describes the area distribution of a cell up to the second moment.
+type CellMoments struct {
+	// Area is the area of the cell in steradians.
+	Area float64
+	// Centroid is the area centroid of the cell projected onto the unit sphere.
+	Centroid s2.Point
+	// Covariance is the covariance of the area distribution about the centroid, expressed as
+	// a symmetric 3x3 matrix acting on the tangent plane at the centroid.
+	Covariance [3][3]float64
+	// MajorAxis is the unit tangent direction of the largest principal variance.
+	MajorAxis r3.Vector
+	// MajorVariance and MinorVariance are the principal variances in the tangent plane.
+	MajorVariance, MinorVariance float64
+	// Anisotropy is MajorVariance / MinorVariance. It is 1 for cells without a preferred
+	// direction, such as regular polygons, and grows as the cell gets elongated.
+	Anisotropy float64
+}
+
+// Moments returns the second moment of the cell about its centroid and the derived anisotropy.
+// The moments are integrated exactly over the spherical triangles of the fan from the centroid.
+func (c Cell) Moments() CellMoments {
+	num := c.NumVertices()
+	site := c.Site()
+
+	first := c.firstMoment()
+	if first.Norm2() == 0 {
+		return CellMoments{Centroid: site, Anisotropy: 1}
+	}
+	centroid := s2.Point{Vector: first.Normalize()}
+
+	var areaSum compensatedSum
+	var second [3][3]float64
+	for i := range num {
+		a, b := c.Vertex((i+1)%num), c.Vertex(i)
+		areaSum.Add(s2.SignedArea(centroid, a, b))
+		m := triangleSecondMoment(centroid, a, b)
+		for r := range 3 {
+			for k := range 3 {
+				second[r][k] += m[r][k]
+			}
+		}
+	}
+
+	area := areaSum.Value()
+
+	u := s2.Ortho(centroid).Vector
+	w := centroid.Cross(u)
+	quad := func(x, y r3.Vector) float64 {
+		return x.Dot(r3.Vector{
+			X: second[0][0]*y.X + second[0][1]*y.Y + second[0][2]*y.Z,
+			Y: second[1][0]*y.X + second[1][1]*y.Y + second[1][2]*y.Z,
+			Z: second[2][0]*y.X + second[2][1]*y.Y + second[2][2]*y.Z,
+		}) / area
+	}
+	cuu, cuw, cww := quad(u, u), quad(u, w), quad(w, w)
+
+	mean := (cuu + cww) / 2
+	delta := math.Hypot((cuu-cww)/2, cuw)
+	major, minor := mean+delta, mean-delta
+	theta := math.Atan2(2*cuw, cuu-cww) / 2
+	axis := u.Mul(math.Cos(theta)).Add(w.Mul(math.Sin(theta)))
+
+	mo := CellMoments{
+		Area:          area,
+		Centroid:      centroid,
+		MajorAxis:     axis,
+		MajorVariance: major,
+		MinorVariance: minor,
+		Anisotropy:    math.Inf(1),
+	}
+	basis := [2]r3.Vector{u, w}
+	cov := [2][2]float64{{cuu, cuw}, {cuw, cww}}
+	for i := range 2 {
+		for j := range 2 {
+			addOuterProduct(&mo.Covariance, basis[i], basis[j], cov[i][j])
+		}
+	}
+	if minor > 0 {
+		mo.Anisotropy = major / minor
+	}
+	return mo
+}
+
+// triangleSecondMoment returns the integral of p*p^T over the spherical triangle ABC,
+// signed by the orientation of the triangle. It follows from the divergence theorem on the
+// sphere: the integral equals area/3 * I plus a boundary term built from the pole and the
+// true centroid of each edge.
+func triangleSecondMoment(a, b, c s2.Point) [3][3]float64 {
+	var m [3][3]float64
+	area := s2.SignedArea(a, b, c)
+	for i := range 3 {
+		m[i][i] = area / 3
+	}
+
+	for _, e := range [3][2]s2.Point{{a, b}, {b, c}, {c, a}} {
+		n := e[0].Cross(e[1].Vector).Normalize()
+		t := s2.EdgeTrueCentroid(e[0], e[1]).Vector
+		addOuterProduct(&m, n, t, 1.0/6)
+		addOuterProduct(&m, t, n, 1.0/6)
+	}
+	return m
+}
+
+// addOuterProduct adds s*x*y^T to m.
+func addOuterProduct(m *[3][3]float64, x, y r3.Vector, s float64) {
+	xv := [3]float64{x.X, x.Y, x.Z}
+	yv := [3]float64{y.X, y.Y, y.Z}
+	for i := range 3 {
+		for j := range 3 {
+			m[i][j] += s * xv[i] * yv[j]
+		}
+	}
 }
@@ -5,9 +5,15 @@
 package s2voronoi
 
 import (
+	"math"
+	"math/rand"
+	"slices"
 	"testing"
 
+	"github.com/2dChan/s2voronoi/internal/fixtures"
+	"github.com/2dChan/s2voronoi/utils"
 	"github.com/golang/geo/r3"
+	"github.com/golang/geo/s1"
 	"github.com/golang/geo/s2"
 	"github.com/google/go-cmp/cmp"
 )
@@ -131,6 +137,145 @@ func TestCell_Neighbor(t *testing.T) {
 	}
 }
 
+func TestCell_VertexAt(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	for i, c := range vd.Cells() {
+		for k := range c.NumVertices() {
+			got, err := c.VertexAt(k)
+			if err != nil || got != c.Vertex(k) {
+				t.Errorf("vd.Cell(%d).VertexAt(%d) = %v, %v, want %v, nil", i, k, got, err,
+					c.Vertex(k))
+			}
+		}
+		for _, k := range []int{-1, c.NumVertices()} {
+			if _, err := c.VertexAt(k); err == nil {
+				t.Errorf("vd.Cell(%d).VertexAt(%d) error = nil, want non-nil", i, k)
+			}
+		}
+	}
+}
+
+func TestCell_NeighborAt(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	for i, c := range vd.Cells() {
+		for k := range c.NumNeighbors() {
+			got, err := c.NeighborAt(k)
+			if err != nil || got != c.Neighbor(k) {
+				t.Errorf("vd.Cell(%d).NeighborAt(%d) = %v, %v, want %v, nil", i, k, got, err,
+					c.Neighbor(k))
+			}
+		}
+		for _, k := range []int{-1, c.NumNeighbors()} {
+			if _, err := c.NeighborAt(k); err == nil {
+				t.Errorf("vd.Cell(%d).NeighborAt(%d) error = nil, want non-nil", i, k)
+			}
+		}
+	}
+}
+
+func TestCell_VertexPoints(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	for i, c := range vd.Cells() {
+		var got []s2.Point
+		for k, p := range c.VertexPoints() {
+			if k != len(got) {
+				t.Errorf("vd.Cell(%d).VertexPoints() yielded position %d, want %d", i, k, len(got))
+			}
+			got = append(got, p)
+		}
+		want := make([]s2.Point, c.NumVertices())
+		for k := range want {
+			want[k] = c.Vertex(k)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("vd.Cell(%d).VertexPoints() mismatch (-want +got):\n%s", i, diff)
+		}
+	}
+
+	c := vd.Cell(0)
+	allocs := testing.AllocsPerRun(10, func() {
+		for range c.VertexPoints() {
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("ranging over c.VertexPoints() allocates %v times, want 0", allocs)
+	}
+}
+
+func TestCell_Neighbors(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	for i, c := range vd.Cells() {
+		var got []Cell
+		for k, n := range c.Neighbors() {
+			if k != len(got) {
+				t.Errorf("vd.Cell(%d).Neighbors() yielded position %d, want %d", i, k, len(got))
+			}
+			got = append(got, n)
+			if k == 2 {
+				break
+			}
+		}
+		for k, n := range got {
+			if n != c.Neighbor(k) {
+				t.Errorf("vd.Cell(%d).Neighbors() yielded %v at %d, want %v", i, n, k,
+					c.Neighbor(k))
+			}
+		}
+	}
+
+	c := vd.Cell(0)
+	allocs := testing.AllocsPerRun(10, func() {
+		for range c.Neighbors() {
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("ranging over c.Neighbors() allocates %v times, want 0", allocs)
+	}
+}
+
+func TestCell_Edge(t *testing.T) {
+	assertPanic := func(c Cell, in int) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("c.Edge(%d) did not panic, want panic", in)
+			}
+		}()
+		c.Edge(in)
+	}
+
+	vd := mustNewDiagram(t, 100)
+	for i := range vd.Sites {
+		c := vd.Cell(i)
+		num := c.NumVertices()
+		for k := range num {
+			e := c.Edge(k)
+			want := CellEdge{
+				V0:       c.Vertex(k),
+				V1:       c.Vertex((k + 1) % num),
+				Vertices: [2]int{c.VertexIndices()[k], c.VertexIndices()[(k+1)%num]},
+				Neighbor: c.NeighborIndices()[k],
+			}
+			if e != want {
+				t.Errorf("vd.Cell(%d).Edge(%d) = %+v, want %+v", i, k, e, want)
+			}
+			// The edge lies on the bisector of the two sites and is traversed reversed by the
+			// neighbor.
+			for _, v := range []s2.Point{e.V0, e.V1} {
+				diff := v.Dot(c.Site().Vector) - v.Dot(vd.Sites[e.Neighbor].Vector)
+				if math.Abs(diff) > 1e-12 {
+					t.Errorf("vd.Cell(%d).Edge(%d) endpoint %v is not equidistant from sites %d and %d",
+						i, k, v, i, e.Neighbor)
+				}
+			}
+			if !hasReversedEdge(vd.Cell(e.Neighbor), i, e.Vertices[0], e.Vertices[1]) {
+				t.Errorf("vd.Cell(%d) does not have edge %d of cell %d reversed", e.Neighbor, k, i)
+			}
+		}
+		assertPanic(c, -1)
+		assertPanic(c, num)
+	}
+}
+
 func TestCell_centroid(t *testing.T) {
 	vd := mustNewDiagram(t, 100)
 	for i := range vd.NumCells() {
@@ -144,7 +289,7 @@ func TestCell_centroid(t *testing.T) {
 		avg := sum.Mul(1.0 / float64(c.NumVertices()))
 		expected := s2.Point{Vector: avg}
 
-		if centroid.Distance(expected) > defaultEps {
+		if centroid.Distance(expected) > DefaultEps {
 			t.Errorf("c.centroid() = %v, want %v", centroid, expected)
 		}
 	}
@@ -169,3 +314,592 @@ func TestCell_centroid_Panic(t *testing.T) {
 	c := Cell{idx: 0, d: d}
 	c.centroid()
 }
+
+func TestCell_Centroid(t *testing.T) {
+	vd, err := NewDiagram(fixtures.Load("octahedron"))
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	for i := range vd.NumCells() {
+		c := vd.Cell(i)
+		if got := c.Centroid(); got.Distance(c.Site()) > 1e-15 {
+			t.Errorf("vd.Cell(%d).Centroid() = %v, want the site %v", i, got, c.Site())
+		}
+	}
+
+	// Splitting an edge of the square cell of (1, 0, 0) keeps its shape symmetric about the
+	// site, but pulls the vertex average towards the split edge.
+	c := vd.Cell(0)
+	ring := slices.Clone(c.VertexIndices())
+	a, b := c.Vertex(0), c.Vertex(1)
+	for k := range 3 {
+		vd.Vertices = append(vd.Vertices, s2.Interpolate(float64(k+1)/4, a, b))
+	}
+	n := len(vd.Vertices)
+	ring = slices.Insert(ring, 1, n-3, n-2, n-1)
+	c = withCellRing(vd, 0, ring).Cell(0)
+	if got := c.Centroid(); got.Distance(c.Site()) > 1e-15 {
+		t.Errorf("split cell Centroid() = %v, want the site %v", got, c.Site())
+	}
+	avg := s2.Point{Vector: c.centroid().Normalize()}
+	if avg.Distance(c.Site()) < 0.1 {
+		t.Errorf("split cell centroid() = %v, want away from the site %v", avg, c.Site())
+	}
+
+	vd2 := mustNewDiagram(t, 100)
+	for i := range vd2.NumCells() {
+		if got, want := vd2.Cell(i).Centroid(), vd2.Cell(i).Moments().Centroid; got != want {
+			t.Errorf("vd.Cell(%d).Centroid() = %v, want Moments().Centroid %v", i, got, want)
+		}
+	}
+
+	vd2, empty := mustNewEmptyCellDiagram(t)
+	if got := vd2.Cell(empty).Centroid(); got != vd2.Sites[empty] {
+		t.Errorf("vd.Cell(%d).Centroid() = %v, want the site %v", empty, got, vd2.Sites[empty])
+	}
+}
+
+func TestCell_WeightedCentroid(t *testing.T) {
+	vd, err := NewDiagram(fixtures.Load("octahedron"))
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	constant := func(s2.Point) float64 { return 1 }
+	// The cells of the octahedron are symmetric about their sites.
+	for i := range vd.NumCells() {
+		c := vd.Cell(i)
+		if got := c.WeightedCentroid(constant, 64); got.Distance(c.Site()) > 1e-12 {
+			t.Errorf("vd.Cell(%d).WeightedCentroid(constant, 64) = %v, want the site %v", i, got,
+				c.Site())
+		}
+	}
+
+	c := vd.Cell(4)
+	north := func(p s2.Point) float64 { return math.Max(0, p.Y) }
+	if got := c.WeightedCentroid(north, 64); got.Y <= 0 {
+		t.Errorf("vd.Cell(4).WeightedCentroid(north, 64) = %v, want Y > 0", got)
+	}
+	zero := func(s2.Point) float64 { return 0 }
+	if got := c.WeightedCentroid(zero, 64); got != c.Site() {
+		t.Errorf("vd.Cell(4).WeightedCentroid(zero, 64) = %v, want the site %v", got, c.Site())
+	}
+
+	// With a constant density the result is the area centroid, which is compared with the mean
+	// of uniform samples of the cell.
+	t.Run("constant density", func(t *testing.T) {
+		vd := mustNewDiagram(t, 100)
+		r := rand.New(rand.NewSource(0))
+		for i := range 10 {
+			c := vd.Cell(i)
+			var sum r3.Vector
+			for range 20000 {
+				sum = sum.Add(c.RandomPoint(r).Vector)
+			}
+			want := s2.Point{Vector: sum.Normalize()}
+			got := c.WeightedCentroid(constant, 256)
+			limit := c.CapBound().Radius() / 50
+			if dist := got.Distance(want); dist > limit {
+				t.Errorf("vd.Cell(%d).WeightedCentroid(constant, 256) = %v, %v from the sampled "+
+					"centroid, want at most %v", i, got, dist, limit)
+			}
+		}
+	})
+
+	t.Run("panics", func(t *testing.T) {
+		vd, empty := mustNewEmptyCellDiagram(t)
+		assertPanic := func(name string, c Cell, samples int) {
+			t.Helper()
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("%s: c.WeightedCentroid(constant, %d) did not panic, want panic", name,
+						samples)
+				}
+			}()
+			c.WeightedCentroid(constant, samples)
+		}
+		assertPanic("empty cell", vd.Cell(empty), 64)
+		assertPanic("zero samples", vd.Cell((empty+1)%vd.NumCells()), 0)
+	})
+}
+
+func TestCell_IsEmpty(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	for i := range vd.NumCells() {
+		if vd.Cell(i).IsEmpty() {
+			t.Errorf("vd.Cell(%d).IsEmpty() = true, want false", i)
+		}
+	}
+
+	vd, empty := mustNewEmptyCellDiagram(t)
+	c := vd.Cell(empty)
+	if !c.IsEmpty() {
+		t.Fatalf("c.IsEmpty() = false, want true")
+	}
+	if got := c.NumVertices(); got != 0 {
+		t.Errorf("c.NumVertices() = %v, want 0", got)
+	}
+	if got := c.NumNeighbors(); got != 0 {
+		t.Errorf("c.NumNeighbors() = %v, want 0", got)
+	}
+	if got := len(c.VertexIndices()) + len(c.NeighborIndices()); got != 0 {
+		t.Errorf("c.VertexIndices() and c.NeighborIndices() have %v elements, want 0", got)
+	}
+	if got := c.Area(); got != 0 {
+		t.Errorf("c.Area() = %v, want 0", got)
+	}
+	if got := c.Moments(); got.Area != 0 || got.Centroid != c.Site() {
+		t.Errorf("c.Moments() = %+v, want zero area centered at the site", got)
+	}
+	if _, err := c.LoopValidated(); err == nil {
+		t.Errorf("c.LoopValidated() error = nil, want non-nil")
+	}
+	if cp := vd.CellCapBounds()[empty]; !cp.ContainsPoint(c.Site()) {
+		t.Errorf("vd.CellCapBounds()[%d] = %v, want to contain the site", empty, cp)
+	}
+	if err := vd.Validate(); err != nil {
+		t.Errorf("vd.Validate() error = %v, want nil", err)
+	}
+}
+
+func TestCell_Area(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	total := 0.0
+	for i := range vd.NumCells() {
+		a := vd.Cell(i).Area()
+		if a <= 0 {
+			t.Errorf("vd.Cell(%d).Area() = %v, want positive", i, a)
+		}
+		total += a
+	}
+	if math.Abs(total-4*math.Pi) > 1e-9 {
+		t.Errorf("sum of c.Area() = %v, want 4π", total)
+	}
+}
+
+func TestCell_Perimeter(t *testing.T) {
+	// The cells of the octahedron are squares whose vertices are the cube corners.
+	vd, err := NewDiagram(fixtures.Load("octahedron"))
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	want := 4 * math.Acos(1.0/3)
+	for i := range vd.NumCells() {
+		if got := vd.Cell(i).Perimeter().Radians(); math.Abs(got-want) > 1e-12 {
+			t.Errorf("vd.Cell(%d).Perimeter() = %v, want %v", i, got, want)
+		}
+	}
+
+	vd = mustNewDiagram(t, 100)
+	for i := range vd.NumCells() {
+		c := vd.Cell(i)
+		// A convex spherical polygon inside a hemisphere is shorter than a great circle.
+		if got := c.Perimeter().Radians(); got <= 0 || got >= 2*math.Pi {
+			t.Errorf("vd.Cell(%d).Perimeter() = %v, want in (0, 2π)", i, got)
+		}
+	}
+
+	vd, empty := mustNewEmptyCellDiagram(t)
+	if got := vd.Cell(empty).Perimeter(); got != 0 {
+		t.Errorf("vd.Cell(%d).Perimeter() = %v, want 0", empty, got)
+	}
+
+	// A degenerate two-vertex ring is traversed there and back.
+	vd.CellVertices = append(vd.CellVertices, 0, 1)
+	vd.CellNeighbors = append(vd.CellNeighbors, 0, 1)
+	vd.CellOffsets[empty+1] += 2
+	arc := vd.Vertices[0].Distance(vd.Vertices[1]).Radians()
+	if got := vd.Cell(empty).Perimeter().Radians(); math.Abs(got-2*arc) > 1e-15 {
+		t.Errorf("vd.Cell(%d).Perimeter() = %v, want %v", empty, got, 2*arc)
+	}
+}
+
+func TestCell_Compactness(t *testing.T) {
+	vd, err := NewDiagram(fixtures.Load("octahedron"))
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	// The cells of the octahedron are squares with the area 4π/6 and the perimeter
+	// 4·acos(1/3).
+	a, p := 4*math.Pi/6, 4*math.Acos(1.0/3)
+	want := a * (4*math.Pi - a) / (p * p)
+	for i := range vd.NumCells() {
+		if got := vd.Cell(i).Compactness(); math.Abs(got-want) > 1e-12 {
+			t.Errorf("vd.Cell(%d).Compactness() = %v, want %v", i, got, want)
+		}
+	}
+
+	vd, empty := mustNewEmptyCellDiagram(t)
+	if got := vd.Cell(empty).Compactness(); got != 0 {
+		t.Errorf("vd.Cell(%d).Compactness() = %v, want 0", empty, got)
+	}
+}
+
+func TestCell_Compactness_Relax(t *testing.T) {
+	vd := mustNewDiagram(t, 500)
+	prev := 0.0
+	for step := range 5 {
+		sum := 0.0
+		for i := range vd.NumCells() {
+			got := vd.Cell(i).Compactness()
+			if got <= 0 || got > 1 {
+				t.Errorf("step %d: vd.Cell(%d).Compactness() = %v, want in (0, 1]", step, i, got)
+			}
+			sum += got
+		}
+		mean := sum / float64(vd.NumCells())
+		if mean <= prev {
+			t.Errorf("step %d: mean compactness = %v, want more than %v", step, mean, prev)
+		}
+		prev = mean
+		if _, err := vd.Relax(1); err != nil {
+			t.Fatalf("vd.Relax(1) error = %v, want nil", err)
+		}
+	}
+}
+
+func TestCell_InteriorAngles(t *testing.T) {
+	tests := []struct {
+		name  string
+		sites s2.PointVector
+	}{
+		{"octahedron", fixtures.Load("octahedron")},
+		// Cocircular sites yield coincident vertices.
+		{"cocircular rings", fixtures.Load("cocircular-rings")},
+		{"random", utils.GenerateRandomPoints(200, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd, err := NewDiagram(tt.sites)
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			for i := range vd.NumCells() {
+				c := vd.Cell(i)
+				angles := c.InteriorAngles()
+				if len(angles) != c.NumVertices() {
+					t.Fatalf("len(vd.Cell(%d).InteriorAngles()) = %v, want %v", i, len(angles),
+						c.NumVertices())
+				}
+				sum := 0.0
+				for k, a := range angles {
+					if math.IsNaN(a.Radians()) || a <= 0 || a > math.Pi+1e-12 {
+						t.Errorf("vd.Cell(%d).InteriorAngles()[%d] = %v, want in (0, π]", i, k, a)
+					}
+					sum += a.Radians()
+				}
+				excess := sum - float64(len(angles)-2)*math.Pi
+				if area := c.Area(); math.Abs(excess-area) > 1e-9 {
+					t.Errorf("vd.Cell(%d) angle excess = %v, want area %v", i, excess, area)
+				}
+			}
+		})
+	}
+
+	// A repeated vertex lies on a straight boundary.
+	vd := mustNewDiagram(t, 100)
+	ring := vd.Cell(0).VertexIndices()
+	want := vd.Cell(0).InteriorAngles()
+	ring = append([]int{ring[0]}, ring...)
+	got := withCellRing(vd, 0, ring).Cell(0).InteriorAngles()
+	want = append([]s1.Angle{want[0], math.Pi}, want[1:]...)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("InteriorAngles() with a repeated vertex mismatch (-want +got):\n%s", diff)
+	}
+
+	// The cells of the octahedron are squares with angles of 120°.
+	vd, err := NewDiagram(fixtures.Load("octahedron"))
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	for k, a := range vd.Cell(0).InteriorAngles() {
+		if math.Abs(a.Radians()-2*math.Pi/3) > 1e-12 {
+			t.Errorf("vd.Cell(0).InteriorAngles()[%d] = %v, want 120°", k, a.Degrees())
+		}
+	}
+
+	vd, empty := mustNewEmptyCellDiagram(t)
+	if got := vd.Cell(empty).InteriorAngles(); got != nil {
+		t.Errorf("vd.Cell(%d).InteriorAngles() = %v, want nil", empty, got)
+	}
+}
+
+func TestCell_ContainsPoint(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+
+	t.Run("random points", func(t *testing.T) {
+		for _, p := range utils.GenerateRandomPoints(1000, 1) {
+			want := nearestSite(vd, p)
+			for i := range vd.NumCells() {
+				if got := vd.Cell(i).ContainsPoint(p); got != (i == want) {
+					t.Fatalf("vd.Cell(%d).ContainsPoint(%v) = %v, want %v", i, p, got, i == want)
+				}
+			}
+		}
+	})
+
+	t.Run("near edges", func(t *testing.T) {
+		for i := range vd.NumCells() {
+			c := vd.Cell(i)
+			for k, j := range c.NeighborIndices() {
+				a, b := c.Vertex(k), c.Vertex((k+1)%c.NumVertices())
+				mid := s2.Point{Vector: a.Add(b.Vector).Normalize()}
+				toward := func(site s2.Point) s2.Point {
+					return s2.InterpolateAtDistance(1e-9, mid, site)
+				}
+				tests := []struct {
+					name  string
+					p     s2.Point
+					wantI bool
+				}{
+					{"inside", toward(c.Site()), true},
+					{"outside", toward(vd.Sites[j]), false},
+				}
+				for _, tt := range tests {
+					if got := c.ContainsPoint(tt.p); got != tt.wantI {
+						t.Errorf("%s edge %d: vd.Cell(%d).ContainsPoint(%v) = %v, want %v",
+							tt.name, k, i, tt.p, got, tt.wantI)
+					}
+					if got := vd.Cell(j).ContainsPoint(tt.p); got == tt.wantI {
+						t.Errorf("%s edge %d: vd.Cell(%d).ContainsPoint(%v) = %v, want %v",
+							tt.name, k, j, tt.p, got, !tt.wantI)
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("exact tie", func(t *testing.T) {
+		// The point is exactly equidistant from the sites 0 and 2 of the octahedron.
+		vd, err := NewDiagram(fixtures.Load("octahedron"))
+		if err != nil {
+			t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+		}
+		p := s2.PointFromCoords(1, 1, 0)
+		for i, want := range []bool{true, false, false, false, false, false} {
+			if got := vd.Cell(i).ContainsPoint(p); got != want {
+				t.Errorf("vd.Cell(%d).ContainsPoint(%v) = %v, want %v", i, p, got, want)
+			}
+		}
+	})
+
+	t.Run("on vertices", func(t *testing.T) {
+		// Every vertex is shared by at least three cells, and belongs to exactly one of them.
+		owners := make([]int, len(vd.Vertices))
+		for i := range vd.NumCells() {
+			c := vd.Cell(i)
+			for k := range c.NumVertices() {
+				if c.ContainsPoint(c.Vertex(k)) {
+					owners[c.VertexIndices()[k]]++
+				}
+			}
+		}
+		for v, n := range owners {
+			if n != 1 {
+				t.Errorf("vertex %d is contained in %d cells, want 1", v, n)
+			}
+		}
+	})
+
+	t.Run("empty cell", func(t *testing.T) {
+		vd, empty := mustNewEmptyCellDiagram(t)
+		c := vd.Cell(empty)
+		if c.ContainsPoint(c.Site()) {
+			t.Errorf("c.ContainsPoint(c.Site()) = true, want false")
+		}
+	})
+}
+
+func TestCell_DistanceToBoundary(t *testing.T) {
+	vd, err := NewDiagram(fixtures.Load("octahedron"))
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	tests := []struct {
+		name string
+		p    s2.Point
+		want s1.Angle
+	}{
+		// The edges of the cell of (1, 0, 0) are nearest to it at their midpoints.
+		{"site", s2.PointFromCoords(1, 0, 0), math.Pi / 4},
+		{"vertex", s2.PointFromCoords(1, 1, 1), 0},
+		{"edge", s2.PointFromCoords(1, 1, 0), 0},
+		{"outside", s2.PointFromCoords(0, 1, 0), math.Pi / 4},
+		// The vertices are the farthest points of the cell, so they are nearest to the antipode.
+		{"antipode", s2.PointFromCoords(-1, 0, 0), s1.Angle(math.Pi - math.Acos(1/math.Sqrt(3)))},
+	}
+	c := vd.Cell(0)
+	if c.Site() != s2.PointFromCoords(1, 0, 0) {
+		t.Fatalf("vd.Cell(0).Site() = %v, want (1, 0, 0)", c.Site())
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, q := c.DistanceToBoundary(tt.p)
+			if math.Abs(float64(got-tt.want)) > 1e-12 {
+				t.Errorf("vd.Cell(0).DistanceToBoundary(%v) = %v, want %v", tt.p, got, tt.want)
+			}
+			if d := tt.p.Distance(q); math.Abs(float64(d-got)) > 1e-12 {
+				t.Errorf("vd.Cell(0).DistanceToBoundary(%v) point %v is %v away, want %v", tt.p,
+					q, d, got)
+			}
+		})
+	}
+
+	t.Run("random", func(t *testing.T) {
+		vd := mustNewDiagram(t, 100)
+		for k, p := range utils.GenerateRandomPoints(200, 1) {
+			c := vd.Cell(k % vd.NumCells())
+			got, q := c.DistanceToBoundary(p)
+			onBoundary := false
+			for j := range c.NumVertices() {
+				e := c.Edge(j)
+				if s2.DistanceFromSegment(q, e.V0, e.V1) < 1e-14 {
+					onBoundary = true
+				}
+				for m := range 33 {
+					b := s2.Interpolate(float64(m)/32, e.V0, e.V1)
+					if d := p.Distance(b); d < got-1e-14 {
+						t.Errorf("vd.Cell(%d).DistanceToBoundary(%v) = %v, but edge %d is %v away",
+							c.SiteIndex(), p, got, j, d)
+					}
+				}
+			}
+			if !onBoundary {
+				t.Errorf("vd.Cell(%d).DistanceToBoundary(%v) point %v is not on the boundary",
+					c.SiteIndex(), p, q)
+			}
+		}
+	})
+
+	// A point on a shared edge is on the boundary of both cells.
+	t.Run("shared edge", func(t *testing.T) {
+		vd := mustNewDiagram(t, 100)
+		for _, e := range vd.Edges() {
+			p := s2.Interpolate(0.3, vd.Vertices[e.Vertices[0]], vd.Vertices[e.Vertices[1]])
+			for _, i := range e.Sites {
+				if got, _ := vd.Cell(i).DistanceToBoundary(p); got > 1e-14 {
+					t.Errorf("vd.Cell(%d).DistanceToBoundary(%v) = %v, want 0", i, p, got)
+				}
+			}
+		}
+	})
+
+	vd, empty := mustNewEmptyCellDiagram(t)
+	if got, q := vd.Cell(empty).DistanceToBoundary(vd.Sites[0]); !math.IsInf(float64(got), 1) ||
+		q != vd.Sites[empty] {
+		t.Errorf("vd.Cell(%d).DistanceToBoundary(...) = %v, %v, want +Inf, the site", empty, got, q)
+	}
+}
+
+func TestCell_Moments(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	var sum [3][3]float64
+	for i := range vd.NumCells() {
+		c := vd.Cell(i)
+		m := c.Moments()
+		if math.Abs(m.Area-c.Area()) > 1e-12 {
+			t.Errorf("vd.Cell(%d).Moments().Area = %v, want %v", i, m.Area, c.Area())
+		}
+		if m.Anisotropy < 1 {
+			t.Errorf("vd.Cell(%d).Moments().Anisotropy = %v, want >= 1", i, m.Anisotropy)
+		}
+		if math.Abs(m.MajorAxis.Dot(m.Centroid.Vector)) > 1e-12 {
+			t.Errorf("vd.Cell(%d).Moments().MajorAxis not tangent to centroid", i)
+		}
+		for j := range c.NumVertices() {
+			tm := triangleSecondMoment(c.Site(), c.Vertex((j+1)%c.NumVertices()), c.Vertex(j))
+			for r := range 3 {
+				for k := range 3 {
+					sum[r][k] += tm[r][k]
+				}
+			}
+		}
+	}
+
+	// The second moment of the whole sphere is 4π/3 * I.
+	for r := range 3 {
+		for k := range 3 {
+			want := 0.0
+			if r == k {
+				want = 4 * math.Pi / 3
+			}
+			if math.Abs(sum[r][k]-want) > 1e-9 {
+				t.Errorf("sphere second moment [%d][%d] = %v, want %v", r, k, sum[r][k], want)
+			}
+		}
+	}
+}
+
+func TestCell_Moments_Anisotropy(t *testing.T) {
+	// The cell around the north pole is a regular hexagon.
+	sites := s2.PointVector{s2.PointFromCoords(0, 0, 1), s2.PointFromCoords(0, 0, -1)}
+	for k := range 6 {
+		sites = append(sites,
+			s2.PointFromLatLng(s2.LatLngFromDegrees(50, float64(k)*60)),
+			s2.PointFromLatLng(s2.LatLngFromDegrees(-10, float64(k)*60+30)))
+	}
+	regular, err := NewDiagram(sites)
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	if a := regular.Cell(0).Moments().Anisotropy; math.Abs(a-1) > 1e-6 {
+		t.Errorf("regular cell anisotropy = %v, want 1", a)
+	}
+
+	vd := mustNewDiagram(t, 200)
+	before := meanAnisotropy(vd)
+	if _, err := vd.Relax(30); err != nil {
+		t.Fatalf("vd.Relax(30) error = %v, want nil", err)
+	}
+	if after := meanAnisotropy(vd); after >= before {
+		t.Errorf("mean anisotropy after relaxation = %v, want < %v", after, before)
+	}
+
+	stretched := mustNewStretchedDiagram(t)
+	for i := range stretched.NumCells() {
+		c := stretched.Cell(i)
+		lat := s2.LatLngFromPoint(c.Site()).Lat.Degrees()
+		if math.Abs(lat) > 45 {
+			continue
+		}
+		if a := c.Moments().Anisotropy; a < 5 {
+			t.Errorf("stretched cell %d anisotropy = %v, want > 5", i, a)
+		}
+	}
+}
+
+func meanAnisotropy(vd *Diagram) float64 {
+	sum := 0.0
+	for i := range vd.NumCells() {
+		sum += vd.Cell(i).Moments().Anisotropy
+	}
+	return sum / float64(vd.NumCells())
+}
+
+func mustNewStretchedDiagram(t *testing.T) *Diagram {
+	t.Helper()
+	// Rows of closely spaced sites far apart in latitude produce tall, narrow cells.
+	random := rand.New(rand.NewSource(0))
+	sites := s2.PointVector{s2.PointFromCoords(0, 0, 1), s2.PointFromCoords(0, 0, -1)}
+	for _, lat := range []float64{-60, -20, 20, 60} {
+		for k := range 40 {
+			lng := float64(k)*9 + random.Float64()*1e-3
+			sites = append(sites, s2.PointFromLatLng(s2.LatLngFromDegrees(lat, lng)))
+		}
+	}
+	vd, err := NewDiagram(sites)
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	return vd
+}
+
+// mustNewEmptyCellDiagram returns a diagram of 100 random sites with an additional site whose
+// cell is empty, as left behind by pruning. It returns the index of the empty cell.
+func mustNewEmptyCellDiagram(t *testing.T) (*Diagram, int) {
+	t.Helper()
+	vd := mustNewDiagram(t, 100)
+	extra := s2.Point{Vector: vd.Sites[0].Add(vd.Sites[1].Vector).Normalize()}
+	vd.Sites = append(vd.Sites, extra)
+	vd.CellOffsets = append(vd.CellOffsets, len(vd.CellVertices))
+	return vd, vd.NumCells() - 1
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"math"
+	"sort"
+	"unsafe"
+
+	"github.com/golang/geo/s2"
+)
+
+// CellIDIndex maps the s2.CellIDs of a fixed level to the cells of a diagram containing them,
+// see BuildCellIDIndex. It is safe for concurrent use, but must be rebuilt after the diagram
+// is modified.
+type CellIDIndex struct {
+	d     *Diagram
+	level int
+	// starts holds the first leaf s2.CellID of every entry in increasing order. The entries
+	// partition the sphere; each is a range of s2.CellIDs of at most the level that lie in the
+	// cell sites[k], or an s2.CellID of the level straddling cells, marked by a site of -1.
+	starts []s2.CellID
+	sites  []int
+	// buckets holds for every s2.CellID of bucketLevel, indexed by hintPos, the entry containing
+	// its first leaf, followed by the last entry, to narrow the search of Lookup.
+	bucketLevel int
+	buckets     []int
+}
+
+// BuildCellIDIndex returns a table of the cells containing the s2.CellIDs of the given level,
+// clamped to [0, s2.MaxLevel], for fast repeated point queries. An s2.CellID is resolved in
+// the table if it lies entirely in a single cell, with a margin of eps so that no point of it
+// is tied between sites; otherwise it is marked ambiguous. The table is built by subdividing
+// the faces only where they straddle cell boundaries, and stores runs of the coarsest s2.CellIDs
+// that are resolved, so its size and build time grow with the number of s2.CellIDs of the level
+// that intersect the edges of the diagram, not with all 6·4^level of them.
+func (d *Diagram) BuildCellIDIndex(level int) *CellIDIndex {
+	x := &CellIDIndex{d: d, level: min(max(level, 0), s2.MaxLevel)}
+	hintLevel, hints := d.locateHints()
+	for face := range 6 {
+		id := s2.CellIDFromFace(face)
+		x.build(id, hints[hintPos(id, hintLevel)])
+	}
+
+	// The buckets are about as many as the entries.
+	for x.bucketLevel < x.level && 6<<(2*(x.bucketLevel+1)) <= len(x.starts) {
+		x.bucketLevel++
+	}
+	x.buckets = make([]int, 0, 6<<(2*x.bucketLevel)+1)
+	k := 0
+	end := s2.CellIDFromFace(5).ChildEndAtLevel(x.bucketLevel)
+	for id := s2.CellIDFromFace(0).ChildBeginAtLevel(x.bucketLevel); id != end; id = id.Next() {
+		for k+1 < len(x.starts) && x.starts[k+1] <= id.RangeMin() {
+			k++
+		}
+		x.buckets = append(x.buckets, k)
+	}
+	x.buckets = append(x.buckets, len(x.starts)-1)
+	return x
+}
+
+// build appends the entries of the s2.CellID id to the table, where hint is a non-empty cell
+// near it.
+func (x *CellIDIndex) build(id s2.CellID, hint int) {
+	bound := s2.CellFromCellID(id).CapBound()
+	i := x.d.locate(bound.Center(), hint)
+	switch {
+	case x.d.containsCap(i, bound):
+	case id.Level() == x.level:
+		i = -1
+	default:
+		for child := id.ChildBegin(); child != id.ChildEnd(); child = child.Next() {
+			x.build(child, i)
+		}
+		return
+	}
+	// Consecutive entries in the same cell are merged.
+	if n := len(x.sites); i >= 0 && n > 0 && x.sites[n-1] == i {
+		return
+	}
+	x.starts = append(x.starts, id.RangeMin())
+	x.sites = append(x.sites, i)
+}
+
+// containsCap reports whether every point of the cap is in the cell i, and not tied between
+// its site and another one, see FindCellIndex.
+func (d *Diagram) containsCap(i int, c s2.Cap) bool {
+	// The cell is the intersection of the half-spaces x·(g_i - g_j) >= 0 over its neighbors j,
+	// where g are the generators. The least dot product of a point of the cap with a vector v
+	// at angle θ from its center is |v| cos(θ + r). A margin of 2eps keeps the chord distances,
+	// or the powers, apart by more than eps.
+	center, r := c.Center().Vector, c.Radius().Radians()
+	g := d.generator(i)
+	for _, j := range d.Cell(i).NeighborIndices() {
+		v := g.Sub(d.generator(j))
+		theta := math.Atan2(center.Cross(v).Norm(), center.Dot(v))
+		if v.Norm()*math.Cos(min(theta+r, math.Pi)) <= 2*d.eps {
+			return false
+		}
+	}
+	return true
+}
+
+// Lookup returns the index of the cell whose site is nearest to p, exactly like FindCellIndex.
+// It answers from the table if the s2.CellID of the level containing p is resolved, and calls
+// FindCellIndex otherwise.
+func (x *CellIDIndex) Lookup(p s2.Point) int {
+	id := s2.CellFromPoint(p).ID()
+	pos := hintPos(id, x.bucketLevel)
+	lo, hi := x.buckets[pos], x.buckets[pos+1]
+	k := lo + sort.Search(hi-lo, func(j int) bool { return x.starts[lo+1+j] > id })
+	if i := x.sites[k]; i >= 0 {
+		return i
+	}
+	return x.d.FindCellIndex(p)
+}
+
+// Level returns the level of the s2.CellIDs of the table.
+func (x *CellIDIndex) Level() int {
+	return x.level
+}
+
+// NumEntries returns the number of entries of the table: the ambiguous s2.CellIDs and the runs
+// of resolved ones in the same cell.
+func (x *CellIDIndex) NumEntries() int {
+	return len(x.starts)
+}
+
+// MemoryFootprint returns the number of bytes used by the table, computed from the capacities
+// of its slices like Diagram.MemoryFootprint. The diagram it refers to is not counted.
+func (x *CellIDIndex) MemoryFootprint() int {
+	return cap(x.starts)*int(unsafe.Sizeof(s2.CellID(0))) +
+		(cap(x.sites)+cap(x.buckets))*int(unsafe.Sizeof(0))
+}
+
+// NumAmbiguous returns the number of s2.CellIDs of the level in the table that straddle cell
+// boundaries, for which Lookup falls back to FindCellIndex.
+func (x *CellIDIndex) NumAmbiguous() int {
+	n := 0
+	for _, i := range x.sites {
+		if i < 0 {
+			n++
+		}
+	}
+	return n
+}
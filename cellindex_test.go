@@ -0,0 +1,131 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/internal/fixtures"
+	"github.com/2dChan/s2voronoi/utils"
+)
+
+func TestCellIDIndex_Lookup(t *testing.T) {
+	random := utils.GenerateRandomPoints(1000, 0)
+	weights := make([]float64, len(random))
+	r := rand.New(rand.NewSource(0))
+	for i := range weights {
+		weights[i] = r.Float64() * 1e-3
+	}
+	power, err := NewPowerDiagram(random, weights)
+	if err != nil {
+		t.Fatalf("NewPowerDiagram(...) error = %v, want nil", err)
+	}
+	tests := []struct {
+		name  string
+		vd    *Diagram
+		level int
+	}{
+		{"random", mustNewDiagram(t, 1000), 10},
+		{"random coarse", mustNewDiagram(t, 1000), 3},
+		// The cells of the axis sites meet at the face corners, so no face is resolved.
+		{"octahedron", mustNewFixtureDiagram(t, "octahedron"), 0},
+		{"cocircular", mustNewFixtureDiagram(t, "cocircular-rings"), 8},
+		{"power", power, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := tt.vd.BuildCellIDIndex(tt.level)
+			// The sites and vertices are the points whose cells are tied.
+			queries := append(utils.GenerateRandomPoints(10000, 1), tt.vd.Sites...)
+			queries = append(queries, tt.vd.Vertices...)
+			for _, p := range queries {
+				if got, want := x.Lookup(p), tt.vd.FindCellIndex(p); got != want {
+					t.Errorf("Lookup(%v) = %d, want FindCellIndex = %d", p, got, want)
+				}
+			}
+			if x.NumAmbiguous() > x.NumEntries() || x.NumEntries() == 0 {
+				t.Errorf("NumAmbiguous() = %d, NumEntries() = %d, want at most NumEntries() > 0",
+					x.NumAmbiguous(), x.NumEntries())
+			}
+		})
+	}
+}
+
+func TestBuildCellIDIndex_Size(t *testing.T) {
+	vd := mustNewDiagram(t, 1000)
+	var x *CellIDIndex
+	prev := 0
+	for level := 6; level <= 10; level++ {
+		x = vd.BuildCellIDIndex(level)
+		// The ambiguous s2.CellIDs cover the cell boundaries, so their number doubles with
+		// every level. The resolved entries are at most three per ambiguous parent, and the
+		// parents number about as many as the ambiguous s2.CellIDs on all levels.
+		if x.NumEntries() > 7*x.NumAmbiguous()+6 {
+			t.Errorf("level %d: NumEntries() = %d, want at most 7 per ambiguous (%d)", level,
+				x.NumEntries(), x.NumAmbiguous())
+		}
+		if prev > 0 && (x.NumAmbiguous() < prev*3/2 || x.NumAmbiguous() > prev*3) {
+			t.Errorf("level %d: NumAmbiguous() = %d, want about twice %d", level,
+				x.NumAmbiguous(), prev)
+		}
+		prev = x.NumAmbiguous()
+	}
+	if all := 6 << (2 * x.Level()); x.NumEntries() > all/8 {
+		t.Errorf("level %d: NumEntries() = %d, want a small part of all %d", x.Level(),
+			x.NumEntries(), all)
+	}
+}
+
+func TestBuildCellIDIndex_Level(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	tests := []struct {
+		level, want int
+	}{
+		{-1, 0},
+		{5, 5},
+	}
+	for _, tt := range tests {
+		if got := vd.BuildCellIDIndex(tt.level).Level(); got != tt.want {
+			t.Errorf("BuildCellIDIndex(%d).Level() = %d, want %d", tt.level, got, tt.want)
+		}
+	}
+}
+
+// Benchmarks
+
+func BenchmarkCellIDIndex_Lookup(b *testing.B) {
+	vd, err := NewDiagram(utils.GenerateRandomPoints(100000, 0))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	x := vd.BuildCellIDIndex(14)
+	queries := utils.GenerateRandomPoints(1024, 1)
+	for i := 0; b.Loop(); i++ {
+		x.Lookup(queries[i%len(queries)])
+	}
+}
+
+func BenchmarkDiagram_BuildCellIDIndex(b *testing.B) {
+	vd, err := NewDiagram(utils.GenerateRandomPoints(100000, 0))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	for b.Loop() {
+		vd.BuildCellIDIndex(14)
+	}
+}
+
+// Helpers
+
+// mustNewFixtureDiagram returns the diagram of the named fixture.
+func mustNewFixtureDiagram(t *testing.T, name string) *Diagram {
+	t.Helper()
+	vd, err := NewDiagram(fixtures.Load(name))
+	if err != nil {
+		t.Fatalf("NewDiagram(fixtures.Load(%q)) error = %v, want nil", name, err)
+	}
+	return vd
+}
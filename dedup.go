@@ -0,0 +1,102 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"math"
+	"slices"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// siteSources maps the cells of a diagram built with WithDeduplication to the input sites
+// merged into them, in a CSR-like format: the input sites of cell i are
+// indices[offsets[i]:offsets[i+1]]. It is empty for other diagrams.
+type siteSources struct {
+	indices []int
+	offsets []int
+}
+
+// clone returns a copy of s that shares no slices with it.
+func (s siteSources) clone() siteSources {
+	return siteSources{indices: slices.Clone(s.indices), offsets: slices.Clone(s.offsets)}
+}
+
+// WithDeduplication merges sites within an angle of tol of each other before the diagram is
+// built, so that messy data with near-coincident sites does not fail with ErrDuplicateSites.
+// The sites are visited in input order, and every site is merged into the nearest kept site
+// within tol, ties broken by the smaller index, or kept otherwise. The diagram then has a cell
+// for every kept site, in input order and at the position of that site, and SourceIndex maps
+// the cells back to the input sites. It must be positive and finite, and it is not supported by
+// NewPowerDiagram, NewDiagramFromParts and NewDiagramFromTriangulation.
+func WithDeduplication(tol s1.Angle) DiagramOption {
+	return func(o *DiagramOptions) error {
+		if !(tol > 0) || math.IsInf(float64(tol), 0) {
+			return errorf(ErrInvalidOption, "s2voronoi: deduplication tolerance must be positive "+
+				"and finite, got %v", tol)
+		}
+		o.Deduplication = tol
+		return nil
+	}
+}
+
+// SourceIndex returns the indices of the input sites merged into the cell i in increasing
+// order, the first of which is the site of the cell. For diagrams not built with
+// WithDeduplication it returns [i]. The mapping is kept by Relax and MoveSite, and discarded by
+// the methods adding or removing sites. It panics if i is out of range.
+func (d *Diagram) SourceIndex(i int) []int {
+	d.checkCellIndex(i)
+	if d.sources.offsets == nil {
+		return []int{i}
+	}
+	return slices.Clone(d.sources.indices[d.sources.offsets[i]:d.sources.offsets[i+1]])
+}
+
+// deduplicate merges the sites within tol of each other, see WithDeduplication, and returns the
+// kept sites in a new slice and the input sites of their cells. The sites are not modified, so
+// that the caller can look up its input rows with SourceIndex. The kept sites are bucketed by
+// the s2.CellID of the finest level whose cells are at least tol wide, so that every site is
+// compared with the kept sites in 9 cells only.
+func deduplicate(sites s2.PointVector, tol s1.Angle) (s2.PointVector, siteSources) {
+	maxChord := s1.ChordAngleFromAngle(tol)
+	level := s2.MinWidthMetric.MaxLevel(tol.Radians())
+	buckets := make(map[s2.CellID][]int)
+	cells := make([]int, len(sites))
+	var kept s2.PointVector
+	for j, s := range sites {
+		id := s2.CellFromPoint(s).ID().Parent(level)
+		best, bestDist := -1, maxChord
+		for _, c := range append(id.AllNeighbors(level), id) {
+			for _, k := range buckets[c] {
+				dist := s2.ChordAngleBetweenPoints(s, kept[k])
+				if dist < bestDist || dist == bestDist && (best < 0 || k < best) {
+					best, bestDist = k, dist
+				}
+			}
+		}
+		if best < 0 {
+			best = len(kept)
+			buckets[id] = append(buckets[id], best)
+			kept = append(kept, s)
+		}
+		cells[j] = best
+	}
+
+	// The input sites are grouped by cell with a counting sort, which keeps them in order.
+	src := siteSources{indices: make([]int, len(sites)), offsets: make([]int, len(kept)+1)}
+	for _, c := range cells {
+		src.offsets[c+1]++
+	}
+	for k := range kept {
+		src.offsets[k+1] += src.offsets[k]
+	}
+	next := slices.Clone(src.offsets[:len(kept)])
+	for j, c := range cells {
+		src.indices[next[c]] = j
+		next[c]++
+	}
+	return kept, src
+}
@@ -0,0 +1,335 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+func TestWithDeduplication(t *testing.T) {
+	tests := []struct {
+		name    string
+		tol     s1.Angle
+		wantErr bool
+	}{
+		{"positive", 1e-6, false},
+		{"zero", 0, true},
+		{"negative", -1e-6, true},
+		{"infinite", s1.InfAngle(), true},
+		{"nan", s1.Angle(math.NaN()), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts DiagramOptions
+			err := WithDeduplication(tt.tol)(&opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WithDeduplication(%v) error = %v, wantErr %v", tt.tol, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidOption) {
+				t.Errorf("WithDeduplication(%v) error = %v, want errors.Is(err, ErrInvalidOption)",
+					tt.tol, err)
+			}
+			if !tt.wantErr && opts.Deduplication != tt.tol {
+				t.Errorf("WithDeduplication(%v) Deduplication = %v, want %v", tt.tol,
+					opts.Deduplication, tt.tol)
+			}
+		})
+	}
+}
+
+func TestNewDiagram_WithDeduplication(t *testing.T) {
+	const tol = s1.Angle(1e-6)
+	sites, want := clusteredSites(200)
+	tests := []struct {
+		name string
+		opts []DiagramOption
+	}{
+		{"default", nil},
+		{"order independent", []DiagramOption{WithOrderIndependentOutput()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]DiagramOption{WithDeduplication(tol)}, tt.opts...)
+			vd, err := NewDiagram(slices.Clone(sites), opts...)
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			if vd.NumCells() != len(want) {
+				t.Fatalf("vd.NumCells() = %d, want %d", vd.NumCells(), len(want))
+			}
+			for i, src := range want {
+				if got := vd.SourceIndex(i); !slices.Equal(got, src) {
+					t.Errorf("vd.SourceIndex(%d) = %v, want %v", i, got, src)
+				}
+				if vd.Sites[i] != sites[i] {
+					t.Errorf("vd.Sites[%d] = %v, want %v", i, vd.Sites[i], sites[i])
+				}
+			}
+			if err := vd.Validate(); err != nil {
+				t.Errorf("vd.Validate() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestNewDiagram_WithDeduplication_InputRows(t *testing.T) {
+	const tol = s1.Angle(1e-6)
+	sites, _ := clusteredSites(200)
+	orig := slices.Clone(sites)
+	vd, err := NewDiagram(sites, WithDeduplication(tol))
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	// The input rows stay in place, so that SourceIndex maps every cell to its own rows.
+	for i := range vd.NumCells() {
+		for _, k := range vd.SourceIndex(i) {
+			if sites[k] != orig[k] {
+				t.Errorf("sites[%d] = %v, want the input row %v", k, sites[k], orig[k])
+			}
+			if d := sites[k].Distance(vd.Sites[i]); d > tol {
+				t.Errorf("sites[vd.SourceIndex(%d)] holds %v at %v from the site, want within %v",
+					i, sites[k], d, tol)
+			}
+		}
+	}
+}
+
+func TestNewDiagram_WithDeduplication_Errors(t *testing.T) {
+	sites, _ := clusteredSites(200)
+	tetra := tetrahedron()
+	few := append(slices.Clone(tetra), jitter(tetra[0], 1e-9), jitter(tetra[1], 1e-9))
+	tests := []struct {
+		name string
+		run  func() error
+		want error
+	}{
+		{
+			name: "too few after merging",
+			run: func() error {
+				_, err := NewDiagram(append(few[:3:3], few[4:]...), WithDeduplication(1e-6))
+				return err
+			},
+			want: ErrInsufficientSites,
+		},
+		{
+			name: "tolerance below jitter",
+			run: func() error {
+				_, err := NewDiagram(slices.Clone(sites), WithDeduplication(1e-12),
+					WithNearDuplicateCheck(), WithEps(1e-8))
+				return err
+			},
+			want: ErrDuplicateSites,
+		},
+		{
+			name: "power diagram",
+			run: func() error {
+				weights := make([]float64, len(sites))
+				weights[0] = 0.01
+				_, err := NewPowerDiagram(slices.Clone(sites), weights, WithDeduplication(1e-6))
+				return err
+			},
+			want: ErrInvalidOption,
+		},
+		{
+			name: "parts",
+			run: func() error {
+				vd := mustNewDiagram(t, 100)
+				_, err := NewDiagramFromParts(vd.Sites, vd.Vertices, vd.CellVertices,
+					vd.CellNeighbors, vd.CellOffsets, WithDeduplication(1e-6))
+				return err
+			},
+			want: ErrInvalidOption,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want errors.Is(err, %v)", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiagram_SourceIndex(t *testing.T) {
+	sites, want := clusteredSites(200)
+	build := func(t *testing.T) *Diagram {
+		vd, err := NewDiagram(slices.Clone(sites), WithDeduplication(1e-6))
+		if err != nil {
+			t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+		}
+		return vd
+	}
+	fresh := utils.GenerateRandomPoints(1, 1)[0]
+	tests := []struct {
+		name string
+		// edit changes the diagram and reports whether the mapping is kept.
+		edit func(t *testing.T, vd *Diagram) bool
+	}{
+		{"unchanged", func(t *testing.T, vd *Diagram) bool { return true }},
+		{
+			name: "move site",
+			edit: func(t *testing.T, vd *Diagram) bool {
+				nb := vd.Sites[vd.Cell(5).NeighborIndices()[0]]
+				if _, err := vd.MoveSite(5, s2.Interpolate(0.7, vd.Sites[5], nb)); err != nil {
+					t.Fatalf("vd.MoveSite(...) error = %v, want nil", err)
+				}
+				return true
+			},
+		},
+		{
+			name: "relax",
+			edit: func(t *testing.T, vd *Diagram) bool {
+				if _, err := vd.Relax(2); err != nil {
+					t.Fatalf("vd.Relax(...) error = %v, want nil", err)
+				}
+				return true
+			},
+		},
+		{
+			name: "add site",
+			edit: func(t *testing.T, vd *Diagram) bool {
+				if _, err := vd.AddSite(fresh); err != nil {
+					t.Fatalf("vd.AddSite(...) error = %v, want nil", err)
+				}
+				return false
+			},
+		},
+		{
+			name: "remove site",
+			edit: func(t *testing.T, vd *Diagram) bool {
+				if err := vd.RemoveSite(3); err != nil {
+					t.Fatalf("vd.RemoveSite(...) error = %v, want nil", err)
+				}
+				return false
+			},
+		},
+		{
+			name: "rebuild",
+			edit: func(t *testing.T, vd *Diagram) bool {
+				if err := vd.Rebuild(slices.Clone(vd.Sites)); err != nil {
+					t.Fatalf("vd.Rebuild(...) error = %v, want nil", err)
+				}
+				return false
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd := build(t)
+			kept := tt.edit(t, vd)
+			for i := range vd.NumCells() {
+				src := []int{i}
+				if kept {
+					src = want[i]
+				}
+				if got := vd.SourceIndex(i); !slices.Equal(got, src) {
+					t.Fatalf("vd.SourceIndex(%d) = %v, want %v", i, got, src)
+				}
+			}
+		})
+	}
+}
+
+func TestDiagram_SourceIndex_Panic(t *testing.T) {
+	vd := mustNewDiagram(t, 10)
+	for _, i := range []int{-1, vd.NumCells()} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("vd.SourceIndex(%d) did not panic, want panic", i)
+				}
+			}()
+			vd.SourceIndex(i)
+		}()
+	}
+}
+
+func TestDeduplicate(t *testing.T) {
+	p := utils.GenerateRandomPoints(3, 0)
+	tests := []struct {
+		name      string
+		sites     s2.PointVector
+		wantSites s2.PointVector
+		want      [][]int
+	}{
+		{"no duplicates", p, p, [][]int{{0}, {1}, {2}}},
+		{
+			name:      "exact",
+			sites:     s2.PointVector{p[0], p[1], p[0], p[2], p[1]},
+			wantSites: p,
+			want:      [][]int{{0, 2}, {1, 4}, {3}},
+		},
+		{
+			name:      "nearest kept site",
+			sites:     s2.PointVector{p[0], jitter(p[0], 1.5e-6), jitter(p[0], 9e-7), p[1]},
+			wantSites: s2.PointVector{p[0], jitter(p[0], 1.5e-6), p[1]},
+			want:      [][]int{{0}, {1, 2}, {3}},
+		},
+		{
+			name:      "chained sites are not merged",
+			sites:     s2.PointVector{p[0], jitter(p[0], 6e-7), jitter(p[0], 1.2e-6)},
+			wantSites: s2.PointVector{p[0], jitter(p[0], 1.2e-6)},
+			want:      [][]int{{0, 1}, {2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := slices.Clone(tt.sites)
+			sites, src := deduplicate(input, 1e-6)
+			if !slices.Equal(input, tt.sites) {
+				t.Errorf("deduplicate(...) modified the input to %v, want %v", input, tt.sites)
+			}
+			if !slices.Equal(sites, tt.wantSites) {
+				t.Errorf("deduplicate(...) sites = %v, want %v", sites, tt.wantSites)
+			}
+			var got [][]int
+			for i := range len(src.offsets) - 1 {
+				got = append(got, src.indices[src.offsets[i]:src.offsets[i+1]])
+			}
+			if !slices.EqualFunc(got, tt.want, slices.Equal) {
+				t.Errorf("deduplicate(...) sources = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDeduplicate(b *testing.B) {
+	sites := utils.GenerateRandomPoints(100000, 0)
+	for b.Loop() {
+		deduplicate(sites, 1e-6)
+	}
+}
+
+// Helpers
+
+// clusteredSites returns n random sites followed by copies of some of them moved by less than
+// 1e-8, and the input sites of every cell of their diagram deduplicated with a tolerance of
+// 1e-6.
+func clusteredSites(n int) (s2.PointVector, [][]int) {
+	sites := utils.GenerateRandomPoints(n, 0)
+	want := make([][]int, n)
+	for i := range want {
+		want[i] = []int{i}
+	}
+	for k, i := range []int{5, 5, n / 4, 5, n - 1, n / 4} {
+		sites = append(sites, jitter(sites[i], s1.Angle(k+1)*1e-9))
+		want[i] = append(want[i], n+k)
+	}
+	return sites, want
+}
+
+// jitter returns p moved by the angle a in a fixed direction.
+func jitter(p s2.Point, a s1.Angle) s2.Point {
+	return s2.InterpolateAtDistance(a, p, s2.Point{Vector: p.Ortho()})
+}
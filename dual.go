@@ -0,0 +1,132 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/2dChan/s2voronoi/s2delaunay"
+)
+
+// The Voronoi diagram is the dual of the Delaunay triangulation of its sites: cell i belongs
+// to vertex i of the triangulation, Voronoi vertex v is the circumcenter of triangle v, and the
+// Voronoi edge between two cells is dual to the Delaunay edge between their sites. NewDiagram
+// builds the diagram so that these indices coincide, and Triangulation recovers the
+// triangulation from the diagram.
+
+// WithRetainedTriangulation makes the diagram keep the Delaunay triangulation it is built from,
+// so that Triangulation returns it instead of recomputing it. The triangulation shares its
+// sites and incidence arrays with the diagram, so keeping it costs only its triangles. It is
+// discarded when the diagram is modified, and it is ignored together with
+// WithOrderIndependentOutput, whose cells are not numbered like the triangulation, and
+// WithVertexMerging, whose vertices are not.
+func WithRetainedTriangulation() DiagramOption {
+	return func(o *DiagramOptions) error {
+		o.RetainTriangulation = true
+		return nil
+	}
+}
+
+// NewDiagramFromTriangulation creates the Voronoi diagram dual to the Delaunay triangulation
+// dt without triangulating its vertices again. Cell i belongs to vertex i of dt and Voronoi
+// vertex v is the circumcenter of triangle v, so the result is identical to that of NewDiagram
+// on dt.Vertices. The diagram shares dt.Vertices, dt.IncidentTriangleIndices and
+// dt.IncidentTriangleOffsets, which must not be modified afterwards. The Delaunay property is not
+// checked, see Validate.
+// The eps of dt is used unless WithEps is given. It returns an error if dt has fewer than 4
+// vertices, if its incidence arrays are not built, if a circumcenter is degenerate, or if
+// WithOrderIndependentOutput, WithVertexMerging or WithSkipNonPoints is given.
+func NewDiagramFromTriangulation(dt *s2delaunay.Triangulation,
+	setters ...DiagramOption) (*Diagram, error) {
+	if dt.NumVertices() < 4 {
+		return nil, errInsufficientSites
+	}
+	if len(dt.IncidentTriangleOffsets) != dt.NumVertices()+1 ||
+		len(dt.IncidentTriangleIndices) != 3*dt.NumTriangles() {
+		return nil, errors.New("s2voronoi: triangulation has no incidence arrays, " +
+			"see s2delaunay.Triangulation.RebuildIncidence")
+	}
+
+	opts := &DiagramOptions{
+		Eps: dt.Eps(),
+	}
+	if opts.Eps <= 0 {
+		opts.Eps = DefaultEps
+	}
+	for _, set := range setters {
+		err := set(opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opts.OrderIndependent {
+		return nil, errorf(ErrInvalidOption,
+			"s2voronoi: order-independent output cannot be built from a triangulation")
+	}
+	if opts.Deduplication != 0 {
+		return nil, errorf(ErrInvalidOption,
+			"s2voronoi: deduplication cannot be applied to a triangulation")
+	}
+	if opts.VertexMerging != 0 {
+		return nil, errorf(ErrInvalidOption,
+			"s2voronoi: vertex merging cannot be applied to a triangulation")
+	}
+	if opts.SkipNonPoints {
+		return nil, errorf(ErrInvalidOption,
+			"s2voronoi: skipping non-points only applies to NewDiagramFromGeoJSON")
+	}
+
+	d := &Diagram{eps: opts.Eps}
+	if err := d.setTriangulation(dt); err != nil {
+		return nil, err
+	}
+	if opts.RetainTriangulation {
+		d.cache.triangulation = dt
+	}
+	return d, nil
+}
+
+// Triangulation returns the Delaunay triangulation dual to the diagram, whose vertices are the
+// sites and whose triangle v consists of the cells VertexCells(v), which fails for diagrams
+// built with WithVertexMerging. If the diagram was built with
+// WithRetainedTriangulation and not modified since, the retained triangulation is returned,
+// which must not be modified. Otherwise it is recomputed on every call and does not share memory
+// with the diagram except for the sites.
+// It returns an error if a vertex is not shared by exactly 3 cells, or if the triangles do not
+// form a valid triangulation, see s2delaunay.NewTriangulationFromTriangles.
+func (d *Diagram) Triangulation() (*s2delaunay.Triangulation, error) {
+	if c := d.cache; c != nil && c.triangulation != nil {
+		return c.triangulation, nil
+	}
+	triangles := make([][3]int, len(d.Vertices))
+	for v := range d.Vertices {
+		cells := d.VertexCells(v)
+		if len(cells) != 3 {
+			return nil, fmt.Errorf("s2voronoi: vertex %d is shared by %d cells, want 3",
+				v, len(cells))
+		}
+		triangles[v] = [3]int{cells[0], cells[1], cells[2]}
+	}
+	return s2delaunay.NewTriangulationFromTriangles(d.Sites, triangles, s2delaunay.WithEps(d.eps))
+}
+
+// VertexTriangle returns the index of the Delaunay triangle of Triangulation whose circumcenter
+// is the vertex, which is the vertex index itself. Vertices merged by WithVertexMerging have no
+// single triangle, and the result is meaningless for them.
+// It panics if the vertex index is out of range.
+func (d *Diagram) VertexTriangle(vIdx int) int {
+	d.checkVertexIndex(vIdx)
+	return vIdx
+}
+
+// DualEdge returns the endpoints of the Voronoi edge dual to the Delaunay edge between the
+// sites a and b, in the order of the ring of cell a, and reports whether the edge exists, see
+// SharedEdge.
+// It panics if either index is out of range.
+func (d *Diagram) DualEdge(a, b int) (v0, v1 int, ok bool) {
+	e, ok := d.SharedEdge(a, b)
+	return e.Vertices[0], e.Vertices[1], ok
+}
@@ -0,0 +1,261 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/internal/fixtures"
+	"github.com/2dChan/s2voronoi/s2delaunay"
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiagram_Triangulation(t *testing.T) {
+	for _, name := range []string{"octahedron", "cocircular-rings", "random"} {
+		t.Run(name, func(t *testing.T) {
+			var vd *Diagram
+			if name == "random" {
+				vd = mustNewDiagram(t, 200)
+			} else {
+				var err error
+				if vd, err = NewDiagram(fixtures.Load(name)); err != nil {
+					t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+				}
+			}
+			dt, err := vd.Triangulation()
+			if err != nil {
+				t.Fatalf("vd.Triangulation() error = %v, want nil", err)
+			}
+			if err := dt.Validate(); err != nil {
+				t.Errorf("vd.Triangulation().Validate() error = %v, want nil", err)
+			}
+
+			// The triangulation is the one NewDiagram was built from, up to the first vertex
+			// of every triangle.
+			want, err := s2delaunay.NewTriangulation(vd.Sites)
+			if err != nil {
+				t.Fatalf("s2delaunay.NewTriangulation(...) error = %v, want nil", err)
+			}
+			for i, tri := range dt.Triangles {
+				if !isRotation(tri[:], want.Triangles[i][:]) {
+					t.Errorf("vd.Triangulation().Triangles[%d] = %v, want a rotation of %v", i,
+						tri, want.Triangles[i])
+				}
+			}
+
+			for v := range vd.Vertices {
+				tIdx := vd.VertexTriangle(v)
+				cc := triangleCircumcenter(dt.TriangleVertices(tIdx)).Normalize()
+				if dist := vd.Vertices[v].Sub(cc).Norm(); dist > vd.Eps() {
+					t.Errorf("vd.Vertices[%d] is %v away from the circumcenter of triangle %d",
+						v, dist, tIdx)
+				}
+			}
+
+			for i := range vd.NumCells() {
+				for j := range vd.NumCells() {
+					v0, v1, ok := vd.DualEdge(i, j)
+					if ok != dt.HasEdge(i, j) {
+						t.Fatalf("vd.DualEdge(%d, %d) ok = %v, want %v", i, j, ok, !ok)
+					}
+					if !ok {
+						continue
+					}
+					// The endpoints are the circumcenters of the two triangles sharing the
+					// Delaunay edge.
+					for _, v := range []int{v0, v1} {
+						if !triangleHasVertices(dt.Triangles[vd.VertexTriangle(v)], i, j) {
+							t.Errorf("vd.DualEdge(%d, %d) = %d, %d, triangle of vertex %d is %v",
+								i, j, v0, v1, v, dt.Triangles[v])
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDiagram_Triangulation_Error(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	// Dropping a vertex from a ring leaves it shared by 2 cells.
+	vd.CellVertices = append(vd.CellVertices[:vd.CellOffsets[0]:vd.CellOffsets[0]],
+		vd.CellVertices[vd.CellOffsets[0]+1:]...)
+	vd.CellNeighbors = append(vd.CellNeighbors[:vd.CellOffsets[0]:vd.CellOffsets[0]],
+		vd.CellNeighbors[vd.CellOffsets[0]+1:]...)
+	for i := range vd.CellOffsets[1:] {
+		vd.CellOffsets[i+1]--
+	}
+	if _, err := vd.Triangulation(); err == nil {
+		t.Errorf("vd.Triangulation() error = nil, want non-nil")
+	}
+}
+
+func TestNewDiagramFromTriangulation(t *testing.T) {
+	for _, name := range []string{"octahedron", "cocircular-rings", "random"} {
+		t.Run(name, func(t *testing.T) {
+			var sites s2.PointVector
+			if name == "random" {
+				sites = utils.GenerateRandomPoints(200, 0)
+			} else {
+				sites = fixtures.Load(name)
+			}
+			want, err := NewDiagram(slices.Clone(sites))
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			dt, err := s2delaunay.NewTriangulation(sites)
+			if err != nil {
+				t.Fatalf("s2delaunay.NewTriangulation(...) error = %v, want nil", err)
+			}
+			vd, err := NewDiagramFromTriangulation(dt)
+			if err != nil {
+				t.Fatalf("NewDiagramFromTriangulation(...) error = %v, want nil", err)
+			}
+			opts := []cmp.Option{
+				cmp.AllowUnexported(Diagram{}),
+				cmp.Comparer(func(a, b *diagramCache) bool { return true }),
+			}
+			if diff := cmp.Diff(want, vd, opts...); diff != "" {
+				t.Errorf("NewDiagramFromTriangulation(...) mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewDiagramFromTriangulation_Eps(t *testing.T) {
+	dt, err := s2delaunay.NewTriangulation(utils.GenerateRandomPoints(100, 0),
+		s2delaunay.WithEps(1e-10))
+	if err != nil {
+		t.Fatalf("s2delaunay.NewTriangulation(...) error = %v, want nil", err)
+	}
+	tests := []struct {
+		name string
+		opts []DiagramOption
+		want float64
+	}{
+		{"inherited", nil, 1e-10},
+		{"overridden", []DiagramOption{WithEps(1e-9)}, 1e-9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd, err := NewDiagramFromTriangulation(dt, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewDiagramFromTriangulation(...) error = %v, want nil", err)
+			}
+			if got := vd.Eps(); got != tt.want {
+				t.Errorf("vd.Eps() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDiagramFromTriangulation_Error(t *testing.T) {
+	dt, err := s2delaunay.NewTriangulation(utils.GenerateRandomPoints(100, 0))
+	if err != nil {
+		t.Fatalf("s2delaunay.NewTriangulation(...) error = %v, want nil", err)
+	}
+	tests := []struct {
+		name string
+		dt   *s2delaunay.Triangulation
+		opts []DiagramOption
+	}{
+		{"too few vertices", &s2delaunay.Triangulation{Vertices: dt.Vertices[:3]}, nil},
+		{
+			name: "no incidence",
+			dt:   &s2delaunay.Triangulation{Vertices: dt.Vertices, Triangles: dt.Triangles},
+		},
+		{"order independent", dt, []DiagramOption{WithOrderIndependentOutput()}},
+		{"invalid eps", dt, []DiagramOption{WithEps(0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewDiagramFromTriangulation(tt.dt, tt.opts...); err == nil {
+				t.Errorf("NewDiagramFromTriangulation(...) error = nil, want non-nil")
+			}
+		})
+	}
+}
+
+func TestWithRetainedTriangulation(t *testing.T) {
+	sites := utils.GenerateRandomPoints(200, 0)
+	want, err := s2delaunay.NewTriangulation(slices.Clone(sites))
+	if err != nil {
+		t.Fatalf("s2delaunay.NewTriangulation(...) error = %v, want nil", err)
+	}
+	vd, err := NewDiagram(sites, WithRetainedTriangulation())
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	dt, err := vd.Triangulation()
+	if err != nil {
+		t.Fatalf("vd.Triangulation() error = %v, want nil", err)
+	}
+	if diff := cmp.Diff(want.Triangles, dt.Triangles); diff != "" {
+		t.Errorf("vd.Triangulation().Triangles mismatch (-want +got):\n%s", diff)
+	}
+	if again, _ := vd.Triangulation(); again != dt {
+		t.Errorf("vd.Triangulation() recomputed the retained triangulation")
+	}
+
+	fromDt, err := NewDiagramFromTriangulation(want, WithRetainedTriangulation())
+	if err != nil {
+		t.Fatalf("NewDiagramFromTriangulation(...) error = %v, want nil", err)
+	}
+	if got, _ := fromDt.Triangulation(); got != want {
+		t.Errorf("fromDt.Triangulation() = %p, want the given triangulation %p", got, want)
+	}
+
+	// A modified diagram drops the retained triangulation.
+	if _, err := vd.AddSite(utils.GenerateRandomPoints(1, 1)[0]); err != nil {
+		t.Fatalf("vd.AddSite(...) error = %v, want nil", err)
+	}
+	dt, err = vd.Triangulation()
+	if err != nil {
+		t.Fatalf("vd.Triangulation() error = %v, want nil", err)
+	}
+	if got, want := dt.NumVertices(), vd.NumCells(); got != want {
+		t.Errorf("vd.Triangulation().NumVertices() = %d, want %d", got, want)
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDiagram_Triangulation(b *testing.B) {
+	sites := utils.GenerateRandomPoints(1e+4, 0)
+	b.Run("TwoHulls", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := NewDiagram(sites); err != nil {
+				b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			if _, err := s2delaunay.NewTriangulation(sites); err != nil {
+				b.Fatalf("s2delaunay.NewTriangulation(...) error = %v, want nil", err)
+			}
+		}
+	})
+	b.Run("FromTriangulation", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			dt, err := s2delaunay.NewTriangulation(sites)
+			if err != nil {
+				b.Fatalf("s2delaunay.NewTriangulation(...) error = %v, want nil", err)
+			}
+			if _, err := NewDiagramFromTriangulation(dt); err != nil {
+				b.Fatalf("NewDiagramFromTriangulation(...) error = %v, want nil", err)
+			}
+		}
+	})
+}
+
+// Helpers
+
+// triangleHasVertices reports whether the triangle contains both vertices.
+func triangleHasVertices(tri [3]int, a, b int) bool {
+	has := func(v int) bool { return tri[0] == v || tri[1] == v || tri[2] == v }
+	return has(a) && has(b)
+}
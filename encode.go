@@ -0,0 +1,357 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+
+	"github.com/golang/geo/r3"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+const (
+	// encodingVersion is the version byte written by Encode.
+	encodingVersion = 1
+	// encodingHasWeights is the flag of Encode for a power diagram.
+	encodingHasWeights = 1
+	// encodingHasMergeTol is the flag of Encode for a diagram built with WithVertexMerging.
+	encodingHasMergeTol = 2
+	// decodeChunk is the number of elements DecodeDiagram allocates at once, so that garbage
+	// counts cannot exhaust memory before the stream runs out.
+	decodeChunk = 1 << 16
+)
+
+// Encode writes the diagram to w in a compact binary format that DecodeDiagram reads back:
+// a version byte, a flags byte, the number of sites, vertices and ring entries as uvarints,
+// eps, the tolerance of WithVertexMerging if vertices were merged, the coordinates of the sites
+// and vertices as little-endian float64s, the ring sizes as uvarints, the vertex indices as
+// zigzag varints of the difference to the previous index, and the weights of power diagrams as
+// float64s. CellNeighbors is not written, as neighbor k of a cell is the cell across its edge k.
+// Like MarshalJSON it does not encode the options other than WithEps and WithVertexMerging, the
+// retained triangulation, the site sources and the values of SetSiteData.
+// It returns an error if writing fails.
+func (d *Diagram) Encode(w io.Writer) error {
+	e := &encoder{w: bufio.NewWriter(w)}
+	var flags byte
+	if d.weights != nil {
+		flags |= encodingHasWeights
+	}
+	if d.mergeTol != 0 {
+		flags |= encodingHasMergeTol
+	}
+	e.writeByte(encodingVersion)
+	e.writeByte(flags)
+	e.writeUvarint(uint64(d.NumCells()))
+	e.writeUvarint(uint64(len(d.Vertices)))
+	e.writeUvarint(uint64(len(d.CellVertices)))
+	e.writeFloat64(d.eps)
+	if d.mergeTol != 0 {
+		e.writeFloat64(float64(d.mergeTol))
+	}
+	for _, ps := range []s2.PointVector{d.Sites, d.Vertices} {
+		for _, p := range ps {
+			e.writeFloat64(p.X)
+			e.writeFloat64(p.Y)
+			e.writeFloat64(p.Z)
+		}
+	}
+	for i := range d.NumCells() {
+		e.writeUvarint(uint64(d.CellOffsets[i+1] - d.CellOffsets[i]))
+	}
+	prev := 0
+	for _, v := range d.CellVertices {
+		e.writeVarint(int64(v - prev))
+		prev = v
+	}
+	for _, wt := range d.weights {
+		e.writeFloat64(wt)
+	}
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+// DecodeDiagram reads a diagram written by Encode from r. If r is not an io.ByteReader it is
+// buffered, and bytes past the end of the diagram may be consumed.
+// The counts are checked against the Euler relation of the sphere while reading, every index
+// is bounds-checked, and the result is checked like NewDiagramFromArrays. It returns an error,
+// and never panics, if the stream is truncated, malformed or of an unknown version, or if the
+// diagram is not valid.
+func DecodeDiagram(r io.Reader) (*Diagram, error) {
+	br, ok := r.(byteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	dec := &decoder{r: br}
+	if v := dec.readByte(); dec.err == nil && v != encodingVersion {
+		return nil, fmt.Errorf("s2voronoi: unknown encoding version %d", v)
+	}
+	flags := dec.readByte()
+	numSites := dec.readCount()
+	numVertices := dec.readCount()
+	numEntries := dec.readCount()
+	eps := dec.readFloat64()
+	var mergeTol s1.Angle
+	if flags&encodingHasMergeTol != 0 {
+		mergeTol = s1.Angle(dec.readFloat64())
+	}
+	if dec.err != nil {
+		return nil, dec.err
+	}
+	if flags&^(encodingHasWeights|encodingHasMergeTol) != 0 {
+		return nil, fmt.Errorf("s2voronoi: unknown encoding flags %#x", flags)
+	}
+	if numSites < 4 {
+		return nil, errInsufficientSites
+	}
+	// A diagram of F non-empty cells with V vertices of degree at least 3 has E = V + F - 2
+	// edges, each in two rings, and V <= 2F - 4.
+	if numVertices > 2*numSites-4 || numEntries > 2*(numVertices+numSites-2) {
+		return nil, fmt.Errorf("s2voronoi: %d vertices and %d ring entries for %d sites",
+			numVertices, numEntries, numSites)
+	}
+	if !(eps > 0) || math.IsInf(eps, 1) {
+		return nil, errorf(ErrInvalidOption, "s2voronoi: eps must be positive, got %v", eps)
+	}
+
+	d := &Diagram{eps: eps, cache: new(diagramCache)}
+	d.Sites = dec.readPoints(numSites)
+	d.Vertices = dec.readPoints(numVertices)
+	d.CellOffsets = make([]int, 1, min(numSites+1, decodeChunk))
+	nonEmpty := 0
+	for i := 0; i < numSites && dec.err == nil; i++ {
+		n := dec.readCount()
+		if n != 0 {
+			nonEmpty++
+		}
+		if n > numEntries-d.CellOffsets[i] {
+			return nil, fmt.Errorf("s2voronoi: rings exceed %d entries", numEntries)
+		}
+		d.CellOffsets = append(d.CellOffsets, d.CellOffsets[i]+n)
+	}
+	d.CellVertices = dec.readIndices(numEntries, numVertices)
+	if flags&encodingHasWeights != 0 {
+		d.weights = make([]float64, 0, min(numSites, decodeChunk))
+		for i := 0; i < numSites && dec.err == nil; i++ {
+			w := dec.readFloat64()
+			if math.IsNaN(w) || math.IsInf(w, 0) {
+				return nil, fmt.Errorf("s2voronoi: weight %d is not finite: %v", i, w)
+			}
+			d.weights = append(d.weights, w)
+		}
+	}
+	if dec.err != nil {
+		return nil, dec.err
+	}
+
+	if d.CellOffsets[numSites] != numEntries || numEntries != 2*(numVertices+nonEmpty-2) {
+		return nil, fmt.Errorf("s2voronoi: %d ring entries for %d vertices and %d non-empty "+
+			"cells, want %d", d.CellOffsets[numSites], numVertices, nonEmpty,
+			2*(numVertices+nonEmpty-2))
+	}
+	if err := checkSites(d.Sites, false); err != nil {
+		return nil, err
+	}
+	neighbors, err := d.edgeNeighbors()
+	if err != nil {
+		return nil, err
+	}
+	return newValidatedDiagram(d.Sites, d.Vertices, d.CellVertices, neighbors, d.CellOffsets,
+		eps, d.weights, mergeTol)
+}
+
+// byteReader is the reader of DecodeDiagram.
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// encoder writes the fields of Encode and keeps the first error.
+type encoder struct {
+	w   *bufio.Writer
+	buf [binary.MaxVarintLen64]byte
+	err error
+}
+
+func (e *encoder) writeByte(b byte) {
+	if e.err == nil {
+		e.err = e.w.WriteByte(b)
+	}
+}
+
+func (e *encoder) writeUvarint(x uint64) {
+	if e.err == nil {
+		_, e.err = e.w.Write(binary.AppendUvarint(e.buf[:0], x))
+	}
+}
+
+func (e *encoder) writeVarint(x int64) {
+	if e.err == nil {
+		_, e.err = e.w.Write(binary.AppendVarint(e.buf[:0], x))
+	}
+}
+
+func (e *encoder) writeFloat64(x float64) {
+	if e.err == nil {
+		_, e.err = e.w.Write(binary.LittleEndian.AppendUint64(e.buf[:0], math.Float64bits(x)))
+	}
+}
+
+// decoder reads the fields of DecodeDiagram and keeps the first error; after an error every
+// read returns zero.
+type decoder struct {
+	r   byteReader
+	buf [8]byte
+	err error
+}
+
+// fail records err, reporting a stream that ends within a field as truncated.
+func (dec *decoder) fail(err error) {
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	dec.err = fmt.Errorf("s2voronoi: decoding diagram: %w", err)
+}
+
+func (dec *decoder) readByte() byte {
+	if dec.err != nil {
+		return 0
+	}
+	b, err := dec.r.ReadByte()
+	if err != nil {
+		dec.fail(err)
+	}
+	return b
+}
+
+// readCount reads a uvarint that must fit into a 32-bit index.
+func (dec *decoder) readCount() int {
+	if dec.err != nil {
+		return 0
+	}
+	x, err := binary.ReadUvarint(dec.r)
+	if err != nil {
+		dec.fail(err)
+		return 0
+	}
+	if x > math.MaxUint32 {
+		dec.fail(fmt.Errorf("count %d exceeds 32 bits", x))
+		return 0
+	}
+	return int(x)
+}
+
+func (dec *decoder) readFloat64() float64 {
+	if dec.err != nil {
+		return 0
+	}
+	if _, err := io.ReadFull(dec.r, dec.buf[:]); err != nil {
+		dec.fail(err)
+		return 0
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(dec.buf[:]))
+}
+
+// readPoints reads n points without normalizing them.
+func (dec *decoder) readPoints(n int) s2.PointVector {
+	ps := make(s2.PointVector, 0, min(n, decodeChunk))
+	for range n {
+		x, y, z := dec.readFloat64(), dec.readFloat64(), dec.readFloat64()
+		if dec.err != nil {
+			return nil
+		}
+		ps = append(ps, s2.Point{Vector: r3.Vector{X: x, Y: y, Z: z}})
+	}
+	return ps
+}
+
+// readIndices reads n indices written as differences to the previous one, each of which must
+// be in [0, limit).
+func (dec *decoder) readIndices(n, limit int) []int {
+	indices := make([]int, 0, min(n, decodeChunk))
+	prev := int64(0)
+	for range n {
+		if dec.err != nil {
+			return nil
+		}
+		delta, err := binary.ReadVarint(dec.r)
+		if err != nil {
+			dec.fail(err)
+			return nil
+		}
+		v := prev + delta
+		if delta < -int64(limit) || delta > int64(limit) || v < 0 || v >= int64(limit) {
+			dec.fail(fmt.Errorf("index %d%+d out of range [0 %d)", prev, delta, limit))
+			return nil
+		}
+		indices = append(indices, int(v))
+		prev = v
+	}
+	return indices
+}
+
+// edgeNeighbors returns the CellNeighbors of the rings of the diagram, where neighbor k of a
+// cell is the cell whose ring runs along its edge k in the opposite direction. The CSR arrays
+// other than CellNeighbors must be in range.
+// It returns an error if an edge is not shared by exactly one other ring.
+func (d *Diagram) edgeNeighbors() ([]int, error) {
+	numEntries := len(d.CellVertices)
+	// cellOf[k] is the cell of entry k, next[k] the entry after k in its ring.
+	cellOf := make([]int, numEntries)
+	next := make([]int, numEntries)
+	for i := range d.NumCells() {
+		start, end := d.CellOffsets[i], d.CellOffsets[i+1]
+		for k := start; k < end; k++ {
+			cellOf[k] = i
+			next[k] = k + 1
+		}
+		if end > start {
+			next[end-1] = start
+		}
+	}
+	// The entries of each vertex, in CSR layout.
+	vertexOffsets := make([]int, len(d.Vertices)+1)
+	for _, v := range d.CellVertices {
+		vertexOffsets[v+1]++
+	}
+	for v := range d.Vertices {
+		vertexOffsets[v+1] += vertexOffsets[v]
+	}
+	entries := make([]int, numEntries)
+	fill := slices.Clone(vertexOffsets[:len(d.Vertices)])
+	for k, v := range d.CellVertices {
+		entries[fill[v]] = k
+		fill[v]++
+	}
+
+	neighbors := make([]int, numEntries)
+	for k, a := range d.CellVertices {
+		b := d.CellVertices[next[k]]
+		neighbors[k] = -1
+		for _, e := range entries[vertexOffsets[b]:vertexOffsets[b+1]] {
+			if d.CellVertices[next[e]] != a {
+				continue
+			}
+			if neighbors[k] != -1 {
+				return nil, fmt.Errorf("s2voronoi: edge from vertex %d to %d is in several rings",
+					b, a)
+			}
+			neighbors[k] = cellOf[e]
+		}
+		if neighbors[k] == -1 {
+			return nil, fmt.Errorf("s2voronoi: edge from vertex %d to %d of cell %d is not shared",
+				a, b, cellOf[k])
+		}
+	}
+	return neighbors, nil
+}
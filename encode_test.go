@@ -0,0 +1,246 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+
+	"github.com/2dChan/s2voronoi/utils"
+)
+
+func TestDiagram_Encode(t *testing.T) {
+	tests := []struct {
+		name string
+		vd   func(t *testing.T) *Diagram
+	}{
+		{"4 sites", func(t *testing.T) *Diagram { return mustNewDiagram(t, 4) }},
+		{"10 sites", func(t *testing.T) *Diagram { return mustNewDiagram(t, 10) }},
+		{"1000 sites", func(t *testing.T) *Diagram { return mustNewDiagram(t, 1000) }},
+		{"empty cell", func(t *testing.T) *Diagram {
+			vd, _ := mustNewEmptyCellDiagram(t)
+			return vd
+		}},
+		{"merged vertices", func(t *testing.T) *Diagram {
+			vd, err := NewDiagram(latLngGrid(5, 8), WithVertexMerging(1e-9))
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			return vd
+		}},
+		{"power", func(t *testing.T) *Diagram {
+			sites := utils.GenerateRandomPoints(100, 0)
+			weights := make([]float64, len(sites))
+			for i := range weights {
+				weights[i] = 0.01 * float64(i%5)
+			}
+			vd, err := NewPowerDiagram(sites, weights)
+			if err != nil {
+				t.Fatalf("NewPowerDiagram(...) error = %v, want nil", err)
+			}
+			return vd
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.vd(t)
+			data := mustEncode(t, want)
+			for _, r := range []io.Reader{bytes.NewReader(data), iotest.OneByteReader(
+				bytes.NewReader(data))} {
+				got, err := DecodeDiagram(r)
+				if err != nil {
+					t.Fatalf("DecodeDiagram(...) error = %v, want nil", err)
+				}
+				if !got.Equal(want) || got.eps != want.eps {
+					t.Errorf("DecodeDiagram(vd.Encode()) differs from vd")
+				}
+			}
+		})
+	}
+}
+
+func TestDiagram_Encode_WriteError(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	w := &limitedWriter{n: 100}
+	if err := vd.Encode(w); err == nil {
+		t.Errorf("vd.Encode(...) error = nil, want error")
+	}
+}
+
+func TestDecodeDiagram_Errors(t *testing.T) {
+	data := mustEncode(t, mustNewDiagram(t, 10))
+	corrupt := func(f func(b []byte) []byte) []byte {
+		return f(bytes.Clone(data))
+	}
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{"empty", nil, io.ErrUnexpectedEOF},
+		{"version", corrupt(func(b []byte) []byte { b[0] = 2; return b }), nil},
+		{"flags", corrupt(func(b []byte) []byte { b[1] = 4; return b }), nil},
+		{"insufficient sites", corrupt(func(b []byte) []byte { b[2] = 3; return b }),
+			ErrInsufficientSites},
+		{"too many vertices", corrupt(func(b []byte) []byte { b[3] = 17; return b }), nil},
+		{"euler", corrupt(func(b []byte) []byte { b[4] -= 2; return b }), nil},
+		{"eps", corrupt(func(b []byte) []byte { clear(b[5 : 5+8]); return b }), ErrInvalidOption},
+		{"huge count", append([]byte{encodingVersion, 0}, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f), nil},
+		{"truncated", data[:len(data)-1], io.ErrUnexpectedEOF},
+		{"index out of range", corrupt(func(b []byte) []byte {
+			// The last byte ends the difference of the last vertex index to the one before.
+			b[len(b)-1] = 0x7e
+			return b
+		}), nil},
+		{"coordinate", corrupt(func(b []byte) []byte {
+			// The first coordinate of the first site follows the header and eps.
+			b[5+8+7] ^= 0x40
+			return b
+		}), ErrNotUnitLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd, err := DecodeDiagram(bytes.NewReader(tt.data))
+			if err == nil {
+				t.Fatalf("DecodeDiagram(...) = %v, nil, want error", vd)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("DecodeDiagram(...) error = %v, want errors.Is(err, %v)", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeDiagram_Garbage(t *testing.T) {
+	data := mustEncode(t, mustNewDiagram(t, 20))
+	decode := func(t *testing.T, b []byte) (*Diagram, error) {
+		t.Helper()
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("DecodeDiagram(%x) panicked: %v", b, r)
+			}
+		}()
+		return DecodeDiagram(bytes.NewReader(b))
+	}
+
+	t.Run("truncated", func(t *testing.T) {
+		for n := range len(data) {
+			if _, err := decode(t, data[:n]); err == nil {
+				t.Errorf("DecodeDiagram(data[:%d]) error = nil, want error", n)
+			}
+		}
+	})
+	t.Run("flipped", func(t *testing.T) {
+		random := rand.New(rand.NewSource(0))
+		for range 2000 {
+			b := bytes.Clone(data)
+			for range 1 + random.Intn(3) {
+				b[random.Intn(len(b))] ^= byte(1 << random.Intn(8))
+			}
+			// A flip of a low mantissa bit may leave a valid diagram.
+			if vd, err := decode(t, b); err == nil {
+				if err := vd.Validate(); err != nil {
+					t.Errorf("DecodeDiagram(...) = invalid diagram, nil: %v", err)
+				}
+			}
+		}
+	})
+	t.Run("random", func(t *testing.T) {
+		random := rand.New(rand.NewSource(0))
+		for range 2000 {
+			b := make([]byte, random.Intn(200))
+			random.Read(b)
+			if len(b) > 0 && random.Intn(2) == 0 {
+				b[0] = encodingVersion
+			}
+			if _, err := decode(t, b); err == nil {
+				t.Errorf("DecodeDiagram(%x) error = nil, want error", b)
+			}
+		}
+	})
+}
+
+func TestDiagram_Encode_Size(t *testing.T) {
+	vd := mustNewDiagram(t, 10000)
+	data := mustEncode(t, vd)
+	js, err := json.Marshal(vd)
+	if err != nil {
+		t.Fatalf("json.Marshal(vd) error = %v, want nil", err)
+	}
+	if 3*len(data) > len(js) {
+		t.Errorf("vd.Encode() wrote %d bytes, want at most a third of %d bytes of JSON",
+			len(data), len(js))
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDiagram_Encode(b *testing.B) {
+	vd, err := NewDiagram(utils.GenerateRandomPoints(100000, 0))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	var buf bytes.Buffer
+	js, err := json.Marshal(vd)
+	if err != nil {
+		b.Fatalf("json.Marshal(vd) error = %v, want nil", err)
+	}
+	for b.Loop() {
+		buf.Reset()
+		if err := vd.Encode(&buf); err != nil {
+			b.Fatalf("vd.Encode(...) error = %v, want nil", err)
+		}
+	}
+	b.ReportMetric(float64(buf.Len())/float64(vd.NumCells()), "bytes/site")
+	b.ReportMetric(float64(len(js))/float64(vd.NumCells()), "json-bytes/site")
+}
+
+func BenchmarkDecodeDiagram(b *testing.B) {
+	vd, err := NewDiagram(utils.GenerateRandomPoints(100000, 0))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	var buf bytes.Buffer
+	if err := vd.Encode(&buf); err != nil {
+		b.Fatalf("vd.Encode(...) error = %v, want nil", err)
+	}
+	for b.Loop() {
+		if _, err := DecodeDiagram(bytes.NewReader(buf.Bytes())); err != nil {
+			b.Fatalf("DecodeDiagram(...) error = %v, want nil", err)
+		}
+	}
+}
+
+// Helpers
+
+// mustEncode returns the encoding of vd.
+func mustEncode(t *testing.T, vd *Diagram) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := vd.Encode(&buf); err != nil {
+		t.Fatalf("vd.Encode(...) error = %v, want nil", err)
+	}
+	return buf.Bytes()
+}
+
+// limitedWriter accepts n bytes and then fails.
+type limitedWriter struct {
+	n int
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		n := w.n
+		w.n = 0
+		return n, io.ErrShortWrite
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"slices"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// Equal reports whether the diagrams are identical as stored: the same sites and vertices, the
+// same CSR arrays and the same weights. Diagrams of the same sites built by different methods,
+// e.g. by AddSite and NewDiagram, may number their vertices or start their rings differently
+// and are then not equal; building both with WithOrderIndependentOutput makes the numbering
+// canonical. Options that do not affect the arrays, such as eps, are not compared.
+func (d *Diagram) Equal(other *Diagram) bool {
+	return d.equal(other, func(a, b s2.Point) bool { return a == b })
+}
+
+// ApproxEqual reports whether the diagrams are equal as stored like by Equal, except that the
+// sites and vertices may differ by an angle of up to tol. The CSR arrays and the weights are
+// compared exactly.
+func (d *Diagram) ApproxEqual(other *Diagram, tol s1.Angle) bool {
+	maxChord := s1.ChordAngleFromAngle(tol)
+	return d.equal(other, func(a, b s2.Point) bool {
+		return s2.ChordAngleBetweenPoints(a, b) <= maxChord
+	})
+}
+
+// equal reports whether the diagrams have the same CSR arrays and weights, and their sites and
+// vertices are equal by eq.
+func (d *Diagram) equal(other *Diagram, eq func(a, b s2.Point) bool) bool {
+	if d == other {
+		return true
+	}
+	if d == nil || other == nil {
+		return false
+	}
+	return slices.EqualFunc(d.Sites, other.Sites, eq) &&
+		slices.EqualFunc(d.Vertices, other.Vertices, eq) &&
+		slices.Equal(d.CellVertices, other.CellVertices) &&
+		slices.Equal(d.CellNeighbors, other.CellNeighbors) &&
+		slices.Equal(d.CellOffsets, other.CellOffsets) &&
+		slices.Equal(d.weights, other.weights)
+}
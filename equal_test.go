@@ -0,0 +1,84 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+func TestDiagram_Equal(t *testing.T) {
+	points := utils.GenerateRandomPoints(100, 0)
+	perturbed := func(dist float64) func(t *testing.T) *Diagram {
+		return func(t *testing.T) *Diagram {
+			sites := slices.Clone(points)
+			for i, p := range sites {
+				sites[i] = s2.Point{Vector: p.Add(s2.Ortho(p).Mul(dist)).Normalize()}
+			}
+			vd, err := NewDiagram(sites)
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			return vd
+		}
+	}
+	tests := []struct {
+		name  string
+		other func(t *testing.T) *Diagram
+		equal bool
+		// approx reports whether ApproxEqual with a tolerance of 1e-9 rad holds.
+		approx bool
+	}{
+		{"same input", func(t *testing.T) *Diagram { return mustNewDiagram(t, 100) }, true, true},
+		{"clone", func(t *testing.T) *Diagram { return mustNewDiagram(t, 100).clone() }, true, true},
+		{"noise", perturbed(1e-13), false, true},
+		{"perturbed", perturbed(1e-3), false, false},
+		{"other sites", func(t *testing.T) *Diagram { return mustNewDiagram(t, 101) }, false, false},
+		{
+			name: "permuted vertices",
+			other: func(t *testing.T) *Diagram {
+				vd := mustNewDiagram(t, 100)
+				last := len(vd.Vertices) - 1
+				vd.Vertices[0], vd.Vertices[last] = vd.Vertices[last], vd.Vertices[0]
+				for k, v := range vd.CellVertices {
+					switch v {
+					case 0:
+						vd.CellVertices[k] = last
+					case last:
+						vd.CellVertices[k] = 0
+					}
+				}
+				return vd
+			},
+		},
+		{
+			name: "power",
+			other: func(t *testing.T) *Diagram {
+				vd := mustNewDiagram(t, 100)
+				vd.weights = make([]float64, vd.NumCells())
+				return vd
+			},
+		},
+		{"nil", func(t *testing.T) *Diagram { return nil }, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd, other := mustNewDiagram(t, 100), tt.other(t)
+			if got := vd.Equal(other); got != tt.equal {
+				t.Errorf("vd.Equal(other) = %v, want %v", got, tt.equal)
+			}
+			if got := vd.ApproxEqual(other, 1e-9*s1.Radian); got != tt.approx {
+				t.Errorf("vd.ApproxEqual(other, 1e-9) = %v, want %v", got, tt.approx)
+			}
+			if other != nil && other.Equal(vd) != tt.equal {
+				t.Errorf("other.Equal(vd) = %v, want %v", !tt.equal, tt.equal)
+			}
+		})
+	}
+}
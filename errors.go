@@ -0,0 +1,60 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"fmt"
+
+	"github.com/2dChan/s2voronoi/s2delaunay"
+)
+
+// The errors of the package match one of these sentinels with errors.Is when they stem from a
+// common failure mode, so callers can tell the modes apart without matching messages. They are
+// the sentinels of s2delaunay, so errors of the underlying triangulation match them as well.
+var (
+	// ErrInsufficientSites is matched by errors about fewer than 4 sites.
+	ErrInsufficientSites = s2delaunay.ErrInsufficientVertices
+	// ErrDegenerateInput is matched by errors about sites whose diagram is not defined within
+	// eps, e.g. coplanar sites, sites in an open hemisphere or degenerate circumcenters.
+	ErrDegenerateInput = s2delaunay.ErrDegenerateInput
+	// ErrDuplicateSites is matched by errors about sites that coincide within eps, which carry
+	// a *DuplicateSitesError for NewDiagram and AddSites.
+	ErrDuplicateSites = s2delaunay.ErrDuplicateVertices
+	// ErrInvalidOption is matched by errors of invalid options and of options that do not apply.
+	ErrInvalidOption = s2delaunay.ErrInvalidOption
+	// ErrNotUnitLength is matched by errors about sites that are not finite unit vectors, see
+	// WithNormalizeInput.
+	ErrNotUnitLength = s2delaunay.ErrNotUnitLength
+)
+
+// DuplicateSitesError is returned when sites coincide with other sites. For NewDiagram,
+// Indices are the later site of every pair of equal sites, or of sites within eps with
+// WithNearDuplicateCheck, and Pairs lists the pairs; sites only dropped by the convex hull are
+// listed in Indices without pairs. For AddSites, Indices are the offending positions in the
+// added sites. It matches ErrDuplicateSites.
+type DuplicateSitesError = s2delaunay.DuplicateVerticesError
+
+// errInsufficientSites is the error for fewer than 4 sites.
+var errInsufficientSites = errorf(ErrInsufficientSites,
+	"s2voronoi: insufficient sites for diagram, minimum 4 required")
+
+// kindError is an error with its own message that matches err with errors.Is and errors.As.
+type kindError struct {
+	msg string
+	err error
+}
+
+// errorf returns an error formatted like fmt.Errorf that matches kind with errors.Is.
+func errorf(kind error, format string, args ...any) error {
+	return &kindError{msg: fmt.Sprintf(format, args...), err: kind}
+}
+
+func (e *kindError) Error() string {
+	return e.msg
+}
+
+func (e *kindError) Unwrap() error {
+	return e.err
+}
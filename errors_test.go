@@ -0,0 +1,156 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/internal/fixtures"
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/r3"
+	"github.com/golang/geo/s2"
+)
+
+func TestErrorKinds(t *testing.T) {
+	build := func(sites s2.PointVector, setters ...DiagramOption) func(t *testing.T) error {
+		return func(t *testing.T) error {
+			_, err := NewDiagram(sites, setters...)
+			return err
+		}
+	}
+	tests := []struct {
+		name string
+		run  func(t *testing.T) error
+		want error
+	}{
+		{"insufficient sites", build(tetrahedron()[:3]), ErrInsufficientSites},
+		{"invalid eps", build(tetrahedron(), WithEps(0)), ErrInvalidOption},
+		{"coplanar", build(fixtures.Load("equatorial-coplanar")), ErrDegenerateInput},
+		{"hemisphere", build(fixtures.Load("hemispheric-cluster")), ErrDegenerateInput},
+		{"near duplicates", build(fixtures.Load("near-duplicates")), ErrDuplicateSites},
+		{"not unit length", build(s2.PointVector{
+			s2.PointFromCoords(1, 1, 1),
+			s2.PointFromCoords(1, -1, -1),
+			{Vector: r3.Vector{X: -1, Y: 1, Z: -1}},
+			s2.PointFromCoords(-1, -1, 1),
+		}), ErrNotUnitLength},
+		{
+			name: "invalid relax option",
+			run: func(t *testing.T) error {
+				_, err := mustNewDiagram(t, 100).Relax(1, WithParallelism(0))
+				return err
+			},
+			want: ErrInvalidOption,
+		},
+		{
+			name: "order independent power diagram",
+			run: func(t *testing.T) error {
+				sites := utils.GenerateRandomPoints(100, 0)
+				weights := make([]float64, len(sites))
+				weights[0] = 0.01
+				_, err := NewPowerDiagram(sites, weights, WithOrderIndependentOutput())
+				return err
+			},
+			want: ErrInvalidOption,
+		},
+		{
+			name: "add duplicate site",
+			run: func(t *testing.T) error {
+				vd := mustNewDiagram(t, 100)
+				_, err := vd.AddSite(vd.Sites[7])
+				return err
+			},
+			want: ErrDuplicateSites,
+		},
+		{
+			name: "remove from 4 sites",
+			run: func(t *testing.T) error {
+				vd, err := NewDiagram(tetrahedron())
+				if err != nil {
+					t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+				}
+				return vd.RemoveSite(0)
+			},
+			want: ErrInsufficientSites,
+		},
+		{
+			name: "remove leaving hemisphere",
+			run: func(t *testing.T) error {
+				vd, err := NewDiagram(s2.PointVector{
+					s2.PointFromCoords(0, 0, -1),
+					s2.PointFromCoords(1, 0, 0.5),
+					s2.PointFromCoords(0, 1, 0.5),
+					s2.PointFromCoords(-1, 0, 0.5),
+					s2.PointFromCoords(0, -1.1, 0.5),
+				})
+				if err != nil {
+					t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+				}
+				return vd.RemoveSite(0)
+			},
+			want: ErrDegenerateInput,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(t); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want errors.Is(err, %v)", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDuplicateSitesError(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	fresh := utils.GenerateRandomPoints(3, 1)
+	_, err := vd.AddSites(s2.PointVector{fresh[0], vd.Sites[3], fresh[1], fresh[0], fresh[2]})
+	var derr *DuplicateSitesError
+	if !errors.As(err, &derr) {
+		t.Fatalf("vd.AddSites(...) error = %v, want *DuplicateSitesError", err)
+	}
+	if want := []int{1, 3}; !slices.Equal(derr.Indices, want) {
+		t.Errorf("vd.AddSites(...) error Indices = %v, want %v", derr.Indices, want)
+	}
+	if !errors.Is(err, ErrDuplicateSites) {
+		t.Errorf("vd.AddSites(...) error = %v, want errors.Is(err, ErrDuplicateSites)", err)
+	}
+}
+
+func TestNewDiagram_DuplicateSitesError(t *testing.T) {
+	near := fixtures.Load("near-duplicates")
+	exact := utils.GenerateRandomPoints(100, 0)
+	exact[40], exact[70] = exact[10], exact[10]
+	tests := []struct {
+		name        string
+		sites       s2.PointVector
+		opts        []DiagramOption
+		wantIndices []int
+		wantPairs   [][2]int
+	}{
+		{"exact", exact, nil, []int{40, 70}, [][2]int{{10, 40}, {10, 70}}},
+		{"exact order independent", exact, []DiagramOption{WithOrderIndependentOutput()},
+			[]int{40, 70}, [][2]int{{10, 40}, {10, 70}}},
+		{"near", near[:6], []DiagramOption{WithNearDuplicateCheck()}, []int{1, 3, 5},
+			[][2]int{{0, 1}, {2, 3}, {4, 5}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDiagram(slices.Clone(tt.sites), tt.opts...)
+			var derr *DuplicateSitesError
+			if !errors.As(err, &derr) || !errors.Is(err, ErrDuplicateSites) {
+				t.Fatalf("NewDiagram(...) error = %v, want *DuplicateSitesError", err)
+			}
+			if !slices.Equal(derr.Indices, tt.wantIndices) {
+				t.Errorf("NewDiagram(...) error Indices = %v, want %v", derr.Indices,
+					tt.wantIndices)
+			}
+			if !slices.Equal(derr.Pairs, tt.wantPairs) {
+				t.Errorf("NewDiagram(...) error Pairs = %v, want %v", derr.Pairs, tt.wantPairs)
+			}
+		})
+	}
+}
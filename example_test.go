@@ -0,0 +1,93 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/2dChan/s2voronoi"
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// This example runs the full pipeline on the embedded airports: it merges the airports of the
+// same city, builds and relaxes the diagram, finds the cells containing a few cities, exports
+// the cells as GeoJSON and validates the result.
+func Example_pipeline() {
+	airports := utils.LoadEmbeddedAirports()
+	// The secondary airports of a city are merged into its major airport.
+	tol := s1.Angle(100 / 6371.0)
+	vd, err := s2voronoi.NewDiagram(airports, s2voronoi.WithDeduplication(tol))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%d airports in %d cells\n", len(airports), vd.NumCells())
+	if _, err := vd.Relax(3); err != nil {
+		log.Fatal(err)
+	}
+
+	cities := []struct {
+		name   string
+		latLng s2.LatLng
+	}{
+		{"Paris", s2.LatLngFromDegrees(48.86, 2.35)},
+		{"Nairobi", s2.LatLngFromDegrees(-1.29, 36.82)},
+		{"Tokyo", s2.LatLngFromDegrees(35.68, 139.69)},
+	}
+	for _, c := range cities {
+		i := vd.FindCellIndex(s2.PointFromLatLng(c.latLng))
+		fmt.Printf("%s: cell %d, %d airports, %d neighbors\n", c.name, i,
+			len(vd.SourceIndex(i)), vd.Cell(i).NumNeighbors())
+	}
+
+	data, err := vd.ToGeoJSON()
+	if err != nil {
+		log.Fatal(err)
+	}
+	var fc struct {
+		Features []json.RawMessage `json:"features"`
+	}
+	if err := json.Unmarshal(data, &fc); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("GeoJSON: %d features\n", len(fc.Features))
+
+	if err := vd.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Output:
+	// 507 airports in 451 cells
+	// Paris: cell 37, 2 airports, 6 neighbors
+	// Nairobi: cell 65, 1 airports, 6 neighbors
+	// Tokyo: cell 102, 2 airports, 7 neighbors
+	// GeoJSON: 451 features
+}
+
+func ExampleDiagram_CellAt() {
+	vd, err := s2voronoi.NewDiagram(utils.GenerateRandomPoints(100, 0))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Indices read from user input are checked instead of panicking.
+	for _, i := range []int{7, 100} {
+		cell, err := vd.CellAt(i)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		if _, err := cell.NeighborAt(cell.NumNeighbors()); err != nil {
+			fmt.Println(err)
+		}
+	}
+
+	// Output:
+	// s2voronoi: neighbor index 6 out of range [0 6)
+	// s2voronoi: cell index 100 out of range [0 100)
+}
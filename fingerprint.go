@@ -0,0 +1,71 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"slices"
+
+	"github.com/golang/geo/s2"
+)
+
+// fingerprintQuantum is the grid to which Fingerprint rounds coordinates and weights.
+const fingerprintQuantum = 1e-12
+
+// Fingerprint returns a 64-bit FNV-1a hash of the content of the diagram, e.g. to check that a
+// deserialized diagram is the expected one. It covers the coordinates of the sites and vertices
+// and the weights of power diagrams, all rounded to multiples of 1e-12, and every ring with its
+// neighbors as if canonicalized, see Canonicalize. The same diagram therefore hashes the same
+// on every run and platform, whatever the starting points of its rings, while any difference in
+// topology or in coordinates beyond the rounding changes the hash with high probability.
+// Coordinates that differ by less than 1e-12 may still round differently, so diagrams that are
+// only ApproxEqual may hash differently.
+func (d *Diagram) Fingerprint() uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	putInt := func(v int) {
+		binary.LittleEndian.PutUint64(buf[:], uint64(v))
+		h.Write(buf[:])
+	}
+	putFloat := func(x float64) {
+		// The rounded value is hashed by its bits, as converting it to int is not portable once
+		// it exceeds the range of int. Negative zero is hashed as zero.
+		r := math.Round(x / fingerprintQuantum)
+		if r == 0 {
+			r = 0
+		}
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(r))
+		h.Write(buf[:])
+	}
+	putPoints := func(ps s2.PointVector) {
+		putInt(len(ps))
+		for _, p := range ps {
+			putFloat(p.X)
+			putFloat(p.Y)
+			putFloat(p.Z)
+		}
+	}
+
+	putPoints(d.Sites)
+	putPoints(d.Vertices)
+	putInt(len(d.weights))
+	for _, w := range d.weights {
+		putFloat(w)
+	}
+	for _, c := range d.Cells() {
+		ring, neighbors := c.VertexIndices(), c.NeighborIndices()
+		putInt(len(ring))
+		if len(ring) > 0 {
+			k := slices.Index(ring, slices.Min(ring))
+			for j := range ring {
+				putInt(ring[(k+j)%len(ring)])
+				putInt(neighbors[(k+j)%len(ring)])
+			}
+		}
+	}
+	return h.Sum64()
+}
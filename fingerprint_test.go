@@ -0,0 +1,114 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/internal/fixtures"
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s2"
+)
+
+func TestDiagram_Fingerprint(t *testing.T) {
+	sites := utils.GenerateRandomPoints(200, 0)
+	want := mustNewDiagram(t, 200).Fingerprint()
+	tests := []struct {
+		name string
+		// build returns a diagram and whether it is the diagram of want.
+		build func(t *testing.T) (*Diagram, bool)
+	}{
+		{
+			name: "rebuild",
+			build: func(t *testing.T) (*Diagram, bool) {
+				vd := mustNewDiagram(t, 200)
+				if err := vd.Rebuild(slices.Clone(sites)); err != nil {
+					t.Fatalf("vd.Rebuild(...) error = %v, want nil", err)
+				}
+				return vd, true
+			},
+		},
+		{
+			name: "canonicalized",
+			build: func(t *testing.T) (*Diagram, bool) {
+				vd := mustNewDiagram(t, 200)
+				vd.Canonicalize()
+				return vd, true
+			},
+		},
+		{
+			name: "clone",
+			build: func(t *testing.T) (*Diagram, bool) {
+				return mustNewDiagram(t, 200).clone(), true
+			},
+		},
+		{
+			name: "moved site",
+			build: func(t *testing.T) (*Diagram, bool) {
+				vd := mustNewDiagram(t, 200)
+				nb := vd.Sites[vd.Cell(7).NeighborIndices()[0]]
+				if _, err := vd.MoveSite(7, s2.Interpolate(1e-3, vd.Sites[7], nb)); err != nil {
+					t.Fatalf("vd.MoveSite(...) error = %v, want nil", err)
+				}
+				return vd, false
+			},
+		},
+		{
+			name: "swapped neighbors",
+			build: func(t *testing.T) (*Diagram, bool) {
+				vd := mustNewDiagram(t, 200)
+				nbs := vd.CellNeighbors[vd.CellOffsets[3]:vd.CellOffsets[4]]
+				nbs[0], nbs[1] = nbs[1], nbs[0]
+				return vd, false
+			},
+		},
+		{
+			name: "removed site",
+			build: func(t *testing.T) (*Diagram, bool) {
+				vd := mustNewDiagram(t, 200)
+				if err := vd.RemoveSite(vd.NumCells() - 1); err != nil {
+					t.Fatalf("vd.RemoveSite(...) error = %v, want nil", err)
+				}
+				return vd, false
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd, same := tt.build(t)
+			if got := vd.Fingerprint(); (got == want) != same {
+				t.Errorf("vd.Fingerprint() = %x, want equal to %x: %v", got, want, same)
+			}
+		})
+	}
+}
+
+func TestDiagram_Fingerprint_LargeWeights(t *testing.T) {
+	// Weights beyond the range of int after rounding must still tell the diagrams apart.
+	fingerprint := func(w float64) uint64 {
+		sites := fixtures.Load("octahedron")
+		vd, err := NewPowerDiagram(sites, slices.Repeat([]float64{w}, len(sites)))
+		if err != nil {
+			t.Fatalf("NewPowerDiagram(..., %v) error = %v, want nil", w, err)
+		}
+		return vd.Fingerprint()
+	}
+	if a, b := fingerprint(1e10), fingerprint(2e10); a == b {
+		t.Errorf("Fingerprint() = %x for weights 1e10 and 2e10, want different", a)
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDiagram_Fingerprint(b *testing.B) {
+	vd, err := NewDiagram(utils.GenerateRandomPoints(100000, 0))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	for b.Loop() {
+		vd.Fingerprint()
+	}
+}
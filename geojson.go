@@ -0,0 +1,212 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/2dChan/s2voronoi/internal/lnglat"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// GeoJSONOptions holds configuration options for ToGeoJSON.
+type GeoJSONOptions struct {
+	MaxSegmentLength s1.Angle
+}
+
+// GeoJSONOption is a functional option type for GeoJSON export configuration.
+type GeoJSONOption func(*GeoJSONOptions) error
+
+// WithMaxSegmentLength subdivides the edges of the cells so that no segment is longer than
+// maxLength, since GeoJSON consumers draw straight lines in longitude and latitude rather than
+// great circle arcs. It must be positive and finite; by default edges are not subdivided.
+func WithMaxSegmentLength(maxLength s1.Angle) GeoJSONOption {
+	return func(o *GeoJSONOptions) error {
+		if !(maxLength > 0) || math.IsInf(float64(maxLength), 1) {
+			return errorf(ErrInvalidOption,
+				"s2voronoi: max segment length must be positive and finite, got %v", maxLength)
+		}
+		o.MaxSegmentLength = maxLength
+		return nil
+	}
+}
+
+// lngLatPosition is a position in longitude and latitude in degrees, as in GeoJSON.
+type lngLatPosition = lnglat.Position
+
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string            `json:"type"`
+	Geometry   *geoJSONGeometry  `json:"geometry"`
+	Properties geoJSONProperties `json:"properties"`
+}
+
+type geoJSONGeometry struct {
+	Type        string `json:"type"`
+	Coordinates any    `json:"coordinates"`
+}
+
+type geoJSONProperties struct {
+	Index int     `json:"index"`
+	Lat   float64 `json:"lat"`
+	Lng   float64 `json:"lng"`
+}
+
+// ToGeoJSON returns the cells of the diagram as an RFC 7946 FeatureCollection with one feature
+// per cell in site order. The properties of a feature hold the "index" of its site and the
+// "lat" and "lng" of the site in degrees. The geometry is a Polygon whose exterior ring is
+// closed and counterclockwise, with positions as [lng, lat] in degrees. Cells crossing the
+// antimeridian are cut along it into a MultiPolygon, and a cell containing a pole is bounded by
+// the pole's line of latitude, so that consumers drawing in longitude and latitude render
+// every cell correctly. Empty cells have a null geometry.
+// It returns an error if an option is invalid.
+func (d *Diagram) ToGeoJSON(setters ...GeoJSONOption) ([]byte, error) {
+	opts := &GeoJSONOptions{}
+	for _, set := range setters {
+		if err := set(opts); err != nil {
+			return nil, err
+		}
+	}
+
+	fc := geoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: make([]geoJSONFeature, 0, d.NumCells()),
+	}
+	for i, c := range d.Cells() {
+		ll := s2.LatLngFromPoint(c.Site())
+		f := geoJSONFeature{
+			Type:       "Feature",
+			Properties: geoJSONProperties{Index: i, Lat: ll.Lat.Degrees(), Lng: ll.Lng.Degrees()},
+		}
+		switch polygons := c.lngLatPolygons(opts.MaxSegmentLength); len(polygons) {
+		case 0:
+		case 1:
+			f.Geometry = &geoJSONGeometry{Type: "Polygon", Coordinates: polygons[0]}
+		default:
+			f.Geometry = &geoJSONGeometry{Type: "MultiPolygon", Coordinates: polygons}
+		}
+		fc.Features = append(fc.Features, f)
+	}
+	return json.Marshal(fc)
+}
+
+// lngLatPolygons returns the polygons of the cell in longitude and latitude, cut at the
+// antimeridian and closed around a pole as ToGeoJSON describes, each a single closed
+// counterclockwise exterior ring, with the edges subdivided to maxSegment if it is positive.
+// It returns nil for an empty cell.
+func (c Cell) lngLatPolygons(maxSegment s1.Angle) [][][]lngLatPosition {
+	points := make(s2.PointVector, c.NumVertices())
+	for k, v := range c.VertexIndices() {
+		points[k] = c.d.Vertices[v]
+	}
+	var polygons [][][]lngLatPosition
+	for _, ring := range lnglat.Polygons(points, maxSegment) {
+		polygons = append(polygons, [][]lngLatPosition{ring})
+	}
+	return polygons
+}
+
+// WithSkipNonPoints makes NewDiagramFromGeoJSON skip the features whose geometry is neither a
+// Point nor a MultiPoint, including null geometries, instead of returning an error. The other
+// constructors and Rebuild reject it with an error matching ErrInvalidOption.
+func WithSkipNonPoints() DiagramOption {
+	return func(o *DiagramOptions) error {
+		o.SkipNonPoints = true
+		return nil
+	}
+}
+
+// NewDiagramFromGeoJSON creates a Voronoi diagram from the Point and MultiPoint features of the
+// RFC 7946 FeatureCollection read from r, e.g. weather stations, and returns the properties of
+// the features aligned with the sites. The points are taken in feature order and, within a
+// MultiPoint, in coordinate order; every point becomes a site at its [lng, lat] in degrees, any
+// altitude being ignored, and the points of a MultiPoint share the properties of their feature.
+// With WithDeduplication the points merged into a cell are those at the indices SourceIndex
+// returns in this order, and the properties of a cell are those of its first point, the site of
+// the cell. Features with other geometries are rejected unless WithSkipNonPoints is given.
+// It returns an error if the input is not a FeatureCollection, if a position is not a finite
+// longitude and latitude, or if the diagram cannot be constructed, see NewDiagram.
+func NewDiagramFromGeoJSON(r io.Reader, setters ...DiagramOption) (*Diagram,
+	[]map[string]any, error) {
+	opts := &DiagramOptions{
+		Eps: DefaultEps,
+	}
+	for _, set := range setters {
+		if err := set(opts); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var fc struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Geometry *struct {
+				Type        string          `json:"type"`
+				Coordinates json.RawMessage `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]any `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.NewDecoder(r).Decode(&fc); err != nil {
+		return nil, nil, fmt.Errorf("s2voronoi: decoding GeoJSON: %w", err)
+	}
+	if fc.Type != "FeatureCollection" {
+		return nil, nil, fmt.Errorf("s2voronoi: GeoJSON type %q, want FeatureCollection", fc.Type)
+	}
+
+	var sites s2.PointVector
+	var properties []map[string]any
+	for i, f := range fc.Features {
+		var positions [][]float64
+		switch {
+		case f.Geometry != nil && f.Geometry.Type == "Point":
+			var p []float64
+			if err := json.Unmarshal(f.Geometry.Coordinates, &p); err != nil {
+				return nil, nil, fmt.Errorf("s2voronoi: feature %d: %w", i, err)
+			}
+			positions = [][]float64{p}
+		case f.Geometry != nil && f.Geometry.Type == "MultiPoint":
+			if err := json.Unmarshal(f.Geometry.Coordinates, &positions); err != nil {
+				return nil, nil, fmt.Errorf("s2voronoi: feature %d: %w", i, err)
+			}
+		case opts.SkipNonPoints:
+			continue
+		case f.Geometry == nil:
+			return nil, nil, fmt.Errorf("s2voronoi: feature %d has no geometry", i)
+		default:
+			return nil, nil, fmt.Errorf("s2voronoi: feature %d is a %s, want Point or MultiPoint",
+				i, f.Geometry.Type)
+		}
+		for _, p := range positions {
+			if len(p) < 2 || math.IsNaN(p[0]) || math.IsInf(p[0], 0) || !(math.Abs(p[1]) <= 90) {
+				return nil, nil, fmt.Errorf("s2voronoi: feature %d has invalid position %v", i, p)
+			}
+			sites = append(sites, s2.PointFromLatLng(s2.LatLngFromDegrees(p[1], p[0])))
+			properties = append(properties, f.Properties)
+		}
+	}
+
+	d := new(Diagram)
+	if err := d.rebuildOptions(context.Background(), sites, opts); err != nil {
+		return nil, nil, err
+	}
+	if d.sources.offsets != nil {
+		kept := make([]map[string]any, d.NumCells())
+		for i := range kept {
+			kept[i] = properties[d.sources.indices[d.sources.offsets[i]]]
+		}
+		properties = kept
+	}
+	return d, properties, nil
+}
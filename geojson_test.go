@@ -0,0 +1,424 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/internal/fixtures"
+	"github.com/2dChan/s2voronoi/s2delaunay"
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+func TestWithMaxSegmentLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		length  s1.Angle
+		wantErr bool
+	}{
+		{"positive", 0.01, false},
+		{"zero", 0, true},
+		{"negative", -0.01, true},
+		{"infinite", s1.InfAngle(), true},
+		{"nan", s1.Angle(math.NaN()), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts GeoJSONOptions
+			err := WithMaxSegmentLength(tt.length)(&opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WithMaxSegmentLength(%v) error = %v, wantErr %v", tt.length, err,
+					tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidOption) {
+				t.Errorf("WithMaxSegmentLength(%v) error = %v, want errors.Is(err, "+
+					"ErrInvalidOption)", tt.length, err)
+			}
+		})
+	}
+}
+
+func TestDiagram_ToGeoJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		sites s2.PointVector
+		// multi are the sites whose cells cross the antimeridian.
+		multi []int
+	}{
+		{"random", utils.GenerateRandomPoints(200, 0), nil},
+		// Site 1 is on the antimeridian.
+		{"octahedron", fixtures.Load("octahedron"), []int{1}},
+		// Sites 1, 4, 7, 10 and 13 are on the antimeridian.
+		{"antimeridian", fixtures.Load("antimeridian"), []int{1, 4, 7, 10, 13}},
+		{"lat lng grid", latLngGrid(5, 8), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd, err := NewDiagram(tt.sites)
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			data, err := vd.ToGeoJSON(WithMaxSegmentLength(s1.Degree / 2))
+			if err != nil {
+				t.Fatalf("vd.ToGeoJSON(...) error = %v, want nil", err)
+			}
+			polygons := checkGeoJSON(t, vd, data)
+			for i, c := range vd.Cells() {
+				area := 0.0
+				for _, ring := range polygons[i] {
+					area += geoJSONRingArea(ring)
+				}
+				if math.Abs(area-c.Area()) > 5e-2*c.Area() {
+					t.Errorf("feature %d area = %v, want %v", i, area, c.Area())
+				}
+			}
+			for _, i := range tt.multi {
+				if len(polygons[i]) != 2 {
+					t.Errorf("feature %d has %d polygons, want 2", i, len(polygons[i]))
+				}
+			}
+		})
+	}
+}
+
+func TestDiagram_ToGeoJSON_Poles(t *testing.T) {
+	vd, err := NewDiagram(fixtures.Load("octahedron"))
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	data, err := vd.ToGeoJSON()
+	if err != nil {
+		t.Fatalf("vd.ToGeoJSON() error = %v, want nil", err)
+	}
+	polygons := checkGeoJSON(t, vd, data)
+	// Sites 4 and 5 are the poles, whose cells are bounded by their lines of latitude.
+	for i, lat := range map[int]float64{4: 90, 5: -90} {
+		if len(polygons[i]) != 1 {
+			t.Fatalf("feature %d has %d polygons, want 1", i, len(polygons[i]))
+		}
+		n := 0
+		for _, p := range polygons[i][0] {
+			if p[1] == lat {
+				n++
+			}
+		}
+		if n < 2 {
+			t.Errorf("feature %d ring %v has %d positions at latitude %v, want at least 2", i,
+				polygons[i][0], n, lat)
+		}
+	}
+}
+
+func TestDiagram_ToGeoJSON_EmptyCell(t *testing.T) {
+	vd, empty := mustNewEmptyCellDiagram(t)
+	data, err := vd.ToGeoJSON()
+	if err != nil {
+		t.Fatalf("vd.ToGeoJSON() error = %v, want nil", err)
+	}
+	if polygons := checkGeoJSON(t, vd, data); polygons[empty] != nil {
+		t.Errorf("feature %d polygons = %v, want null geometry", empty, polygons[empty])
+	}
+}
+
+func TestDiagram_ToGeoJSON_Errors(t *testing.T) {
+	vd := mustNewDiagram(t, 10)
+	if _, err := vd.ToGeoJSON(WithMaxSegmentLength(0)); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("vd.ToGeoJSON(WithMaxSegmentLength(0)) error = %v, want errors.Is(err, "+
+			"ErrInvalidOption)", err)
+	}
+}
+
+func TestNewDiagramFromGeoJSON(t *testing.T) {
+	vd, properties, err := NewDiagramFromGeoJSON(strings.NewReader(stationsGeoJSON))
+	if err != nil {
+		t.Fatalf("NewDiagramFromGeoJSON(...) error = %v, want nil", err)
+	}
+	if err := vd.Validate(); err != nil {
+		t.Errorf("vd.Validate() = %v, want nil", err)
+	}
+	// The MultiPoint feature Svalbard has two points.
+	wantNames := []string{"Reykjavik", "Tokyo", "Sydney", "Cape Town", "Buenos Aires",
+		"Honolulu", "Anchorage", "Nairobi", "McMurdo", "Svalbard", "Svalbard"}
+	if vd.NumCells() != len(wantNames) || len(properties) != len(wantNames) {
+		t.Fatalf("NewDiagramFromGeoJSON(...) = %d sites, %d properties, want %d", vd.NumCells(),
+			len(properties), len(wantNames))
+	}
+	for i, name := range wantNames {
+		if got := properties[i]["name"]; got != name {
+			t.Errorf("properties[%d][\"name\"] = %v, want %q", i, got, name)
+		}
+	}
+	if got := properties[1]["elevation"]; got != 25.2 {
+		t.Errorf("properties[1][\"elevation\"] = %v, want 25.2", got)
+	}
+	want := s2.PointFromLatLng(s2.LatLngFromDegrees(35.6895, 139.6917))
+	if vd.Sites[1].Distance(want) > 1e-15 {
+		t.Errorf("vd.Sites[1] = %v, want Tokyo %v", vd.Sites[1], want)
+	}
+}
+
+func TestNewDiagramFromGeoJSON_Deduplication(t *testing.T) {
+	// A second Reykjavik station about 1 m from the first.
+	data := withFeatures(`{"type": "Feature", "geometry": {"type": "Point",
+		"coordinates": [-21.94, 64.13001]}, "properties": {"name": "Reykjavik 2"}}`)
+	vd, properties, err := NewDiagramFromGeoJSON(strings.NewReader(data),
+		WithDeduplication(1e-6))
+	if err != nil {
+		t.Fatalf("NewDiagramFromGeoJSON(...) error = %v, want nil", err)
+	}
+	if vd.NumCells() != 11 || len(properties) != 11 {
+		t.Fatalf("NewDiagramFromGeoJSON(...) = %d sites, %d properties, want 11", vd.NumCells(),
+			len(properties))
+	}
+	if got := vd.SourceIndex(0); len(got) != 2 || got[1] != 11 {
+		t.Errorf("vd.SourceIndex(0) = %v, want [0 11]", got)
+	}
+	if got := properties[0]["name"]; got != "Reykjavik" {
+		t.Errorf("properties[0][\"name\"] = %v, want \"Reykjavik\"", got)
+	}
+}
+
+func TestNewDiagramFromGeoJSON_NonPoints(t *testing.T) {
+	data := withFeatures(`{"type": "Feature", "geometry": {"type": "LineString",
+		"coordinates": [[0, 0], [1, 1]]}, "properties": {"name": "road"}},
+		{"type": "Feature", "geometry": null, "properties": null}`)
+	if _, _, err := NewDiagramFromGeoJSON(strings.NewReader(data)); err == nil {
+		t.Errorf("NewDiagramFromGeoJSON(...) error = nil, want error for a LineString")
+	}
+	vd, properties, err := NewDiagramFromGeoJSON(strings.NewReader(data), WithSkipNonPoints())
+	if err != nil {
+		t.Fatalf("NewDiagramFromGeoJSON(..., WithSkipNonPoints()) error = %v, want nil", err)
+	}
+	if vd.NumCells() != 11 || len(properties) != 11 {
+		t.Errorf("NewDiagramFromGeoJSON(..., WithSkipNonPoints()) = %d sites, %d properties, "+
+			"want 11", vd.NumCells(), len(properties))
+	}
+}
+
+func TestWithSkipNonPoints_OtherConstructors(t *testing.T) {
+	sites := utils.GenerateRandomPoints(100, 0)
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{"NewDiagram", func() error {
+			_, err := NewDiagram(sites, WithSkipNonPoints())
+			return err
+		}},
+		{"Rebuild", func() error {
+			return mustNewDiagram(t, 100).Rebuild(sites, WithSkipNonPoints())
+		}},
+		{"NewPowerDiagram", func() error {
+			_, err := NewPowerDiagram(sites, make([]float64, len(sites)), WithSkipNonPoints())
+			return err
+		}},
+		{"NewDiagramFromParts", func() error {
+			vd := mustNewDiagram(t, 100)
+			_, err := NewDiagramFromParts(vd.Sites, vd.Vertices, vd.CellVertices,
+				vd.CellNeighbors, vd.CellOffsets, WithSkipNonPoints())
+			return err
+		}},
+		{"NewDiagramFromTriangulation", func() error {
+			dt, err := s2delaunay.NewTriangulation(sites)
+			if err != nil {
+				t.Fatalf("s2delaunay.NewTriangulation(...) error = %v, want nil", err)
+			}
+			_, err = NewDiagramFromTriangulation(dt, WithSkipNonPoints())
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); !errors.Is(err, ErrInvalidOption) {
+				t.Errorf("%s(..., WithSkipNonPoints()) error = %v, want errors.Is(err, %v)",
+					tt.name, err, ErrInvalidOption)
+			}
+		})
+	}
+}
+
+func TestNewDiagramFromGeoJSON_Errors(t *testing.T) {
+	point := func(coords string) string {
+		return `{"type": "FeatureCollection", "features": [{"type": "Feature", "geometry":
+			{"type": "Point", "coordinates": ` + coords + `}, "properties": null}]}`
+	}
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+	}{
+		{"malformed", `{"type": `, nil},
+		{"feature", `{"type": "Feature", "geometry": null, "properties": null}`, nil},
+		{"latitude", point("[0, 91]"), nil},
+		{"short position", point("[0]"), nil},
+		{"coordinates", point(`"north"`), nil},
+		{"insufficient sites", point("[0, 0]"), ErrInsufficientSites},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := NewDiagramFromGeoJSON(strings.NewReader(tt.data))
+			if err == nil {
+				t.Fatalf("NewDiagramFromGeoJSON(...) error = nil, want error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewDiagramFromGeoJSON(...) error = %v, want errors.Is(err, %v)", err,
+					tt.wantErr)
+			}
+		})
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDiagram_ToGeoJSON(b *testing.B) {
+	vd, err := NewDiagram(utils.GenerateRandomPoints(10000, 0))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	for b.Loop() {
+		if _, err := vd.ToGeoJSON(); err != nil {
+			b.Fatalf("vd.ToGeoJSON() error = %v, want nil", err)
+		}
+	}
+}
+
+// Helpers
+
+// checkGeoJSON decodes the output of vd.ToGeoJSON, checks it against RFC 7946 and the sites of
+// vd, and returns the exterior rings of the polygons of every feature, nil for a null geometry.
+func checkGeoJSON(t *testing.T, vd *Diagram, data []byte) [][][][2]float64 {
+	t.Helper()
+	var fc struct {
+		Type     string
+		Features []struct {
+			Type     string
+			Geometry *struct {
+				Type        string
+				Coordinates json.RawMessage
+			}
+			Properties struct {
+				Index    int
+				Lat, Lng float64
+			}
+		}
+	}
+	if err := json.Unmarshal(data, &fc); err != nil {
+		t.Fatalf("json.Unmarshal(vd.ToGeoJSON()) error = %v, want nil", err)
+	}
+	if fc.Type != "FeatureCollection" || len(fc.Features) != vd.NumCells() {
+		t.Fatalf("vd.ToGeoJSON() = %s with %d features, want FeatureCollection with %d", fc.Type,
+			len(fc.Features), vd.NumCells())
+	}
+
+	out := make([][][][2]float64, len(fc.Features))
+	for i, f := range fc.Features {
+		ll := s2.LatLngFromPoint(vd.Sites[i])
+		if f.Type != "Feature" || f.Properties.Index != i || f.Properties.Lat != ll.Lat.Degrees() ||
+			f.Properties.Lng != ll.Lng.Degrees() {
+			t.Errorf("feature %d = %s %+v, want Feature with index %d at %v", i, f.Type,
+				f.Properties, i, ll)
+		}
+		if f.Geometry == nil {
+			continue
+		}
+		var polygons [][][][2]float64
+		switch f.Geometry.Type {
+		case "Polygon":
+			var p [][][2]float64
+			if err := json.Unmarshal(f.Geometry.Coordinates, &p); err != nil {
+				t.Fatalf("feature %d Polygon coordinates error = %v, want nil", i, err)
+			}
+			polygons = append(polygons, p)
+		case "MultiPolygon":
+			if err := json.Unmarshal(f.Geometry.Coordinates, &polygons); err != nil {
+				t.Fatalf("feature %d MultiPolygon coordinates error = %v, want nil", i, err)
+			}
+			if len(polygons) < 2 {
+				t.Errorf("feature %d MultiPolygon has %d polygons, want at least 2", i,
+					len(polygons))
+			}
+		default:
+			t.Fatalf("feature %d geometry type = %q, want Polygon or MultiPolygon", i,
+				f.Geometry.Type)
+		}
+		for _, p := range polygons {
+			if len(p) != 1 {
+				t.Fatalf("feature %d polygon has %d rings, want 1", i, len(p))
+			}
+			ring := p[0]
+			if len(ring) < 4 || ring[0] != ring[len(ring)-1] {
+				t.Errorf("feature %d ring %v is not closed", i, ring)
+			}
+			for _, q := range ring {
+				if math.Abs(q[0]) > 180 || math.Abs(q[1]) > 90 {
+					t.Errorf("feature %d position %v out of range", i, q)
+				}
+			}
+			if area := planarArea(ring); area <= 0 {
+				t.Errorf("feature %d ring has planar area %v, want counterclockwise", i, area)
+			}
+			out[i] = append(out[i], ring)
+		}
+	}
+	return out
+}
+
+// planarArea returns the signed area of the closed ring in the longitude and latitude plane,
+// positive if it is counterclockwise.
+func planarArea(ring [][2]float64) float64 {
+	area := 0.0
+	for k := range len(ring) - 1 {
+		a, b := ring[k], ring[k+1]
+		area += a[0]*b[1] - b[0]*a[1]
+	}
+	return area / 2
+}
+
+// geoJSONRingArea returns the area in steradians enclosed by the closed counterclockwise ring
+// with straight edges in longitude and latitude, the integral of -sin(lat) d(lng) around it.
+func geoJSONRingArea(ring [][2]float64) float64 {
+	area := 0.0
+	for k := range len(ring) - 1 {
+		a, b := ring[k], ring[k+1]
+		sa, sb := math.Sin(a[1]*math.Pi/180), math.Sin(b[1]*math.Pi/180)
+		area -= (sa + sb) / 2 * (b[0] - a[0]) * math.Pi / 180
+	}
+	return area
+}
+
+// withFeatures returns stationsGeoJSON with the comma-separated features appended.
+func withFeatures(features string) string {
+	return strings.TrimSuffix(stationsGeoJSON, "]}") + ", " + features + "]}"
+}
+
+// stationsGeoJSON is a FeatureCollection of weather stations around the world, the last of
+// which is a MultiPoint.
+const stationsGeoJSON = `{"type": "FeatureCollection", "features": [
+	{"type": "Feature", "geometry": {"type": "Point", "coordinates": [-21.94, 64.13]},
+		"properties": {"name": "Reykjavik", "elevation": 52}},
+	{"type": "Feature", "geometry": {"type": "Point", "coordinates": [139.6917, 35.6895, 40]},
+		"properties": {"name": "Tokyo", "elevation": 25.2}},
+	{"type": "Feature", "geometry": {"type": "Point", "coordinates": [151.21, -33.87]},
+		"properties": {"name": "Sydney", "elevation": 39}},
+	{"type": "Feature", "geometry": {"type": "Point", "coordinates": [18.42, -33.92]},
+		"properties": {"name": "Cape Town", "elevation": 42}},
+	{"type": "Feature", "geometry": {"type": "Point", "coordinates": [-58.38, -34.6]},
+		"properties": {"name": "Buenos Aires", "elevation": 25}},
+	{"type": "Feature", "geometry": {"type": "Point", "coordinates": [-157.86, 21.31]},
+		"properties": {"name": "Honolulu", "elevation": 2}},
+	{"type": "Feature", "geometry": {"type": "Point", "coordinates": [-149.9, 61.22]},
+		"properties": {"name": "Anchorage", "elevation": 31}},
+	{"type": "Feature", "geometry": {"type": "Point", "coordinates": [36.82, -1.29]},
+		"properties": {"name": "Nairobi", "elevation": 1795}},
+	{"type": "Feature", "geometry": {"type": "Point", "coordinates": [166.67, -77.85]},
+		"properties": {"name": "McMurdo", "elevation": 24}},
+	{"type": "Feature", "geometry": {"type": "MultiPoint", "coordinates": [[15.63, 78.22],
+		[11.93, 78.92]]}, "properties": {"name": "Svalbard", "elevation": 28}}]}`
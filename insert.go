@@ -0,0 +1,382 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/golang/geo/s2"
+)
+
+// AddSite inserts the site p into the diagram and returns its index, which is NumCells() before
+// the call. Only the cells whose regions the new cell carves are recomputed: they are found by
+// walking from the cell containing p to the Voronoi vertices that are closer to p than to their
+// sites, which are replaced by the vertices of the new cell. The CSR arrays are rewritten once,
+// the freed vertex indices are reused and the new cell and its neighbors keep the ring order of
+// NewDiagram, so the result equals NewDiagram on the extended sites up to the numbering of the
+// vertices and the first vertex of every ring. Diagrams built with WithOrderIndependentOutput
+// or WithVertexMerging are rebuilt from scratch instead.
+//
+// It returns an error and leaves the diagram unchanged if p coincides with a site within eps,
+// if the vertices of the new cell are degenerate, or if the diagram is a power diagram.
+func (d *Diagram) AddSite(p s2.Point) (int, error) {
+	if d.weights != nil {
+		return -1, errors.New("s2voronoi: sites cannot be added to power diagrams")
+	}
+	if d.orderIndependent || d.mergeTol != 0 {
+		i := d.FindCellIndex(p)
+		if p.Sub(d.Sites[i].Vector).Norm() <= d.eps {
+			return -1, errorf(ErrDuplicateSites, "s2voronoi: site %v coincides with site %d", p, i)
+		}
+		nd, err := NewDiagram(append(slices.Clip(d.Sites), p), d.options()...)
+		if err != nil {
+			return -1, err
+		}
+		nd.siteData = extendSiteData(d.siteData, nd.NumCells())
+		*d = *nd
+		return d.NumCells() - 1, nil
+	}
+
+	ins := newSiteInserter(d)
+	i, err := ins.insert(p, d.FindCellIndex(p))
+	if err != nil {
+		return -1, err
+	}
+	ins.commit()
+	return i, nil
+}
+
+// cellRing holds the ring of a cell changed by a siteInserter.
+type cellRing struct {
+	vertices, neighbors []int
+}
+
+// siteInserter inserts sites into a diagram one at a time with the Bowyer-Watson algorithm
+// applied to the dual. The changed rings are kept in an overlay over the CSR arrays of the
+// diagram, which is left untouched until commit writes all of them back at once.
+type siteInserter struct {
+	d        *Diagram
+	sites    s2.PointVector
+	vertices s2.PointVector
+	rings    map[int]cellRing
+	free     []int
+}
+
+// newSiteInserter returns a siteInserter for d. It copies the vertices and extends the sites in
+// a new backing array, so d and the arrays shared with it are not modified.
+func newSiteInserter(d *Diagram) *siteInserter {
+	return &siteInserter{
+		d:        d,
+		sites:    slices.Clip(d.Sites),
+		vertices: slices.Clone(d.Vertices),
+		rings:    make(map[int]cellRing),
+	}
+}
+
+// ring returns the current ring of cell i.
+func (s *siteInserter) ring(i int) cellRing {
+	if r, ok := s.rings[i]; ok {
+		return r
+	}
+	if i >= s.d.NumCells() {
+		return cellRing{}
+	}
+	c := s.d.Cell(i)
+	return cellRing{vertices: c.VertexIndices(), neighbors: c.NeighborIndices()}
+}
+
+// locate returns the index of the current site nearest to p, walking greedily from the cell
+// hint over the current rings, see Diagram.locate.
+func (s *siteInserter) locate(p s2.Point, hint int) int {
+	cur := hint
+	curDot := p.Dot(s.sites[cur].Vector)
+	for {
+		next, nextDot := cur, curDot
+		for _, n := range s.ring(cur).neighbors {
+			if dot := p.Dot(s.sites[n].Vector); dot > nextDot {
+				next, nextDot = n, dot
+			}
+		}
+		if next == cur {
+			return cur
+		}
+		cur, curDot = next, nextDot
+	}
+}
+
+// insert adds the site p, whose nearest current site is found by walking from the cell hint,
+// and returns its index. On error nothing is changed.
+func (s *siteInserter) insert(p s2.Point, hint int) (int, error) {
+	nearest := s.locate(p, hint)
+	if p.Sub(s.sites[nearest].Vector).Norm() <= s.d.eps {
+		return -1, errorf(ErrDuplicateSites,
+			"s2voronoi: site %v coincides with site %d", p, nearest)
+	}
+	idx := len(s.sites)
+
+	// A vertex conflicts with p if p is closer to it than its sites, i.e. if the vertex lies in
+	// the new cell. Every vertex is decided once, so rounding cannot make the cells sharing it
+	// disagree. The conflicting vertices are connected and include a vertex of the nearest cell.
+	conflicts := make(map[int]bool)
+	conflict := func(v, i int) bool {
+		c, ok := conflicts[v]
+		if !ok {
+			c = p.Dot(s.vertices[v].Vector) > s.sites[i].Dot(s.vertices[v].Vector)
+			conflicts[v] = c
+		}
+		return c
+	}
+	var affected []int
+	seen := map[int]struct{}{nearest: {}}
+	queue := []int{nearest}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		r := s.ring(i)
+		hit := false
+		for k, v := range r.vertices {
+			if !conflict(v, i) {
+				continue
+			}
+			hit = true
+			num := len(r.vertices)
+			for _, j := range []int{r.neighbors[k], r.neighbors[(k+num-1)%num]} {
+				if _, ok := seen[j]; !ok {
+					seen[j] = struct{}{}
+					queue = append(queue, j)
+				}
+			}
+		}
+		if hit {
+			affected = append(affected, i)
+		}
+	}
+	if len(affected) < 3 {
+		return -1, errorf(ErrDegenerateInput, "s2voronoi: site %v does not carve a cell", p)
+	}
+
+	// The conflicting vertices of an affected cell form a single run, which is replaced by the
+	// vertex shared with the neighbor before the run, the edge to the new cell and the vertex
+	// shared with the neighbor after it. Those vertices are keyed by the neighbor pair.
+	type patch struct {
+		keep       cellRing
+		prev, next int
+	}
+	patches := make(map[int]patch, len(affected))
+	for _, i := range affected {
+		r := s.ring(i)
+		num := len(r.vertices)
+		start, count := -1, 0
+		for k, v := range r.vertices {
+			if conflicts[v] {
+				count++
+				if !conflicts[r.vertices[(k+num-1)%num]] {
+					start = k
+				}
+			}
+		}
+		if start < 0 || count == num {
+			return -1, errorf(ErrDegenerateInput, "s2voronoi: site %v swallows cell %d", p, i)
+		}
+		for k := range count {
+			if !conflicts[r.vertices[(start+k)%num]] {
+				return -1, errorf(ErrDegenerateInput,
+					"s2voronoi: site %v carves cell %d in several places", p, i)
+			}
+		}
+		end := (start + count) % num
+		var keep cellRing
+		for k := end; k != start; k = (k + 1) % num {
+			keep.vertices = append(keep.vertices, r.vertices[k])
+			keep.neighbors = append(keep.neighbors, r.neighbors[k])
+		}
+		patches[i] = patch{
+			keep: keep,
+			prev: r.neighbors[(start+num-1)%num],
+			next: r.neighbors[(end+num-1)%num],
+		}
+	}
+
+	// The ring of the new cell runs around the affected cells in reverse, entering every cell at
+	// the vertex shared with its next neighbor.
+	var ring cellRing
+	cur := affected[0]
+	for range affected {
+		pt, ok := patches[cur]
+		if !ok || patches[pt.prev].next != cur || slices.Contains(ring.neighbors, cur) {
+			return -1, errorf(ErrDegenerateInput,
+				"s2voronoi: site %v carves an inconsistent cell", p)
+		}
+		ring.neighbors = append(ring.neighbors, cur)
+		cur = pt.prev
+	}
+	if cur != affected[0] {
+		return -1, errorf(ErrDegenerateInput, "s2voronoi: site %v carves an inconsistent cell", p)
+	}
+
+	// The new vertices are the circumcenters of the new Delaunay triangles. They take the
+	// indices of the conflicting vertices first.
+	centers := make([]s2.Point, len(ring.neighbors))
+	for k, i := range ring.neighbors {
+		j := patches[i].next
+		cc := triangleCircumcenter(p, s.sites[i], s.sites[j])
+		if cc.Norm() <= s.d.eps {
+			return -1, errorf(ErrDegenerateInput,
+				"s2voronoi: circumcenter of sites %d, %d and %v is degenerate", i, j, p)
+		}
+		centers[k] = s2.Point{Vector: cc.Normalize()}
+	}
+	for v, c := range conflicts {
+		if c {
+			s.free = append(s.free, v)
+		}
+	}
+	slices.Sort(s.free)
+	keys := make(map[uint64]int, len(ring.neighbors))
+	for k, i := range ring.neighbors {
+		v := len(s.vertices)
+		if len(s.free) > 0 {
+			v, s.free = s.free[0], s.free[1:]
+			s.vertices[v] = centers[k]
+		} else {
+			s.vertices = append(s.vertices, centers[k])
+		}
+		keys[edgeKey(i, patches[i].next)] = v
+		ring.vertices = append(ring.vertices, v)
+	}
+
+	for _, i := range affected {
+		pt := patches[i]
+		pt.keep.vertices = append(pt.keep.vertices, keys[edgeKey(i, pt.prev)],
+			keys[edgeKey(i, pt.next)])
+		pt.keep.neighbors = append(pt.keep.neighbors, idx, pt.next)
+		s.rings[i] = pt.keep
+	}
+	s.rings[idx] = ring
+	s.sites = append(s.sites, p)
+	return idx, nil
+}
+
+// commit replaces the arrays of the diagram by the sites, vertices and rings of the inserter.
+func (s *siteInserter) commit() {
+	n := len(s.sites)
+	offsets := make([]int, n+1)
+	for i := range n {
+		offsets[i+1] = offsets[i] + len(s.ring(i).vertices)
+	}
+	cellVertices := make([]int, 0, offsets[n])
+	cellNeighbors := make([]int, 0, offsets[n])
+	for i := range n {
+		r := s.ring(i)
+		cellVertices = append(cellVertices, r.vertices...)
+		cellNeighbors = append(cellNeighbors, r.neighbors...)
+	}
+
+	s.d.Sites = s.sites
+	s.d.Vertices = s.vertices
+	s.d.CellVertices = cellVertices
+	s.d.CellNeighbors = cellNeighbors
+	s.d.CellOffsets = offsets
+	s.d.sources = siteSources{}
+	s.d.siteData = extendSiteData(s.d.siteData, len(s.sites))
+	s.d.invalidateCaches()
+}
+
+// AddSites inserts the sites ps into the diagram and returns their indices, which follow the
+// existing ones in the order of ps. The insertion cell of every site is located in the diagram
+// before any change, the sites are inserted into an overlay like by AddSite and the CSR arrays
+// are rewritten once, so the cost of the rewrite is shared by all of them. Diagrams built with
+// WithOrderIndependentOutput or WithVertexMerging are rebuilt from scratch once instead.
+//
+// It returns an error listing the indices in ps of all the sites that coincide within eps with
+// an existing site or with an earlier site of ps, and returns an error if the diagram is a power
+// diagram or a new cell is degenerate. On error the diagram is unchanged.
+func (d *Diagram) AddSites(ps s2.PointVector) ([]int, error) {
+	if d.weights != nil {
+		return nil, errors.New("s2voronoi: sites cannot be added to power diagrams")
+	}
+	if d.mergeTol != 0 {
+		return d.addSitesFromScratch(ps)
+	}
+	hints := make([]int, len(ps))
+	for k, p := range ps {
+		hints[k] = d.FindCellIndex(p)
+	}
+
+	ins := newSiteInserter(d)
+	indices := make([]int, 0, len(ps))
+	var duplicates []int
+	for k, p := range ps {
+		nearest := ins.locate(p, hints[k])
+		if p.Sub(ins.sites[nearest].Vector).Norm() <= d.eps {
+			duplicates = append(duplicates, k)
+			continue
+		}
+		i, err := ins.insert(p, nearest)
+		if err != nil {
+			return nil, err
+		}
+		indices = append(indices, i)
+	}
+	if len(duplicates) > 0 {
+		return nil, &kindError{
+			msg: fmt.Sprintf("s2voronoi: sites %v coincide with other sites", duplicates),
+			err: &DuplicateSitesError{Indices: duplicates},
+		}
+	}
+	if len(ps) == 0 {
+		return indices, nil
+	}
+
+	if d.orderIndependent {
+		nd, err := NewDiagram(ins.sites, d.options()...)
+		if err != nil {
+			return nil, err
+		}
+		nd.siteData = extendSiteData(d.siteData, nd.NumCells())
+		*d = *nd
+		return indices, nil
+	}
+	ins.commit()
+	return indices, nil
+}
+
+// addSitesFromScratch implements AddSites for diagrams built with WithVertexMerging, whose
+// vertices do not correspond to Delaunay triangles, by rebuilding the diagram on all sites.
+func (d *Diagram) addSitesFromScratch(ps s2.PointVector) ([]int, error) {
+	n := d.NumCells()
+	indices := make([]int, len(ps))
+	for k := range ps {
+		indices[k] = n + k
+	}
+	if len(ps) == 0 {
+		return indices, nil
+	}
+	opts := append(d.options(), WithNearDuplicateCheck())
+	nd, err := NewDiagram(append(slices.Clip(d.Sites), ps...), opts...)
+	var derr *DuplicateSitesError
+	if errors.As(err, &derr) {
+		var duplicates []int
+		for _, j := range derr.Indices {
+			if j >= n {
+				duplicates = append(duplicates, j-n)
+			}
+		}
+		if len(duplicates) > 0 {
+			return nil, &kindError{
+				msg: fmt.Sprintf("s2voronoi: sites %v coincide with other sites", duplicates),
+				err: &DuplicateSitesError{Indices: duplicates},
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	nd.siteData = extendSiteData(d.siteData, nd.NumCells())
+	*d = *nd
+	return indices, nil
+}
@@ -0,0 +1,299 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiagram_AddSite(t *testing.T) {
+	const n, added = 200, 50
+	points := utils.GenerateRandomPoints(n, 0)
+	all := slices.Clone(points)
+	want, err := NewDiagram(all)
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+
+	// The sites share the backing array of points, which must not be written.
+	vd, err := NewDiagram(points[:n-added])
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	for k := n - added; k < n; k++ {
+		p := all[k]
+		i, err := vd.AddSite(p)
+		if err != nil {
+			t.Fatalf("vd.AddSite(%v) error = %v, want nil", p, err)
+		}
+		if i != k {
+			t.Errorf("vd.AddSite(%v) = %d, want %d", p, i, k)
+		}
+		if err := vd.Validate(); err != nil {
+			t.Fatalf("vd.Validate() after adding site %d = %v, want nil", k, err)
+		}
+	}
+	if !slices.Equal(points, all) {
+		t.Errorf("vd.AddSite(...) modified the array of the initial sites")
+	}
+	if len(vd.Vertices) != 2*n-4 {
+		t.Errorf("len(vd.Vertices) = %d, want %d", len(vd.Vertices), 2*n-4)
+	}
+	checkSameDiagram(t, vd, want)
+
+	// The added cell is found by queries.
+	if got := vd.FindCellIndex(points[n-1]); got != n-1 {
+		t.Errorf("vd.FindCellIndex(points[%d]) = %d, want %d", n-1, got, n-1)
+	}
+}
+
+func TestDiagram_AddSite_OrderIndependent(t *testing.T) {
+	points := utils.GenerateRandomPoints(100, 0)
+	want, err := NewDiagram(points, WithOrderIndependentOutput())
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	vd, err := NewDiagram(slices.Clone(points[:99]), WithOrderIndependentOutput())
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	if i, err := vd.AddSite(points[99]); err != nil || i != 99 {
+		t.Fatalf("vd.AddSite(...) = %d, %v, want 99, nil", i, err)
+	}
+	if diff := cmp.Diff(want.Vertices, vd.Vertices); diff != "" {
+		t.Errorf("vd.Vertices mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want.CellVertices, vd.CellVertices); diff != "" {
+		t.Errorf("vd.CellVertices mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDiagram_AddSite_Errors(t *testing.T) {
+	points := utils.GenerateRandomPoints(100, 0)
+	weights := make([]float64, len(points))
+	weights[0] = 0.01
+	power, err := NewPowerDiagram(points, weights)
+	if err != nil {
+		t.Fatalf("NewPowerDiagram(...) error = %v, want nil", err)
+	}
+	tests := []struct {
+		name    string
+		diagram *Diagram
+		p       s2.Point
+	}{
+		{"duplicate", mustNewDiagram(t, 100), points[7]},
+		{
+			name:    "near duplicate",
+			diagram: mustNewDiagram(t, 100),
+			p:       s2.Point{Vector: points[7].Add(s2.Ortho(points[7]).Mul(1e-14)).Normalize()},
+		},
+		{"power diagram", power, s2.PointFromCoords(1, 2, 3)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := tt.diagram.clone()
+			if _, err := tt.diagram.AddSite(tt.p); err == nil {
+				t.Errorf("tt.diagram.AddSite(%v) error = nil, want non-nil", tt.p)
+			}
+			if diff := cmp.Diff(before.CellOffsets, tt.diagram.CellOffsets); diff != "" {
+				t.Errorf("tt.diagram.CellOffsets changed (-before +after):\n%s", diff)
+			}
+			if diff := cmp.Diff(before.Vertices, tt.diagram.Vertices); diff != "" {
+				t.Errorf("tt.diagram.Vertices changed (-before +after):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDiagram_AddSites(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []DiagramOption
+		n, k int
+	}{
+		{"random", nil, 2000, 300},
+		{"more new than old", nil, 50, 500},
+		{"empty", nil, 100, 0},
+		{"order independent", []DiagramOption{WithOrderIndependentOutput()}, 200, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points := utils.GenerateRandomPoints(tt.n+tt.k, 0)
+			want, err := NewDiagram(slices.Clone(points), tt.opts...)
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			vd, err := NewDiagram(slices.Clone(points[:tt.n]), tt.opts...)
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			got, err := vd.AddSites(points[tt.n:])
+			if err != nil {
+				t.Fatalf("vd.AddSites(...) error = %v, want nil", err)
+			}
+			wantIndices := make([]int, tt.k)
+			for k := range wantIndices {
+				wantIndices[k] = tt.n + k
+			}
+			if !slices.Equal(got, wantIndices) {
+				t.Errorf("vd.AddSites(...) = %v, want %v", got, wantIndices)
+			}
+			if err := vd.Validate(); err != nil {
+				t.Errorf("vd.Validate() = %v, want nil", err)
+			}
+			checkSameDiagram(t, vd, want)
+		})
+	}
+}
+
+func TestDiagram_AddSites_Errors(t *testing.T) {
+	points := utils.GenerateRandomPoints(100, 0)
+	weights := make([]float64, len(points))
+	weights[0] = 0.01
+	power, err := NewPowerDiagram(points, weights)
+	if err != nil {
+		t.Fatalf("NewPowerDiagram(...) error = %v, want nil", err)
+	}
+	fresh := utils.GenerateRandomPoints(3, 1)
+	tests := []struct {
+		name    string
+		diagram *Diagram
+		ps      s2.PointVector
+		// want is a substring of the error listing the offending inputs, or empty.
+		want string
+	}{
+		{
+			name:    "existing sites",
+			diagram: mustNewDiagram(t, 100),
+			ps:      s2.PointVector{fresh[0], points[3], fresh[1], points[9]},
+			want:    "[1 3]",
+		},
+		{
+			name:    "repeated input",
+			diagram: mustNewDiagram(t, 100),
+			ps:      s2.PointVector{fresh[0], fresh[1], fresh[0], fresh[2]},
+			want:    "[2]",
+		},
+		{"power diagram", power, fresh, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := tt.diagram.clone()
+			_, err := tt.diagram.AddSites(tt.ps)
+			if err == nil {
+				t.Fatalf("tt.diagram.AddSites(...) error = nil, want non-nil")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("tt.diagram.AddSites(...) error = %v, want it to list %s", err, tt.want)
+			}
+			if diff := cmp.Diff(before.Sites, tt.diagram.Sites); diff != "" {
+				t.Errorf("tt.diagram.Sites changed (-before +after):\n%s", diff)
+			}
+			if diff := cmp.Diff(before.CellOffsets, tt.diagram.CellOffsets); diff != "" {
+				t.Errorf("tt.diagram.CellOffsets changed (-before +after):\n%s", diff)
+			}
+		})
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDiagram_AddSite(b *testing.B) {
+	for _, n := range []int{1e+3, 1e+4} {
+		b.Run(fmt.Sprintf("N%d", n), func(b *testing.B) {
+			points := utils.GenerateRandomPoints(n+1, 0)
+			vd, err := NewDiagram(points[:n])
+			if err != nil {
+				b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			b.ReportAllocs()
+			for b.Loop() {
+				d := *vd
+				if _, err := d.AddSite(points[n]); err != nil {
+					b.Fatalf("d.AddSite(...) error = %v, want nil", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDiagram_AddSites(b *testing.B) {
+	const n, k = 1e+4, 300
+	points := utils.GenerateRandomPoints(n+k, 0)
+	vd, err := NewDiagram(points[:n])
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	b.Run("AddSites", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			d := *vd
+			if _, err := d.AddSites(points[n:]); err != nil {
+				b.Fatalf("d.AddSites(...) error = %v, want nil", err)
+			}
+		}
+	})
+	b.Run("AddSite", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			d := *vd
+			for _, p := range points[n:] {
+				if _, err := d.AddSite(p); err != nil {
+					b.Fatalf("d.AddSite(...) error = %v, want nil", err)
+				}
+			}
+		}
+	})
+}
+
+// Helpers
+
+// checkSameDiagram checks that got equals want up to the numbering of the vertices and the
+// first vertex of every ring.
+func checkSameDiagram(t *testing.T, got, want *Diagram) {
+	t.Helper()
+	if diff := cmp.Diff(want.Sites, got.Sites); diff != "" {
+		t.Fatalf("Sites mismatch (-want +got):\n%s", diff)
+	}
+	for i := range want.NumCells() {
+		gc, wc := got.Cell(i), want.Cell(i)
+		num := wc.NumVertices()
+		if gc.NumVertices() != num {
+			t.Errorf("cell %d has %d vertices, want %d", i, gc.NumVertices(), num)
+			continue
+		}
+		if num == 0 {
+			continue
+		}
+		shift := slices.Index(gc.NeighborIndices(), wc.NeighborIndices()[0])
+		if shift < 0 {
+			t.Errorf("cell %d neighbors = %v, want %v", i, gc.NeighborIndices(),
+				wc.NeighborIndices())
+			continue
+		}
+		for k := range num {
+			gk := (k + shift) % num
+			if gc.NeighborIndices()[gk] != wc.NeighborIndices()[k] {
+				t.Errorf("cell %d neighbors = %v, want %v up to rotation", i,
+					gc.NeighborIndices(), wc.NeighborIndices())
+				break
+			}
+			if dist := gc.Vertex(gk).Distance(wc.Vertex(k)); dist.Radians() > 1e-12 {
+				t.Errorf("cell %d vertex %d = %v, want %v", i, k, gc.Vertex(gk), wc.Vertex(k))
+			}
+		}
+	}
+	if math.Abs(got.TotalArea()-want.TotalArea()) > 1e-12 {
+		t.Errorf("TotalArea() = %v, want %v", got.TotalArea(), want.TotalArea())
+	}
+}
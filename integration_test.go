@@ -0,0 +1,103 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+const (
+	// numMajorAirports is the number of airports in utils.LoadEmbeddedAirports before the
+	// secondary ones.
+	numMajorAirports = 451
+	// airportTolerance merges the secondary airports, and no two major ones.
+	airportTolerance = s1.Angle(100 / 6371.0)
+)
+
+// queryCities are city centers with the index of their nearest airport in
+// utils.LoadEmbeddedAirports, and the index of the cell containing them after relaxing the
+// airport diagram 3 times. Relaxation drags the sites out of dense regions, so a city may end up
+// in the cell of a neighboring airport.
+var queryCities = []struct {
+	name    string
+	latLng  s2.LatLng
+	airport int
+	relaxed int
+}{
+	{"Chicago", s2.LatLngFromDegrees(41.88, -87.63), 2, 2},         // ORD
+	{"Buenos Aires", s2.LatLngFromDegrees(-34.60, -58.38), 28, 28}, // EZE
+	{"Paris", s2.LatLngFromDegrees(48.86, 2.35), 37, 37},           // CDG
+	{"Nairobi", s2.LatLngFromDegrees(-1.29, 36.82), 65, 65},        // NBO
+	{"Tokyo", s2.LatLngFromDegrees(35.68, 139.69), 101, 102},       // NRT, KIX
+	{"Sydney", s2.LatLngFromDegrees(-33.87, 151.21), 110, 112},     // SYD, BNE
+}
+
+func TestPipeline_Airports(t *testing.T) {
+	airports := utils.LoadEmbeddedAirports()
+	vd, err := NewDiagram(airports, WithDeduplication(airportTolerance))
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	if err := vd.Validate(); err != nil {
+		t.Fatalf("vd.Validate() error = %v, want nil", err)
+	}
+	// The secondary airports at the end are merged into the major airports of their cities.
+	if vd.NumCells() != numMajorAirports {
+		t.Fatalf("vd.NumCells() = %d, want %d", vd.NumCells(), numMajorAirports)
+	}
+	for i := range vd.NumCells() {
+		for _, k := range vd.SourceIndex(i)[1:] {
+			if k < numMajorAirports || airports[k].Distance(vd.Sites[i]) > airportTolerance {
+				t.Errorf("vd.SourceIndex(%d) holds airport %d, want a secondary airport within %v",
+					i, k, airportTolerance)
+			}
+		}
+	}
+	for _, c := range queryCities {
+		if got := vd.FindCellIndex(s2.PointFromLatLng(c.latLng)); got != c.airport {
+			t.Errorf("vd.FindCellIndex(%s) = %v, want %v", c.name, got, c.airport)
+		}
+	}
+
+	if _, err := vd.Relax(3); err != nil {
+		t.Fatalf("vd.Relax(3) error = %v, want nil", err)
+	}
+	if err := vd.Validate(); err != nil {
+		t.Fatalf("vd.Validate() after Relax error = %v, want nil", err)
+	}
+	for _, c := range queryCities {
+		p := s2.PointFromLatLng(c.latLng)
+		if got := vd.FindCellIndex(p); got != c.relaxed || got != nearestSite(vd, p) {
+			t.Errorf("vd.FindCellIndex(%s) after Relax = %v, want %v", c.name, got, c.relaxed)
+		}
+	}
+
+	data, err := vd.ToGeoJSON()
+	if err != nil {
+		t.Fatalf("vd.ToGeoJSON() error = %v, want nil", err)
+	}
+	var fc struct {
+		Features []struct {
+			Properties geoJSONProperties `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(data, &fc); err != nil {
+		t.Fatalf("json.Unmarshal(vd.ToGeoJSON()) error = %v, want nil", err)
+	}
+	if len(fc.Features) != vd.NumCells() {
+		t.Fatalf("vd.ToGeoJSON() has %d features, want %d", len(fc.Features), vd.NumCells())
+	}
+	for _, c := range queryCities {
+		if got := fc.Features[c.relaxed].Properties.Index; got != c.relaxed {
+			t.Errorf("vd.ToGeoJSON() feature %d has index %d, want %d", c.relaxed, got,
+				c.relaxed)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+// Package fixtures provides a corpus of degenerate and regression inputs for tests.
+//
+// Every fixture is a text file in testdata with one point per line given as "x y z"
+// coordinates; lines starting with '#' are comments. New algorithms should be exercised
+// against all of them:
+//
+//	for _, name := range fixtures.Names() {
+//		points := fixtures.Load(name)
+//		...
+//	}
+package fixtures
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/golang/geo/r3"
+	"github.com/golang/geo/s2"
+)
+
+//go:embed testdata/*.txt
+var files embed.FS
+
+// Names returns the names of all fixtures in lexical order.
+func Names() []string {
+	entries, err := files.ReadDir("testdata")
+	if err != nil {
+		panic(fmt.Sprintf("fixtures: %v", err))
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".txt"))
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Load returns the points of the named fixture.
+// It panics if the fixture does not exist or is malformed.
+func Load(name string) s2.PointVector {
+	data, err := files.ReadFile(path.Join("testdata", name+".txt"))
+	if err != nil {
+		panic(fmt.Sprintf("fixtures: unknown fixture %q", name))
+	}
+
+	var points s2.PointVector
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		var v r3.Vector
+		if _, err := fmt.Sscan(text, &v.X, &v.Y, &v.Z); err != nil {
+			panic(fmt.Sprintf("fixtures: %s:%d: %v", name, line, err))
+		}
+		points = append(points, s2.Point{Vector: v})
+	}
+	return points
+}
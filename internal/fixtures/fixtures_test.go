@@ -0,0 +1,36 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package fixtures
+
+import (
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	names := Names()
+	if len(names) == 0 {
+		t.Fatalf("Names() is empty, want fixtures")
+	}
+	for _, name := range names {
+		points := Load(name)
+		if len(points) < 4 {
+			t.Errorf("Load(%q) has %d points, want at least 4", name, len(points))
+		}
+		for i, p := range points {
+			if !p.IsUnit() {
+				t.Errorf("Load(%q)[%d] = %v, want unit length", name, i, p)
+			}
+		}
+	}
+}
+
+func TestLoad_Panic(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Load(\"missing\") did not panic, want panic")
+		}
+	}()
+	Load("missing")
+}
@@ -0,0 +1,258 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+// Package lnglat converts rings of points on the sphere into polygons in longitude and latitude
+// that flat consumers, such as GeoJSON readers and map renderers, draw correctly.
+
+package lnglat
+
+import (
+	"math"
+	"slices"
+
+	"github.com/golang/geo/r3"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// Position is a position in longitude and latitude in degrees, as in GeoJSON.
+type Position = [2]float64
+
+// geoPosition is a point of a ring in degrees, with its point for the crossing of the
+// antimeridian.
+type geoPosition struct {
+	lng, lat float64
+	p        s2.Point
+}
+
+// Polygons returns the polygons in longitude and latitude of the ring of points, which is CCW
+// when looking out of the sphere like the rings of Voronoi cells and Delaunay triangles, with
+// the edges subdivided to maxSegment if it is positive. Each polygon is a single closed
+// counterclockwise ring. A ring crossing the antimeridian is cut along it into several
+// polygons, and a ring around a pole is bounded by the pole's line of latitude. It returns nil
+// for an empty ring or one that does not enclose any area.
+func Polygons(points s2.PointVector, maxSegment s1.Angle) [][]Position {
+	rings := Rings(points, maxSegment)
+	for k, r := range rings {
+		rings[k] = CloseRing(r)
+	}
+	return rings
+}
+
+// Rings returns the polygons of the ring of points like Polygons, but each as a ring in the
+// order of the points, not closed. A thin ring near a pole may be clockwise in longitude and
+// latitude although its points are CCW on the sphere.
+func Rings(points s2.PointVector, maxSegment s1.Angle) [][]Position {
+	if len(points) == 0 {
+		return nil
+	}
+	ring := geoRing(points, maxSegment)
+
+	// Unwrap the longitudes so that the ring is continuous, inserting a position wherever it
+	// crosses the antimeridian. offset is the multiple of 360 added to the longitudes.
+	path := make([]Position, 0, len(ring)+4)
+	offset := 0.0
+	crossing := -1
+	for k, g := range ring {
+		h := ring[(k+1)%len(ring)]
+		path = append(path, Position{g.lng + offset, g.lat})
+		switch delta := h.lng - g.lng; {
+		case delta > 180:
+			path = append(path, Position{offset - 180, antimeridianLatitude(g, h)})
+			offset -= 360
+		case delta < -180:
+			path = append(path, Position{offset + 180, antimeridianLatitude(g, h)})
+			offset += 360
+		default:
+			continue
+		}
+		if crossing == -1 {
+			crossing = len(path) - 1
+		}
+	}
+	// A clockwise ring around a pole winds once around the axis, westwards around the north pole
+	// and eastwards around the south pole. It is started at the antimeridian, so that it spans
+	// exactly one strip, and closed along the line of latitude of the pole.
+	if offset != 0 {
+		for k := range path[:crossing] {
+			path[k][0] += offset
+		}
+		path = slices.Concat(path[crossing:], path[:crossing])
+		first := path[0]
+		pole := math.Copysign(90, -offset)
+		path = append(path, Position{first[0] + offset, first[1]},
+			Position{first[0] + offset, pole}, Position{first[0], pole})
+	}
+
+	// Cut the path into the strips of width 360 it overlaps and shift them into [-180, 180].
+	minLng, maxLng := path[0][0], path[0][0]
+	for _, q := range path {
+		minLng, maxLng = min(minLng, q[0]), max(maxLng, q[0])
+	}
+	var rings [][]Position
+	for s := math.Floor((minLng + 180) / 360); s*360-180 < maxLng; s++ {
+		lo, hi := s*360-180, s*360+180
+		piece := clipRange(path, lo, hi)
+		for k := range piece {
+			piece[k][0] = min(max(piece[k][0]-s*360, -180), 180)
+		}
+		if r, _ := compactRing(piece); r != nil {
+			rings = append(rings, r)
+		}
+	}
+	return rings
+}
+
+// Lines returns the polyline of points in longitude and latitude, with the edges subdivided to
+// maxSegment if it is positive. A polyline crossing the antimeridian is cut along it into
+// several lines. It returns nil for fewer than two points.
+func Lines(points s2.PointVector, maxSegment s1.Angle) [][]Position {
+	if len(points) < 2 {
+		return nil
+	}
+	path := geoPath(points, maxSegment, false)
+	var lines [][]Position
+	line := []Position{{path[0].lng, path[0].lat}}
+	for k, g := range path[:len(path)-1] {
+		h := path[k+1]
+		if delta := h.lng - g.lng; math.Abs(delta) > 180 {
+			lat := antimeridianLatitude(g, h)
+			side := math.Copysign(180, g.lng)
+			lines = append(lines, append(line, Position{side, lat}))
+			line = []Position{{-side, lat}}
+		}
+		line = append(line, Position{h.lng, h.lat})
+	}
+	return append(lines, line)
+}
+
+// CloseRing returns the positions as a closed counterclockwise ring without repeated
+// positions, or nil if they do not enclose any area.
+func CloseRing(positions []Position) []Position {
+	positions, area := compactRing(positions)
+	if positions == nil {
+		return nil
+	}
+	if area < 0 {
+		slices.Reverse(positions)
+	}
+	return append(positions, positions[0])
+}
+
+// compactRing returns the positions of a ring without repeated positions and twice its signed
+// area, or nil if they do not enclose any area.
+func compactRing(positions []Position) ([]Position, float64) {
+	positions = slices.Compact(positions)
+	for len(positions) > 1 && positions[0] == positions[len(positions)-1] {
+		positions = positions[:len(positions)-1]
+	}
+	if len(positions) < 3 {
+		return nil, 0
+	}
+	// The shoelace formula relative to the first position is exactly zero for positions on a
+	// line of longitude, such as the parts of a ring along the antimeridian.
+	o, area := positions[0], 0.0
+	for k, a := range positions {
+		b := positions[(k+1)%len(positions)]
+		area += (a[0]-o[0])*(b[1]-o[1]) - (b[0]-o[0])*(a[1]-o[1])
+	}
+	if area == 0 {
+		return nil, 0
+	}
+	return positions, area
+}
+
+// geoRing returns the positions of the ring of points, with the edges subdivided to maxSegment
+// if it is positive. A point at a pole, whose longitude is undefined, is replaced by positions
+// at the longitudes of the points before and after it.
+func geoRing(vertices s2.PointVector, maxSegment s1.Angle) []geoPosition {
+	return geoPath(vertices, maxSegment, true)
+}
+
+// geoPath returns the positions of the ring or, if closed is false, the polyline of points like
+// geoRing. A polyline starting or ending at a pole takes the longitude of its only neighbor
+// there.
+func geoPath(vertices s2.PointVector, maxSegment s1.Angle, closed bool) []geoPosition {
+	var points s2.PointVector
+	n := len(vertices)
+	for k := range n {
+		a := vertices[k]
+		points = append(points, a)
+		if !closed && k == n-1 {
+			break
+		}
+		b := vertices[(k+1)%n]
+		if maxSegment > 0 {
+			num := math.Ceil(float64(a.Distance(b) / maxSegment))
+			for j := 1.0; j < num; j++ {
+				points = append(points, s2.Interpolate(j/num, a, b))
+			}
+		}
+	}
+
+	ring := make([]geoPosition, 0, len(points)+2)
+	for k, p := range points {
+		ll := s2.LatLngFromPoint(p)
+		if p.X != 0 || p.Y != 0 {
+			ring = append(ring, geoPosition{ll.Lng.Degrees(), ll.Lat.Degrees(), p})
+			continue
+		}
+		prev, next := (k+len(points)-1)%len(points), (k+1)%len(points)
+		if !closed && k == 0 {
+			prev = next
+		} else if !closed && k == len(points)-1 {
+			next = prev
+		}
+		prevLng := s2.LatLngFromPoint(points[prev]).Lng.Degrees()
+		nextLng := s2.LatLngFromPoint(points[next]).Lng.Degrees()
+		ring = append(ring, geoPosition{prevLng, ll.Lat.Degrees(), p})
+		if nextLng != prevLng {
+			ring = append(ring, geoPosition{nextLng, ll.Lat.Degrees(), p})
+		}
+	}
+	return ring
+}
+
+// antimeridianLatitude returns the latitude in degrees at which the great circle arc from g to
+// h crosses the antimeridian.
+func antimeridianLatitude(g, h geoPosition) float64 {
+	if g.p == h.p {
+		return g.lat
+	}
+	// The arc meets the half-plane y = 0, x < 0 along the intersection of its plane with y = 0.
+	n := g.p.Cross(h.p.Vector).Normalize()
+	v := r3.Vector{X: -n.Z, Y: 0, Z: n.X}
+	if v.X > 0 {
+		v = v.Mul(-1)
+	}
+	if v.X > -1e-15 {
+		// The arc runs along a meridian through a pole.
+		return math.Copysign(90, g.p.Z+h.p.Z)
+	}
+	return s1.Angle(math.Atan2(v.Z, -v.X)).Degrees()
+}
+
+// clipRange returns the part of the closed path with longitudes in [lo, hi], by clipping
+// against both bounds in turn.
+func clipRange(path []Position, lo, hi float64) []Position {
+	path = clip(path, lo, 1)
+	return clip(path, hi, -1)
+}
+
+// clip returns the part of the closed path with sign*(lng - bound) >= 0.
+func clip(path []Position, bound, sign float64) []Position {
+	var out []Position
+	for k, a := range path {
+		b := path[(k+1)%len(path)]
+		da, db := sign*(a[0]-bound), sign*(b[0]-bound)
+		if da >= 0 {
+			out = append(out, a)
+		}
+		if (da < 0 && db > 0) || (da > 0 && db < 0) {
+			t := da / (da - db)
+			out = append(out, Position{bound, a[1] + t*(b[1]-a[1])})
+		}
+	}
+	return out
+}
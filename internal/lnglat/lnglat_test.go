@@ -0,0 +1,137 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package lnglat
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+)
+
+func TestPolygons(t *testing.T) {
+	tests := []struct {
+		name string
+		// lngLats are the positions of the ring in degrees, CCW when looking out of the sphere.
+		lngLats      []Position
+		wantPolygons int
+		// wantArea is the area of the polygons in square degrees, within tol.
+		wantArea, tol float64
+	}{
+		{"empty", nil, 0, 0, 0},
+		{"square", []Position{{10, 10}, {10, -10}, {-10, -10}, {-10, 10}}, 1, 400, 1e-9},
+		// The edges cross the antimeridian as great circle arcs, beyond latitude 10.
+		{"antimeridian", []Position{{-170, 10}, {-170, -10}, {170, -10}, {170, 10}}, 2, 400, 5},
+		// The edge across the antimeridian bulges towards the pole, and the others are straight.
+		{"north pole", []Position{{0, 80}, {-120, 80}, {120, 80}}, 1, 360 * 10, 400},
+		{"south pole", []Position{{0, -80}, {120, -80}, {-120, -80}}, 1, 360 * 10, 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points := make(s2.PointVector, len(tt.lngLats))
+			for k, q := range tt.lngLats {
+				points[k] = s2.PointFromLatLng(s2.LatLngFromDegrees(q[1], q[0]))
+			}
+			got := Polygons(points, 0)
+			if len(got) != tt.wantPolygons {
+				t.Fatalf("Polygons(...) = %v, want %d polygons", got, tt.wantPolygons)
+			}
+			area := 0.0
+			for _, polygon := range got {
+				if polygon[0] != polygon[len(polygon)-1] {
+					t.Errorf("Polygons(...) polygon %v is not closed", polygon)
+				}
+				a := shoelace(polygon)
+				if a <= 0 {
+					t.Errorf("Polygons(...) polygon %v is not counterclockwise", polygon)
+				}
+				area += a
+			}
+			if math.Abs(area-tt.wantArea) > tt.tol {
+				t.Errorf("Polygons(...) area = %v, want %v", area, tt.wantArea)
+			}
+		})
+	}
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		lngLats []Position
+		want    [][]Position
+	}{
+		{"single", []Position{{10, 0}}, nil},
+		{"straight", []Position{{10, 0}, {20, 0}, {20, 5}},
+			[][]Position{{{10, 0}, {20, 0}, {20, 5}}}},
+		{"eastwards", []Position{{170, 0}, {-170, 0}}, [][]Position{{{170, 0}, {180, 0}},
+			{{-180, 0}, {-170, 0}}}},
+		{"westwards", []Position{{-170, 0}, {170, 0}}, [][]Position{{{-170, 0}, {-180, 0}},
+			{{180, 0}, {170, 0}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points := make(s2.PointVector, len(tt.lngLats))
+			for k, q := range tt.lngLats {
+				points[k] = s2.PointFromLatLng(s2.LatLngFromDegrees(q[1], q[0]))
+			}
+			got := Lines(points, 0)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Lines(...) = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if len(got[i]) != len(tt.want[i]) {
+					t.Fatalf("Lines(...) = %v, want %v", got, tt.want)
+				}
+				for k := range got[i] {
+					g, w := got[i][k], tt.want[i][k]
+					if math.Abs(g[0]-w[0]) > 1e-9 || math.Abs(g[1]-w[1]) > 1e-9 {
+						t.Fatalf("Lines(...) = %v, want %v", got, tt.want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCloseRing(t *testing.T) {
+	tests := []struct {
+		name      string
+		positions []Position
+		want      []Position
+	}{
+		{"empty", nil, nil},
+		{"line", []Position{{0, 0}, {1, 1}, {2, 2}, {0, 0}}, nil},
+		{"meridian", []Position{{180, 0}, {180, 10}, {180, -5}}, nil},
+		{"counterclockwise", []Position{{0, 0}, {1, 0}, {1, 1}},
+			[]Position{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+		{"clockwise", []Position{{0, 0}, {1, 1}, {1, 1}, {1, 0}, {0, 0}},
+			[]Position{{1, 0}, {1, 1}, {0, 0}, {1, 0}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CloseRing(tt.positions)
+			if len(got) != len(tt.want) {
+				t.Fatalf("CloseRing(%v) = %v, want %v", tt.positions, got, tt.want)
+			}
+			for k := range got {
+				if got[k] != tt.want[k] {
+					t.Fatalf("CloseRing(%v) = %v, want %v", tt.positions, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// Helpers
+
+// shoelace returns the signed area of the closed ring.
+func shoelace(ring []Position) float64 {
+	area := 0.0
+	for k := range len(ring) - 1 {
+		a, b := ring[k], ring[k+1]
+		area += a[0]*b[1] - b[0]*a[1]
+	}
+	return area / 2
+}
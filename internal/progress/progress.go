@@ -0,0 +1,33 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+// Package progress splits the phases of a construction into chunks that are reported to the
+// progress functions of s2delaunay.WithProgress and s2voronoi.WithProgress.
+package progress
+
+// Reports is the number of reports of a phase after its start.
+const Reports = 100
+
+// Func is the function a phase is reported to, with the number of items done out of the total
+// of the phase.
+type Func = func(phase string, done, total int)
+
+// ForEachChunk calls do for consecutive chunks [start, end) of the items [0, total), and report
+// after every chunk, until do returns an error. Without report, do is called once for all
+// items.
+func ForEachChunk(total int, report Func, phase string, do func(start, end int) error) error {
+	if report == nil {
+		return do(0, total)
+	}
+	report(phase, 0, total)
+	step := max(total/Reports, 1)
+	for start := 0; start < total; start += step {
+		end := min(start+step, total)
+		if err := do(start, end); err != nil {
+			return err
+		}
+		report(phase, end, total)
+	}
+	return nil
+}
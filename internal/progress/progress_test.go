@@ -0,0 +1,85 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package progress
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestForEachChunk(t *testing.T) {
+	tests := []struct {
+		name        string
+		total       int
+		wantReports int
+	}{
+		{"empty", 0, 1},
+		{"fewer than reports", 7, 8},
+		{"multiple of reports", 1000, Reports + 1},
+		// The remainder of the division is an extra chunk.
+		{"remainder", 1010, Reports + 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			covered := 0
+			var reports []int
+			report := func(phase string, done, total int) {
+				if phase != "phase" || total != tt.total {
+					t.Errorf("report(%q, %d, %d), want phase %q and total %d", phase, done, total,
+						"phase", tt.total)
+				}
+				reports = append(reports, done)
+			}
+			err := ForEachChunk(tt.total, report, "phase", func(start, end int) error {
+				if start != covered || end <= start {
+					t.Errorf("do(%d, %d), want a chunk starting at %d", start, end, covered)
+				}
+				covered = end
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("ForEachChunk(...) error = %v, want nil", err)
+			}
+			if covered != tt.total {
+				t.Errorf("ForEachChunk(...) covered %d items, want %d", covered, tt.total)
+			}
+			if len(reports) != tt.wantReports || reports[0] != 0 ||
+				reports[len(reports)-1] != tt.total {
+				t.Errorf("ForEachChunk(...) reports = %v, want %d from 0 to %d", reports,
+					tt.wantReports, tt.total)
+			}
+		})
+	}
+}
+
+func TestForEachChunk_NoReport(t *testing.T) {
+	calls := 0
+	err := ForEachChunk(1000, nil, "phase", func(start, end int) error {
+		calls++
+		if start != 0 || end != 1000 {
+			t.Errorf("do(%d, %d), want do(0, 1000)", start, end)
+		}
+		return nil
+	})
+	if err != nil || calls != 1 {
+		t.Errorf("ForEachChunk(...) = %v after %d calls, want nil after 1", err, calls)
+	}
+}
+
+func TestForEachChunk_Error(t *testing.T) {
+	errStop := errors.New("stop")
+	calls := 0
+	report := func(string, int, int) {}
+	err := ForEachChunk(1000, report, "phase", func(start, end int) error {
+		calls++
+		if calls == 3 {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) || calls != 3 {
+		t.Errorf("ForEachChunk(...) = %v after %d calls, want %v after 3", err, calls, errStop)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/s2"
+)
+
+// InterpolateIDW returns the inverse distance weighted average of the values at the k sites
+// nearest to p, where the weight of a site is its geodesic distance to p raised to -power.
+// If p coincides with a site within eps, the value of that site is returned exactly, see
+// FindCellIndex. Sites of empty cells are not considered, and k is clamped to the number of
+// the other sites.
+// It panics if len(values) differs from the number of cells, k is less than 1, or power is
+// negative.
+func (d *Diagram) InterpolateIDW(p s2.Point, values []float64, k int, power float64) float64 {
+	if len(values) != d.NumCells() {
+		panic(fmt.Sprintf("s2voronoi: %d values for %d cells", len(values), d.NumCells()))
+	}
+	if k < 1 {
+		panic(fmt.Sprintf("s2voronoi: k must be at least 1, got %d", k))
+	}
+	if power < 0 {
+		panic(fmt.Sprintf("s2voronoi: power must be non-negative, got %v", power))
+	}
+
+	nearest := d.FindCellIndex(p)
+	if p.Sub(d.Sites[nearest].Vector).Norm() <= d.eps {
+		return values[nearest]
+	}
+	k = min(k, d.NumCells())
+
+	var sum, weights compensatedSum
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, i := range d.nearestSites(p, nearest, k) {
+		w := math.Pow(p.Distance(d.Sites[i]).Radians(), -power)
+		sum.Add(w * values[i])
+		weights.Add(w)
+		lo, hi = min(lo, values[i]), max(hi, values[i])
+	}
+	// Rounding may push the quotient out of the range of the averaged values.
+	return min(max(sum.Value()/weights.Value(), lo), hi)
+}
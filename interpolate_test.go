@@ -0,0 +1,81 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"math"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s2"
+)
+
+func TestDiagram_InterpolateIDW(t *testing.T) {
+	vd := mustNewDiagram(t, 200)
+	// The field is the height above the equatorial plane.
+	values := make([]float64, vd.NumCells())
+	for i, s := range vd.Sites {
+		values[i] = s.Z
+	}
+
+	for _, k := range []int{1, 3, 8} {
+		for _, p := range utils.GenerateRandomPoints(200, 1) {
+			got := vd.InterpolateIDW(p, values, k, 2)
+			near := bruteForceNearestSites(vd, p, k)
+			lo, hi := values[near[0]], values[near[0]]
+			for _, i := range near {
+				lo, hi = min(lo, values[i]), max(hi, values[i])
+			}
+			if got < lo || got > hi {
+				t.Errorf("vd.InterpolateIDW(%v, values, %d, 2) = %v, want in [%v, %v]", p, k, got,
+					lo, hi)
+			}
+			if k == 1 && got != values[near[0]] {
+				t.Errorf("vd.InterpolateIDW(%v, values, 1, 2) = %v, want %v", p, got,
+					values[near[0]])
+			}
+		}
+	}
+
+	// A k beyond the number of sites averages all of them.
+	p := s2.PointFromCoords(0.3, -0.2, 0.9)
+	want := vd.InterpolateIDW(p, values, vd.NumCells(), 2)
+	if got := vd.InterpolateIDW(p, values, math.MaxInt, 2); got != want {
+		t.Errorf("vd.InterpolateIDW(%v, values, math.MaxInt, 2) = %v, want %v", p, got, want)
+	}
+
+	for i, s := range vd.Sites {
+		if got := vd.InterpolateIDW(s, values, 8, 2); got != values[i] {
+			t.Errorf("vd.InterpolateIDW(vd.Sites[%d], values, 8, 2) = %v, want %v", i, got,
+				values[i])
+		}
+	}
+}
+
+func TestDiagram_InterpolateIDW_Panic(t *testing.T) {
+	vd := mustNewDiagram(t, 10)
+	p := vd.Sites[0]
+	tests := []struct {
+		name   string
+		values []float64
+		k      int
+		power  float64
+	}{
+		{"short values", make([]float64, 9), 3, 2},
+		{"zero k", make([]float64, 10), 0, 2},
+		{"negative power", make([]float64, 10), 3, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("vd.InterpolateIDW(p, values, %d, %v) did not panic, want panic",
+						tt.k, tt.power)
+				}
+			}()
+			vd.InterpolateIDW(p, tt.values, tt.k, tt.power)
+		})
+	}
+}
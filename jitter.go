@@ -0,0 +1,78 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"context"
+	"math"
+
+	"github.com/2dChan/s2voronoi/s2delaunay"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// WithJitter breaks the symmetries of regular inputs, e.g. latitude-longitude grids or the
+// vertices of a polyhedron, whose many cocircular sites make the triangulation arbitrary. Every
+// site is moved along the sphere by a pseudo-random angle of at most maxAngle in a pseudo-random
+// direction, both derived from the site and the seed, and the topology of the diagram is that
+// of the moved sites. Sites and Vertices are those of the given sites, so the cells of
+// cocircular sites meet at a common vertex through edges of zero length. The result depends on
+// the seed only, and WithOrderIndependentOutput keeps working. maxAngle must be positive and
+// should be far smaller than the distance between sites, since the topology is only Delaunay for
+// the given sites within it. The option applies to the construction only and is not kept by
+// Relax and the methods editing sites.
+func WithJitter(maxAngle s1.Angle, seed int64) DiagramOption {
+	return func(o *DiagramOptions) error {
+		if !(maxAngle > 0) || math.IsInf(float64(maxAngle), 0) {
+			return errorf(ErrInvalidOption, "s2voronoi: jitter angle must be positive and finite, "+
+				"got %v", maxAngle)
+		}
+		o.Jitter = maxAngle
+		o.JitterSeed = seed
+		return nil
+	}
+}
+
+// triangulate stores the Delaunay triangulation of the sites in dt with b, of the sites jittered
+// as selected by o, see WithJitter.
+func (o *DiagramOptions) triangulate(ctx context.Context, b *s2delaunay.Builder,
+	dt *s2delaunay.Triangulation, sites s2.PointVector) error {
+	if o.Jitter == 0 {
+		return b.TriangulateContext(ctx, dt, sites)
+	}
+	jittered := jitterSites(sites, o.Jitter, o.JitterSeed)
+	if err := b.TriangulateContext(ctx, dt, jittered); err != nil {
+		return err
+	}
+	dt.Vertices = sites
+	return nil
+}
+
+// jitterSites returns a copy of the sites each moved by an angle of at most maxAngle in a
+// direction given by the hash of the site and the seed. The angles are distributed so that the
+// moved sites are uniform in the cap of radius maxAngle.
+func jitterSites(sites s2.PointVector, maxAngle s1.Angle, seed int64) s2.PointVector {
+	jittered := make(s2.PointVector, len(sites))
+	for i, p := range sites {
+		h := splitMix64(uint64(seed) ^ splitMix64(math.Float64bits(p.X+0)) ^
+			splitMix64(math.Float64bits(p.Y+0)<<1) ^ splitMix64(math.Float64bits(p.Z+0)<<2))
+		u, v := float64(h>>40)/(1<<24), float64(h&(1<<24-1))/(1<<24)
+		r, theta := float64(maxAngle)*math.Sqrt(u), 2*math.Pi*v
+
+		x := p.Ortho()
+		y := p.Cross(x)
+		dir := x.Mul(math.Cos(theta)).Add(y.Mul(math.Sin(theta)))
+		jittered[i] = s2.Point{Vector: p.Mul(math.Cos(r)).Add(dir.Mul(math.Sin(r))).Normalize()}
+	}
+	return jittered
+}
+
+// splitMix64 returns the SplitMix64 mix of x.
+func splitMix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+	return x ^ x>>31
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/internal/fixtures"
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+func TestWithJitter(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxAngle s1.Angle
+		wantErr  bool
+	}{
+		{"positive", 1e-9, false},
+		{"zero", 0, true},
+		{"negative", -1e-9, true},
+		{"infinite", s1.InfAngle(), true},
+		{"nan", s1.Angle(math.NaN()), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts DiagramOptions
+			err := WithJitter(tt.maxAngle, 7)(&opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WithJitter(%v, 7) error = %v, wantErr %v", tt.maxAngle, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidOption) {
+				t.Errorf("WithJitter(%v, 7) error = %v, want errors.Is(err, ErrInvalidOption)",
+					tt.maxAngle, err)
+			}
+			if !tt.wantErr && (opts.Jitter != tt.maxAngle || opts.JitterSeed != 7) {
+				t.Errorf("WithJitter(%v, 7) Jitter, JitterSeed = %v, %d, want %v, 7", tt.maxAngle,
+					opts.Jitter, opts.JitterSeed, tt.maxAngle)
+			}
+		})
+	}
+}
+
+func TestNewDiagram_WithJitter(t *testing.T) {
+	tests := []struct {
+		name  string
+		sites s2.PointVector
+	}{
+		{"lat lng grid", latLngGrid(17, 36)},
+		{"octahedron", fixtures.Load("octahedron")},
+		{"cocircular rings", fixtures.Load("cocircular-rings")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd, err := NewDiagram(slices.Clone(tt.sites), WithJitter(1e-9, 1))
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			if !slices.Equal(vd.Sites, tt.sites) {
+				t.Errorf("vd.Sites = %v, want the given sites", vd.Sites)
+			}
+			if err := vd.Validate(); err != nil {
+				t.Errorf("vd.Validate() = %v, want nil", err)
+			}
+
+			again, err := NewDiagram(slices.Clone(tt.sites), WithJitter(1e-9, 1))
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			if !vd.Equal(again) {
+				t.Errorf("NewDiagram(...) with the same seed differs")
+			}
+		})
+	}
+}
+
+func TestNewDiagram_WithJitter_OrderIndependent(t *testing.T) {
+	sites := latLngGrid(9, 12)
+	perm := utils.GenerateRandomPoints(len(sites), 3)
+	order := make([]int, len(sites))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(a, b int) int { return perm[a].Cmp(perm[b].Vector) })
+	shuffled := make(s2.PointVector, len(sites))
+	for k, i := range order {
+		shuffled[k] = sites[i]
+	}
+
+	opts := []DiagramOption{WithJitter(1e-9, 5), WithOrderIndependentOutput()}
+	vd, err := NewDiagram(slices.Clone(sites), opts...)
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	sd, err := NewDiagram(shuffled, opts...)
+	if err != nil {
+		t.Fatalf("NewDiagram(shuffled, ...) error = %v, want nil", err)
+	}
+	for k, i := range order {
+		got := make([]int, 0, sd.Cell(k).NumVertices())
+		for _, j := range sd.Cell(k).NeighborIndices() {
+			got = append(got, order[j])
+		}
+		if want := vd.Cell(i).NeighborIndices(); !slices.Equal(got, want) {
+			t.Errorf("cell %d neighbors = %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestJitterSites(t *testing.T) {
+	const maxAngle = s1.Angle(1e-6)
+	sites := utils.GenerateRandomPoints(1000, 0)
+	jittered := jitterSites(sites, maxAngle, 1)
+	other := jitterSites(sites, maxAngle, 2)
+	for i, p := range jittered {
+		if !p.IsUnit() {
+			t.Fatalf("jitterSites(...)[%d] = %v, want unit length", i, p)
+		}
+		if a := sites[i].Distance(p); a <= 0 || a > maxAngle*(1+1e-6) {
+			t.Fatalf("jitterSites(...)[%d] moved by %v, want in (0, %v]", i, a, maxAngle)
+		}
+		if p == other[i] {
+			t.Errorf("jitterSites(...)[%d] = %v for seeds 1 and 2, want different", i, p)
+		}
+	}
+	if got := jitterSites(s2.PointVector{sites[3]}, maxAngle, 1)[0]; got != jittered[3] {
+		t.Errorf("jitterSites(...) of a single site = %v, want %v", got, jittered[3])
+	}
+}
+
+// Helpers
+
+// latLngGrid returns the sites of a grid of rows by cols sites, whose rows are evenly spaced in
+// latitude strictly between the poles, together with the poles.
+func latLngGrid(rows, cols int) s2.PointVector {
+	sites := s2.PointVector{s2.PointFromCoords(0, 0, 1), s2.PointFromCoords(0, 0, -1)}
+	for i := 1; i <= rows; i++ {
+		lat := -90 + 180*float64(i)/float64(rows+1)
+		for j := range cols {
+			lng := -180 + 360*float64(j)/float64(cols)
+			sites = append(sites, s2.PointFromLatLng(s2.LatLngFromDegrees(lat, lng)))
+		}
+	}
+	return sites
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/golang/geo/r3"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// diagramJSON is the JSON form of a Diagram, see Diagram.MarshalJSON.
+type diagramJSON struct {
+	Eps           float64      `json:"eps"`
+	Sites         [][3]float64 `json:"sites"`
+	Vertices      [][3]float64 `json:"vertices"`
+	CellVertices  []int        `json:"cellVertices"`
+	CellNeighbors []int        `json:"cellNeighbors"`
+	CellOffsets   []int        `json:"cellOffsets"`
+	Weights       []float64    `json:"weights,omitempty"`
+	MergeTol      float64      `json:"mergeTol,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. The diagram is encoded as an object with the fields
+// "sites" and "vertices", arrays of [x, y, z] unit vectors, "cellVertices", "cellNeighbors" and
+// "cellOffsets", the CSR arrays of the same name, "eps", "weights" for power diagrams and
+// "mergeTol", the tolerance in radians of WithVertexMerging, for diagrams with merged vertices.
+// Points are stored as coordinates rather than latitude and longitude so that they round-trip
+// exactly; a renderer gets the latitude as asin(z) and the longitude as atan2(y, x).
+// The options of the diagram other than WithEps and WithVertexMerging, the retained
+// triangulation, the site sources of WithDeduplication and the values of SetSiteData are not
+// encoded.
+func (d *Diagram) MarshalJSON() ([]byte, error) {
+	return json.Marshal(diagramJSON{
+		Eps:           d.eps,
+		Sites:         pointsToArrays(d.Sites),
+		Vertices:      pointsToArrays(d.Vertices),
+		CellVertices:  nonNil(d.CellVertices),
+		CellNeighbors: nonNil(d.CellNeighbors),
+		CellOffsets:   nonNil(d.CellOffsets),
+		Weights:       d.weights,
+		MergeTol:      float64(d.mergeTol),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler for the encoding of MarshalJSON. The decoded diagram
+// is checked like NewDiagramFromArrays, so offsets that are not monotone, do not number the
+// sites plus one or do not cover the rings, indices out of range, vertices that do not match
+// their sites and vertices outside every ring are all rejected.
+// It returns an error and leaves the diagram unchanged if the data is malformed or the diagram
+// is not valid.
+func (d *Diagram) UnmarshalJSON(data []byte) error {
+	var dj diagramJSON
+	if err := json.Unmarshal(data, &dj); err != nil {
+		return fmt.Errorf("s2voronoi: decoding diagram: %w", err)
+	}
+	nd, err := newValidatedDiagram(arraysToPoints(dj.Sites), arraysToPoints(dj.Vertices),
+		dj.CellVertices, dj.CellNeighbors, dj.CellOffsets, dj.Eps, dj.Weights,
+		s1.Angle(dj.MergeTol))
+	if err != nil {
+		return err
+	}
+	*d = *nd
+	return nil
+}
+
+// pointsToArrays returns the coordinates of ps.
+func pointsToArrays(ps s2.PointVector) [][3]float64 {
+	out := make([][3]float64, len(ps))
+	for i, p := range ps {
+		out[i] = [3]float64{p.X, p.Y, p.Z}
+	}
+	return out
+}
+
+// arraysToPoints returns the points with the coordinates of as, without normalizing them.
+func arraysToPoints(as [][3]float64) s2.PointVector {
+	out := make(s2.PointVector, len(as))
+	for i, a := range as {
+		out[i] = s2.Point{Vector: r3.Vector{X: a[0], Y: a[1], Z: a[2]}}
+	}
+	return out
+}
+
+// nonNil returns s, or an empty slice if s is nil, so that it is encoded as [] and not null.
+func nonNil(s []int) []int {
+	if s == nil {
+		return []int{}
+	}
+	return s
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/utils"
+)
+
+func TestDiagram_JSON(t *testing.T) {
+	power := func(t *testing.T) *Diagram {
+		sites := utils.GenerateRandomPoints(100, 0)
+		weights := make([]float64, len(sites))
+		for i := range weights {
+			weights[i] = 0.01 * float64(i%5)
+		}
+		vd, err := NewPowerDiagram(sites, weights)
+		if err != nil {
+			t.Fatalf("NewPowerDiagram(...) error = %v, want nil", err)
+		}
+		return vd
+	}
+	tests := []struct {
+		name string
+		vd   func(t *testing.T) *Diagram
+	}{
+		{"4 sites", func(t *testing.T) *Diagram { return mustNewDiagram(t, 4) }},
+		{"10 sites", func(t *testing.T) *Diagram { return mustNewDiagram(t, 10) }},
+		{"100 sites", func(t *testing.T) *Diagram { return mustNewDiagram(t, 100) }},
+		{"1000 sites", func(t *testing.T) *Diagram { return mustNewDiagram(t, 1000) }},
+		{"empty cell", func(t *testing.T) *Diagram {
+			vd, _ := mustNewEmptyCellDiagram(t)
+			return vd
+		}},
+		{"power", power},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.vd(t)
+			data, err := json.Marshal(want)
+			if err != nil {
+				t.Fatalf("json.Marshal(vd) error = %v, want nil", err)
+			}
+			got := new(Diagram)
+			if err := json.Unmarshal(data, got); err != nil {
+				t.Fatalf("json.Unmarshal(...) error = %v, want nil", err)
+			}
+			if !got.Equal(want) {
+				t.Errorf("json.Unmarshal(json.Marshal(vd)) differs from vd")
+			}
+			if got.eps != want.eps {
+				t.Errorf("decoded eps = %v, want %v", got.eps, want.eps)
+			}
+			if err := got.Validate(); err != nil {
+				t.Errorf("got.Validate() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestDiagram_UnmarshalJSON_Errors(t *testing.T) {
+	vd := mustNewDiagram(t, 10)
+	n := vd.NumCells()
+	tests := []struct {
+		name string
+		// corrupt modifies the encoding of vd.
+		corrupt func(dj *diagramJSON)
+		wantErr error
+	}{
+		{
+			name: "offsets not monotone",
+			corrupt: func(dj *diagramJSON) {
+				dj.CellOffsets[1], dj.CellOffsets[2] = dj.CellOffsets[2], dj.CellOffsets[1]
+			},
+		},
+		{
+			name:    "offsets too short",
+			corrupt: func(dj *diagramJSON) { dj.CellOffsets = dj.CellOffsets[:n] },
+		},
+		{
+			name:    "offsets too long",
+			corrupt: func(dj *diagramJSON) { dj.CellOffsets = append(dj.CellOffsets, 0) },
+		},
+		{
+			name:    "last offset",
+			corrupt: func(dj *diagramJSON) { dj.CellOffsets[n]-- },
+		},
+		{
+			name:    "vertex out of range",
+			corrupt: func(dj *diagramJSON) { dj.CellVertices[0] = len(dj.Vertices) },
+		},
+		{
+			name:    "negative vertex",
+			corrupt: func(dj *diagramJSON) { dj.CellVertices[0] = -1 },
+		},
+		{
+			name:    "neighbor out of range",
+			corrupt: func(dj *diagramJSON) { dj.CellNeighbors[0] = n },
+		},
+		{
+			name:    "neighbors too short",
+			corrupt: func(dj *diagramJSON) { dj.CellNeighbors = dj.CellNeighbors[1:] },
+		},
+		{
+			name:    "moved vertex",
+			corrupt: func(dj *diagramJSON) { dj.Vertices[0] = dj.Vertices[1] },
+		},
+		{
+			// A vertex outside every ring passes Validate, but not the Euler relation.
+			name:    "unused vertex",
+			corrupt: func(dj *diagramJSON) { dj.Vertices = append(dj.Vertices, dj.Vertices[0]) },
+		},
+		{
+			name:    "site not unit length",
+			corrupt: func(dj *diagramJSON) { dj.Sites[0][0] *= 2 },
+			wantErr: ErrNotUnitLength,
+		},
+		{
+			name:    "insufficient sites",
+			corrupt: func(dj *diagramJSON) { dj.Sites = dj.Sites[:3] },
+			wantErr: ErrInsufficientSites,
+		},
+		{
+			name:    "weights",
+			corrupt: func(dj *diagramJSON) { dj.Weights = []float64{1} },
+		},
+		{
+			name:    "eps",
+			corrupt: func(dj *diagramJSON) { dj.Eps = 0 },
+			wantErr: ErrInvalidOption,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(vd)
+			if err != nil {
+				t.Fatalf("json.Marshal(vd) error = %v, want nil", err)
+			}
+			var dj diagramJSON
+			if err := json.Unmarshal(data, &dj); err != nil {
+				t.Fatalf("json.Unmarshal(..., &dj) error = %v, want nil", err)
+			}
+			tt.corrupt(&dj)
+			if data, err = json.Marshal(dj); err != nil {
+				t.Fatalf("json.Marshal(dj) error = %v, want nil", err)
+			}
+
+			got := mustNewDiagram(t, 10)
+			want := got.clone()
+			err = json.Unmarshal(data, got)
+			if err == nil {
+				t.Fatalf("json.Unmarshal(...) error = nil, want error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("json.Unmarshal(...) error = %v, want errors.Is(err, %v)", err, tt.wantErr)
+			}
+			if !got.Equal(want) {
+				t.Errorf("json.Unmarshal(...) modified the diagram on error")
+			}
+		})
+	}
+
+	t.Run("malformed", func(t *testing.T) {
+		for _, data := range []string{`{`, `[]`, `{"sites": [[1, 0]]}`} {
+			if err := json.Unmarshal([]byte(data), new(Diagram)); err == nil {
+				t.Errorf("json.Unmarshal(%q) error = nil, want error", data)
+			}
+		}
+	})
+}
+
+func TestDiagram_MarshalJSON_Layout(t *testing.T) {
+	vd := mustNewDiagram(t, 10)
+	data, err := json.Marshal(vd)
+	if err != nil {
+		t.Fatalf("json.Marshal(vd) error = %v, want nil", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("json.Unmarshal(..., &fields) error = %v, want nil", err)
+	}
+	var keys []string
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	want := []string{"cellNeighbors", "cellOffsets", "cellVertices", "eps", "sites", "vertices"}
+	if !slices.Equal(keys, want) {
+		t.Errorf("json.Marshal(vd) keys = %v, want %v", keys, want)
+	}
+	var sites [][]float64
+	if err := json.Unmarshal(fields["sites"], &sites); err != nil {
+		t.Fatalf("json.Unmarshal(sites) error = %v, want nil", err)
+	}
+	if len(sites) != 10 || len(sites[0]) != 3 || sites[0][0] != vd.Sites[0].X {
+		t.Errorf("sites = %v, want [x, y, z] of the 10 sites", sites)
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDiagram_MarshalJSON(b *testing.B) {
+	vd, err := NewDiagram(utils.GenerateRandomPoints(10000, 0))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	for b.Loop() {
+		if _, err := json.Marshal(vd); err != nil {
+			b.Fatalf("json.Marshal(vd) error = %v, want nil", err)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+
+	"github.com/golang/geo/s2"
+)
+
+// KMLOptions holds configuration options for ToKML.
+type KMLOptions struct {
+	CellColor func(i int) color.Color
+}
+
+// KMLOption is a functional option type for KML export configuration.
+type KMLOption func(*KMLOptions) error
+
+// WithCellColor styles the outline and fill of the cell of each site i with the color
+// cellColor(i), including its alpha. It must not be nil; by default cells use the style of
+// the viewer.
+func WithCellColor(cellColor func(i int) color.Color) KMLOption {
+	return func(o *KMLOptions) error {
+		if cellColor == nil {
+			return errorf(ErrInvalidOption, "s2voronoi: cell color function must not be nil")
+		}
+		o.CellColor = cellColor
+		return nil
+	}
+}
+
+type kmlRoot struct {
+	XMLName  xml.Name    `xml:"http://www.opengis.net/kml/2.2 kml"`
+	Document kmlDocument `xml:"Document"`
+}
+
+type kmlDocument struct {
+	Name    string      `xml:"name"`
+	Folders []kmlFolder `xml:"Folder"`
+}
+
+type kmlFolder struct {
+	Name       string         `xml:"name"`
+	Placemarks []kmlPlacemark `xml:"Placemark"`
+}
+
+type kmlPlacemark struct {
+	Name          string            `xml:"name"`
+	Style         *kmlStyle         `xml:"Style,omitempty"`
+	Point         *kmlPoint         `xml:"Point,omitempty"`
+	Polygon       *kmlPolygon       `xml:"Polygon,omitempty"`
+	MultiGeometry *kmlMultiGeometry `xml:"MultiGeometry,omitempty"`
+}
+
+type kmlStyle struct {
+	LineColor string `xml:"LineStyle>color"`
+	PolyColor string `xml:"PolyStyle>color"`
+}
+
+type kmlPoint struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+type kmlPolygon struct {
+	Tessellate  int    `xml:"tessellate"`
+	Coordinates string `xml:"outerBoundaryIs>LinearRing>coordinates"`
+}
+
+type kmlMultiGeometry struct {
+	Polygons []kmlPolygon `xml:"Polygon"`
+}
+
+// ToKML writes the diagram to w as a KML 2.2 Document for viewers such as Google Earth. The
+// folder "cells" holds one Placemark per cell in site order, named by the index of its site,
+// and the folder "sites" one Point Placemark per site. The geometry of a cell is that of
+// ToGeoJSON: a Polygon with a closed counterclockwise outer boundary, a MultiGeometry of
+// Polygons for a cell cut at the antimeridian, and no geometry for an empty cell. The polygons
+// are tessellated so that their edges follow great circles, and the coordinates are
+// "lng,lat,0" in degrees.
+// It returns an error if an option is invalid or if writing fails.
+func (d *Diagram) ToKML(w io.Writer, setters ...KMLOption) error {
+	opts := &KMLOptions{}
+	for _, set := range setters {
+		if err := set(opts); err != nil {
+			return err
+		}
+	}
+
+	cells := kmlFolder{Name: "cells", Placemarks: make([]kmlPlacemark, d.NumCells())}
+	sites := kmlFolder{Name: "sites", Placemarks: make([]kmlPlacemark, d.NumCells())}
+	for i, c := range d.Cells() {
+		name := strconv.Itoa(i)
+		ll := s2.LatLngFromPoint(c.Site())
+		site := []lngLatPosition{{ll.Lng.Degrees(), ll.Lat.Degrees()}}
+		sites.Placemarks[i] = kmlPlacemark{
+			Name:  name,
+			Point: &kmlPoint{Coordinates: string(appendKMLCoordinates(nil, site))},
+		}
+
+		p := kmlPlacemark{Name: name}
+		if opts.CellColor != nil {
+			style := kmlColor(opts.CellColor(i))
+			p.Style = &kmlStyle{LineColor: style, PolyColor: style}
+		}
+		polygons := c.lngLatPolygons(0)
+		switch len(polygons) {
+		case 0:
+		case 1:
+			p.Polygon = newKMLPolygon(polygons[0][0])
+		default:
+			p.MultiGeometry = &kmlMultiGeometry{}
+			for _, polygon := range polygons {
+				p.MultiGeometry.Polygons = append(p.MultiGeometry.Polygons,
+					*newKMLPolygon(polygon[0]))
+			}
+		}
+		cells.Placemarks[i] = p
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	root := kmlRoot{Document: kmlDocument{
+		Name:    "s2voronoi",
+		Folders: []kmlFolder{cells, sites},
+	}}
+	if err := enc.Encode(root); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// newKMLPolygon returns the tessellated polygon with the outer boundary ring.
+func newKMLPolygon(ring []lngLatPosition) *kmlPolygon {
+	return &kmlPolygon{Tessellate: 1, Coordinates: string(appendKMLCoordinates(nil, ring))}
+}
+
+// appendKMLCoordinates appends the positions to dst as space-separated "lng,lat,0" tuples.
+func appendKMLCoordinates(dst []byte, positions []lngLatPosition) []byte {
+	for k, p := range positions {
+		if k > 0 {
+			dst = append(dst, ' ')
+		}
+		dst = strconv.AppendFloat(dst, p[0], 'f', -1, 64)
+		dst = append(dst, ',')
+		dst = strconv.AppendFloat(dst, p[1], 'f', -1, 64)
+		dst = append(dst, ",0"...)
+	}
+	return dst
+}
+
+// kmlColor returns c in the aabbggrr hexadecimal form of KML.
+func kmlColor(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("%02x%02x%02x%02x", n.A, n.B, n.G, n.R)
+}
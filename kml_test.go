@@ -0,0 +1,226 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/internal/fixtures"
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s2"
+)
+
+func TestDiagram_ToKML(t *testing.T) {
+	load := func(name string) func(t *testing.T) (*Diagram, int) {
+		return func(t *testing.T) (*Diagram, int) {
+			vd, err := NewDiagram(fixtures.Load(name))
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			return vd, -1
+		}
+	}
+	tests := []struct {
+		name string
+		vd   func(t *testing.T) (*Diagram, int)
+	}{
+		{"random", func(t *testing.T) (*Diagram, int) { return mustNewDiagram(t, 200), -1 }},
+		{"octahedron", load("octahedron")},
+		{"antimeridian", load("antimeridian")},
+		{"empty cell", mustNewEmptyCellDiagram},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd, empty := tt.vd(t)
+			var buf bytes.Buffer
+			if err := vd.ToKML(&buf); err != nil {
+				t.Fatalf("vd.ToKML(...) error = %v, want nil", err)
+			}
+			cells, sites := checkKML(t, vd, buf.Bytes())
+			for i, c := range vd.Cells() {
+				if cells[i].Style != nil {
+					t.Errorf("cell %d has style %+v, want none", i, cells[i].Style)
+				}
+				polygons := kmlPolygons(t, i, cells[i])
+				want := c.lngLatPolygons(0)
+				if i == empty && polygons != nil {
+					t.Errorf("cell %d has polygons %v, want none", i, polygons)
+				}
+				if len(polygons) != len(want) {
+					t.Errorf("cell %d has %d polygons, want %d", i, len(polygons), len(want))
+					continue
+				}
+				for k, ring := range polygons {
+					assertPositionsNear(t, i, ring, want[k][0], 0)
+				}
+
+				ll := s2.LatLngFromPoint(c.Site())
+				got := parseKMLCoordinates(t, sites[i].Point.Coordinates)
+				if len(got) != 1 || got[0] != [2]float64{ll.Lng.Degrees(), ll.Lat.Degrees()} {
+					t.Errorf("site %d coordinates = %v, want [%v %v]", i, got, ll.Lng.Degrees(),
+						ll.Lat.Degrees())
+				}
+			}
+		})
+	}
+}
+
+func TestDiagram_ToKML_CellColor(t *testing.T) {
+	vd := mustNewDiagram(t, 10)
+	colors := []color.Color{
+		color.NRGBA{R: 0x12, G: 0x34, B: 0x56, A: 0x78},
+		color.RGBA{R: 0x80, G: 0x40, B: 0x20, A: 0x80},
+		color.Gray{Y: 0xab},
+	}
+	var buf bytes.Buffer
+	err := vd.ToKML(&buf, WithCellColor(func(i int) color.Color { return colors[i%len(colors)] }))
+	if err != nil {
+		t.Fatalf("vd.ToKML(..., WithCellColor(...)) error = %v, want nil", err)
+	}
+	want := []string{"78563412", "803f7fff", "ffababab"}
+	cells, _ := checkKML(t, vd, buf.Bytes())
+	for i, p := range cells {
+		w := want[i%len(want)]
+		if p.Style == nil || p.Style.LineColor != w || p.Style.PolyColor != w {
+			t.Errorf("cell %d style = %+v, want color %s", i, p.Style, w)
+		}
+	}
+}
+
+func TestDiagram_ToKML_Errors(t *testing.T) {
+	vd := mustNewDiagram(t, 10)
+	if err := vd.ToKML(io.Discard, WithCellColor(nil)); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("vd.ToKML(..., WithCellColor(nil)) error = %v, want ErrInvalidOption", err)
+	}
+	for _, n := range []int{0, 100} {
+		if err := vd.ToKML(&limitedWriter{n: n}); err == nil {
+			t.Errorf("vd.ToKML(limitedWriter{%d}) error = nil, want error", n)
+		}
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDiagram_ToKML(b *testing.B) {
+	vd, err := NewDiagram(utils.GenerateRandomPoints(10000, 0))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	for b.Loop() {
+		if err := vd.ToKML(io.Discard); err != nil {
+			b.Fatalf("vd.ToKML(...) error = %v, want nil", err)
+		}
+	}
+}
+
+// Helpers
+
+// checkKML checks that data is well-formed XML holding a KML Document with the folders of
+// vd.ToKML, one Placemark per cell and one Point Placemark per site named by their index, and
+// returns the Placemarks of the cells and of the sites.
+func checkKML(t *testing.T, vd *Diagram, data []byte) (cells, sites []kmlPlacemark) {
+	t.Helper()
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		if _, err := dec.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("vd.ToKML() is not well-formed XML: %v", err)
+		}
+	}
+
+	var root kmlRoot
+	if err := xml.Unmarshal(data, &root); err != nil {
+		t.Fatalf("xml.Unmarshal(vd.ToKML()) error = %v, want nil", err)
+	}
+	if root.XMLName.Space != "http://www.opengis.net/kml/2.2" {
+		t.Errorf("vd.ToKML() namespace = %q, want KML 2.2", root.XMLName.Space)
+	}
+	folders := root.Document.Folders
+	if len(folders) != 2 || folders[0].Name != "cells" || folders[1].Name != "sites" {
+		t.Fatalf("vd.ToKML() has folders %d, want cells and sites", len(folders))
+	}
+	for _, f := range folders {
+		if len(f.Placemarks) != vd.NumCells() {
+			t.Fatalf("folder %s has %d Placemarks, want %d", f.Name, len(f.Placemarks),
+				vd.NumCells())
+		}
+		for i, p := range f.Placemarks {
+			if p.Name != strconv.Itoa(i) {
+				t.Errorf("folder %s Placemark %d name = %q, want %d", f.Name, i, p.Name, i)
+			}
+			if f.Name == "sites" && p.Point == nil {
+				t.Errorf("site %d has no Point", i)
+			}
+		}
+	}
+	return folders[0].Placemarks, folders[1].Placemarks
+}
+
+// kmlPolygons returns the outer boundaries of the polygons of the Placemark p of cell i,
+// checking that they are tessellated, closed and counterclockwise.
+func kmlPolygons(t *testing.T, i int, p kmlPlacemark) [][][2]float64 {
+	t.Helper()
+	var polygons []kmlPolygon
+	if p.Polygon != nil {
+		polygons = append(polygons, *p.Polygon)
+	}
+	if p.MultiGeometry != nil {
+		if len(p.MultiGeometry.Polygons) < 2 {
+			t.Errorf("cell %d MultiGeometry has %d Polygons, want at least 2", i,
+				len(p.MultiGeometry.Polygons))
+		}
+		polygons = append(polygons, p.MultiGeometry.Polygons...)
+	}
+	var rings [][][2]float64
+	for _, polygon := range polygons {
+		if polygon.Tessellate != 1 {
+			t.Errorf("cell %d tessellate = %d, want 1", i, polygon.Tessellate)
+		}
+		ring := parseKMLCoordinates(t, polygon.Coordinates)
+		if len(ring) < 4 || ring[0] != ring[len(ring)-1] {
+			t.Errorf("cell %d ring %v is not closed", i, ring)
+		}
+		if planarArea(ring) <= 0 {
+			t.Errorf("cell %d ring %v is not counterclockwise", i, ring)
+		}
+		rings = append(rings, ring)
+	}
+	return rings
+}
+
+// parseKMLCoordinates parses "lng,lat,0" tuples, checking that the altitudes are 0 and the
+// positions in range.
+func parseKMLCoordinates(t *testing.T, s string) [][2]float64 {
+	t.Helper()
+	var positions [][2]float64
+	for _, tuple := range strings.Fields(s) {
+		parts := strings.Split(tuple, ",")
+		if len(parts) != 3 || parts[2] != "0" {
+			t.Fatalf("coordinates %q, want lng,lat,0", tuple)
+		}
+		var q [2]float64
+		for k := range q {
+			x, err := strconv.ParseFloat(parts[k], 64)
+			if err != nil {
+				t.Fatalf("strconv.ParseFloat(%q) error = %v, want nil", parts[k], err)
+			}
+			q[k] = x
+		}
+		if math.Abs(q[0]) > 180 || math.Abs(q[1]) > 90 {
+			t.Errorf("position %v out of range", q)
+		}
+		positions = append(positions, q)
+	}
+	return positions
+}
@@ -0,0 +1,303 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"container/heap"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// maxHintLevel is the finest s2.CellID level of the table of walk starts used by FindCell.
+const maxHintLevel = 12
+
+// FindCell returns the cell whose site is nearest to p, see FindCellIndex.
+func (d *Diagram) FindCell(p s2.Point) Cell {
+	return d.Cell(d.FindCellIndex(p))
+}
+
+// FindCellIndex returns the index of the cell whose site is nearest to p. Sites whose chord
+// distance to p exceeds the nearest one by at most eps are tied, and the smallest index among
+// them is returned. In a power diagram, the cell of the smallest power is returned, and sites
+// are tied if the dot products of p with their lifted points, see NewPowerDiagram, differ by at
+// most eps. Empty cells are never returned.
+//
+// The query walks the Delaunay graph from a site near p, which is looked up in a table over the
+// s2.CellIDs of the level with about as many cells as there are sites. The table is built on
+// first use.
+func (d *Diagram) FindCellIndex(p s2.Point) int {
+	level, hints := d.locateHints()
+	return d.smallestTied(p, d.locate(p, hints[hintPos(s2.CellFromPoint(p).ID(), level)]))
+}
+
+// AssignPoints returns for every point of ps the index of the cell whose site is nearest to it,
+// exactly like FindCellIndex. The table of walk starts is looked up once, and the points are
+// split into contiguous chunks processed by runtime.GOMAXPROCS(0) goroutines.
+//
+// Within a chunk, the walk for a point starts at the cell of the previous point if both fall
+// in the same cell of the table, so sorting the points by s2.CellID shortens the walks and
+// speeds up large batches.
+func (d *Diagram) AssignPoints(ps []s2.Point) []int {
+	cells := make([]int, len(ps))
+	level, hints := d.locateHints()
+	chunk := max((len(ps)+runtime.GOMAXPROCS(0)-1)/runtime.GOMAXPROCS(0), minAssignChunk)
+
+	var wg sync.WaitGroup
+	for start := 0; start < len(ps); start += chunk {
+		end := min(start+chunk, len(ps))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			prevPos, prev := -1, 0
+			for k := start; k < end; k++ {
+				pos := hintPos(s2.CellFromPoint(ps[k]).ID(), level)
+				hint := hints[pos]
+				if pos == prevPos {
+					hint = prev
+				}
+				prevPos, prev = pos, d.locate(ps[k], hint)
+				cells[k] = d.smallestTied(ps[k], prev)
+			}
+		}()
+	}
+	wg.Wait()
+	return cells
+}
+
+// minAssignChunk is the smallest number of points AssignPoints hands to a goroutine.
+const minAssignChunk = 1 << 12
+
+// smallestTied returns the smallest index among the sites tied with the nearest site i to p,
+// see FindCellIndex.
+func (d *Diagram) smallestTied(p s2.Point, i int) int {
+	// The tied sites lie in a cap around p, so they induce a connected subgraph of the
+	// Delaunay graph containing the nearest site.
+	limit := p.Sub(d.Sites[i].Vector).Norm() + d.eps
+	tied := func(u int) bool { return p.Sub(d.Sites[u].Vector).Norm() <= limit }
+	if d.weights != nil {
+		dot := p.Dot(d.generator(i)) - d.eps
+		tied = func(u int) bool { return p.Dot(d.generator(u)) >= dot }
+	}
+	if !slices.ContainsFunc(d.Cell(i).NeighborIndices(), tied) {
+		return i
+	}
+	best := i
+	visited := map[int]struct{}{i: {}}
+	stack := []int{i}
+	for len(stack) > 0 {
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, u := range d.Cell(v).NeighborIndices() {
+			if _, ok := visited[u]; ok {
+				continue
+			}
+			visited[u] = struct{}{}
+			if tied(u) {
+				best = min(best, u)
+				stack = append(stack, u)
+			}
+		}
+	}
+	return best
+}
+
+// locateHints returns the level and the table of walk starts of FindCellIndex, building them on
+// first use. The table holds for every s2.CellID of the level, indexed by hintPos, the index of
+// the site nearest to its center.
+func (d *Diagram) locateHints() (int, []int) {
+	c := d.caches()
+	c.hintsOnce.Do(func() {
+		level := 0
+		for level < maxHintLevel && 6<<(2*(level+1)) <= d.NumCells() {
+			level++
+		}
+		hint := d.firstNonEmpty()
+
+		// The cells are visited along the Hilbert curve, so consecutive walks are short.
+		c.hints = make([]int, 6<<(2*level))
+		end := s2.CellIDFromFace(5).ChildEndAtLevel(level)
+		for id := s2.CellIDFromFace(0).ChildBeginAtLevel(level); id != end; id = id.Next() {
+			hint = d.locate(id.Point(), hint)
+			c.hints[hintPos(id, level)] = hint
+		}
+		c.hintsLevel = level
+	})
+	return c.hintsLevel, c.hints
+}
+
+// firstNonEmpty returns the index of the first non-empty cell, from which the walks of locate
+// may start in any diagram. Only cells of power diagrams can be empty.
+func (d *Diagram) firstNonEmpty() int {
+	i := 0
+	for i < d.NumCells()-1 && d.Cell(i).IsEmpty() {
+		i++
+	}
+	return i
+}
+
+// hintPos returns the position of the ancestor of id at the given level among all cells of
+// that level.
+func hintPos(id s2.CellID, level int) int {
+	return int(uint64(id.Parent(level)) >> (2*(s2.MaxLevel-level) + 1))
+}
+
+// locate returns the index of the cell containing p, i.e. of the site nearest to p.
+// It walks the Delaunay graph greedily from the cell hint, moving to the neighbor whose site is
+// closest to p until no neighbor is closer. The walk always ends at the nearest site, since a
+// site that is not nearest to p has a Delaunay neighbor that is closer to p. The same holds for
+// the power in a power diagram, whose dual graph is the edge graph of the hull of the lifted
+// points, and the hint must then be a non-empty cell.
+func (d *Diagram) locate(p s2.Point, hint int) int {
+	cur := hint
+	curDot := p.Dot(d.generator(cur))
+	for {
+		next, nextDot := cur, curDot
+		for _, n := range d.Cell(cur).NeighborIndices() {
+			if dot := p.Dot(d.generator(n)); dot > nextDot {
+				next, nextDot = n, dot
+			}
+		}
+		if next == cur {
+			return cur
+		}
+		cur, curDot = next, nextDot
+	}
+}
+
+// locateMany returns the index of the cell containing each point, see locate. The first walk
+// starts from the first non-empty cell and every later one from the cell of the previous point,
+// which is fast for spatially coherent input.
+func (d *Diagram) locateMany(points []s2.Point) []int {
+	cells := make([]int, len(points))
+	hint := d.firstNonEmpty()
+	for i, p := range points {
+		hint = d.locate(p, hint)
+		cells[i] = hint
+	}
+	return cells
+}
+
+// KNearestSites returns the indices of the k sites nearest to p in increasing order of
+// geodesic distance, with ties broken by the smaller index. The query starts at the cell found
+// by FindCellIndex and explores the Delaunay graph outwards, so its cost depends on k rather
+// than on the number of sites. If k exceeds the number of sites, all sites are returned; sites
+// of empty cells are never returned. It returns an error if k is not positive.
+func (d *Diagram) KNearestSites(p s2.Point, k int) ([]int, error) {
+	if k <= 0 {
+		return nil, fmt.Errorf("s2voronoi: k must be positive, got %d", k)
+	}
+	return d.nearestSites(p, d.FindCellIndex(p), min(k, d.NumCells())), nil
+}
+
+// nearestSites returns the indices of the up to k sites nearest to p in increasing order of
+// distance, given the nearest site. It runs a best-first search of the Delaunay graph: the m
+// sites nearest to p lie in a cap around p, so they induce a connected subgraph, and the next
+// nearest site is a neighbor of one of them.
+func (d *Diagram) nearestSites(p s2.Point, nearest, k int) []int {
+	// The heap orders by decreasing distance, so the distances are negated.
+	queue := &distanceHeap{{dist: -p.Sub(d.Sites[nearest].Vector).Norm2(), idx: nearest}}
+	seen := map[int]struct{}{nearest: {}}
+	out := make([]int, 0, k)
+	for queue.Len() > 0 && len(out) < k {
+		i := heap.Pop(queue).(distanceItem).idx
+		out = append(out, i)
+		for _, j := range d.Cell(i).NeighborIndices() {
+			if _, ok := seen[j]; ok {
+				continue
+			}
+			seen[j] = struct{}{}
+			heap.Push(queue, distanceItem{dist: -p.Sub(d.Sites[j].Vector).Norm2(), idx: j})
+		}
+	}
+	return out
+}
+
+// SitesWithinDistance returns the indices of the sites whose geodesic distance to p is at most
+// radius, in increasing order. A site exactly at distance radius, as computed by
+// s2.Point.Distance, is included. The query starts at the cell found by FindCellIndex and
+// explores the Delaunay graph only within the cap, which induces a connected subgraph, so its
+// cost depends on the number of reported sites rather than on the number of all sites. Sites
+// of empty cells are never returned.
+func (d *Diagram) SitesWithinDistance(p s2.Point, radius s1.Angle) []int {
+	nearest := d.FindCellIndex(p)
+	if p.Distance(d.Sites[nearest]) > radius {
+		return nil
+	}
+
+	out := []int{nearest}
+	seen := map[int]struct{}{nearest: {}}
+	stack := []int{nearest}
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, j := range d.Cell(i).NeighborIndices() {
+			if _, ok := seen[j]; ok {
+				continue
+			}
+			seen[j] = struct{}{}
+			if p.Distance(d.Sites[j]) <= radius {
+				out = append(out, j)
+				stack = append(stack, j)
+			}
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// NearestVertex returns the index of the Voronoi vertex nearest to p and its distance.
+// Vertices whose chord distance to p exceeds the nearest one by at most eps are tied, and the
+// smallest index among them is returned, so coincident vertices, e.g. of cocircular sites, are
+// resolved deterministically. Vertices that belong to no cell are never returned.
+//
+// The query starts at the cell found by FindCellIndex and explores the cells whose bounds, see
+// CellCapBounds, intersect the cap around p reaching the nearest vertex found so far. The
+// segment from p to the nearest vertex crosses a chain of adjacent cells that all intersect the
+// cap, so its cost depends on the cells around p rather than on the number of all cells.
+func (d *Diagram) NearestVertex(p s2.Point) (int, s1.ChordAngle) {
+	start := d.FindCellIndex(p)
+	caps := d.CellCapBounds()
+
+	best, bestDist := -1, 0.0
+	seen := map[int]struct{}{start: {}}
+	cells := []int{start}
+	stack := []int{start}
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, v := range d.Cell(i).VertexIndices() {
+			if dist := p.Sub(d.Vertices[v].Vector).Norm(); best < 0 || dist < bestDist {
+				best, bestDist = v, dist
+			}
+		}
+		r := bestDist + d.eps
+		bound := s2.CapFromCenterChordAngle(p, s1.ChordAngleFromSquaredLength(r*r))
+		for _, j := range d.Cell(i).NeighborIndices() {
+			if _, ok := seen[j]; ok {
+				continue
+			}
+			seen[j] = struct{}{}
+			if caps[j].Intersects(bound) {
+				cells = append(cells, j)
+				stack = append(stack, j)
+			}
+		}
+	}
+
+	limit := bestDist + d.eps
+	for _, i := range cells {
+		for _, v := range d.Cell(i).VertexIndices() {
+			if v < best && p.Sub(d.Vertices[v].Vector).Norm() <= limit {
+				best = v
+			}
+		}
+	}
+	return best, s2.ChordAngleBetweenPoints(p, d.Vertices[best])
+}
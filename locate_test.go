@@ -0,0 +1,334 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/internal/fixtures"
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+func TestDiagram_locate(t *testing.T) {
+	vd := mustNewDiagram(t, 200)
+	for i, p := range utils.GenerateRandomPoints(500, 1) {
+		want := nearestSite(vd, p)
+		if got := vd.locate(p, i%vd.NumCells()); got != want {
+			t.Errorf("vd.locate(%v, %d) = %v, want %v", p, i%vd.NumCells(), got, want)
+		}
+	}
+}
+
+func TestDiagram_FindCellIndex(t *testing.T) {
+	for _, n := range []int{4, 100, 5000} {
+		points := utils.GenerateRandomPoints(n, 0)
+		vd, err := NewDiagram(points)
+		if err != nil {
+			t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+		}
+		for _, p := range utils.GenerateRandomPoints(1000, 1) {
+			want := nearestSite(vd, p)
+			if got := vd.FindCellIndex(p); got != want {
+				t.Errorf("n = %d: vd.FindCellIndex(%v) = %v, want %v", n, p, got, want)
+			}
+		}
+		for i, s := range vd.Sites {
+			if got := vd.FindCell(s).SiteIndex(); got != i {
+				t.Errorf("n = %d: vd.FindCell(vd.Sites[%d]).SiteIndex() = %v, want %v", n, i, got, i)
+			}
+		}
+	}
+}
+
+func TestDiagram_FindCellIndex_Ties(t *testing.T) {
+	vd, err := NewDiagram(fixtures.Load("octahedron"))
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	tests := []struct {
+		name string
+		p    s2.Point
+		want int
+	}{
+		{"edge", s2.PointFromCoords(0, 1, 1), 2},
+		{"edge within eps", s2.PointFromCoords(0, 1, 1+1e-14), 2},
+		{"vertex", s2.PointFromCoords(-1, -1, -1), 1},
+		{"interior", s2.PointFromCoords(0, 1, 1.1), 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := vd.FindCellIndex(tt.p); got != tt.want {
+				t.Errorf("vd.FindCellIndex(%v) = %v, want %v", tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiagram_FindCellIndex_EmptyCell(t *testing.T) {
+	vd, empty := mustNewEmptyCellDiagram(t)
+	p := vd.Sites[empty]
+	if got := vd.FindCellIndex(p); got == empty {
+		t.Errorf("vd.FindCellIndex(%v) = %v, want a non-empty cell", p, got)
+	}
+}
+
+func TestDiagram_AssignPoints(t *testing.T) {
+	weights := make([]float64, 2000)
+	for i := range weights {
+		weights[i] = float64(i%7) * 1e-4
+	}
+	power, err := NewPowerDiagram(utils.GenerateRandomPoints(2000, 0), weights)
+	if err != nil {
+		t.Fatalf("NewPowerDiagram(...) error = %v, want nil", err)
+	}
+	octahedron, err := NewDiagram(fixtures.Load("octahedron"))
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	emptyCell, empty := mustNewEmptyCellDiagram(t)
+	random := utils.GenerateRandomPoints(20000, 1)
+	sorted := slices.Clone(random)
+	slices.SortFunc(sorted, func(a, b s2.Point) int {
+		return cmp.Compare(s2.CellFromPoint(a).ID(), s2.CellFromPoint(b).ID())
+	})
+	tests := []struct {
+		name    string
+		diagram *Diagram
+		ps      []s2.Point
+	}{
+		{"random", mustNewDiagram(t, 5000), random},
+		{"sorted", mustNewDiagram(t, 5000), sorted},
+		{"sites", mustNewDiagram(t, 5000), mustNewDiagram(t, 5000).Sites},
+		{"power", power, random},
+		{"ties", octahedron, []s2.Point{
+			s2.PointFromCoords(0, 1, 1),
+			s2.PointFromCoords(0, 1, 1+1e-14),
+			s2.PointFromCoords(-1, -1, -1),
+		}},
+		{"empty cell", emptyCell, []s2.Point{emptyCell.Sites[empty]}},
+		{"no points", mustNewDiagram(t, 100), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.diagram.AssignPoints(tt.ps)
+			if len(got) != len(tt.ps) {
+				t.Fatalf("len(tt.diagram.AssignPoints(...)) = %d, want %d", len(got), len(tt.ps))
+			}
+			for k, p := range tt.ps {
+				if want := tt.diagram.FindCellIndex(p); got[k] != want {
+					t.Errorf("tt.diagram.AssignPoints(...)[%d] = %d, want %d", k, got[k], want)
+				}
+			}
+		})
+	}
+}
+
+func TestDiagram_nearestSites(t *testing.T) {
+	vd := mustNewDiagram(t, 200)
+	for _, p := range utils.GenerateRandomPoints(200, 1) {
+		for _, k := range []int{1, 5, 20, 500} {
+			want := bruteForceNearestSites(vd, p, k)
+			if got := vd.nearestSites(p, vd.FindCellIndex(p), k); !slices.Equal(got, want) {
+				t.Errorf("vd.nearestSites(%v, %d) = %v, want %v", p, k, got, want)
+			}
+		}
+	}
+}
+
+func TestDiagram_KNearestSites(t *testing.T) {
+	vd := mustNewDiagram(t, 2000)
+	for _, p := range utils.GenerateRandomPoints(100, 1) {
+		for _, k := range []int{1, 7, 50, 2000, 3000} {
+			got, err := vd.KNearestSites(p, k)
+			if err != nil {
+				t.Fatalf("vd.KNearestSites(%v, %d) error = %v, want nil", p, k, err)
+			}
+			if want := bruteForceNearestSites(vd, p, k); !slices.Equal(got, want) {
+				t.Errorf("vd.KNearestSites(%v, %d) = %v, want %v", p, k, got, want)
+			}
+		}
+	}
+
+	for _, k := range []int{0, -1} {
+		if _, err := vd.KNearestSites(vd.Sites[0], k); err == nil {
+			t.Errorf("vd.KNearestSites(p, %d) error = nil, want non-nil", k)
+		}
+	}
+}
+
+func TestDiagram_SitesWithinDistance(t *testing.T) {
+	vd := mustNewDiagram(t, 2000)
+	for _, p := range utils.GenerateRandomPoints(100, 1) {
+		for _, radius := range []s1.Angle{-1, 0, 0.01, 0.1, 0.5, 2, math.Pi} {
+			var want []int
+			for i, s := range vd.Sites {
+				if p.Distance(s) <= radius {
+					want = append(want, i)
+				}
+			}
+			if got := vd.SitesWithinDistance(p, radius); !slices.Equal(got, want) {
+				t.Errorf("vd.SitesWithinDistance(%v, %v) = %v, want %v", p, radius, got, want)
+			}
+		}
+	}
+
+	// The boundary is inclusive.
+	p, j := vd.Sites[0], vd.Cell(0).NeighborIndices()[0]
+	radius := p.Distance(vd.Sites[j])
+	if got := vd.SitesWithinDistance(p, radius); !slices.Contains(got, j) {
+		t.Errorf("vd.SitesWithinDistance(%v, %v) = %v, want the site at distance radius", p, radius,
+			got)
+	}
+}
+
+func TestDiagram_NearestVertex(t *testing.T) {
+	tests := []struct {
+		name  string
+		sites s2.PointVector
+	}{
+		{"octahedron", fixtures.Load("octahedron")},
+		{"cocircular rings", fixtures.Load("cocircular-rings")},
+		{"random", utils.GenerateRandomPoints(1000, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd, err := NewDiagram(tt.sites)
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			queries := append(utils.GenerateRandomPoints(500, 1), vd.Sites...)
+			queries = append(queries, vd.Vertices...)
+			for _, p := range queries {
+				want := bruteForceNearestVertex(vd, p)
+				got, dist := vd.NearestVertex(p)
+				if got != want {
+					t.Errorf("vd.NearestVertex(%v) = %v, want %v", p, got, want)
+				}
+				if wantDist := s2.ChordAngleBetweenPoints(p, vd.Vertices[got]); dist != wantDist {
+					t.Errorf("vd.NearestVertex(%v) distance = %v, want %v", p, dist, wantDist)
+				}
+			}
+		})
+	}
+}
+
+func TestDiagram_NearestVertex_Ties(t *testing.T) {
+	vd, err := NewDiagram(fixtures.Load("octahedron"))
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	// The vertices of a cell of the octahedron are equidistant from its site.
+	for i := range vd.NumCells() {
+		want := slices.Min(vd.Cell(i).VertexIndices())
+		if got, _ := vd.NearestVertex(vd.Sites[i]); got != want {
+			t.Errorf("vd.NearestVertex(vd.Sites[%d]) = %v, want %v", i, got, want)
+		}
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDiagram_FindCellIndex(b *testing.B) {
+	vd, err := NewDiagram(utils.GenerateRandomPoints(100000, 0))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	queries := utils.GenerateRandomPoints(1024, 1)
+	vd.FindCellIndex(queries[0])
+	for i := 0; b.Loop(); i++ {
+		vd.FindCellIndex(queries[i%len(queries)])
+	}
+}
+
+func BenchmarkDiagram_AssignPoints(b *testing.B) {
+	vd, err := NewDiagram(utils.GenerateRandomPoints(10000, 0))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	random := utils.GenerateRandomPoints(1000000, 1)
+	sorted := slices.Clone(random)
+	slices.SortFunc(sorted, func(a, b s2.Point) int {
+		return cmp.Compare(s2.CellFromPoint(a).ID(), s2.CellFromPoint(b).ID())
+	})
+	vd.FindCellIndex(random[0])
+	b.Run("AssignPoints/random", func(b *testing.B) {
+		for b.Loop() {
+			vd.AssignPoints(random)
+		}
+	})
+	b.Run("AssignPoints/sorted", func(b *testing.B) {
+		for b.Loop() {
+			vd.AssignPoints(sorted)
+		}
+	})
+	b.Run("FindCellIndex/random", func(b *testing.B) {
+		for b.Loop() {
+			for _, p := range random {
+				vd.FindCellIndex(p)
+			}
+		}
+	})
+}
+
+func BenchmarkDiagram_KNearestSites(b *testing.B) {
+	vd, err := NewDiagram(utils.GenerateRandomPoints(100000, 0))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	queries := utils.GenerateRandomPoints(1024, 1)
+	vd.FindCellIndex(queries[0])
+	for i := 0; b.Loop(); i++ {
+		if _, err := vd.KNearestSites(queries[i%len(queries)], 16); err != nil {
+			b.Fatalf("vd.KNearestSites(...) error = %v, want nil", err)
+		}
+	}
+}
+
+func BenchmarkDiagram_NearestVertex(b *testing.B) {
+	vd, err := NewDiagram(utils.GenerateRandomPoints(100000, 0))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	queries := utils.GenerateRandomPoints(1024, 1)
+	vd.NearestVertex(queries[0])
+	for i := 0; b.Loop(); i++ {
+		vd.NearestVertex(queries[i%len(queries)])
+	}
+}
+
+// Helpers
+
+// bruteForceNearestVertex returns the smallest index of the vertices whose chord distance to p
+// exceeds the nearest one by at most eps.
+func bruteForceNearestVertex(vd *Diagram, p s2.Point) int {
+	nearest := math.Inf(1)
+	for _, v := range vd.Vertices {
+		nearest = min(nearest, p.Sub(v.Vector).Norm())
+	}
+	for i, v := range vd.Vertices {
+		if p.Sub(v.Vector).Norm() <= nearest+vd.Eps() {
+			return i
+		}
+	}
+	return -1
+}
+
+// bruteForceNearestSites returns the indices of the up to k sites nearest to p in increasing
+// order of distance.
+func bruteForceNearestSites(vd *Diagram, p s2.Point, k int) []int {
+	order := make([]int, vd.NumCells())
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(a, b int) int {
+		return cmp.Compare(p.Sub(vd.Sites[a].Vector).Norm2(), p.Sub(vd.Sites[b].Vector).Norm2())
+	})
+	return order[:min(k, len(order))]
+}
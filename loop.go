@@ -0,0 +1,144 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/2dChan/s2voronoi/internal/lnglat"
+	"github.com/golang/geo/s2"
+)
+
+// Loop returns an s2.Loop bounding the cell, with the ring reversed into the s2 convention so
+// that the interior of the loop is the cell and contains the site. The ring is used as stored,
+// so a cell with coincident vertices, e.g. from cocircular sites, yields an invalid loop; see
+// LoopValidated for a repairing variant. Empty cells yield s2.EmptyLoop().
+func (c Cell) Loop() *s2.Loop {
+	num := c.NumVertices()
+	if num == 0 {
+		return s2.EmptyLoop()
+	}
+	pts := make([]s2.Point, num)
+	for i := range num {
+		pts[num-1-i] = c.Vertex(i)
+	}
+	return s2.LoopFromPoints(pts)
+}
+
+// Polygon returns an s2.Polygon consisting of the single loop returned by Loop. Empty cells
+// yield an empty polygon.
+func (c Cell) Polygon() *s2.Polygon {
+	return s2.PolygonFromLoops([]*s2.Loop{c.Loop()})
+}
+
+// LatLngRings returns the boundary of the cell as rings in latitude and longitude, in the
+// order of the vertices, CCW when looking out of the sphere, and closed implicitly like those of
+// s2.Loop. A cell that does not cross the antimeridian has a single ring of its vertices, with
+// coincident consecutive vertices merged. A cell crossing it is split into a ring on each
+// side, with longitudes of exactly -180 or 180 at the points where its edges, which are great
+// circle arcs, cross the meridian, and a cell around a pole is closed along the pole's line of
+// latitude. ToGeoJSON, ToKML and WKT write the same rings, closed and counterclockwise in
+// longitude and latitude. It returns nil for an empty cell.
+func (c Cell) LatLngRings() [][]s2.LatLng {
+	points := make(s2.PointVector, c.NumVertices())
+	for k, v := range c.VertexIndices() {
+		points[k] = c.d.Vertices[v]
+	}
+	var rings [][]s2.LatLng
+	for _, r := range lnglat.Rings(points, 0) {
+		ring := make([]s2.LatLng, len(r))
+		for k, q := range r {
+			ring[k] = s2.LatLngFromDegrees(q[1], q[0])
+		}
+		rings = append(rings, ring)
+	}
+	return rings
+}
+
+// LoopValidated builds an s2.Loop bounding the cell and checks that it is valid.
+// It repairs trivial inconsistencies of the stored ring: consecutive vertices closer than eps
+// are merged, and a reversed ring is flipped so that the site is inside the loop. In a power
+// diagram, where a site may lie outside of its cell, the vertex average takes the place of the
+// site.
+// It returns an error if the ring cannot be repaired, e.g. if it has fewer than 3 distinct
+// vertices, does not wind around the site exactly once, or is not convex.
+func (c Cell) LoopValidated() (*s2.Loop, error) {
+	eps := c.d.eps
+	num := c.NumVertices()
+
+	// The ring is CCW when looking out of the sphere, which is clockwise in the s2 convention.
+	pts := make([]s2.Point, 0, num)
+	for i := num - 1; i >= 0; i-- {
+		v := c.Vertex(i)
+		if len(pts) > 0 && pts[len(pts)-1].Sub(v.Vector).Norm() <= eps {
+			continue
+		}
+		pts = append(pts, v)
+	}
+	for len(pts) > 1 && pts[0].Sub(pts[len(pts)-1].Vector).Norm() <= eps {
+		pts = pts[:len(pts)-1]
+	}
+	if len(pts) < 3 {
+		return nil, fmt.Errorf("s2voronoi: cell %d has %d distinct vertices, need at least 3",
+			c.idx, len(pts))
+	}
+
+	site := c.fanCenter()
+	n := len(pts)
+	winding := 0.0
+	for i := range n {
+		winding += subtendedAngle(site, pts[i], pts[(i+1)%n])
+	}
+	switch turns := math.Round(winding / (2 * math.Pi)); turns {
+	case 1:
+	case -1:
+		for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
+			pts[i], pts[j] = pts[j], pts[i]
+		}
+	default:
+		return nil, fmt.Errorf("s2voronoi: cell %d ring winds %v times around the site",
+			c.idx, turns)
+	}
+
+	for i := range n {
+		if s2.RobustSign(pts[i], pts[(i+1)%n], pts[(i+2)%n]) == s2.Clockwise {
+			return nil, fmt.Errorf("s2voronoi: cell %d ring is not convex at vertex %d",
+				c.idx, (i+1)%n)
+		}
+		if c.d.weights == nil && s2.RobustSign(pts[i], pts[(i+1)%n], site) == s2.Clockwise {
+			return nil, fmt.Errorf("s2voronoi: cell %d site is outside of edge %d", c.idx, i)
+		}
+	}
+
+	l := s2.LoopFromPoints(pts)
+	if err := l.Validate(); err != nil {
+		return nil, fmt.Errorf("s2voronoi: cell %d: %w", c.idx, err)
+	}
+	return l, nil
+}
+
+// AllLoopsValidated builds validated loops for all cells of the diagram, see
+// Cell.LoopValidated. The loop of a cell that fails validation is nil, and all failures are
+// joined into the returned error.
+func (d *Diagram) AllLoopsValidated() ([]*s2.Loop, error) {
+	loops := make([]*s2.Loop, d.NumCells())
+	var errs []error
+	for i := range d.NumCells() {
+		l, err := d.Cell(i).LoopValidated()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		loops[i] = l
+	}
+	return loops, errors.Join(errs...)
+}
+
+// subtendedAngle returns the signed angle from a to b as seen from o, positive for CCW turns.
+func subtendedAngle(o, a, b s2.Point) float64 {
+	return math.Atan2(o.Dot(a.Cross(b.Vector)), a.Dot(b.Vector)-a.Dot(o.Vector)*b.Dot(o.Vector))
+}
@@ -0,0 +1,395 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/internal/fixtures"
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+func TestCell_Loop(t *testing.T) {
+	tests := []struct {
+		name  string
+		sites s2.PointVector
+	}{
+		// The cells of the axis sites contain the poles and span all longitudes.
+		{"octahedron", fixtures.Load("octahedron")},
+		{"tetrahedron", s2.PointVector{
+			s2.PointFromCoords(1, 1, 1), s2.PointFromCoords(1, -1, -1),
+			s2.PointFromCoords(-1, 1, -1), s2.PointFromCoords(-1, -1, 1),
+		}},
+		{"random", utils.GenerateRandomPoints(100, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd, err := NewDiagram(tt.sites)
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			for i := range vd.NumCells() {
+				c := vd.Cell(i)
+				l := c.Loop()
+				if err := l.Validate(); err != nil {
+					t.Fatalf("vd.Cell(%d).Loop().Validate() error = %v, want nil", i, err)
+				}
+				if !l.ContainsPoint(c.Site()) {
+					t.Errorf("vd.Cell(%d).Loop() does not contain the site", i)
+				}
+				if got, want := l.Area(), c.Area(); math.Abs(got-want) > 1e-12 {
+					t.Errorf("vd.Cell(%d).Loop().Area() = %v, want %v", i, got, want)
+				}
+
+				p := c.Polygon()
+				if err := p.Validate(); err != nil {
+					t.Fatalf("vd.Cell(%d).Polygon().Validate() error = %v, want nil", i, err)
+				}
+				if p.NumLoops() != 1 || !p.ContainsPoint(c.Site()) {
+					t.Errorf("vd.Cell(%d).Polygon() = %d loops, want 1 loop containing the site",
+						i, p.NumLoops())
+				}
+			}
+		})
+	}
+}
+
+func TestCell_Loop_EmptyCell(t *testing.T) {
+	vd, empty := mustNewEmptyCellDiagram(t)
+	c := vd.Cell(empty)
+	if l := c.Loop(); !l.IsEmpty() {
+		t.Errorf("c.Loop() = %v, want empty loop", l)
+	}
+	if p := c.Polygon(); !p.IsEmpty() {
+		t.Errorf("c.Polygon() = %v, want empty polygon", p)
+	}
+}
+
+func TestCell_LatLngRings(t *testing.T) {
+	tests := []struct {
+		name  string
+		sites s2.PointVector
+		// wantSplit is the number of cells that are split at the antimeridian.
+		wantSplit int
+	}{
+		// The cell of -x straddles the antimeridian, and the cells of the poles cross it once.
+		{"octahedron", fixtures.Load("octahedron"), 1},
+		// The cells of the 5 sites on the antimeridian straddle it.
+		{"antimeridian", fixtures.Load("antimeridian"), 5},
+		{"dateline", s2.PointVector{
+			s2.PointFromLatLng(s2.LatLngFromDegrees(0, 180)),
+			s2.PointFromLatLng(s2.LatLngFromDegrees(40, 120)),
+			s2.PointFromLatLng(s2.LatLngFromDegrees(-40, -120)),
+			s2.PointFromLatLng(s2.LatLngFromDegrees(10, 0)),
+			s2.PointFromLatLng(s2.LatLngFromDegrees(-80, 30)),
+		}, -1},
+		{"random", utils.GenerateRandomPoints(100, 0), -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd, err := NewDiagram(tt.sites)
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			split := 0
+			for i, c := range vd.Cells() {
+				rings := c.LatLngRings()
+				if len(rings) > 1 {
+					split++
+				}
+				area := 0.0
+				for _, ring := range rings {
+					area += ringArea(ring)
+				}
+				if math.Abs(area-c.Area()) > 1e-12 {
+					t.Errorf("cell %d rings cover %v, want Area() = %v", i, area, c.Area())
+				}
+				var vertices []s2.LatLng
+				for _, v := range c.VertexPoints() {
+					vertices = append(vertices, s2.LatLngFromPoint(v))
+				}
+				crosses := false
+				for k, ll := range vertices {
+					next := vertices[(k+1)%len(vertices)]
+					crosses = crosses || math.Abs((next.Lng-ll.Lng).Degrees()) > 180
+				}
+				// Without a crossing, the ring is the vertices with coincident ones merged.
+				vertices = slices.Compact(vertices)
+				for len(vertices) > 1 && vertices[0] == vertices[len(vertices)-1] {
+					vertices = vertices[:len(vertices)-1]
+				}
+				if !crosses && len(vertices) > 0 {
+					if len(rings) != 1 || len(rings[0]) != len(vertices) {
+						t.Fatalf("cell %d LatLngRings() = %v, want vertices %v", i, rings, vertices)
+					}
+					for k, ll := range vertices {
+						if !rings[0][k].ApproxEqual(ll) {
+							t.Errorf("cell %d LatLngRings()[0][%d] = %v, want vertex %v", i, k,
+								rings[0][k], ll)
+						}
+					}
+				}
+			}
+			if tt.wantSplit >= 0 && split != tt.wantSplit {
+				t.Errorf("%d cells split, want %d", split, tt.wantSplit)
+			}
+		})
+	}
+}
+
+func TestCell_LatLngRings_Dateline(t *testing.T) {
+	// The cell of the site on the dateline lies on both sides of it.
+	vd, err := NewDiagram(s2.PointVector{
+		s2.PointFromLatLng(s2.LatLngFromDegrees(0, 180)),
+		s2.PointFromLatLng(s2.LatLngFromDegrees(40, 120)),
+		s2.PointFromLatLng(s2.LatLngFromDegrees(-40, -120)),
+		s2.PointFromLatLng(s2.LatLngFromDegrees(10, 0)),
+		s2.PointFromLatLng(s2.LatLngFromDegrees(-80, 30)),
+	})
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	c := vd.Cell(0)
+	rings := c.LatLngRings()
+	if len(rings) != 2 {
+		t.Fatalf("LatLngRings() = %v, want 2 rings", rings)
+	}
+	for _, ring := range rings {
+		for _, ll := range ring {
+			if (ll.Lng.Degrees() < 0) != (ring[0].Lng.Degrees() < 0) &&
+				math.Abs(ll.Lng.Degrees()) != 180 {
+				t.Errorf("LatLngRings() ring %v is on both sides of the dateline", ring)
+			}
+		}
+		// Every split point lies on the great circle arc of the edge it splits, so it is
+		// equidistant from the site and its neighbor across the edge.
+		for _, ll := range ring {
+			if math.Abs(ll.Lng.Degrees()) != 180 || math.Abs(ll.Lat.Degrees()) == 90 {
+				continue
+			}
+			p := s2.PointFromLatLng(ll)
+			d0 := p.Distance(vd.Sites[0])
+			nearest := s1.InfAngle()
+			for j := 1; j < vd.NumCells(); j++ {
+				nearest = min(nearest, p.Distance(vd.Sites[j]))
+			}
+			if math.Abs(float64(d0-nearest)) > 1e-12 {
+				t.Errorf("split point %v is %v from the site and %v from the nearest other", ll,
+					d0, nearest)
+			}
+		}
+	}
+	area := ringArea(rings[0]) + ringArea(rings[1])
+	if math.Abs(area-c.Area()) > 1e-12 {
+		t.Errorf("LatLngRings() cover %v, want Area() = %v", area, c.Area())
+	}
+}
+
+func TestCell_LoopValidated(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	for i := range vd.NumCells() {
+		c := vd.Cell(i)
+		l, err := c.LoopValidated()
+		if err != nil {
+			t.Fatalf("vd.Cell(%d).LoopValidated() error = %v, want nil", i, err)
+		}
+		if l.NumVertices() != c.NumVertices() {
+			t.Errorf("vd.Cell(%d).LoopValidated() vertices = %d, want %d", i, l.NumVertices(),
+				c.NumVertices())
+		}
+		if !l.ContainsPoint(c.Site()) {
+			t.Errorf("vd.Cell(%d).LoopValidated() does not contain the site", i)
+		}
+	}
+}
+
+func TestCell_LoopValidated_Power(t *testing.T) {
+	const n = 500
+	sites := utils.GenerateRandomPoints(n, 0)
+	weights := make([]float64, n)
+	for i := range weights {
+		weights[i] = 4 * math.Pi / n * math.Sin(float64(7*i))
+	}
+	vd, err := NewPowerDiagram(sites, weights)
+	if err != nil {
+		t.Fatalf("NewPowerDiagram(...) error = %v, want nil", err)
+	}
+	outside := 0
+	for i, c := range vd.Cells() {
+		if c.IsEmpty() {
+			continue
+		}
+		if !c.ContainsPoint(c.Site()) {
+			outside++
+		}
+		if _, err := c.LoopValidated(); err != nil {
+			t.Errorf("vd.Cell(%d).LoopValidated() error = %v, want nil", i, err)
+		}
+	}
+	if outside == 0 {
+		t.Errorf("all sites lie in their cells, want some outside")
+	}
+}
+
+func TestCell_LoopValidated_Repair(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(ring []int) []int
+	}{
+		{
+			name: "duplicate consecutive vertex",
+			corrupt: func(ring []int) []int {
+				return slices.Insert(ring, 1, ring[1])
+			},
+		},
+		{
+			name: "duplicate wrap-around vertex",
+			corrupt: func(ring []int) []int {
+				return append(ring, ring[0])
+			},
+		},
+		{
+			name: "reversed ring",
+			corrupt: func(ring []int) []int {
+				slices.Reverse(ring)
+				return ring
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd := mustNewDiagram(t, 100)
+			want := vd.Cell(0).NumVertices()
+			vd = withCellRing(vd, 0, tt.corrupt(slices.Clone(vd.Cell(0).VertexIndices())))
+
+			l, err := vd.Cell(0).LoopValidated()
+			if err != nil {
+				t.Fatalf("vd.Cell(0).LoopValidated() error = %v, want nil", err)
+			}
+			if l.NumVertices() != want {
+				t.Errorf("vd.Cell(0).LoopValidated() vertices = %d, want %d", l.NumVertices(), want)
+			}
+			if !l.ContainsPoint(vd.Cell(0).Site()) {
+				t.Errorf("vd.Cell(0).LoopValidated() does not contain the site")
+			}
+		})
+	}
+}
+
+func TestCell_LoopValidated_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(ring []int) []int
+	}{
+		{
+			name: "too few vertices",
+			corrupt: func(ring []int) []int {
+				return []int{ring[0], ring[1], ring[1]}
+			},
+		},
+		{
+			name: "out of order vertices",
+			corrupt: func(ring []int) []int {
+				ring[1], ring[3] = ring[3], ring[1]
+				return ring
+			},
+		},
+		{
+			name: "ring winds twice",
+			corrupt: func(ring []int) []int {
+				return append(ring, ring...)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd := mustNewDiagram(t, 100)
+			i := 0
+			for vd.Cell(i).NumVertices() < 5 {
+				i++
+			}
+			vd = withCellRing(vd, i, tt.corrupt(slices.Clone(vd.Cell(i).VertexIndices())))
+
+			if _, err := vd.Cell(i).LoopValidated(); err == nil {
+				t.Errorf("vd.Cell(%d).LoopValidated() error = nil, want non-nil", i)
+			}
+		})
+	}
+}
+
+func TestDiagram_AllLoopsValidated(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	loops, err := vd.AllLoopsValidated()
+	if err != nil {
+		t.Fatalf("vd.AllLoopsValidated() error = %v, want nil", err)
+	}
+	if len(loops) != vd.NumCells() {
+		t.Fatalf("vd.AllLoopsValidated() loops = %d, want %d", len(loops), vd.NumCells())
+	}
+
+	broken := []int{3, 7}
+	for _, i := range broken {
+		ring := slices.Clone(vd.Cell(i).VertexIndices())
+		vd = withCellRing(vd, i, ring[:2])
+	}
+	loops, err = vd.AllLoopsValidated()
+	if err == nil {
+		t.Fatalf("vd.AllLoopsValidated() error = nil, want non-nil")
+	}
+	for _, i := range broken {
+		if loops[i] != nil {
+			t.Errorf("vd.AllLoopsValidated() loop %d = %v, want nil", i, loops[i])
+		}
+		if !strings.Contains(err.Error(), fmt.Sprintf("cell %d ", i)) {
+			t.Errorf("vd.AllLoopsValidated() error = %v, want mention of cell %d", err, i)
+		}
+	}
+}
+
+// withCellRing returns a copy of the diagram with the vertex ring of cell i replaced.
+// The neighbor ring of the cell is resized to match and filled with the cell's old neighbors.
+func withCellRing(vd *Diagram, i int, ring []int) *Diagram {
+	nd := *vd
+	nd.CellVertices = nil
+	nd.CellNeighbors = nil
+	nd.CellOffsets = []int{0}
+	for j := range vd.NumCells() {
+		verts := vd.Cell(j).VertexIndices()
+		neighbors := vd.Cell(j).NeighborIndices()
+		if j == i {
+			verts = ring
+			neighbors = make([]int, len(ring))
+			for k := range neighbors {
+				neighbors[k] = vd.Cell(j).NeighborIndices()[k%vd.Cell(j).NumNeighbors()]
+			}
+		}
+		nd.CellVertices = append(nd.CellVertices, verts...)
+		nd.CellNeighbors = append(nd.CellNeighbors, neighbors...)
+		nd.CellOffsets = append(nd.CellOffsets, len(nd.CellVertices))
+	}
+	return &nd
+}
+
+// Helpers
+
+// ringArea returns the area of the ring in latitude and longitude, CCW when looking out of the
+// sphere, merging the positions on a pole.
+func ringArea(ring []s2.LatLng) float64 {
+	var points []s2.Point
+	for k := len(ring) - 1; k >= 0; k-- {
+		p := s2.PointFromLatLng(ring[k])
+		if len(points) > 0 && points[len(points)-1].Distance(p) < 1e-12 {
+			continue
+		}
+		points = append(points, p)
+	}
+	return s2.LoopFromPoints(points).Area()
+}
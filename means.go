@@ -0,0 +1,51 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/s2"
+)
+
+// WeightedCellMeans assigns every data point to the cell containing it and returns for each
+// cell the weighted spherical mean of its points, i.e. the normalized weighted sum, and their
+// total weight. Cells without points or with zero total weight have a total weight of 0 and
+// their site as mean, so one Lloyd step towards the data leaves them in place.
+// It returns an error if the lengths of points and weights differ or a weight is negative or
+// not finite.
+func (d *Diagram) WeightedCellMeans(points []s2.Point,
+	weights []float64) ([]s2.Point, []float64, error) {
+	if len(points) != len(weights) {
+		return nil, nil, fmt.Errorf("s2voronoi: %d points but %d weights", len(points), len(weights))
+	}
+	for i, w := range weights {
+		if w < 0 || math.IsInf(w, 0) || math.IsNaN(w) {
+			return nil, nil, fmt.Errorf("s2voronoi: weight %d must be finite and non-negative, got %v",
+				i, w)
+		}
+	}
+
+	sums := make([]compensatedVector, d.NumCells())
+	totals := make([]compensatedSum, d.NumCells())
+	for i, c := range d.locateMany(points) {
+		sums[c].Add(points[i].Mul(weights[i]))
+		totals[c].Add(weights[i])
+	}
+
+	means := make([]s2.Point, d.NumCells())
+	total := make([]float64, d.NumCells())
+	for i := range means {
+		total[i] = totals[i].Value()
+		sum := sums[i].Value()
+		if total[i] == 0 || sum.Norm2() == 0 {
+			means[i] = d.Sites[i]
+			continue
+		}
+		means[i] = s2.Point{Vector: sum.Normalize()}
+	}
+	return means, total, nil
+}
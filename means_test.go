@@ -0,0 +1,125 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/r3"
+	"github.com/golang/geo/s2"
+)
+
+func TestDiagram_WeightedCellMeans(t *testing.T) {
+	vd := mustNewDiagram(t, 50)
+	points := utils.GenerateRandomPoints(2000, 1)
+	random := rand.New(rand.NewSource(0))
+	weights := make([]float64, len(points))
+	for i := range weights {
+		weights[i] = random.Float64()
+	}
+
+	means, totals, err := vd.WeightedCellMeans(points, weights)
+	if err != nil {
+		t.Fatalf("vd.WeightedCellMeans(...) error = %v, want nil", err)
+	}
+
+	sums := make([]r3.Vector, vd.NumCells())
+	wantTotals := make([]float64, vd.NumCells())
+	for i, p := range points {
+		c := nearestSite(vd, p)
+		sums[c] = sums[c].Add(p.Mul(weights[i]))
+		wantTotals[c] += weights[i]
+	}
+	for i := range vd.NumCells() {
+		if math.Abs(totals[i]-wantTotals[i]) > 1e-9 {
+			t.Errorf("vd.WeightedCellMeans(...) total[%d] = %v, want %v", i, totals[i], wantTotals[i])
+		}
+		want := s2.Point{Vector: sums[i].Normalize()}
+		if wantTotals[i] == 0 {
+			want = vd.Sites[i]
+		}
+		if means[i].Distance(want) > 1e-9 {
+			t.Errorf("vd.WeightedCellMeans(...) mean[%d] = %v, want %v", i, means[i], want)
+		}
+	}
+}
+
+func TestDiagram_WeightedCellMeans_Empty(t *testing.T) {
+	vd := mustNewDiagram(t, 50)
+	points := []s2.Point{vd.Sites[1], vd.Sites[2]}
+	means, totals, err := vd.WeightedCellMeans(points, []float64{2, 0})
+	if err != nil {
+		t.Fatalf("vd.WeightedCellMeans(...) error = %v, want nil", err)
+	}
+	if totals[1] != 2 || means[1] != vd.Sites[1] {
+		t.Errorf("vd.WeightedCellMeans(...) cell 1 = %v, %v, want %v, 2", means[1], totals[1],
+			vd.Sites[1])
+	}
+	for _, i := range []int{0, 2} {
+		if totals[i] != 0 || means[i] != vd.Sites[i] {
+			t.Errorf("vd.WeightedCellMeans(...) cell %d = %v, %v, want site, 0", i, means[i],
+				totals[i])
+		}
+	}
+}
+
+func TestDiagram_WeightedCellMeans_EmptyPowerCell(t *testing.T) {
+	// Cell 0 is empty, so no walk may start there.
+	const n = 100
+	sites := utils.GenerateRandomPoints(n, 0)
+	siteWeights := make([]float64, n)
+	siteWeights[0] = -1
+	vd, err := NewPowerDiagram(sites, siteWeights)
+	if err != nil {
+		t.Fatalf("NewPowerDiagram(...) error = %v, want nil", err)
+	}
+	if !vd.Cell(0).IsEmpty() {
+		t.Fatalf("vd.Cell(0).IsEmpty() = false, want true")
+	}
+	points := utils.GenerateRandomPoints(1000, 1)
+	weights := make([]float64, len(points))
+	for i := range weights {
+		weights[i] = 1
+	}
+
+	_, totals, err := vd.WeightedCellMeans(points, weights)
+	if err != nil {
+		t.Fatalf("vd.WeightedCellMeans(...) error = %v, want nil", err)
+	}
+	wantTotals := make([]float64, n)
+	for _, p := range points {
+		wantTotals[bruteForcePowerCell(sites, siteWeights, p)]++
+	}
+	for i := range n {
+		if totals[i] != wantTotals[i] {
+			t.Errorf("vd.WeightedCellMeans(...) total[%d] = %v, want %v", i, totals[i],
+				wantTotals[i])
+		}
+	}
+}
+
+func TestDiagram_WeightedCellMeans_Error(t *testing.T) {
+	vd := mustNewDiagram(t, 50)
+	points := []s2.Point{vd.Sites[0], vd.Sites[1]}
+	tests := []struct {
+		name    string
+		weights []float64
+	}{
+		{"length mismatch", []float64{1}},
+		{"negative weight", []float64{1, -1}},
+		{"NaN weight", []float64{math.NaN(), 1}},
+		{"infinite weight", []float64{1, math.Inf(1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := vd.WeightedCellMeans(points, tt.weights); err == nil {
+				t.Errorf("vd.WeightedCellMeans(..., %v) error = nil, want non-nil", tt.weights)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"unsafe"
+
+	"github.com/golang/geo/s2"
+)
+
+// MemoryStats holds the number of bytes used by the major arrays of a diagram. The sizes are
+// computed from the capacities of the slices and the sizes of their elements; maps are counted
+// by their entries only, so the figures are a lower bound of the actual heap usage.
+type MemoryStats struct {
+	Sites         int
+	Vertices      int
+	CellVertices  int
+	CellNeighbors int
+	CellOffsets   int
+	// Weights are the site weights of a power diagram.
+	Weights int
+	// Sources are the input sites of WithDeduplication, see SourceIndex.
+	Sources int
+	// SiteData are the interface values of SetSiteData, not counting what they point to.
+	SiteData int
+	// Caches are the lazily built structures, which count only once materialized, and the
+	// triangles kept by Rebuild and WithRetainedTriangulation. Of the index of ShapeIndex only
+	// the vertices of the cell loops count, not the index cells built by s2.ShapeIndex.
+	Caches int
+	// Total is the sum of all other fields.
+	Total int
+}
+
+// MemoryFootprint reports the memory used by the diagram. The tables of BuildCellIDIndex are
+// not held by the diagram and report their own size, see CellIDIndex.MemoryFootprint.
+// It must not be called concurrently with methods that build caches.
+func (d *Diagram) MemoryFootprint() MemoryStats {
+	const (
+		pointSize = int(unsafe.Sizeof(s2.Point{}))
+		intSize   = int(unsafe.Sizeof(0))
+		capSize   = int(unsafe.Sizeof(s2.Cap{}))
+		keySize   = int(unsafe.Sizeof(uint64(0)))
+		floatSize = int(unsafe.Sizeof(float64(0)))
+		anySize   = int(unsafe.Sizeof(any(nil)))
+	)
+
+	s := MemoryStats{
+		Sites:         cap(d.Sites) * pointSize,
+		Vertices:      cap(d.Vertices) * pointSize,
+		CellVertices:  cap(d.CellVertices) * intSize,
+		CellNeighbors: cap(d.CellNeighbors) * intSize,
+		CellOffsets:   cap(d.CellOffsets) * intSize,
+		Weights:       cap(d.weights) * floatSize,
+		Sources:       (cap(d.sources.indices) + cap(d.sources.offsets)) * intSize,
+		SiteData:      cap(d.siteData) * anySize,
+	}
+	if c := d.cache; c != nil {
+		s.Caches = len(c.neighbors)*keySize + cap(c.capBounds)*capSize + cap(c.hints)*intSize +
+			(cap(c.vertexCellOffsets)+cap(c.vertexCells)+cap(c.vertexNeighbors))*intSize
+		if c.shapeIndex != nil {
+			for i := range c.shapeIndex.Len() {
+				if cs, ok := c.shapeIndex.Shape(int32(i)).(*CellShape); ok {
+					s.Caches += cap(cs.Vertices()) * pointSize
+				}
+			}
+		}
+	}
+	if b := d.buffers; b != nil {
+		s.Caches += cap(b.dt.Triangles) * 3 * intSize
+	}
+	// A retained triangulation shares all but its triangles with the diagram.
+	if c := d.cache; c != nil && c.triangulation != nil &&
+		(d.buffers == nil || c.triangulation != &d.buffers.dt) {
+		s.Caches += cap(c.triangulation.Triangles) * 3 * intSize
+	}
+	s.Total = s.Sites + s.Vertices + s.CellVertices + s.CellNeighbors + s.CellOffsets +
+		s.Weights + s.Sources + s.SiteData + s.Caches
+	return s
+}
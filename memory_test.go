@@ -0,0 +1,97 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiagram_MemoryFootprint(t *testing.T) {
+	if strconv.IntSize != 64 {
+		t.Skip("sizes are computed for 64-bit ints")
+	}
+
+	// 100 sites give 196 vertices and 588 cell edges.
+	vd := mustNewDiagram(t, 100)
+	want := MemoryStats{
+		Sites:         100 * 24,
+		Vertices:      196 * 24,
+		CellVertices:  588 * 8,
+		CellNeighbors: 588 * 8,
+		CellOffsets:   101 * 8,
+	}
+	want.Total = want.Sites + want.Vertices + want.CellVertices + want.CellNeighbors +
+		want.CellOffsets
+	if diff := cmp.Diff(want, vd.MemoryFootprint()); diff != "" {
+		t.Errorf("vd.MemoryFootprint() mismatch (-want +got):\n%s", diff)
+	}
+
+	vd.AreNeighbors(0, 1)
+	want.Caches = 294 * 8
+	want.Total += want.Caches
+	if diff := cmp.Diff(want, vd.MemoryFootprint()); diff != "" {
+		t.Errorf("vd.MemoryFootprint() after AreNeighbors mismatch (-want +got):\n%s", diff)
+	}
+
+	vd.CellCapBounds()
+	want.Caches += 100 * 32
+	want.Total += 100 * 32
+	if diff := cmp.Diff(want, vd.MemoryFootprint()); diff != "" {
+		t.Errorf("vd.MemoryFootprint() after CellCapBounds mismatch (-want +got):\n%s", diff)
+	}
+
+	// Without vertex merging the loops of the shape index hold the 588 cell edges.
+	vd.ShapeIndex()
+	want.Caches += 588 * 24
+	want.Total += 588 * 24
+	if diff := cmp.Diff(want, vd.MemoryFootprint()); diff != "" {
+		t.Errorf("vd.MemoryFootprint() after ShapeIndex mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDiagram_MemoryFootprint_Extras(t *testing.T) {
+	if strconv.IntSize != 64 {
+		t.Skip("sizes are computed for 64-bit ints")
+	}
+
+	sites := utils.GenerateRandomPoints(100, 0)
+	weights := make([]float64, len(sites))
+	weights[0] = 0.01
+	power, err := NewPowerDiagram(sites, weights)
+	if err != nil {
+		t.Fatalf("NewPowerDiagram(...) error = %v, want nil", err)
+	}
+	power.SetSiteData(0, "a")
+	got := power.MemoryFootprint()
+	if got.Weights < 100*8 {
+		t.Errorf("power.MemoryFootprint().Weights = %d, want at least %d", got.Weights, 100*8)
+	}
+	if got.SiteData != 100*16 {
+		t.Errorf("power.MemoryFootprint().SiteData = %d, want %d", got.SiteData, 100*16)
+	}
+
+	clustered, _ := clusteredSites(200)
+	dedup, err := NewDiagram(clustered, WithDeduplication(1e-6))
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	got = dedup.MemoryFootprint()
+	if want := (len(clustered) + dedup.NumCells() + 1) * 8; got.Sources < want {
+		t.Errorf("dedup.MemoryFootprint().Sources = %d, want at least %d", got.Sources, want)
+	}
+	if sum := got.Sites + got.Vertices + got.CellVertices + got.CellNeighbors +
+		got.CellOffsets + got.Weights + got.Sources + got.SiteData + got.Caches; got.Total != sum {
+		t.Errorf("dedup.MemoryFootprint().Total = %d, want the sum %d", got.Total, sum)
+	}
+
+	x := dedup.BuildCellIDIndex(8)
+	if want := x.NumEntries() * 16; x.MemoryFootprint() < want {
+		t.Errorf("x.MemoryFootprint() = %d, want at least %d", x.MemoryFootprint(), want)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"math"
+
+	"github.com/golang/geo/r3"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// WithVertexMerging merges the Voronoi vertices joined by an edge shorter than an angle of tol
+// into single vertices of higher degree, e.g. the circumcenters of four or more cocircular
+// sites, which are otherwise connected by edges of zero length. The merged vertex lies at the
+// normalized mean of the vertices it replaces, the remaining vertices keep their relative order,
+// and the short edges are removed from the rings together with their neighbors, so cells that
+// touch at a merged vertex only are no longer neighbors.
+//
+// The vertices of the diagram then no longer correspond to the triangles of the dual Delaunay
+// triangulation: VertexCells may have more than 3 cells, Triangulation and
+// RebuildFromSitesAndVertices fail, and the methods adding, moving or removing sites rebuild the
+// diagram from scratch. The option is kept by Relax. A merged vertex is no longer exactly
+// equidistant from its sites, so Validate allows it to be off by tol per merged edge, and
+// MarshalJSON, Encode and ToProto keep tol for that check. tol must be positive and small enough
+// not to collapse any cell. WithRetainedTriangulation is ignored together with it, and it is not
+// supported by NewPowerDiagram, NewDiagramFromParts and NewDiagramFromTriangulation.
+func WithVertexMerging(tol s1.Angle) DiagramOption {
+	return func(o *DiagramOptions) error {
+		if !(tol > 0) || math.IsInf(float64(tol), 0) {
+			return errorf(ErrInvalidOption, "s2voronoi: vertex merging tolerance must be positive "+
+				"and finite, got %v", tol)
+		}
+		o.VertexMerging = tol
+		return nil
+	}
+}
+
+// mergeVertices merges the vertices joined by edges shorter than tol in place, see
+// WithVertexMerging, and records tol in the diagram. The vertices are grouped by vertexGroups,
+// each group taking the smallest index of its members. It returns an error if a ring would have
+// fewer than 3 vertices or visit a vertex twice.
+func (d *Diagram) mergeVertices(tol s1.Angle) error {
+	d.mergeTol = tol
+	root, merged := d.vertexGroups(s1.ChordAngleFromAngle(tol))
+	if !merged {
+		return nil
+	}
+
+	// remap holds the new index of every vertex, and sums the sums of the groups.
+	remap := make([]int, len(d.Vertices))
+	var sums []r3.Vector
+	for v := range d.Vertices {
+		if root[v] == v {
+			remap[v] = len(sums)
+			sums = append(sums, r3.Vector{})
+		}
+	}
+	for v, p := range d.Vertices {
+		remap[v] = remap[root[v]]
+		sums[remap[v]] = sums[remap[v]].Add(p.Vector)
+	}
+	d.Vertices = d.Vertices[:len(sums)]
+	for v, s := range sums {
+		d.Vertices[v] = s2.Point{Vector: s.Normalize()}
+	}
+
+	// The rings are compacted in place: the write position never passes the read position.
+	offsets, cellVertices, cellNeighbors := d.CellOffsets, d.CellVertices, d.CellNeighbors
+	w, start := 0, 0
+	for i := range d.NumCells() {
+		end := offsets[i+1]
+		if end == start {
+			offsets[i+1] = w
+			continue
+		}
+		first := remap[cellVertices[start]]
+		for k := start; k < end; k++ {
+			a, b := remap[cellVertices[k]], first
+			if k+1 < end {
+				b = remap[cellVertices[k+1]]
+			}
+			if a == b {
+				continue
+			}
+			cellVertices[w] = a
+			cellNeighbors[w] = cellNeighbors[k]
+			w++
+		}
+		ring := cellVertices[offsets[i]:w]
+		if len(ring) < 3 {
+			return errorf(ErrInvalidOption, "s2voronoi: merging vertices within %v collapses "+
+				"cell %d to %d vertices", tol, i, len(ring))
+		}
+		for k, v := range ring {
+			for _, u := range ring[k+1:] {
+				if u == v {
+					return errorf(ErrInvalidOption, "s2voronoi: merging vertices within %v "+
+						"makes the ring of cell %d visit vertex %d twice", tol, i, v)
+				}
+			}
+		}
+		start = end
+		offsets[i+1] = w
+	}
+	d.CellVertices = cellVertices[:w]
+	d.CellNeighbors = cellNeighbors[:w]
+	d.invalidateCaches()
+	return nil
+}
+
+// vertexGroups groups the vertices joined by cell edges of at most maxChord with a union-find,
+// and returns the smallest index of the group of every vertex and whether any group has more
+// than one member.
+func (d *Diagram) vertexGroups(maxChord s1.ChordAngle) ([]int, bool) {
+	parent := make([]int, len(d.Vertices))
+	for v := range parent {
+		parent[v] = v
+	}
+	find := func(v int) int {
+		for parent[v] != v {
+			parent[v] = parent[parent[v]]
+			v = parent[v]
+		}
+		return v
+	}
+	merged := false
+	for i := range d.NumCells() {
+		ring := d.Cell(i).VertexIndices()
+		for k, a := range ring {
+			b := ring[(k+1)%len(ring)]
+			if s2.ChordAngleBetweenPoints(d.Vertices[a], d.Vertices[b]) > maxChord {
+				continue
+			}
+			if ra, rb := find(a), find(b); ra != rb {
+				parent[max(ra, rb)] = min(ra, rb)
+				merged = true
+			}
+		}
+	}
+	for v := range parent {
+		parent[v] = find(v)
+	}
+	return parent, merged
+}
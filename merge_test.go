@@ -0,0 +1,336 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/internal/fixtures"
+	"github.com/2dChan/s2voronoi/s2delaunay"
+	"github.com/2dChan/s2voronoi/s2voronoipb"
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/r3"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+func TestWithVertexMerging(t *testing.T) {
+	tests := []struct {
+		name    string
+		tol     s1.Angle
+		wantErr bool
+	}{
+		{"positive", 1e-9, false},
+		{"zero", 0, true},
+		{"negative", -1e-9, true},
+		{"infinite", s1.InfAngle(), true},
+		{"nan", s1.Angle(math.NaN()), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts DiagramOptions
+			err := WithVertexMerging(tt.tol)(&opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WithVertexMerging(%v) error = %v, wantErr %v", tt.tol, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidOption) {
+				t.Errorf("WithVertexMerging(%v) error = %v, want errors.Is(err, ErrInvalidOption)",
+					tt.tol, err)
+			}
+			if !tt.wantErr && opts.VertexMerging != tt.tol {
+				t.Errorf("WithVertexMerging(%v) VertexMerging = %v, want %v", tt.tol,
+					opts.VertexMerging, tt.tol)
+			}
+		})
+	}
+}
+
+func TestNewDiagram_WithVertexMerging(t *testing.T) {
+	tests := []struct {
+		name         string
+		sites        s2.PointVector
+		opts         []DiagramOption
+		wantVertices int
+		// wantRing is the number of vertices of every cell, or 0 if it varies.
+		wantRing int
+	}{
+		{"cube", cube(), nil, 6, 3},
+		{"cube order independent", cube(), []DiagramOption{WithOrderIndependentOutput()}, 6, 3},
+		{"cocircular rings", fixtures.Load("cocircular-rings"), nil, 14, 3},
+		{"five points", fixtures.Load("five-points"), nil, 6, 0},
+		{"lat lng grid", latLngGrid(5, 8), nil, 4*8 + 2*8, 0},
+		{"random", utils.GenerateRandomPoints(100, 0), nil, 2*100 - 4, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]DiagramOption{WithVertexMerging(1e-9)}, tt.opts...)
+			vd, err := NewDiagram(slices.Clone(tt.sites), opts...)
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			if err := vd.Validate(); err != nil {
+				t.Errorf("vd.Validate() = %v, want nil", err)
+			}
+			if len(vd.Vertices) != tt.wantVertices {
+				t.Errorf("len(vd.Vertices) = %d, want %d", len(vd.Vertices), tt.wantVertices)
+			}
+			checkMergedRings(t, vd)
+			for i, c := range vd.Cells() {
+				if tt.wantRing != 0 && c.NumVertices() != tt.wantRing {
+					t.Errorf("cell %d has %d vertices, want %d", i, c.NumVertices(), tt.wantRing)
+				}
+			}
+		})
+	}
+}
+
+func TestDiagram_WithVertexMerging_Edits(t *testing.T) {
+	sites := latLngGrid(5, 8)
+	fresh := s2.PointFromLatLng(s2.LatLngFromDegrees(10, 10))
+	tests := []struct {
+		name string
+		// edit changes the diagram and returns its expected sites.
+		edit func(t *testing.T, vd *Diagram) s2.PointVector
+	}{
+		{
+			name: "add site",
+			edit: func(t *testing.T, vd *Diagram) s2.PointVector {
+				if _, err := vd.AddSite(fresh); err != nil {
+					t.Fatalf("vd.AddSite(...) error = %v, want nil", err)
+				}
+				return append(slices.Clone(sites), fresh)
+			},
+		},
+		{
+			name: "add sites",
+			edit: func(t *testing.T, vd *Diagram) s2.PointVector {
+				if _, err := vd.AddSites(s2.PointVector{fresh}); err != nil {
+					t.Fatalf("vd.AddSites(...) error = %v, want nil", err)
+				}
+				return append(slices.Clone(sites), fresh)
+			},
+		},
+		{
+			name: "remove site",
+			edit: func(t *testing.T, vd *Diagram) s2.PointVector {
+				if err := vd.RemoveSite(3); err != nil {
+					t.Fatalf("vd.RemoveSite(...) error = %v, want nil", err)
+				}
+				want := slices.Clone(sites)
+				want[3] = want[len(want)-1]
+				return want[:len(want)-1]
+			},
+		},
+		{
+			name: "move site",
+			edit: func(t *testing.T, vd *Diagram) s2.PointVector {
+				if _, err := vd.MoveSite(3, fresh); err != nil {
+					t.Fatalf("vd.MoveSite(...) error = %v, want nil", err)
+				}
+				want := slices.Clone(sites)
+				want[3] = fresh
+				return want
+			},
+		},
+		{
+			name: "relax",
+			edit: func(t *testing.T, vd *Diagram) s2.PointVector {
+				if _, err := vd.Relax(1); err != nil {
+					t.Fatalf("vd.Relax(...) error = %v, want nil", err)
+				}
+				return slices.Clone(vd.Sites)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd, err := NewDiagram(slices.Clone(sites), WithVertexMerging(1e-9))
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			want, err := NewDiagram(tt.edit(t, vd), WithVertexMerging(1e-9))
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			if !vd.Equal(want) {
+				t.Errorf("edited diagram differs from NewDiagram(...) with WithVertexMerging")
+			}
+			checkMergedRings(t, vd)
+		})
+	}
+}
+
+func TestNewDiagram_WithVertexMerging_Errors(t *testing.T) {
+	sites := cube()
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{
+			name: "collapsing tolerance",
+			run: func() error {
+				_, err := NewDiagram(utils.GenerateRandomPoints(100, 0), WithVertexMerging(1))
+				return err
+			},
+		},
+		{
+			name: "power diagram",
+			run: func() error {
+				weights := make([]float64, len(sites))
+				weights[0] = 0.01
+				_, err := NewPowerDiagram(slices.Clone(sites), weights, WithVertexMerging(1e-9))
+				return err
+			},
+		},
+		{
+			name: "parts",
+			run: func() error {
+				vd := mustNewDiagram(t, 100)
+				_, err := NewDiagramFromParts(vd.Sites, vd.Vertices, vd.CellVertices,
+					vd.CellNeighbors, vd.CellOffsets, WithVertexMerging(1e-9))
+				return err
+			},
+		},
+		{
+			name: "triangulation",
+			run: func() error {
+				dt, err := s2delaunay.NewTriangulation(slices.Clone(sites))
+				if err != nil {
+					t.Fatalf("s2delaunay.NewTriangulation(...) error = %v, want nil", err)
+				}
+				_, err = NewDiagramFromTriangulation(dt, WithVertexMerging(1e-9))
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); !errors.Is(err, ErrInvalidOption) {
+				t.Errorf("error = %v, want errors.Is(err, ErrInvalidOption)", err)
+			}
+		})
+	}
+}
+
+func TestDiagram_WithVertexMerging_RoundTrip(t *testing.T) {
+	// The sites are perturbed off their circles, so the merged vertices lie about 1e-8 away
+	// from the circumcenters they replace, far beyond the tolerance of unmerged vertices.
+	r := rand.New(rand.NewSource(0))
+	var sites s2.PointVector
+	for _, p := range latLngGrid(5, 8) {
+		off := r3.Vector{X: r.Float64() - 0.5, Y: r.Float64() - 0.5, Z: r.Float64() - 0.5}
+		sites = append(sites, s2.Point{Vector: p.Add(off.Mul(2e-8)).Normalize()})
+	}
+	vd, err := NewDiagram(sites, WithVertexMerging(1e-6))
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	if got, unmerged := len(vd.Vertices), 2*len(sites)-4; got >= unmerged {
+		t.Fatalf("len(vd.Vertices) = %d, want fewer than %d", got, unmerged)
+	}
+	if err := vd.Validate(); err != nil {
+		t.Errorf("vd.Validate() = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name string
+		// decode returns the diagram read back from the encoding of vd.
+		decode func(t *testing.T) (*Diagram, error)
+	}{
+		{
+			name: "json",
+			decode: func(t *testing.T) (*Diagram, error) {
+				data, err := json.Marshal(vd)
+				if err != nil {
+					t.Fatalf("json.Marshal(vd) error = %v, want nil", err)
+				}
+				got := new(Diagram)
+				return got, json.Unmarshal(data, got)
+			},
+		},
+		{
+			name: "binary",
+			decode: func(t *testing.T) (*Diagram, error) {
+				var buf bytes.Buffer
+				if err := vd.Encode(&buf); err != nil {
+					t.Fatalf("vd.Encode(...) error = %v, want nil", err)
+				}
+				return DecodeDiagram(&buf)
+			},
+		},
+		{
+			name: "proto",
+			decode: func(t *testing.T) (*Diagram, error) {
+				var m s2voronoipb.Diagram
+				if err := m.Unmarshal(mustMarshalProto(t, vd)); err != nil {
+					t.Fatalf("m.Unmarshal(...) error = %v, want nil", err)
+				}
+				return DiagramFromProto(&m)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.decode(t)
+			if err != nil {
+				t.Fatalf("decoding error = %v, want nil", err)
+			}
+			if err := got.Validate(); err != nil {
+				t.Errorf("got.Validate() = %v, want nil", err)
+			}
+			if !got.Equal(vd) || got.mergeTol != vd.mergeTol {
+				t.Errorf("decoded diagram differs from vd")
+			}
+		})
+	}
+}
+
+func TestDiagram_Triangulation_MergedVertices(t *testing.T) {
+	vd, err := NewDiagram(cube(), WithVertexMerging(1e-9), WithRetainedTriangulation())
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	if _, err := vd.Triangulation(); err == nil {
+		t.Errorf("vd.Triangulation() error = nil, want error")
+	}
+}
+
+// Helpers
+
+// cube returns the 8 vertices of a cube, whose faces are 6 quadruples of cocircular sites.
+func cube() s2.PointVector {
+	var sites s2.PointVector
+	for _, x := range []float64{-1, 1} {
+		for _, y := range []float64{-1, 1} {
+			for _, z := range []float64{-1, 1} {
+				sites = append(sites, s2.PointFromCoords(x, y, z))
+			}
+		}
+	}
+	return sites
+}
+
+// checkMergedRings checks that no ring of vd visits a vertex twice or has an edge shorter than
+// the merging tolerance of the tests.
+func checkMergedRings(t *testing.T, vd *Diagram) {
+	t.Helper()
+	for i, c := range vd.Cells() {
+		ring := c.VertexIndices()
+		for k, v := range ring {
+			if slices.Contains(ring[k+1:], v) {
+				t.Errorf("cell %d ring %v visits vertex %d twice", i, ring, v)
+			}
+			if w := ring[(k+1)%len(ring)]; vd.Vertices[v].Distance(vd.Vertices[w]) <= 1e-9 {
+				t.Errorf("cell %d edge %d from %d to %d has zero length", i, k, v, w)
+			}
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/golang/geo/s2"
+)
+
+// MoveSite moves the site i to p and reports whether the topology of the diagram, i.e. the
+// neighbors of any cell, changed.
+//
+// If the Delaunay triangles around i keep their orientation and their circumcircles stay empty
+// of the sites around them, the topology is unchanged and only the site and the vertices of its
+// cell are updated in place, in O(k) time for a cell with k vertices. Otherwise the site is
+// removed and inserted at p like by RemoveSite and AddSite, keeping the index i, which rewrites
+// the CSR arrays once per step. Diagrams built with WithOrderIndependentOutput or
+// WithVertexMerging are rebuilt from scratch instead.
+//
+// It returns an error and leaves the diagram unchanged if p coincides with another site within
+// eps, if the diagram is a power diagram, or if the local re-triangulation fails. It panics if i
+// is out of range.
+func (d *Diagram) MoveSite(i int, p s2.Point) (bool, error) {
+	n := d.NumCells()
+	if i < 0 || i >= n {
+		panic(fmt.Sprintf("s2voronoi: site index %d out of range [0 %d)", i, n))
+	}
+	if d.weights != nil {
+		return false, errors.New("s2voronoi: sites cannot be moved in power diagrams")
+	}
+
+	centers, ok := d.movedCenters(i, p)
+	if d.orderIndependent || d.mergeTol != 0 {
+		sites := slices.Clone(d.Sites)
+		sites[i] = p
+		nd, err := NewDiagram(sites, d.options()...)
+		if err != nil {
+			return false, err
+		}
+		nd.sources = d.sources
+		nd.siteData = d.siteData
+		*d = *nd
+		return !ok, nil
+	}
+	if ok {
+		// The nearest other site to p is a neighbor once the topology is kept, so checking
+		// them rejects the same points as AddSite does.
+		for _, j := range d.Cell(i).NeighborIndices() {
+			if p.Sub(d.Sites[j].Vector).Norm() <= d.eps {
+				return false, errorf(ErrDuplicateSites, "s2voronoi: site %v coincides with site %d",
+					p, j)
+			}
+		}
+		d.Sites[i] = p
+		for k, v := range d.Cell(i).VertexIndices() {
+			d.Vertices[v] = centers[k]
+		}
+		d.invalidateCaches()
+		return false, nil
+	}
+
+	nd := d.clone()
+	if err := nd.RemoveSite(i); err != nil {
+		return false, err
+	}
+	j, err := nd.AddSite(p)
+	if err != nil {
+		return false, err
+	}
+	nd.swapSites(i, j)
+	nd.sources = d.sources
+	nd.siteData = d.siteData
+	*d = *nd
+	return true, nil
+}
+
+// movedCenters returns the vertices of cell i with its site moved to p, in ring order, and
+// whether the moved site keeps the topology: every Delaunay triangle around it keeps its
+// orientation and no site adjacent to its corners lies in its circumcircle. Then all edges are
+// locally Delaunay and the triangulation is still the Delaunay one.
+func (d *Diagram) movedCenters(i int, p s2.Point) ([]s2.Point, bool) {
+	c := d.Cell(i)
+	ns := c.NeighborIndices()
+	num := len(ns)
+	centers := make([]s2.Point, num)
+	for k := range num {
+		a, b := ns[(k+num-1)%num], ns[k]
+		sa, sb := d.Sites[a], d.Sites[b]
+		orient := s2.RobustSign(sa, sb, d.Sites[i])
+		if orient == s2.Indeterminate || s2.RobustSign(sa, sb, p) != orient {
+			return nil, false
+		}
+		cc := triangleCircumcenter(p, sa, sb)
+		if cc.Norm() <= d.eps {
+			return nil, false
+		}
+		center := s2.Point{Vector: cc.Normalize()}
+		bound := p.Dot(center.Vector)
+		for _, x := range [2]int{a, b} {
+			for _, j := range d.Cell(x).NeighborIndices() {
+				if j != i && j != a && j != b && d.Sites[j].Dot(center.Vector) > bound {
+					return nil, false
+				}
+			}
+		}
+		centers[k] = center
+	}
+	return centers, true
+}
+
+// swapSites exchanges the indices of the sites i and j, rewriting the CSR arrays.
+func (d *Diagram) swapSites(i, j int) {
+	if i == j {
+		return
+	}
+	swap := func(k int) int {
+		switch k {
+		case i:
+			return j
+		case j:
+			return i
+		}
+		return k
+	}
+	n := d.NumCells()
+	offsets := make([]int, 1, n+1)
+	cellVertices := make([]int, 0, len(d.CellVertices))
+	cellNeighbors := make([]int, 0, len(d.CellNeighbors))
+	for k := range n {
+		c := d.Cell(swap(k))
+		cellVertices = append(cellVertices, c.VertexIndices()...)
+		for _, nb := range c.NeighborIndices() {
+			cellNeighbors = append(cellNeighbors, swap(nb))
+		}
+		offsets = append(offsets, len(cellVertices))
+	}
+	d.Sites[i], d.Sites[j] = d.Sites[j], d.Sites[i]
+	d.CellVertices = cellVertices
+	d.CellNeighbors = cellNeighbors
+	d.CellOffsets = offsets
+	d.invalidateCaches()
+}
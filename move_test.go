@@ -0,0 +1,206 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiagram_MoveSite(t *testing.T) {
+	const n, i = 300, 17
+	tests := []struct {
+		name string
+		// target returns the new position of site i.
+		target  func(vd *Diagram) s2.Point
+		changed bool
+	}{
+		{
+			name: "small move",
+			target: func(vd *Diagram) s2.Point {
+				nb := vd.Sites[vd.Cell(i).NeighborIndices()[0]]
+				return s2.Interpolate(0.01, vd.Sites[i], nb)
+			},
+			changed: false,
+		},
+		{
+			name: "across a neighbor",
+			target: func(vd *Diagram) s2.Point {
+				nb := vd.Sites[vd.Cell(i).NeighborIndices()[0]]
+				return s2.Interpolate(1.5, vd.Sites[i], nb)
+			},
+			changed: true,
+		},
+		{
+			name:    "far away",
+			target:  func(vd *Diagram) s2.Point { return s2.Point{Vector: vd.Sites[i].Mul(-1)} },
+			changed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd := mustNewDiagram(t, n)
+			p := tt.target(vd)
+			changed, err := vd.MoveSite(i, p)
+			if err != nil {
+				t.Fatalf("vd.MoveSite(%d, %v) error = %v, want nil", i, p, err)
+			}
+			if changed != tt.changed {
+				t.Errorf("vd.MoveSite(%d, %v) = %v, want %v", i, p, changed, tt.changed)
+			}
+			if vd.Sites[i] != p {
+				t.Errorf("vd.Sites[%d] = %v, want %v", i, vd.Sites[i], p)
+			}
+			if err := vd.Validate(); err != nil {
+				t.Errorf("vd.Validate() = %v, want nil", err)
+			}
+			want, err := NewDiagram(slices.Clone(vd.Sites))
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			checkSameDiagram(t, vd, want)
+		})
+	}
+}
+
+func TestDiagram_MoveSite_Drag(t *testing.T) {
+	// Drag a site in small steps along a great circle through many cells.
+	vd := mustNewDiagram(t, 500)
+	const i, steps = 3, 200
+	start := vd.Sites[i]
+	end := s2.Point{Vector: s2.Ortho(start).Vector}
+	changes := 0
+	for k := 1; k <= steps; k++ {
+		p := s2.Interpolate(float64(k)/steps, start, end)
+		before := slices.Clone(vd.CellNeighbors)
+		changed, err := vd.MoveSite(i, p)
+		if err != nil {
+			t.Fatalf("vd.MoveSite(%d, %v) error = %v, want nil", i, p, err)
+		}
+		if !changed && !slices.Equal(before, vd.CellNeighbors) {
+			t.Fatalf("vd.MoveSite(%d, %v) = false, but the neighbors changed", i, p)
+		}
+		if changed {
+			changes++
+		}
+		if err := vd.Validate(); err != nil {
+			t.Fatalf("vd.Validate() after step %d = %v, want nil", k, err)
+		}
+	}
+	if changes == 0 {
+		t.Errorf("dragging across the sphere changed no topology, want some changes")
+	}
+	want, err := NewDiagram(slices.Clone(vd.Sites))
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	checkSameDiagram(t, vd, want)
+}
+
+func TestDiagram_MoveSite_OrderIndependent(t *testing.T) {
+	points := utils.GenerateRandomPoints(100, 0)
+	vd, err := NewDiagram(slices.Clone(points), WithOrderIndependentOutput())
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	p := s2.Interpolate(0.5, points[0], points[1])
+	if _, err := vd.MoveSite(0, p); err != nil {
+		t.Fatalf("vd.MoveSite(0, %v) error = %v, want nil", p, err)
+	}
+	points[0] = p
+	want, err := NewDiagram(points, WithOrderIndependentOutput())
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	if diff := cmp.Diff(want.CellVertices, vd.CellVertices); diff != "" {
+		t.Errorf("vd.CellVertices mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDiagram_MoveSite_Errors(t *testing.T) {
+	points := utils.GenerateRandomPoints(100, 0)
+	weights := make([]float64, len(points))
+	weights[0] = 0.01
+	power, err := NewPowerDiagram(points, weights)
+	if err != nil {
+		t.Fatalf("NewPowerDiagram(...) error = %v, want nil", err)
+	}
+	plain := mustNewDiagram(t, 100)
+	nb := points[plain.Cell(0).NeighborIndices()[0]]
+	tests := []struct {
+		name    string
+		diagram *Diagram
+		p       s2.Point
+	}{
+		{"onto another site", mustNewDiagram(t, 100), points[50]},
+		{"near a neighbor", plain, s2.InterpolateAtDistance(s1.Angle(DefaultEps/2), nb, points[0])},
+		{"power diagram", power, s2.Interpolate(0.01, points[0], points[1])},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := tt.diagram.clone()
+			if _, err := tt.diagram.MoveSite(0, tt.p); err == nil {
+				t.Errorf("tt.diagram.MoveSite(0, %v) error = nil, want non-nil", tt.p)
+			}
+			if diff := cmp.Diff(before.Sites, tt.diagram.Sites); diff != "" {
+				t.Errorf("tt.diagram.Sites changed (-before +after):\n%s", diff)
+			}
+			if diff := cmp.Diff(before.CellNeighbors, tt.diagram.CellNeighbors); diff != "" {
+				t.Errorf("tt.diagram.CellNeighbors changed (-before +after):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDiagram_MoveSite_Panics(t *testing.T) {
+	vd := mustNewDiagram(t, 10)
+	for _, i := range []int{-1, 10} {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("vd.MoveSite(%d, ...) did not panic", i)
+				}
+			}()
+			_, _ = vd.MoveSite(i, vd.Sites[0])
+		})
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDiagram_MoveSite(b *testing.B) {
+	vd, err := NewDiagram(utils.GenerateRandomPoints(1e+4, 0))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	nb := vd.Sites[vd.Cell(0).NeighborIndices()[0]]
+	start := vd.Sites[0]
+	tests := []struct {
+		name string
+		p    s2.Point
+	}{
+		{"Local", s2.Interpolate(0.01, start, nb)},
+		{"Topology", s2.Interpolate(1.5, start, nb)},
+	}
+	for _, tt := range tests {
+		b.Run(tt.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := vd.MoveSite(0, tt.p); err != nil {
+					b.Fatalf("vd.MoveSite(...) error = %v, want nil", err)
+				}
+				if _, err := vd.MoveSite(0, start); err != nil {
+					b.Fatalf("vd.MoveSite(...) error = %v, want nil", err)
+				}
+			}
+		})
+	}
+}
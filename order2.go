@@ -0,0 +1,151 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/golang/geo/r3"
+	"github.com/golang/geo/s2"
+)
+
+// Order2Region is a region of the order-2 Voronoi diagram, the set of points whose two nearest
+// sites are Sites[0] and Sites[1] in either order. It is a convex spherical polygon.
+type Order2Region struct {
+	// Sites are the indices of the two nearest sites, the smaller one first.
+	Sites [2]int
+	// Vertices is the boundary ring of the region, CCW when looking out of the sphere like the
+	// rings of the cells. Consecutive vertices are more than eps apart and no vertex lies on the
+	// arc between its neighbors within eps.
+	Vertices []s2.Point
+}
+
+// Loop returns an s2.Loop bounding the region, with the ring reversed into the s2 convention so
+// that the interior of the loop is the region.
+func (r Order2Region) Loop() *s2.Loop {
+	pts := slices.Clone(r.Vertices)
+	slices.Reverse(pts)
+	return s2.LoopFromPoints(pts)
+}
+
+// Order2Cell returns the indices of the two sites nearest to p, the smaller one first, i.e. the
+// Sites of the Order2Region containing p. The nearest site is found by FindCellIndex, and the
+// second nearest one is the neighbor of its cell nearest to p, with ties broken by the smaller
+// index. In a power diagram, the sites of the two smallest powers are returned.
+func (d *Diagram) Order2Cell(p s2.Point) (i, j int) {
+	i = d.FindCellIndex(p)
+	j, best := -1, math.Inf(-1)
+	for _, k := range d.Cell(i).NeighborIndices() {
+		if dot := p.Dot(d.generator(k)); dot > best || (dot == best && k < j) {
+			j, best = k, dot
+		}
+	}
+	return min(i, j), max(i, j)
+}
+
+// Order2Regions returns the non-empty regions of the order-2 Voronoi diagram, ordered like the
+// Voronoi edges returned by Edges. The two nearest sites of a point are always neighbors, so
+// every region belongs to an edge: it is the union of the part of cell i in which j is the
+// second nearest site and vice versa, which are obtained by clipping the cells against the
+// bisectors of j and i with the other neighbors. Regions that degenerate to fewer than 3
+// vertices within eps, e.g. those of cocircular sites, are omitted.
+func (d *Diagram) Order2Regions() []Order2Region {
+	edges := d.Edges()
+	regions := make([]Order2Region, 0, len(edges))
+	for _, e := range edges {
+		i, j := e.Sites[0], e.Sites[1]
+		pts := append(d.order2Part(i, j), d.order2Part(j, i)...)
+		if ring := d.convexRing(pts); len(ring) >= 3 {
+			regions = append(regions, Order2Region{Sites: e.Sites, Vertices: ring})
+		}
+	}
+	return regions
+}
+
+// order2Part returns the ring of the part of cell i in which j is the second nearest site,
+// which is empty if the part is.
+func (d *Diagram) order2Part(i, j int) []s2.Point {
+	c := d.Cell(i)
+	ring := make([]s2.Point, c.NumVertices())
+	for k := range ring {
+		ring[k] = c.Vertex(k)
+	}
+	gj := d.generator(j)
+	for _, k := range c.NeighborIndices() {
+		if k != j && len(ring) > 0 {
+			ring = clipHemisphere(ring, gj.Sub(d.generator(k)))
+		}
+	}
+	return ring
+}
+
+// clipHemisphere returns the part of the convex ring in the closed hemisphere {p : p·n >= 0}.
+// The crossings of the boundary circle are inserted where an edge leaves or enters the
+// hemisphere, so the orientation of the ring is kept.
+func clipHemisphere(ring []s2.Point, n r3.Vector) []s2.Point {
+	out := make([]s2.Point, 0, len(ring)+1)
+	for k, a := range ring {
+		b := ring[(k+1)%len(ring)]
+		da, db := a.Dot(n), b.Dot(n)
+		if da >= 0 {
+			out = append(out, a)
+		}
+		if (da < 0) != (db < 0) {
+			// The combination is orthogonal to n and lies on the arc between a and b.
+			x := a.Mul(math.Abs(db)).Add(b.Mul(math.Abs(da)))
+			out = append(out, s2.Point{Vector: x.Normalize()})
+		}
+	}
+	return out
+}
+
+// convexRing returns the points in convex position as a ring that is CCW when looking out of
+// the sphere, sorted by their angle around their normalized sum. Points within eps of the
+// previous one and points on the arc between their neighbors within eps are dropped.
+func (d *Diagram) convexRing(pts []s2.Point) []s2.Point {
+	var sum r3.Vector
+	for _, p := range pts {
+		sum = sum.Add(p.Vector)
+	}
+	if sum.Norm() <= d.eps {
+		return nil
+	}
+	center := s2.Point{Vector: sum.Normalize()}
+	u := s2.Ortho(center).Vector
+	w := center.Cross(u)
+
+	// Decreasing angles run clockwise in the s2 convention.
+	type polar struct {
+		p     s2.Point
+		angle float64
+	}
+	sorted := make([]polar, len(pts))
+	for k, p := range pts {
+		sorted[k] = polar{p, math.Atan2(p.Dot(w), p.Dot(u))}
+	}
+	slices.SortFunc(sorted, func(a, b polar) int { return cmp.Compare(b.angle, a.angle) })
+	ring := make([]s2.Point, len(sorted))
+	for k, q := range sorted {
+		ring[k] = q.p
+	}
+
+	ring = slices.CompactFunc(ring, func(a, b s2.Point) bool {
+		return a.Sub(b.Vector).Norm() <= d.eps
+	})
+	for len(ring) > 1 && ring[0].Sub(ring[len(ring)-1].Vector).Norm() <= d.eps {
+		ring = ring[:len(ring)-1]
+	}
+	for k := 0; len(ring) >= 3 && k < len(ring); {
+		prev, next := ring[(k+len(ring)-1)%len(ring)], ring[(k+1)%len(ring)]
+		if math.Abs(ring[k].Dot(prev.PointCross(next).Normalize())) <= d.eps {
+			ring = slices.Delete(ring, k, k+1)
+			continue
+		}
+		k++
+	}
+	return ring
+}
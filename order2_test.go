@@ -0,0 +1,128 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"math"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/internal/fixtures"
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s2"
+)
+
+func TestDiagram_Order2Cell(t *testing.T) {
+	vd := mustNewDiagram(t, 300)
+	for _, p := range utils.GenerateRandomPoints(2000, 1) {
+		want := bruteForceTwoNearest(vd, p)
+		if i, j := vd.Order2Cell(p); [2]int{i, j} != want {
+			t.Errorf("vd.Order2Cell(%v) = %d, %d, want %v", p, i, j, want)
+		}
+	}
+}
+
+func TestDiagram_Order2Regions(t *testing.T) {
+	tests := []struct {
+		name    string
+		diagram func(t *testing.T) *Diagram
+		// regions is the number of non-empty regions, or 0 if it is not checked.
+		regions int
+	}{
+		{
+			name: "octahedron",
+			diagram: func(t *testing.T) *Diagram {
+				vd, err := NewDiagram(fixtures.Load("octahedron"))
+				if err != nil {
+					t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+				}
+				return vd
+			},
+			regions: 12,
+		},
+		{
+			name:    "random",
+			diagram: func(t *testing.T) *Diagram { return mustNewDiagram(t, 300) },
+			regions: 3*300 - 6,
+		},
+		{
+			name: "power",
+			diagram: func(t *testing.T) *Diagram {
+				sites := utils.GenerateRandomPoints(300, 0)
+				weights := make([]float64, len(sites))
+				for i := range weights {
+					weights[i] = 4 * math.Pi / 300 * sites[i].X
+				}
+				vd, err := NewPowerDiagram(sites, weights)
+				if err != nil {
+					t.Fatalf("NewPowerDiagram(...) error = %v, want nil", err)
+				}
+				return vd
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd := tt.diagram(t)
+			regions := vd.Order2Regions()
+			if tt.regions > 0 && len(regions) != tt.regions {
+				t.Errorf("len(vd.Order2Regions()) = %d, want %d", len(regions), tt.regions)
+			}
+
+			// The regions are valid convex loops that tile the sphere.
+			loops := make(map[[2]int]*s2.Loop, len(regions))
+			total := 0.0
+			for _, r := range regions {
+				if r.Sites[0] >= r.Sites[1] {
+					t.Errorf("region Sites = %v, want the smaller index first", r.Sites)
+				}
+				l := r.Loop()
+				if err := l.Validate(); err != nil {
+					t.Errorf("region %v Loop().Validate() = %v, want nil", r.Sites, err)
+				}
+				if _, ok := loops[r.Sites]; ok {
+					t.Errorf("region %v is returned twice", r.Sites)
+				}
+				loops[r.Sites] = l
+				total += l.Area()
+			}
+			if math.Abs(total-4*math.Pi) > 1e-9 {
+				t.Errorf("total area of vd.Order2Regions() = %v, want 4π", total)
+			}
+
+			for _, p := range utils.GenerateRandomPoints(1000, 1) {
+				i, j := vd.Order2Cell(p)
+				l, ok := loops[[2]int{i, j}]
+				if !ok {
+					t.Errorf("vd.Order2Cell(%v) = %d, %d, which has no region", p, i, j)
+					continue
+				}
+				if !l.ContainsPoint(p) {
+					t.Errorf("region [%d %d] does not contain %v, want it to", i, j, p)
+				}
+			}
+		})
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDiagram_Order2Regions(b *testing.B) {
+	vd, err := NewDiagram(utils.GenerateRandomPoints(1e+4, 0))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		vd.Order2Regions()
+	}
+}
+
+// Helpers
+
+// bruteForceTwoNearest returns the indices of the two sites nearest to p, the smaller one first.
+func bruteForceTwoNearest(vd *Diagram, p s2.Point) [2]int {
+	nearest := bruteForceNearestSites(vd, p, 2)
+	return [2]int{min(nearest[0], nearest[1]), max(nearest[0], nearest[1])}
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"container/heap"
+	"math"
+)
+
+// FarthestPointOrdering returns a permutation of the site indices that starts with start and
+// continues greedily with the site farthest from all sites ordered before it. Every prefix of
+// the ordering is therefore well distributed, and feeding growing prefixes to NewDiagram yields
+// progressively refined tessellations.
+//
+// The distance of every site to the ordered prefix is maintained by a search of the Delaunay
+// graph around each newly ordered site, restricted to the cap that can contain improvements.
+// Since the sites inside any cap induce a connected subgraph of their convex hull, the result is
+// the same as that of the quadratic brute-force algorithm. Ties are broken by the smaller index.
+// It panics if start is out of range.
+func (d *Diagram) FarthestPointOrdering(start int) []int {
+	d.checkCellIndex(start)
+	n := d.NumCells()
+
+	// dist holds the squared chord distance from every site to the ordered prefix.
+	dist := make([]float64, n)
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+	ordered := make([]bool, n)
+	visited := make([]int, n)
+	for i := range visited {
+		visited[i] = -1
+	}
+
+	var empty []int
+	for i := range n {
+		if d.Cell(i).IsEmpty() {
+			empty = append(empty, i)
+		}
+	}
+
+	order := make([]int, 0, n)
+	queue := &distanceHeap{}
+	var stack []int
+	cur := start
+	for {
+		order = append(order, cur)
+		ordered[cur] = true
+		radius := dist[cur]
+		dist[cur] = 0
+		site := d.Sites[cur]
+
+		update := func(u int) bool {
+			du := d.Sites[u].Sub(site.Vector).Norm2()
+			if du >= radius {
+				return false
+			}
+			if du < dist[u] {
+				dist[u] = du
+				if !ordered[u] {
+					heap.Push(queue, distanceItem{dist: du, idx: u})
+				}
+			}
+			return true
+		}
+
+		// Empty cells are not connected to the Delaunay graph, so their sites are updated
+		// directly.
+		for _, u := range empty {
+			update(u)
+		}
+		if d.Cell(cur).IsEmpty() {
+			for u := range n {
+				update(u)
+			}
+		} else {
+			visited[cur] = cur
+			stack = append(stack[:0], cur)
+			for len(stack) > 0 {
+				v := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				for _, u := range d.Cell(v).NeighborIndices() {
+					if visited[u] == cur {
+						continue
+					}
+					visited[u] = cur
+					if update(u) {
+						stack = append(stack, u)
+					}
+				}
+			}
+		}
+		if len(order) == n {
+			return order
+		}
+		if len(order) == 1 {
+			// Sites not connected to the start keep an infinite distance and are ordered next.
+			for u := range n {
+				if math.IsInf(dist[u], 1) && !ordered[u] {
+					heap.Push(queue, distanceItem{dist: dist[u], idx: u})
+				}
+			}
+		}
+
+		for queue.Len() > 0 {
+			top := (*queue)[0]
+			if !ordered[top.idx] && top.dist == dist[top.idx] {
+				break
+			}
+			heap.Pop(queue)
+		}
+		cur = heap.Pop(queue).(distanceItem).idx
+	}
+}
+
+// distanceItem is an entry of distanceHeap.
+type distanceItem struct {
+	dist float64
+	idx  int
+}
+
+// distanceHeap is a max-heap of distances with ties broken by the smaller index. It may hold
+// stale entries, which are skipped when popped.
+type distanceHeap []distanceItem
+
+func (h distanceHeap) Len() int { return len(h) }
+func (h distanceHeap) Less(i, j int) bool {
+	if h[i].dist != h[j].dist {
+		return h[i].dist > h[j].dist
+	}
+	return h[i].idx < h[j].idx
+}
+func (h distanceHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *distanceHeap) Push(x any)   { *h = append(*h, x.(distanceItem)) }
+func (h *distanceHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
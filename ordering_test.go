@@ -0,0 +1,124 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiagram_FarthestPointOrdering(t *testing.T) {
+	for _, n := range []int{4, 10, 50, 200} {
+		vd := mustNewDiagram(t, n)
+		for _, start := range []int{0, n / 2, n - 1} {
+			got := vd.FarthestPointOrdering(start)
+			want := bruteForceFarthestPointOrdering(vd, start)
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("vd.FarthestPointOrdering(%d) mismatch for %d sites (-want +got):\n%s",
+					start, n, diff)
+			}
+		}
+	}
+}
+
+func TestDiagram_FarthestPointOrdering_Prefixes(t *testing.T) {
+	vd := mustNewDiagram(t, 300)
+	order := vd.FarthestPointOrdering(0)
+
+	sorted := slices.Clone(order)
+	slices.Sort(sorted)
+	for i, v := range sorted {
+		if v != i {
+			t.Fatalf("vd.FarthestPointOrdering(0) is not a permutation: %v", order)
+		}
+	}
+
+	// The minimum pairwise distance within a prefix never grows as the prefix grows.
+	prev := math.Inf(1)
+	for k := 2; k <= len(order); k++ {
+		last := vd.Sites[order[k-1]]
+		cur := prev
+		for _, j := range order[:k-1] {
+			cur = min(cur, float64(last.Distance(vd.Sites[j])))
+		}
+		if cur > prev {
+			t.Errorf("prefix %d minimum distance = %v, want <= %v", k, cur, prev)
+		}
+		prev = cur
+	}
+
+	for _, k := range []int{10, 50, 150} {
+		prefix := make([]int, k)
+		copy(prefix, order[:k])
+		sites := vd.Sites[:0:0]
+		for _, i := range prefix {
+			sites = append(sites, vd.Sites[i])
+		}
+		if _, err := NewDiagram(sites); err != nil {
+			t.Errorf("NewDiagram(prefix of %d sites) error = %v, want nil", k, err)
+		}
+	}
+}
+
+func TestDiagram_FarthestPointOrdering_EmptyCell(t *testing.T) {
+	vd, empty := mustNewEmptyCellDiagram(t)
+	got := vd.FarthestPointOrdering(0)
+	want := bruteForceFarthestPointOrdering(vd, 0)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("vd.FarthestPointOrdering(0) mismatch with empty cell %d (-want +got):\n%s",
+			empty, diff)
+	}
+}
+
+func TestDiagram_FarthestPointOrdering_Panic(t *testing.T) {
+	vd := mustNewDiagram(t, 10)
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("vd.FarthestPointOrdering(%d) did not panic, want panic", vd.NumCells())
+		}
+	}()
+	vd.FarthestPointOrdering(vd.NumCells())
+}
+
+// Benchmarks
+
+func BenchmarkDiagram_FarthestPointOrdering(b *testing.B) {
+	vd, err := NewDiagram(utils.GenerateRandomPoints(10000, 0))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	for b.Loop() {
+		vd.FarthestPointOrdering(0)
+	}
+}
+
+// Helpers
+
+func bruteForceFarthestPointOrdering(vd *Diagram, start int) []int {
+	n := vd.NumCells()
+	dist := make([]float64, n)
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+	order := []int{}
+	cur := start
+	for len(order) < n {
+		order = append(order, cur)
+		for u := range n {
+			dist[u] = min(dist[u], vd.Sites[u].Sub(vd.Sites[cur].Vector).Norm2())
+		}
+		cur = 0
+		for u := range n {
+			if dist[u] > dist[cur] {
+				cur = u
+			}
+		}
+	}
+	return order
+}
@@ -0,0 +1,216 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// ValidationLevel selects the checks NewDiagramFromParts performs before accepting the data.
+type ValidationLevel int
+
+const (
+	// ValidationFull performs all checks of Diagram.Validate.
+	ValidationFull ValidationLevel = iota
+	// ValidationStructural performs the checks of Diagram.Validate except that vertices are
+	// equidistant from the sites of their cells, for vertices computed with less precision than
+	// NewDiagram uses, e.g. in float32 on a GPU.
+	ValidationStructural
+)
+
+// WithValidationLevel sets the checks performed by NewDiagramFromParts. The default is
+// ValidationFull. It is ignored by NewDiagram.
+func WithValidationLevel(level ValidationLevel) DiagramOption {
+	return func(o *DiagramOptions) error {
+		if level != ValidationFull && level != ValidationStructural {
+			return errorf(ErrInvalidOption, "s2voronoi: unknown validation level %d", level)
+		}
+		o.Validation = level
+		return nil
+	}
+}
+
+// NewDiagramFromParts creates a Voronoi diagram from precomputed parts in the layout of the
+// Diagram fields, e.g. circumcenters computed elsewhere or arrays read back from storage. The
+// diagram takes ownership of the given slices.
+// Rings given CW when looking out of the sphere are reversed in place together with their
+// neighbors, and the result is then checked as selected by WithValidationLevel, see
+// Diagram.Validate, and against the Euler relation of the sphere like NewDiagramFromArrays.
+// It returns an error describing the first violation if the parts are not consistent.
+func NewDiagramFromParts(sites, vertices s2.PointVector, cellVertices, cellNeighbors,
+	cellOffsets []int, setters ...DiagramOption) (*Diagram, error) {
+	if len(sites) < 4 {
+		return nil, errInsufficientSites
+	}
+
+	opts := &DiagramOptions{
+		Eps: DefaultEps,
+	}
+	for _, set := range setters {
+		err := set(opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opts.Deduplication != 0 {
+		return nil, errorf(ErrInvalidOption,
+			"s2voronoi: deduplication cannot be applied to the parts of a diagram")
+	}
+	if opts.VertexMerging != 0 {
+		return nil, errorf(ErrInvalidOption,
+			"s2voronoi: vertex merging cannot be applied to the parts of a diagram")
+	}
+	if opts.SkipNonPoints {
+		return nil, errorf(ErrInvalidOption,
+			"s2voronoi: skipping non-points only applies to NewDiagramFromGeoJSON")
+	}
+
+	d := &Diagram{
+		Sites:         sites,
+		Vertices:      vertices,
+		CellVertices:  cellVertices,
+		CellNeighbors: cellNeighbors,
+		CellOffsets:   cellOffsets,
+
+		eps:              opts.Eps,
+		orderIndependent: opts.OrderIndependent,
+		cache:            new(diagramCache),
+	}
+	if err := prepareSites(sites, opts); err != nil {
+		return nil, err
+	}
+	// The orientation of the rings can only be decided once the indices are known to be valid.
+	if err := d.validate(ValidationStructural); err != nil {
+		if !d.reverseRings() {
+			return nil, err
+		}
+	}
+	if err := d.checkArrays(opts.Validation); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// NewDiagramFromArrays creates a Voronoi diagram from the raw arrays of a diagram computed
+// elsewhere, in the layout of the Diagram fields, with the precision eps of WithEps. The
+// diagram takes ownership of the given slices.
+// Unlike NewDiagramFromParts nothing is repaired, so that untrusted arrays are accepted only
+// if they are exactly those of a diagram: the sites must be of unit length, and the arrays must
+// pass Validate, which checks the offsets for monotonicity and length, every index for bounds,
+// the rings for CCW order when looking out of the sphere, the vertices for unit length and
+// equidistance, and the neighbors for symmetry, and the Euler relation of the sphere, which
+// rejects vertices outside every ring. The result behaves like a diagram built by NewDiagram
+// with WithEps(eps) from the same sites.
+// It returns an error describing the first violation if eps is not positive and finite, there
+// are fewer than 4 sites or the arrays are not those of a diagram; it never panics.
+func NewDiagramFromArrays(sites, vertices s2.PointVector, cellVertices, cellNeighbors,
+	cellOffsets []int, eps float64) (*Diagram, error) {
+	return newValidatedDiagram(sites, vertices, cellVertices, cellNeighbors, cellOffsets, eps,
+		nil, 0)
+}
+
+// newValidatedDiagram returns the diagram of the given arrays, eps, weights, which may be nil,
+// and tolerance of WithVertexMerging, which may be 0, after checking them like
+// NewDiagramFromArrays, the weights for their count and for being finite, and the tolerance for
+// being finite and not negative. It is the constructor of every diagram decoded from untrusted
+// data.
+func newValidatedDiagram(sites, vertices s2.PointVector, cellVertices, cellNeighbors,
+	cellOffsets []int, eps float64, weights []float64, mergeTol s1.Angle) (*Diagram, error) {
+	if !(eps > 0) || math.IsInf(eps, 1) {
+		return nil, errorf(ErrInvalidOption, "s2voronoi: eps must be positive, got %v", eps)
+	}
+	if !(mergeTol >= 0) || math.IsInf(float64(mergeTol), 1) {
+		return nil, errorf(ErrInvalidOption, "s2voronoi: vertex merging tolerance must be "+
+			"non-negative and finite, got %v", mergeTol)
+	}
+	if len(sites) < 4 {
+		return nil, errInsufficientSites
+	}
+	if weights != nil {
+		if len(weights) != len(sites) {
+			return nil, fmt.Errorf("s2voronoi: got %d weights for %d sites", len(weights),
+				len(sites))
+		}
+		for i, w := range weights {
+			if math.IsNaN(w) || math.IsInf(w, 0) {
+				return nil, fmt.Errorf("s2voronoi: weight %d is not finite: %v", i, w)
+			}
+		}
+	}
+	if err := checkSites(sites, false); err != nil {
+		return nil, err
+	}
+
+	d := &Diagram{
+		Sites:         sites,
+		Vertices:      vertices,
+		CellVertices:  cellVertices,
+		CellNeighbors: cellNeighbors,
+		CellOffsets:   cellOffsets,
+
+		eps:      eps,
+		weights:  weights,
+		mergeTol: mergeTol,
+		cache:    new(diagramCache),
+	}
+	if weights != nil {
+		d.maxWeight = slices.Max(weights)
+	}
+	if err := d.checkArrays(ValidationFull); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// checkArrays checks the arrays of a diagram built from given parts as selected by level, see
+// Validate, and against the Euler relation, see checkEuler. It is the check shared by
+// NewDiagramFromParts and newValidatedDiagram.
+func (d *Diagram) checkArrays(level ValidationLevel) error {
+	if err := d.validate(level); err != nil {
+		return err
+	}
+	return d.checkEuler()
+}
+
+// reverseRings reverses the rings that wind around their sites in the wrong direction together
+// with their neighbors, and reports whether any ring was reversed. It does nothing if the CSR
+// arrays are inconsistent or out of range.
+func (d *Diagram) reverseRings() bool {
+	offsets := d.CellOffsets
+	if len(offsets) != d.NumCells()+1 || offsets[0] != 0 ||
+		offsets[d.NumCells()] != len(d.CellVertices) || len(d.CellVertices) != len(d.CellNeighbors) {
+		return false
+	}
+	for i := range d.NumCells() {
+		if offsets[i+1] < offsets[i] {
+			return false
+		}
+	}
+	for _, v := range d.CellVertices {
+		if v < 0 || v >= len(d.Vertices) {
+			return false
+		}
+	}
+
+	reversed := false
+	for i := range d.NumCells() {
+		c := d.Cell(i)
+		if c.NumVertices() == 0 || ringTurns(c) != 1 {
+			continue
+		}
+		// Edge k of the reversed ring is edge n-2-k of the original one, except for the closing
+		// edge n-1, which keeps its place.
+		vs, ns := c.VertexIndices(), c.NeighborIndices()
+		slices.Reverse(vs)
+		slices.Reverse(ns[:len(ns)-1])
+		reversed = true
+	}
+	return reversed
+}
@@ -0,0 +1,332 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s2"
+)
+
+func TestNewDiagramFromParts(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(p *diagramParts)
+		opts    []DiagramOption
+		wantErr string
+	}{
+		{"valid", func(p *diagramParts) {}, nil, ""},
+		{"reversed rings", func(p *diagramParts) { reverseAllRings(p) }, nil, ""},
+		{"too few sites", func(p *diagramParts) { p.sites = p.sites[:3] }, nil, "insufficient sites"},
+		{"site not unit", func(p *diagramParts) {
+			p.sites[0] = s2.Point{Vector: p.sites[0].Mul(2)}
+		}, nil, "site 0 is not unit length"},
+		{"vertex not unit", func(p *diagramParts) {
+			p.vertices[0] = s2.Point{Vector: p.vertices[0].Mul(2)}
+		}, nil, "vertex 0 is not unit length"},
+		{"offsets not monotone", func(p *diagramParts) {
+			p.offsets[1], p.offsets[2] = p.offsets[2], p.offsets[1]
+		}, nil, "need 0 or at least 3"},
+		{"offsets truncated", func(p *diagramParts) { p.offsets = p.offsets[1:] }, nil,
+			"cell offsets are inconsistent"},
+		{"vertex out of range", func(p *diagramParts) { p.cellVertices[0] = len(p.vertices) }, nil,
+			"out of range"},
+		{"neighbor out of range", func(p *diagramParts) { p.cellNeighbors[0] = len(p.sites) }, nil,
+			"out of range"},
+		{"asymmetric neighbor", func(p *diagramParts) {
+			p.cellNeighbors[0] = p.cellNeighbors[1]
+		}, nil, "is not shared"},
+		{"moved vertex", func(p *diagramParts) {
+			p.vertices[0] = s2.Point{Vector: p.vertices[0].Add(p.sites[0].Mul(1e-6)).Normalize()}
+		}, nil, "not equidistant"},
+		{"unused vertex", func(p *diagramParts) {
+			p.vertices = append(p.vertices, p.sites[0])
+		}, nil, "ring entries"},
+		{"moved vertex structural", func(p *diagramParts) {
+			p.vertices[0] = s2.Point{Vector: p.vertices[0].Add(p.sites[0].Mul(1e-6)).Normalize()}
+		}, []DiagramOption{WithValidationLevel(ValidationStructural)}, ""},
+		{"invalid validation level", func(p *diagramParts) {},
+			[]DiagramOption{WithValidationLevel(ValidationLevel(-1))}, "unknown validation level"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd := mustNewDiagram(t, 100)
+			p := partsOf(vd)
+			tt.corrupt(&p)
+			got, err := NewDiagramFromParts(p.sites, p.vertices, p.cellVertices, p.cellNeighbors,
+				p.offsets, tt.opts...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NewDiagramFromParts(...) error = %v, want containing %q", err, tt.wantErr)
+				}
+				if got != nil {
+					t.Errorf("NewDiagramFromParts(...) = %v, want nil", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewDiagramFromParts(...) error = %v, want nil", err)
+			}
+			if !slices.Equal(got.CellVertices, vd.CellVertices) ||
+				!slices.Equal(got.CellNeighbors, vd.CellNeighbors) ||
+				!slices.Equal(got.CellOffsets, vd.CellOffsets) {
+				t.Errorf("NewDiagramFromParts(...) cells differ from the original diagram")
+			}
+		})
+	}
+}
+
+func TestNewDiagramFromParts_Eps(t *testing.T) {
+	vd := mustNewDiagram(t, 20)
+	p := partsOf(vd)
+	got, err := NewDiagramFromParts(p.sites, p.vertices, p.cellVertices, p.cellNeighbors,
+		p.offsets, WithEps(1e-10))
+	if err != nil {
+		t.Fatalf("NewDiagramFromParts(...) error = %v, want nil", err)
+	}
+	if got.Eps() != 1e-10 {
+		t.Errorf("NewDiagramFromParts(...).Eps() = %v, want %v", got.Eps(), 1e-10)
+	}
+	if _, err := got.Relax(1); err != nil {
+		t.Errorf("NewDiagramFromParts(...).Relax(1) error = %v, want nil", err)
+	}
+}
+
+func TestNewDiagramFromArrays(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(p *diagramParts)
+		eps     float64
+		wantErr string
+	}{
+		{"valid", func(p *diagramParts) {}, DefaultEps, ""},
+		{"eps", func(p *diagramParts) {}, 1e-10, ""},
+		{"zero eps", func(p *diagramParts) {}, 0, "eps must be positive"},
+		{"nan eps", func(p *diagramParts) {}, math.NaN(), "eps must be positive"},
+		{"infinite eps", func(p *diagramParts) {}, math.Inf(1), "eps must be positive"},
+		{"reversed rings", func(p *diagramParts) { reverseAllRings(p) }, DefaultEps, "winds"},
+		{"too few sites", func(p *diagramParts) { p.sites = p.sites[:3] }, DefaultEps,
+			"insufficient sites"},
+		{"site not unit", func(p *diagramParts) {
+			p.sites[0] = s2.Point{Vector: p.sites[0].Mul(2)}
+		}, DefaultEps, "site 0 is not unit length"},
+		{"site nan", func(p *diagramParts) { p.sites[1].X = math.NaN() }, DefaultEps,
+			"non-finite"},
+		{"vertex not unit", func(p *diagramParts) {
+			p.vertices[0] = s2.Point{Vector: p.vertices[0].Mul(2)}
+		}, DefaultEps, "vertex 0 is not unit length"},
+		{"offsets not monotone", func(p *diagramParts) {
+			p.offsets[1], p.offsets[2] = p.offsets[2], p.offsets[1]
+		}, DefaultEps, "need 0 or at least 3"},
+		{"offsets truncated", func(p *diagramParts) { p.offsets = p.offsets[1:] }, DefaultEps,
+			"cell offsets are inconsistent"},
+		{"offsets nil", func(p *diagramParts) { p.offsets = nil }, DefaultEps,
+			"cell offsets are inconsistent"},
+		{"vertex out of range", func(p *diagramParts) { p.cellVertices[0] = len(p.vertices) },
+			DefaultEps, "out of range"},
+		{"negative vertex", func(p *diagramParts) { p.cellVertices[0] = -1 }, DefaultEps,
+			"out of range"},
+		{"neighbor out of range", func(p *diagramParts) { p.cellNeighbors[0] = len(p.sites) },
+			DefaultEps, "out of range"},
+		{"asymmetric neighbor", func(p *diagramParts) {
+			p.cellNeighbors[0] = p.cellNeighbors[1]
+		}, DefaultEps, "is not shared"},
+		{"moved vertex", func(p *diagramParts) {
+			p.vertices[0] = s2.Point{Vector: p.vertices[0].Add(p.sites[0].Mul(1e-6)).Normalize()}
+		}, DefaultEps, "not equidistant"},
+		{"unused vertex", func(p *diagramParts) {
+			p.vertices = append(p.vertices, p.sites[0])
+		}, DefaultEps, "ring entries"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd := mustNewDiagram(t, 100)
+			p := partsOf(vd)
+			tt.corrupt(&p)
+			got, err := NewDiagramFromArrays(p.sites, p.vertices, p.cellVertices, p.cellNeighbors,
+				p.offsets, tt.eps)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NewDiagramFromArrays(...) error = %v, want containing %q", err,
+						tt.wantErr)
+				}
+				if got != nil {
+					t.Errorf("NewDiagramFromArrays(...) = %v, want nil", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewDiagramFromArrays(...) error = %v, want nil", err)
+			}
+			if !got.Equal(vd) || got.Eps() != tt.eps {
+				t.Errorf("NewDiagramFromArrays(...) differs from the original diagram")
+			}
+		})
+	}
+}
+
+func TestNewDiagramFromArrays_Fresh(t *testing.T) {
+	sites := utils.GenerateRandomPoints(200, 0)
+	want, err := NewDiagram(slices.Clone(sites))
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	p := partsOf(want)
+	got, err := NewDiagramFromArrays(p.sites, p.vertices, p.cellVertices, p.cellNeighbors,
+		p.offsets, want.Eps())
+	if err != nil {
+		t.Fatalf("NewDiagramFromArrays(...) error = %v, want nil", err)
+	}
+
+	// The loaded diagram answers queries and is edited like the one built from the sites.
+	for _, q := range utils.GenerateRandomPoints(100, 1) {
+		if g, w := got.FindCellIndex(q), want.FindCellIndex(q); g != w {
+			t.Errorf("FindCellIndex(%v) = %d, want %d", q, g, w)
+		}
+	}
+	added := utils.GenerateRandomPoints(20, 2)
+	if _, err := got.AddSites(slices.Clone(added)); err != nil {
+		t.Fatalf("got.AddSites(...) error = %v, want nil", err)
+	}
+	if _, err := want.AddSites(slices.Clone(added)); err != nil {
+		t.Fatalf("want.AddSites(...) error = %v, want nil", err)
+	}
+	if !got.Equal(want) {
+		t.Errorf("AddSites on NewDiagramFromArrays(...) differs from AddSites on NewDiagram")
+	}
+	if _, err := got.Relax(2); err != nil {
+		t.Fatalf("got.Relax(2) error = %v, want nil", err)
+	}
+	if err := got.Validate(); err != nil {
+		t.Errorf("got.Validate() after Relax(2) = %v, want nil", err)
+	}
+}
+
+func TestNewDiagramFromArrays_Mutations(t *testing.T) {
+	vd := mustNewDiagram(t, 30)
+	random := rand.New(rand.NewSource(0))
+	// Each mutation damages the arrays in a way hostile input could.
+	ints := func(p *diagramParts) []*[]int {
+		return []*[]int{&p.cellVertices, &p.cellNeighbors, &p.offsets}
+	}
+	mutations := []func(p *diagramParts){
+		func(p *diagramParts) {
+			a := ints(p)[random.Intn(3)]
+			if len(*a) > 0 {
+				(*a)[random.Intn(len(*a))] += random.Intn(7) - 3
+			}
+		},
+		func(p *diagramParts) {
+			a := ints(p)[random.Intn(3)]
+			if len(*a) > 0 {
+				(*a)[random.Intn(len(*a))] = []int{-1 << 62, -1, 1 << 62, len(p.sites),
+					len(p.vertices)}[random.Intn(5)]
+			}
+		},
+		func(p *diagramParts) {
+			a := ints(p)[random.Intn(3)]
+			if len(*a) > 1 {
+				i, j := random.Intn(len(*a)), random.Intn(len(*a))
+				(*a)[i], (*a)[j] = (*a)[j], (*a)[i]
+			}
+		},
+		func(p *diagramParts) {
+			a := ints(p)[random.Intn(3)]
+			*a = (*a)[:random.Intn(len(*a)+1)]
+		},
+		func(p *diagramParts) {
+			a := ints(p)[random.Intn(3)]
+			*a = append(*a, random.Intn(len(p.vertices)+1))
+		},
+		func(p *diagramParts) {
+			p.vertices = p.vertices[:random.Intn(len(p.vertices)+1)]
+		},
+		func(p *diagramParts) {
+			ps := []s2.PointVector{p.sites, p.vertices}[random.Intn(2)]
+			if len(ps) > 0 {
+				i := random.Intn(len(ps))
+				ps[i] = s2.Point{Vector: ps[i].Mul([]float64{0, -1, 1 + 1e-9,
+					math.NaN(), math.Inf(1)}[random.Intn(5)])}
+			}
+		},
+		func(p *diagramParts) {
+			ps := []s2.PointVector{p.sites, p.vertices}[random.Intn(2)]
+			if len(ps) > 1 {
+				i, j := random.Intn(len(ps)), random.Intn(len(ps))
+				ps[i], ps[j] = ps[j], ps[i]
+			}
+		},
+	}
+
+	accepted := 0
+	for range 5000 {
+		p := partsOf(vd)
+		for range 1 + random.Intn(3) {
+			mutations[random.Intn(len(mutations))](&p)
+		}
+		got, err := NewDiagramFromArrays(p.sites, p.vertices, p.cellVertices, p.cellNeighbors,
+			p.offsets, DefaultEps)
+		if err != nil {
+			continue
+		}
+		// A mutation that cancels out, such as swapping equal values, leaves a diagram that
+		// must be fully usable.
+		accepted++
+		if !got.Equal(vd) {
+			t.Fatalf("NewDiagramFromArrays(...) accepted a diagram that differs from the original")
+		}
+		for _, c := range got.Cells() {
+			c.Area()
+			c.Centroid()
+			for k := range c.NumVertices() {
+				c.Edge(k)
+				c.Neighbor(k)
+			}
+		}
+		got.Edges()
+	}
+	if accepted == 5000 {
+		t.Errorf("NewDiagramFromArrays(...) accepted every mutation, want errors")
+	}
+}
+
+// Helpers
+
+// diagramParts holds copies of the fields of a diagram.
+type diagramParts struct {
+	sites, vertices                      s2.PointVector
+	cellVertices, cellNeighbors, offsets []int
+}
+
+func partsOf(vd *Diagram) diagramParts {
+	return diagramParts{
+		sites:         slices.Clone(vd.Sites),
+		vertices:      slices.Clone(vd.Vertices),
+		cellVertices:  slices.Clone(vd.CellVertices),
+		cellNeighbors: slices.Clone(vd.CellNeighbors),
+		offsets:       slices.Clone(vd.CellOffsets),
+	}
+}
+
+// reverseAllRings turns every ring CW when looking out of the sphere, keeping every neighbor
+// paired with its edge.
+func reverseAllRings(p *diagramParts) {
+	for i := range len(p.offsets) - 1 {
+		vs := p.cellVertices[p.offsets[i]:p.offsets[i+1]]
+		ns := p.cellNeighbors[p.offsets[i]:p.offsets[i+1]]
+		slices.Reverse(vs)
+		slices.Reverse(ns)
+		// After reversal neighbor k belongs to the edge from vertex k-1 to k, so rotate them
+		// left by one.
+		first := ns[0]
+		copy(ns, ns[1:])
+		ns[len(ns)-1] = first
+	}
+}
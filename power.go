@@ -0,0 +1,178 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/2dChan/s2voronoi/s2delaunay"
+	"github.com/golang/geo/r3"
+	"github.com/golang/geo/s2"
+	"github.com/markus-wa/quickhull-go/v2"
+)
+
+// NewPowerDiagram creates the power diagram, also known as the Laguerre diagram, of the given
+// weighted sites. The cell of site i holds the points p minimizing the power
+//
+//	-2 ln cos d(p, s_i) - w_i,
+//
+// which equals d(p, s_i)² - w_i up to terms of fourth order in the geodesic distance d, so a
+// larger weight grows the cell at the expense of its neighbors. Unlike with d² - w_i, the cells
+// are bounded by great-circle arcs, and the diagram is dual to the convex hull of the sites
+// lifted to s_i·exp(w_i/2). A site whose lifted point lies inside that hull, e.g. one with a
+// large negative weight, gets an empty cell, and a site may lie outside of its own cell.
+//
+// With all weights zero the result is identical to NewDiagram. Otherwise the diagram keeps its
+// weights: ContainsPoint, FindCell, Validate and Relax use the power instead of the distance,
+// while the queries ranking sites by geodesic distance, such as KNearestSites, do not.
+// WithOrderIndependentOutput is not supported for non-zero weights.
+//
+// The sites must satisfy the requirements of NewDiagram, and their lifted points must not lie
+// in an open half-space through the origin. It returns an error if the number of weights differs
+// from the number of sites, if a weight is not finite, or if the diagram cannot be constructed.
+func NewPowerDiagram(sites s2.PointVector, weights []float64,
+	setters ...DiagramOption) (*Diagram, error) {
+	if len(weights) != len(sites) {
+		return nil, fmt.Errorf("s2voronoi: got %d weights for %d sites", len(weights), len(sites))
+	}
+	zero := true
+	for i, w := range weights {
+		if math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, fmt.Errorf("s2voronoi: weight %d is not finite: %v", i, w)
+		}
+		zero = zero && w == 0
+	}
+	if zero {
+		return NewDiagram(sites, setters...)
+	}
+	if len(sites) < 4 {
+		return nil, errInsufficientSites
+	}
+
+	opts := &DiagramOptions{
+		Eps: DefaultEps,
+	}
+	for _, set := range setters {
+		err := set(opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opts.OrderIndependent {
+		return nil, errorf(ErrInvalidOption,
+			"s2voronoi: order independent output is not supported for power diagrams")
+	}
+	if opts.Deduplication != 0 {
+		return nil, errorf(ErrInvalidOption,
+			"s2voronoi: deduplication is not supported for power diagrams")
+	}
+	if opts.VertexMerging != 0 {
+		return nil, errorf(ErrInvalidOption,
+			"s2voronoi: vertex merging is not supported for power diagrams")
+	}
+	if opts.SkipNonPoints {
+		return nil, errorf(ErrInvalidOption,
+			"s2voronoi: skipping non-points only applies to NewDiagramFromGeoJSON")
+	}
+	if err := prepareSites(sites, opts); err != nil {
+		return nil, err
+	}
+	return newPowerDiagram(sites, slices.Clone(weights), opts)
+}
+
+// newPowerDiagram creates the power diagram of the given weighted sites, see NewPowerDiagram.
+// The diagram takes ownership of the weights.
+func newPowerDiagram(sites s2.PointVector, weights []float64,
+	opts *DiagramOptions) (*Diagram, error) {
+	// The lifted points are scaled so that the largest one has unit length, which keeps the
+	// tolerances of the hull relative to the sphere.
+	maxWeight := slices.Max(weights)
+	points := make([]r3.Vector, len(sites))
+	for i, s := range sites {
+		points[i] = s.Mul(math.Exp((weights[i] - maxWeight) / 2))
+	}
+
+	var qh quickhull.QuickHull
+	ch := qh.ConvexHull(points, true, true, opts.Eps)
+
+	// The hull vertices are numbered in increasing order of their sites, so that the cells of
+	// the hull triangulation appear in the order of the sites.
+	sub := make([]int, len(sites))
+	for i := range sub {
+		sub[i] = -1
+	}
+	for _, v := range ch.Indices {
+		sub[v] = 0
+	}
+	var hull []int
+	var lifted s2.PointVector
+	for i := range sub {
+		if sub[i] == 0 {
+			sub[i] = len(hull)
+			hull = append(hull, i)
+			lifted = append(lifted, s2.Point{Vector: points[i]})
+		}
+	}
+	if len(hull) < 4 || len(ch.Indices) != 3*(2*len(hull)-4) {
+		return nil, errorf(ErrDegenerateInput,
+			"s2voronoi: lifted sites do not span a closed convex hull")
+	}
+
+	triangles := make([][3]int, len(ch.Indices)/3)
+	hullSign := false
+	for i := range triangles {
+		for j := range 3 {
+			triangles[i][j] = sub[ch.Indices[3*i+j]]
+		}
+		// The hull faces are wound consistently, so their orientations share one sign unless
+		// the origin is outside of the hull.
+		a, b, c := lifted[triangles[i][0]], lifted[triangles[i][1]], lifted[triangles[i][2]]
+		o := a.Dot(b.Cross(c.Vector))
+		if i == 0 {
+			hullSign = math.Signbit(o)
+		} else if math.Signbit(o) != hullSign {
+			return nil, errorf(ErrDegenerateInput, "s2voronoi: lifted sites lie in an open "+
+				"half-space, the origin is outside their hull")
+		}
+	}
+	dt, err := s2delaunay.NewTriangulationFromTriangles(lifted, triangles,
+		s2delaunay.WithEps(opts.Eps))
+	if err != nil {
+		return nil, err
+	}
+
+	// The Voronoi vertices of the hull triangulation are the unit outer normals of its faces,
+	// which maximize the dot product with the lifted points exactly at the face.
+	d := &Diagram{eps: opts.Eps, weights: weights, maxWeight: maxWeight}
+	if err := d.setTriangulation(dt); err != nil {
+		return nil, err
+	}
+	d.Sites = sites
+	for k, j := range d.CellNeighbors {
+		d.CellNeighbors[k] = hull[j]
+	}
+	d.CellOffsets = make([]int, len(sites)+1)
+	for i, k := range sub {
+		d.CellOffsets[i+1] = d.CellOffsets[i]
+		if k >= 0 {
+			d.CellOffsets[i+1] += dt.IncidentTriangleOffsets[k+1] - dt.IncidentTriangleOffsets[k]
+		}
+	}
+	return d, nil
+}
+
+// generator returns the vector whose dot product with a point decides the cell of the point:
+// p lies in the cell i maximizing p·generator(i). It is the site for ordinary diagrams and the
+// site scaled by exp((w_i - w_max)/2) for power diagrams, the lifted point of NewPowerDiagram.
+// Subtracting the largest weight keeps the generators finite and the longest of them at unit
+// length, so that the tolerances of the lookups stay relative to the sphere for any weights.
+func (d *Diagram) generator(i int) r3.Vector {
+	if d.weights == nil {
+		return d.Sites[i].Vector
+	}
+	return d.Sites[i].Mul(math.Exp((d.weights[i] - d.maxWeight) / 2))
+}
@@ -0,0 +1,277 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/internal/fixtures"
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewPowerDiagram_ZeroWeights(t *testing.T) {
+	for _, name := range []string{"octahedron", "cocircular-rings", "antipodal-pairs"} {
+		t.Run(name, func(t *testing.T) {
+			want, err := NewDiagram(fixtures.Load(name))
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			sites := fixtures.Load(name)
+			got, err := NewPowerDiagram(sites, make([]float64, len(sites)))
+			if err != nil {
+				t.Fatalf("NewPowerDiagram(..., zeros) error = %v, want nil", err)
+			}
+			opts := []cmp.Option{
+				cmp.AllowUnexported(Diagram{}),
+				cmp.Comparer(func(a, b *diagramCache) bool { return true }),
+			}
+			if diff := cmp.Diff(want, got, opts...); diff != "" {
+				t.Errorf("NewPowerDiagram(..., zeros) mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewPowerDiagram(t *testing.T) {
+	const n = 500
+	sites := utils.GenerateRandomPoints(n, 0)
+	r := rand.New(rand.NewSource(1))
+	weights := make([]float64, n)
+	for i := range weights {
+		// The cells have an area of about 4π/n, so the weights shift their edges noticeably.
+		weights[i] = (r.Float64() - 0.5) * 8 * math.Pi / n
+	}
+	vd, err := NewPowerDiagram(sites, weights)
+	if err != nil {
+		t.Fatalf("NewPowerDiagram(...) error = %v, want nil", err)
+	}
+	if err := vd.Validate(); err != nil {
+		t.Errorf("vd.Validate() = %v, want nil", err)
+	}
+	if got := vd.TotalArea(); math.Abs(got-4*math.Pi) > 1e-9 {
+		t.Errorf("vd.TotalArea() = %v, want 4π", got)
+	}
+
+	// Every point lies in the cell of the smallest power, which differs from the nearest site.
+	differ := 0
+	for _, p := range utils.GenerateRandomPoints(2000, 1) {
+		want := bruteForcePowerCell(sites, weights, p)
+		if got := vd.FindCellIndex(p); got != want {
+			t.Errorf("vd.FindCellIndex(%v) = %d, want %d", p, got, want)
+		}
+		if !vd.Cell(want).ContainsPoint(p) {
+			t.Errorf("vd.Cell(%d).ContainsPoint(%v) = false, want true", want, p)
+		}
+		if want != bruteForcePowerCell(sites, make([]float64, n), p) {
+			differ++
+		}
+	}
+	if differ == 0 {
+		t.Errorf("all points lie in the cell of their nearest site, want the weights to matter")
+	}
+
+	// Sites may lie outside of their cells, which are then sampled from their vertex average.
+	r = rand.New(rand.NewSource(2))
+	outside := 0
+	for i := range n {
+		c := vd.Cell(i)
+		if c.IsEmpty() {
+			continue
+		}
+		if !c.ContainsPoint(sites[i]) {
+			outside++
+		}
+		if p := c.RandomPoint(r); !c.ContainsPoint(p) {
+			t.Errorf("vd.Cell(%d).RandomPoint(...) = %v, want a point in the cell", i, p)
+		}
+	}
+	if outside == 0 {
+		t.Errorf("all sites lie in their cells, want some outside")
+	}
+
+	// A larger weight grows the cell.
+	plain := mustNewDiagram(t, n)
+	single := make([]float64, n)
+	single[0] = 4 * math.Pi / n
+	grown, err := NewPowerDiagram(sites, single)
+	if err != nil {
+		t.Fatalf("NewPowerDiagram(...) error = %v, want nil", err)
+	}
+	if got, want := grown.Cell(0).Area(), plain.Cell(0).Area(); got <= want {
+		t.Errorf("grown.Cell(0).Area() = %v, want more than %v", got, want)
+	}
+}
+
+func TestNewPowerDiagram_ShiftedWeights(t *testing.T) {
+	// Adding a constant to all weights leaves the diagram unchanged, however large or negative
+	// the constant, for which exp(w_i/2) would overflow or vanish below eps.
+	const n = 300
+	sites := utils.GenerateRandomPoints(n, 0)
+	r := rand.New(rand.NewSource(1))
+	base := make([]float64, n)
+	for i := range base {
+		base[i] = (r.Float64() - 0.5) * 8 * math.Pi / n
+	}
+	for _, shift := range []float64{2000, -60} {
+		t.Run(fmt.Sprint(shift), func(t *testing.T) {
+			weights := make([]float64, n)
+			for i, w := range base {
+				weights[i] = w + shift
+			}
+			vd, err := NewPowerDiagram(sites, weights)
+			if err != nil {
+				t.Fatalf("NewPowerDiagram(...) error = %v, want nil", err)
+			}
+			if err := vd.Validate(); err != nil {
+				t.Errorf("vd.Validate() = %v, want nil", err)
+			}
+			for _, p := range utils.GenerateRandomPoints(500, 1) {
+				want := bruteForcePowerCell(sites, base, p)
+				if got := vd.FindCellIndex(p); got != want {
+					t.Errorf("vd.FindCellIndex(%v) = %d, want %d", p, got, want)
+				}
+				if !vd.Cell(want).ContainsPoint(p) {
+					t.Errorf("vd.Cell(%d).ContainsPoint(%v) = false, want true", want, p)
+				}
+			}
+		})
+	}
+}
+
+func TestNewPowerDiagram_EmptyCell(t *testing.T) {
+	// The lifted point of site 0 lies deep inside the hull of the others.
+	const n = 100
+	sites := utils.GenerateRandomPoints(n, 0)
+	weights := make([]float64, n)
+	weights[0] = -1
+	vd, err := NewPowerDiagram(sites, weights)
+	if err != nil {
+		t.Fatalf("NewPowerDiagram(...) error = %v, want nil", err)
+	}
+	if err := vd.Validate(); err != nil {
+		t.Errorf("vd.Validate() = %v, want nil", err)
+	}
+	for i := range vd.NumCells() {
+		if got, want := vd.Cell(i).IsEmpty(), i == 0; got != want {
+			t.Errorf("vd.Cell(%d).IsEmpty() = %v, want %v", i, got, want)
+		}
+		if slices.Contains(vd.Cell(i).NeighborIndices(), 0) {
+			t.Errorf("vd.Cell(%d).NeighborIndices() contains the empty cell 0", i)
+		}
+	}
+	if got := vd.TotalArea(); math.Abs(got-4*math.Pi) > 1e-12 {
+		t.Errorf("vd.TotalArea() = %v, want 4π", got)
+	}
+	if got := vd.FindCellIndex(sites[0]); got == 0 {
+		t.Errorf("vd.FindCellIndex(sites[0]) = 0, want a non-empty cell")
+	}
+}
+
+func TestNewPowerDiagram_Relax(t *testing.T) {
+	const n = 300
+	sites := utils.GenerateRandomPoints(n, 0)
+	weights := make([]float64, n)
+	for i := range weights {
+		weights[i] = 4 * math.Pi / n * sites[i].Z
+	}
+	vd, err := NewPowerDiagram(sites, weights)
+	if err != nil {
+		t.Fatalf("NewPowerDiagram(...) error = %v, want nil", err)
+	}
+	// Every cell stands for two input sites, as if deduplicated.
+	for i := range n {
+		vd.sources.indices = append(vd.sources.indices, 2*i, 2*i+1)
+		vd.sources.offsets = append(vd.sources.offsets, 2*i)
+	}
+	vd.sources.offsets = append(vd.sources.offsets, 2*n)
+	if _, err := vd.Relax(3); err != nil {
+		t.Fatalf("vd.Relax(3) error = %v, want nil", err)
+	}
+	if got, want := vd.SourceIndex(5), []int{10, 11}; !slices.Equal(got, want) {
+		t.Errorf("vd.SourceIndex(5) = %v, want %v", got, want)
+	}
+	if err := vd.Validate(); err != nil {
+		t.Errorf("vd.Validate() = %v, want nil", err)
+	}
+	for _, p := range utils.GenerateRandomPoints(200, 1) {
+		want := bruteForcePowerCell(vd.Sites, weights, p)
+		if got := vd.FindCellIndex(p); got != want {
+			t.Errorf("vd.FindCellIndex(%v) = %d, want %d", p, got, want)
+		}
+	}
+}
+
+func TestNewPowerDiagram_Errors(t *testing.T) {
+	sites := fixtures.Load("octahedron")
+	var hemisphere s2.PointVector
+	for _, p := range utils.GenerateRandomPoints(100, 0) {
+		if p.Z > 0.1 {
+			hemisphere = append(hemisphere, p)
+		}
+	}
+	tests := []struct {
+		name    string
+		sites   s2.PointVector
+		weights []float64
+		opts    []DiagramOption
+	}{
+		{"too few weights", sites, make([]float64, len(sites)-1), nil},
+		{"nan weight", sites, []float64{0, 0, math.NaN(), 0, 0, 0}, nil},
+		{"infinite weight", sites, []float64{0, 0, math.Inf(1), 0, 0, 0}, nil},
+		{"too few sites", sites[:3], []float64{0, 1, 0}, nil},
+		{"order independent", sites, []float64{0, 1, 0, 0, 0, 0},
+			[]DiagramOption{WithOrderIndependentOutput()}},
+		{"invalid eps", sites, []float64{0, 1, 0, 0, 0, 0}, []DiagramOption{WithEps(-1)}},
+		{"origin outside hull", hemisphere, slices.Repeat([]float64{0.1}, len(hemisphere)), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewPowerDiagram(tt.sites, tt.weights, tt.opts...); err == nil {
+				t.Errorf("NewPowerDiagram(...) error = nil, want non-nil")
+			}
+		})
+	}
+}
+
+// Benchmarks
+
+func BenchmarkNewPowerDiagram(b *testing.B) {
+	for _, n := range []int{1e+3, 1e+4} {
+		b.Run(fmt.Sprintf("N%d", n), func(b *testing.B) {
+			sites := utils.GenerateRandomPoints(n, 0)
+			weights := make([]float64, n)
+			for i := range weights {
+				weights[i] = 4 * math.Pi / float64(n) * sites[i].Z
+			}
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := NewPowerDiagram(sites, weights); err != nil {
+					b.Fatalf("NewPowerDiagram(...) error = %v, want nil", err)
+				}
+			}
+		})
+	}
+}
+
+// Helpers
+
+// bruteForcePowerCell returns the index of the site of the smallest power at p, see
+// NewPowerDiagram.
+func bruteForcePowerCell(sites s2.PointVector, weights []float64, p s2.Point) int {
+	maxWeight := slices.Max(weights)
+	best, bestDot := -1, math.Inf(-1)
+	for i, s := range sites {
+		if dot := p.Dot(s.Vector) * math.Exp((weights[i]-maxWeight)/2); dot > bestDot {
+			best, bestDot = i, dot
+		}
+	}
+	return best
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/2dChan/s2voronoi/s2voronoipb"
+	"github.com/golang/geo/r3"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// ToProto returns the diagram as the protocol buffer message of s2voronoipb, with the
+// coordinates of the sites and vertices flattened to x, y, z triples and the CSR arrays as
+// uint32 indices. The options of the diagram other than WithEps and WithVertexMerging, the
+// retained triangulation, the site sources of WithDeduplication and the values of SetSiteData
+// are not converted.
+// It returns an error if an index does not fit into a uint32.
+func (d *Diagram) ToProto() (*s2voronoipb.Diagram, error) {
+	cellVertices, err := intsToUint32s(d.CellVertices)
+	if err != nil {
+		return nil, err
+	}
+	cellNeighbors, err := intsToUint32s(d.CellNeighbors)
+	if err != nil {
+		return nil, err
+	}
+	cellOffsets, err := intsToUint32s(d.CellOffsets)
+	if err != nil {
+		return nil, err
+	}
+	return &s2voronoipb.Diagram{
+		Version:       s2voronoipb.Version,
+		Eps:           d.eps,
+		Sites:         pointsToCoords(d.Sites),
+		Vertices:      pointsToCoords(d.Vertices),
+		CellVertices:  cellVertices,
+		CellNeighbors: cellNeighbors,
+		CellOffsets:   cellOffsets,
+		Weights:       d.weights,
+		MergeTol:      float64(d.mergeTol),
+	}, nil
+}
+
+// DiagramFromProto returns the diagram of a message written by ToProto. The diagram is checked
+// like NewDiagramFromArrays, so offsets that are not monotone, do not number the sites plus one
+// or do not cover the rings, indices out of range and vertices that do not match their sites
+// are all rejected, and the counts are checked against the Euler relation of the sphere.
+// It returns an error if the message is nil, of an unknown version or malformed, or if the
+// diagram is not valid.
+func DiagramFromProto(m *s2voronoipb.Diagram) (*Diagram, error) {
+	if m == nil {
+		return nil, errors.New("s2voronoi: nil message")
+	}
+	if m.Version != s2voronoipb.Version {
+		return nil, fmt.Errorf("s2voronoi: unknown message version %d", m.Version)
+	}
+	if len(m.Sites)%3 != 0 || len(m.Vertices)%3 != 0 {
+		return nil, fmt.Errorf("s2voronoi: got %d site and %d vertex coordinates, want "+
+			"multiples of 3", len(m.Sites), len(m.Vertices))
+	}
+	var weights []float64
+	if len(m.Weights) != 0 {
+		weights = m.Weights
+	}
+	return newValidatedDiagram(coordsToPoints(m.Sites), coordsToPoints(m.Vertices),
+		uint32sToInts(m.CellVertices), uint32sToInts(m.CellNeighbors),
+		uint32sToInts(m.CellOffsets), m.Eps, weights, s1.Angle(m.MergeTol))
+}
+
+// pointsToCoords returns the coordinates of ps as consecutive x, y, z triples.
+func pointsToCoords(ps s2.PointVector) []float64 {
+	out := make([]float64, 0, 3*len(ps))
+	for _, p := range ps {
+		out = append(out, p.X, p.Y, p.Z)
+	}
+	return out
+}
+
+// coordsToPoints returns the points of the x, y, z triples of cs, without normalizing them.
+func coordsToPoints(cs []float64) s2.PointVector {
+	out := make(s2.PointVector, len(cs)/3)
+	for i := range out {
+		out[i] = s2.Point{Vector: r3.Vector{X: cs[3*i], Y: cs[3*i+1], Z: cs[3*i+2]}}
+	}
+	return out
+}
+
+// intsToUint32s returns the indices of xs as uint32, or an error if one does not fit.
+func intsToUint32s(xs []int) ([]uint32, error) {
+	out := make([]uint32, len(xs))
+	for i, x := range xs {
+		if x < 0 || uint64(x) > math.MaxUint32 {
+			return nil, fmt.Errorf("s2voronoi: index %d does not fit into uint32", x)
+		}
+		out[i] = uint32(x)
+	}
+	return out, nil
+}
+
+// uint32sToInts returns the indices of xs as int.
+func uint32sToInts(xs []uint32) []int {
+	out := make([]int, len(xs))
+	for i, x := range xs {
+		out[i] = int(x)
+	}
+	return out
+}
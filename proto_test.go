@@ -0,0 +1,256 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/s2voronoipb"
+	"github.com/2dChan/s2voronoi/utils"
+)
+
+func TestDiagram_Proto(t *testing.T) {
+	power := func(t *testing.T) *Diagram {
+		sites := utils.GenerateRandomPoints(100, 0)
+		weights := make([]float64, len(sites))
+		for i := range weights {
+			weights[i] = 0.01 * float64(i%5)
+		}
+		vd, err := NewPowerDiagram(sites, weights)
+		if err != nil {
+			t.Fatalf("NewPowerDiagram(...) error = %v, want nil", err)
+		}
+		return vd
+	}
+	tests := []struct {
+		name string
+		vd   func(t *testing.T) *Diagram
+	}{
+		{"4 sites", func(t *testing.T) *Diagram { return mustNewDiagram(t, 4) }},
+		{"100 sites", func(t *testing.T) *Diagram { return mustNewDiagram(t, 100) }},
+		{"50000 sites", func(t *testing.T) *Diagram { return mustNewDiagram(t, 50000) }},
+		{"eps", func(t *testing.T) *Diagram {
+			vd, err := NewDiagram(utils.GenerateRandomPoints(100, 0), WithEps(1e-9))
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			return vd
+		}},
+		{"empty cell", func(t *testing.T) *Diagram {
+			vd, _ := mustNewEmptyCellDiagram(t)
+			return vd
+		}},
+		{"power", power},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.vd(t)
+			data := mustMarshalProto(t, want)
+			var m s2voronoipb.Diagram
+			if err := m.Unmarshal(data); err != nil {
+				t.Fatalf("m.Unmarshal(...) error = %v, want nil", err)
+			}
+			got, err := DiagramFromProto(&m)
+			if err != nil {
+				t.Fatalf("DiagramFromProto(...) error = %v, want nil", err)
+			}
+			if !got.Equal(want) {
+				t.Errorf("DiagramFromProto(vd.ToProto()) differs from vd")
+			}
+			if got.eps != want.eps {
+				t.Errorf("decoded eps = %v, want %v", got.eps, want.eps)
+			}
+			if !slices.Equal(got.weights, want.weights) {
+				t.Errorf("decoded weights = %v, want %v", got.weights, want.weights)
+			}
+		})
+	}
+}
+
+func TestDiagramFromProto_Errors(t *testing.T) {
+	vd := mustNewDiagram(t, 10)
+	n := uint32(vd.NumCells())
+	tests := []struct {
+		name string
+		// corrupt modifies the message of vd.
+		corrupt func(m *s2voronoipb.Diagram)
+		wantErr error
+	}{
+		{
+			name: "offsets not monotone",
+			corrupt: func(m *s2voronoipb.Diagram) {
+				m.CellOffsets[1], m.CellOffsets[2] = m.CellOffsets[2], m.CellOffsets[1]
+			},
+		},
+		{
+			name:    "offset out of range",
+			corrupt: func(m *s2voronoipb.Diagram) { m.CellOffsets[1] = math.MaxUint32 },
+		},
+		{
+			name:    "offsets too short",
+			corrupt: func(m *s2voronoipb.Diagram) { m.CellOffsets = m.CellOffsets[:n] },
+		},
+		{
+			name:    "offsets missing",
+			corrupt: func(m *s2voronoipb.Diagram) { m.CellOffsets = nil },
+		},
+		{
+			name:    "last offset",
+			corrupt: func(m *s2voronoipb.Diagram) { m.CellOffsets[n]-- },
+		},
+		{
+			name: "vertex out of range",
+			corrupt: func(m *s2voronoipb.Diagram) {
+				m.CellVertices[0] = uint32(len(m.Vertices) / 3)
+			},
+		},
+		{
+			name:    "neighbor out of range",
+			corrupt: func(m *s2voronoipb.Diagram) { m.CellNeighbors[0] = n },
+		},
+		{
+			name:    "neighbors too short",
+			corrupt: func(m *s2voronoipb.Diagram) { m.CellNeighbors = m.CellNeighbors[1:] },
+		},
+		{
+			name:    "moved vertex",
+			corrupt: func(m *s2voronoipb.Diagram) { copy(m.Vertices[:3], m.Vertices[3:6]) },
+		},
+		{
+			name: "unused vertex",
+			corrupt: func(m *s2voronoipb.Diagram) {
+				m.Vertices = append(m.Vertices, m.Vertices[:3]...)
+			},
+		},
+		{
+			name:    "coordinates",
+			corrupt: func(m *s2voronoipb.Diagram) { m.Vertices = m.Vertices[1:] },
+		},
+		{
+			name:    "site not unit length",
+			corrupt: func(m *s2voronoipb.Diagram) { m.Sites[0] *= 2 },
+			wantErr: ErrNotUnitLength,
+		},
+		{
+			name:    "insufficient sites",
+			corrupt: func(m *s2voronoipb.Diagram) { m.Sites = m.Sites[:9] },
+			wantErr: ErrInsufficientSites,
+		},
+		{
+			name:    "weights",
+			corrupt: func(m *s2voronoipb.Diagram) { m.Weights = []float64{1} },
+		},
+		{
+			name: "weight not finite",
+			corrupt: func(m *s2voronoipb.Diagram) {
+				m.Weights = make([]float64, n)
+				m.Weights[0] = math.NaN()
+			},
+		},
+		{
+			name:    "eps",
+			corrupt: func(m *s2voronoipb.Diagram) { m.Eps = 0 },
+			wantErr: ErrInvalidOption,
+		},
+		{
+			name:    "version",
+			corrupt: func(m *s2voronoipb.Diagram) { m.Version = 2 },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := vd.ToProto()
+			if err != nil {
+				t.Fatalf("vd.ToProto() error = %v, want nil", err)
+			}
+			tt.corrupt(m)
+			got, err := DiagramFromProto(m)
+			if err == nil {
+				t.Fatalf("DiagramFromProto(...) = %v, want error", got)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("DiagramFromProto(...) error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := DiagramFromProto(nil); err == nil {
+		t.Errorf("DiagramFromProto(nil) error = nil, want error")
+	}
+}
+
+func TestIntsToUint32s(t *testing.T) {
+	got, err := intsToUint32s([]int{0, 1, math.MaxInt32})
+	if err != nil {
+		t.Fatalf("intsToUint32s(...) error = %v, want nil", err)
+	}
+	if want := []uint32{0, 1, math.MaxInt32}; !slices.Equal(got, want) {
+		t.Errorf("intsToUint32s(...) = %v, want %v", got, want)
+	}
+	if _, err := intsToUint32s([]int{0, -1}); err == nil {
+		t.Errorf("intsToUint32s([0 -1]) error = nil, want error")
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDiagram_ToProto(b *testing.B) {
+	vd, err := NewDiagram(utils.GenerateRandomPoints(10000, 0))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	for b.Loop() {
+		m, err := vd.ToProto()
+		if err != nil {
+			b.Fatalf("vd.ToProto() error = %v, want nil", err)
+		}
+		if _, err := m.Marshal(); err != nil {
+			b.Fatalf("m.Marshal() error = %v, want nil", err)
+		}
+	}
+}
+
+func BenchmarkDiagramFromProto(b *testing.B) {
+	vd, err := NewDiagram(utils.GenerateRandomPoints(10000, 0))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	m, err := vd.ToProto()
+	if err != nil {
+		b.Fatalf("vd.ToProto() error = %v, want nil", err)
+	}
+	data, err := m.Marshal()
+	if err != nil {
+		b.Fatalf("m.Marshal() error = %v, want nil", err)
+	}
+	for b.Loop() {
+		var m s2voronoipb.Diagram
+		if err := m.Unmarshal(data); err != nil {
+			b.Fatalf("m.Unmarshal(...) error = %v, want nil", err)
+		}
+		if _, err := DiagramFromProto(&m); err != nil {
+			b.Fatalf("DiagramFromProto(...) error = %v, want nil", err)
+		}
+	}
+}
+
+// Helpers
+
+// mustMarshalProto returns the wire encoding of the message of vd.
+func mustMarshalProto(t *testing.T, vd *Diagram) []byte {
+	t.Helper()
+	m, err := vd.ToProto()
+	if err != nil {
+		t.Fatalf("vd.ToProto() error = %v, want nil", err)
+	}
+	data, err := m.Marshal()
+	if err != nil {
+		t.Fatalf("m.Marshal() error = %v, want nil", err)
+	}
+	return data
+}
@@ -0,0 +1,206 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/2dChan/s2voronoi/s2delaunay"
+	"github.com/golang/geo/s2"
+)
+
+// rebuildBuffers holds the triangulation state that Rebuild reuses between calls.
+type rebuildBuffers struct {
+	builder *s2delaunay.Builder
+	dt      s2delaunay.Triangulation
+
+	// The options the builder was created with. The progress function is set on every call.
+	eps  float64
+	near bool
+}
+
+// Rebuild replaces the contents of the diagram by the Voronoi diagram of the sites, see
+// NewDiagram, whose result it equals. The diagram takes ownership of the sites like NewDiagram.
+//
+// The slices of the receiver are overwritten and reused whenever their capacity suffices, and
+// they grow only if the number of sites does, since a diagram of n sites always has 2n-4
+// vertices. The convex hull buffers are kept in the receiver from the second build on, so
+// rebuilding a diagram from successive site sets of the same size, e.g. per animation frame,
+// allocates little beyond the internal structures of the hull algorithm. Slices obtained from
+// the diagram before the call must therefore not be used after it, and shallow copies of the
+// diagram must not be rebuilt. The same holds for the triangulation retained with
+// WithRetainedTriangulation, which lives in the kept buffers and is overwritten by the next
+// call. Diagrams built with WithOrderIndependentOutput are allocated anew.
+//
+// It returns an error if the diagram cannot be constructed, in which case the contents of the
+// diagram are unspecified.
+func (d *Diagram) Rebuild(sites s2.PointVector, setters ...DiagramOption) error {
+	return d.rebuild(context.Background(), sites, setters...)
+}
+
+// rebuild implements Rebuild, checking ctx between the phases of the construction.
+func (d *Diagram) rebuild(ctx context.Context, sites s2.PointVector,
+	setters ...DiagramOption) error {
+	if len(sites) < 4 {
+		return errInsufficientSites
+	}
+
+	opts := &DiagramOptions{
+		Eps: DefaultEps,
+	}
+	for _, set := range setters {
+		err := set(opts)
+		if err != nil {
+			return err
+		}
+	}
+	if opts.SkipNonPoints {
+		return errorf(ErrInvalidOption,
+			"s2voronoi: skipping non-points only applies to NewDiagramFromGeoJSON")
+	}
+	return d.rebuildOptions(ctx, sites, opts)
+}
+
+// rebuildOptions implements rebuild for parsed options.
+func (d *Diagram) rebuildOptions(ctx context.Context, sites s2.PointVector,
+	opts *DiagramOptions) error {
+	if len(sites) < 4 {
+		return errInsufficientSites
+	}
+	if err := prepareSites(sites, opts); err != nil {
+		return err
+	}
+	var sources siteSources
+	if opts.Deduplication != 0 {
+		sites, sources = deduplicate(sites, opts.Deduplication)
+		if len(sites) < 4 {
+			return errorf(ErrInsufficientSites,
+				"s2voronoi: %d sites left after deduplication, need at least 4", len(sites))
+		}
+	}
+
+	if opts.OrderIndependent {
+		nd, err := newOrderIndependentDiagram(ctx, sites, opts)
+		if err != nil {
+			return err
+		}
+		*d = *nd
+		d.sources = sources
+		return nil
+	}
+
+	// A fresh diagram, e.g. one of NewDiagram, does not keep the buffers.
+	keep := d.Sites != nil
+	buf := d.buffers
+	if buf == nil || buf.eps != opts.Eps || buf.near != opts.NearDuplicateCheck {
+		b, err := s2delaunay.NewBuilder(opts.triangulationOptions()...)
+		if err != nil {
+			return err
+		}
+		buf = &rebuildBuffers{builder: b, eps: opts.Eps, near: opts.NearDuplicateCheck}
+	}
+	buf.builder.SetProgress(opts.Progress)
+	dt := &buf.dt
+	dt.IncidentTriangleIndices = d.CellVertices
+	dt.IncidentTriangleOffsets = d.CellOffsets
+	if err := opts.triangulate(ctx, buf.builder, dt, sites); err != nil {
+		return err
+	}
+
+	d.eps = opts.Eps
+	d.orderIndependent = false
+	d.weights = nil
+	d.maxWeight = 0
+	d.sources = sources
+	d.mergeTol = 0
+	d.siteData = nil
+	d.buffers = nil
+	if keep {
+		d.buffers = buf
+	}
+	if err := d.setTriangulationContext(ctx, dt, opts.Progress); err != nil {
+		return err
+	}
+	if opts.VertexMerging != 0 {
+		return d.mergeVertices(opts.VertexMerging)
+	}
+	if opts.RetainTriangulation {
+		d.cache.triangulation = dt
+	}
+	return nil
+}
+
+// RebuildFromSitesAndVertices recomputes the topology of the diagram from Sites and Vertices,
+// e.g. after manual edits or deserialization. Sites and Vertices are the source of truth and
+// are not modified: the sites are triangulated again and every Delaunay triangle is matched to
+// the stored vertex at its circumcenter, so vertex indices are preserved. CellVertices,
+// CellNeighbors and CellOffsets are regenerated from scratch and all cached structures are
+// discarded, including a retained triangulation. The options of the diagram, its site sources
+// and the values of SetSiteData are kept.
+// It returns an error and leaves the diagram unchanged if the diagram is a power diagram, whose
+// topology depends on the weights, if the sites cannot be triangulated, or if the vertices do
+// not correspond one-to-one to the circumcenters of the triangulation.
+func (d *Diagram) RebuildFromSitesAndVertices() error {
+	if d.weights != nil {
+		return errors.New("s2voronoi: power diagrams cannot be rebuilt from sites and vertices")
+	}
+	dt, err := s2delaunay.NewTriangulation(d.Sites, s2delaunay.WithEps(d.eps))
+	if err != nil {
+		return err
+	}
+	if dt.NumTriangles() != len(d.Vertices) {
+		return fmt.Errorf("s2voronoi: %d vertices for %d sites, want %d",
+			len(d.Vertices), d.NumCells(), dt.NumTriangles())
+	}
+
+	// Match circumcenters to vertices by a sweep over the vertices sorted by X.
+	order := make([]int, len(d.Vertices))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(a, b int) int {
+		return d.Vertices[a].Cmp(d.Vertices[b].Vector)
+	})
+	used := make([]bool, len(d.Vertices))
+	vertexOf := make([]int, dt.NumTriangles())
+	for i := range dt.Triangles {
+		cc := triangleCircumcenter(dt.TriangleVertices(i)).Normalize()
+		start, _ := slices.BinarySearchFunc(order, cc.X-validateTolerance, func(v int, x float64) int {
+			return cmp.Compare(d.Vertices[v].X, x)
+		})
+		best, bestDist := -1, validateTolerance
+		for _, v := range order[start:] {
+			if d.Vertices[v].X > cc.X+validateTolerance {
+				break
+			}
+			if dist := d.Vertices[v].Sub(cc).Norm(); !used[v] && dist <= bestDist {
+				best, bestDist = v, dist
+			}
+		}
+		if best < 0 {
+			return fmt.Errorf("s2voronoi: no vertex at the circumcenter of Delaunay triangle %v",
+				dt.Triangles[i])
+		}
+		used[best] = true
+		vertexOf[i] = best
+	}
+
+	cellVertices := make([]int, len(dt.IncidentTriangleIndices))
+	for k, tIdx := range dt.IncidentTriangleIndices {
+		cellVertices[k] = vertexOf[tIdx]
+	}
+	cellNeighbors := make([]int, len(dt.IncidentTriangleIndices))
+	fillCellNeighbors(cellNeighbors, dt)
+
+	d.CellVertices = cellVertices
+	d.CellNeighbors = cellNeighbors
+	d.CellOffsets = dt.IncidentTriangleOffsets
+	d.invalidateCaches()
+	return nil
+}
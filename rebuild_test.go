@@ -0,0 +1,244 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiagram_RebuildFromSitesAndVertices(t *testing.T) {
+	want := mustNewDiagram(t, 100)
+	vd := mustNewDiagram(t, 100)
+	random := rand.New(rand.NewSource(0))
+	random.Shuffle(len(vd.CellVertices), func(i, j int) {
+		vd.CellVertices[i], vd.CellVertices[j] = vd.CellVertices[j], vd.CellVertices[i]
+	})
+	clear(vd.CellNeighbors)
+	vd.CellOffsets = []int{0}
+	vd.SetSiteData(3, "kept")
+
+	if err := vd.RebuildFromSitesAndVertices(); err != nil {
+		t.Fatalf("vd.RebuildFromSitesAndVertices() error = %v, want nil", err)
+	}
+	if got := vd.SiteData(3); got != "kept" {
+		t.Errorf("vd.SiteData(3) = %v, want kept", got)
+	}
+	if err := vd.Validate(); err != nil {
+		t.Errorf("vd.Validate() error = %v, want nil", err)
+	}
+	if diff := cmp.Diff(want.CellVertices, vd.CellVertices); diff != "" {
+		t.Errorf("vd.CellVertices mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want.CellNeighbors, vd.CellNeighbors); diff != "" {
+		t.Errorf("vd.CellNeighbors mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want.CellOffsets, vd.CellOffsets); diff != "" {
+		t.Errorf("vd.CellOffsets mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDiagram_RebuildFromSitesAndVertices_PermutedVertices(t *testing.T) {
+	want := mustNewDiagram(t, 100)
+	vd := mustNewDiagram(t, 100)
+	perm := rand.New(rand.NewSource(0)).Perm(len(vd.Vertices))
+	for i, p := range perm {
+		vd.Vertices[p] = want.Vertices[i]
+	}
+
+	if err := vd.RebuildFromSitesAndVertices(); err != nil {
+		t.Fatalf("vd.RebuildFromSitesAndVertices() error = %v, want nil", err)
+	}
+	for k, v := range want.CellVertices {
+		if got := vd.CellVertices[k]; got != perm[v] {
+			t.Errorf("vd.CellVertices[%d] = %v, want %v", k, got, perm[v])
+		}
+	}
+}
+
+func TestDiagram_RebuildFromSitesAndVertices_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(vd *Diagram)
+	}{
+		{"missing vertex", func(vd *Diagram) { vd.Vertices = vd.Vertices[1:] }},
+		{"moved vertex", func(vd *Diagram) { vd.Vertices[0] = vd.Vertices[1] }},
+		{"too few sites", func(vd *Diagram) { vd.Sites = vd.Sites[:3] }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd := mustNewDiagram(t, 100)
+			tt.corrupt(vd)
+			cellVertices := vd.CellVertices
+			if err := vd.RebuildFromSitesAndVertices(); err == nil {
+				t.Errorf("vd.RebuildFromSitesAndVertices() error = nil, want non-nil")
+			}
+			if &vd.CellVertices[0] != &cellVertices[0] {
+				t.Errorf("vd.RebuildFromSitesAndVertices() modified the diagram on error")
+			}
+		})
+	}
+}
+
+func TestDiagram_RebuildFromSitesAndVertices_Power(t *testing.T) {
+	sites := utils.GenerateRandomPoints(100, 0)
+	weights := make([]float64, len(sites))
+	weights[0] = 0.01
+	vd, err := NewPowerDiagram(sites, weights)
+	if err != nil {
+		t.Fatalf("NewPowerDiagram(...) error = %v, want nil", err)
+	}
+	want := vd.clone()
+	if err := vd.RebuildFromSitesAndVertices(); err == nil {
+		t.Errorf("vd.RebuildFromSitesAndVertices() error = nil, want non-nil")
+	}
+	if !vd.Equal(want) {
+		t.Errorf("vd.RebuildFromSitesAndVertices() modified the diagram on error")
+	}
+}
+
+func TestDiagram_Rebuild(t *testing.T) {
+	power := func(t *testing.T) *Diagram {
+		sites := utils.GenerateRandomPoints(100, 5)
+		weights := make([]float64, len(sites))
+		weights[0] = 0.01
+		vd, err := NewPowerDiagram(sites, weights)
+		if err != nil {
+			t.Fatalf("NewPowerDiagram(...) error = %v, want nil", err)
+		}
+		return vd
+	}
+	tests := []struct {
+		name    string
+		initial func(t *testing.T) *Diagram
+		n       int
+		opts    []DiagramOption
+	}{
+		{"same size", func(t *testing.T) *Diagram { return mustNewDiagram(t, 200) }, 200, nil},
+		{"grow", func(t *testing.T) *Diagram { return mustNewDiagram(t, 100) }, 300, nil},
+		{"shrink", func(t *testing.T) *Diagram { return mustNewDiagram(t, 300) }, 100, nil},
+		{"empty", func(t *testing.T) *Diagram { return new(Diagram) }, 100, nil},
+		{"eps", func(t *testing.T) *Diagram { return mustNewDiagram(t, 100) }, 100,
+			[]DiagramOption{WithEps(1e-9)}},
+		{"order independent", func(t *testing.T) *Diagram { return mustNewDiagram(t, 100) }, 100,
+			[]DiagramOption{WithOrderIndependentOutput()}},
+		{"power", power, 100, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd := tt.initial(t)
+			// Rebuild twice, so that the second build reuses the kept buffers.
+			for k := range 2 {
+				sites := utils.GenerateRandomPoints(tt.n, int64(k+1))
+				want, err := NewDiagram(slices.Clone(sites), tt.opts...)
+				if err != nil {
+					t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+				}
+				if err := vd.Rebuild(sites, tt.opts...); err != nil {
+					t.Fatalf("vd.Rebuild(...) error = %v, want nil", err)
+				}
+				opts := []cmp.Option{
+					cmp.AllowUnexported(Diagram{}),
+					cmp.Comparer(func(a, b *diagramCache) bool { return true }),
+					cmp.Comparer(func(a, b *rebuildBuffers) bool { return true }),
+				}
+				if diff := cmp.Diff(want, vd, opts...); diff != "" {
+					t.Errorf("vd.Rebuild(...) mismatch (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestDiagram_Rebuild_ReusesMemory(t *testing.T) {
+	vd := mustNewDiagram(t, 200)
+	if err := vd.Rebuild(utils.GenerateRandomPoints(200, 1)); err != nil {
+		t.Fatalf("vd.Rebuild(...) error = %v, want nil", err)
+	}
+	vertices, cellNeighbors := vd.Vertices, vd.CellNeighbors
+	if err := vd.Rebuild(utils.GenerateRandomPoints(200, 2)); err != nil {
+		t.Fatalf("vd.Rebuild(...) error = %v, want nil", err)
+	}
+	if &vd.Vertices[0] != &vertices[0] {
+		t.Errorf("vd.Rebuild(...) reallocated Vertices, want them reused")
+	}
+	if &vd.CellNeighbors[0] != &cellNeighbors[0] {
+		t.Errorf("vd.Rebuild(...) reallocated CellNeighbors, want them reused")
+	}
+
+	if got := new(Diagram); got.Rebuild(utils.GenerateRandomPoints(10, 0)) != nil ||
+		got.buffers != nil {
+		t.Errorf("new(Diagram).Rebuild(...) kept its buffers, want them released")
+	}
+}
+
+func TestDiagram_Rebuild_Error(t *testing.T) {
+	tests := []struct {
+		name  string
+		sites s2.PointVector
+		opts  []DiagramOption
+	}{
+		{"too few sites", utils.GenerateRandomPoints(3, 0), nil},
+		{"invalid eps", utils.GenerateRandomPoints(10, 0), []DiagramOption{WithEps(-1)}},
+		{
+			name: "hemisphere",
+			sites: s2.PointVector{
+				s2.PointFromCoords(1, 0, 0.5),
+				s2.PointFromCoords(0, 1, 0.5),
+				s2.PointFromCoords(-1, 0, 0.5),
+				s2.PointFromCoords(0, -1.1, 0.5),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd := mustNewDiagram(t, 100)
+			if err := vd.Rebuild(tt.sites, tt.opts...); err == nil {
+				t.Errorf("vd.Rebuild(...) error = nil, want non-nil")
+			}
+		})
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDiagram_Rebuild(b *testing.B) {
+	for _, n := range []int{1e+3, 1e+4} {
+		frames := make([]s2.PointVector, 2)
+		for k := range frames {
+			frames[k] = utils.GenerateRandomPoints(n, int64(k))
+		}
+		b.Run(fmt.Sprintf("Rebuild/N%d", n), func(b *testing.B) {
+			vd, err := NewDiagram(slices.Clone(frames[0]))
+			if err != nil {
+				b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			b.ReportAllocs()
+			k := 0
+			for b.Loop() {
+				k++
+				if err := vd.Rebuild(frames[k%2]); err != nil {
+					b.Fatalf("vd.Rebuild(...) error = %v, want nil", err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("NewDiagram/N%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			k := 0
+			for b.Loop() {
+				k++
+				if _, err := NewDiagram(frames[k%2]); err != nil {
+					b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,375 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/2dChan/s2voronoi/s2delaunay"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// RelaxOptions holds configuration options for Lloyd's relaxation.
+type RelaxOptions struct {
+	Parallelism      int
+	Density          func(s2.Point) float64
+	ExactCentroids   bool
+	Tolerance        s1.Angle
+	StepCallback     func(step int, d *Diagram) error
+	Stats            bool
+	RelaxationFactor float64
+}
+
+// RelaxResult describes a completed relaxation.
+type RelaxResult struct {
+	// Steps is the number of executed steps, which is less than requested if the relaxation
+	// converged early.
+	Steps int
+	// Converged reports whether the relaxation stopped because the sites moved by less than the
+	// tolerance given by WithTolerance.
+	Converged bool
+	// MaxDisplacement is the largest distance a site moved in the last executed step, or 0 if
+	// no step was executed.
+	MaxDisplacement s1.Angle
+	// Stats holds one entry per executed step if WithStats is given, and is nil otherwise.
+	Stats []RelaxStepStats
+}
+
+// RelaxStepStats describes a single executed relaxation step.
+type RelaxStepStats struct {
+	// MaxDisplacement and MeanDisplacement are the largest and the mean distance the sites
+	// moved in the step.
+	MaxDisplacement, MeanDisplacement s1.Angle
+	// Energy is the CVT energy of the diagram before the step, i.e. the sum over all cells of
+	// the integral of the squared chord distance to the site. Lloyd's relaxation with exact
+	// centroids never increases it.
+	Energy float64
+}
+
+// RelaxOption is a functional option type for relaxation configuration.
+type RelaxOption func(*RelaxOptions) error
+
+// WithParallelism sets the number of goroutines used to compute cell centroids.
+// It must be positive; the default is runtime.GOMAXPROCS(0).
+func WithParallelism(n int) RelaxOption {
+	return func(o *RelaxOptions) error {
+		if n <= 0 {
+			return errorf(ErrInvalidOption, "s2voronoi: parallelism must be positive, got %d", n)
+		}
+		o.Parallelism = n
+		return nil
+	}
+}
+
+// densitySamples is the number of density samples per cell used by Relax with WithDensity.
+const densitySamples = 256
+
+// WithDensity makes Relax move every site to the centroid of its cell weighted by the density
+// f, see Cell.WeightedCentroid, instead of the average of its vertices. The relaxed cells are
+// then smaller where the density is high. f must be non-negative and safe for concurrent use.
+func WithDensity(f func(s2.Point) float64) RelaxOption {
+	return func(o *RelaxOptions) error {
+		if f == nil {
+			return errorf(ErrInvalidOption, "s2voronoi: density must not be nil")
+		}
+		o.Density = f
+		return nil
+	}
+}
+
+// WithExactCentroids makes Relax move every site to the area centroid of its cell, see
+// Cell.Centroid, instead of the average of its vertices. The vertex average is cheaper but
+// biased towards the parts of the boundary with more vertices. It is ignored if WithDensity is
+// given, whose centroids are area-weighted as well.
+func WithExactCentroids() RelaxOption {
+	return func(o *RelaxOptions) error {
+		o.ExactCentroids = true
+		return nil
+	}
+}
+
+// WithTolerance makes Relax stop early once no site moves by tol or more in a step, so that
+// the number of steps given to Relax acts as an upper bound. The step in which the sites moved
+// by less than tol is executed and counted in RelaxResult.Steps. It must be non-negative; the
+// default 0 never stops early.
+func WithTolerance(tol s1.Angle) RelaxOption {
+	return func(o *RelaxOptions) error {
+		if tol < 0 {
+			return errorf(ErrInvalidOption,
+				"s2voronoi: tolerance must be non-negative, got %v", tol)
+		}
+		o.Tolerance = tol
+		return nil
+	}
+}
+
+// WithStepCallback makes Relax call f after every executed step with the number of executed
+// steps, starting at 1, and the diagram after that step. If f returns an error, the relaxation
+// stops and returns it, leaving the receiver unchanged.
+//
+// The diagram passed to f is the working copy of the relaxation, not the receiver. Its slices
+// are overwritten in place by the following step, so f must not modify it or keep it beyond the
+// call; Relaxed(0) returns an independent copy. The diagram passed after the last step becomes
+// the result.
+func WithStepCallback(f func(step int, d *Diagram) error) RelaxOption {
+	return func(o *RelaxOptions) error {
+		if f == nil {
+			return errorf(ErrInvalidOption, "s2voronoi: step callback must not be nil")
+		}
+		o.StepCallback = f
+		return nil
+	}
+}
+
+// WithRelaxationFactor makes every site move omega of the way from its site to its centroid
+// along the great circle through both, instead of onto the centroid. A factor above 1
+// over-relaxes and usually converges in fewer steps, one below 1 damps the movement. It must be
+// in (0, 2]; the default 1 moves every site exactly onto its centroid.
+func WithRelaxationFactor(omega float64) RelaxOption {
+	return func(o *RelaxOptions) error {
+		if !(omega > 0 && omega <= 2) {
+			return errorf(ErrInvalidOption,
+				"s2voronoi: relaxation factor must be in (0, 2], got %v", omega)
+		}
+		o.RelaxationFactor = omega
+		return nil
+	}
+}
+
+// WithStats makes Relax collect RelaxResult.Stats. Every step then additionally computes the
+// distance each site moved and the CVT energy, which costs about as much as CellAreas.
+func WithStats() RelaxOption {
+	return func(o *RelaxOptions) error {
+		o.Stats = true
+		return nil
+	}
+}
+
+// Relax performs Lloyd's relaxation by moving sites to centroids and recomputing the diagram.
+// It is equivalent to RelaxContext with context.Background().
+func (d *Diagram) Relax(steps int, setters ...RelaxOption) (RelaxResult, error) {
+	return d.RelaxContext(context.Background(), steps, setters...)
+}
+
+// RelaxContext performs up to steps steps of Lloyd's relaxation by moving sites to centroids
+// and recomputing the diagram, and reports how many were executed. It checks ctx between steps
+// and returns ctx.Err() if it is done. The diagram is replaced by the result of Relaxed, so on
+// error it is left unchanged. Invalid options yield errors matching ErrInvalidOption, and
+// failures to rebuild the diagram those of NewDiagram.
+//
+// The result is deterministic: every centroid is accumulated by a single goroutine in the
+// stored vertex order of its cell and there are no cross-cell reductions, so the relaxed
+// sites are bitwise identical for any parallelism.
+func (d *Diagram) RelaxContext(ctx context.Context, steps int,
+	setters ...RelaxOption) (RelaxResult, error) {
+	nd, res, err := d.relaxed(ctx, steps, nil, setters...)
+	if err != nil {
+		return RelaxResult{}, err
+	}
+	*d = *nd
+	return res, nil
+}
+
+// RelaxSubset performs up to steps steps of Lloyd's relaxation like Relax, but moves only the
+// sites with the given indices to their centroids while all other sites stay fixed. Every step
+// still rebuilds the whole diagram, so the listed cells adapt to their fixed neighbors. Duplicate
+// indices are ignored. It returns an error if an index is out of range, leaving the diagram
+// unchanged.
+func (d *Diagram) RelaxSubset(steps int, siteIndices []int,
+	setters ...RelaxOption) (RelaxResult, error) {
+	for _, i := range siteIndices {
+		if i < 0 || i >= d.NumCells() {
+			return RelaxResult{}, fmt.Errorf("s2voronoi: site index %d out of range [0 %d)", i,
+				d.NumCells())
+		}
+	}
+	cells := append(make([]int, 0, len(siteIndices)), siteIndices...)
+	slices.Sort(cells)
+	cells = slices.Compact(cells)
+
+	nd, res, err := d.relaxed(context.Background(), steps, cells, setters...)
+	if err != nil {
+		return RelaxResult{}, err
+	}
+	*d = *nd
+	return res, nil
+}
+
+// Relaxed returns the diagram after up to steps steps of Lloyd's relaxation, see Relax, and
+// leaves the receiver untouched. The result shares no slices with the receiver, even for zero
+// steps, so either can be modified without affecting the other.
+func (d *Diagram) Relaxed(steps int, setters ...RelaxOption) (*Diagram, RelaxResult, error) {
+	return d.relaxed(context.Background(), steps, nil, setters...)
+}
+
+// relaxed implements Relaxed, checking ctx between steps and moving only the sites listed in
+// cells, which must be distinct, unless cells is nil. Every step keeps the sizes of all arrays,
+// so the diagram and its triangulation are rebuilt in place and allocate little after the first
+// step. Power diagrams and diagrams built with WithOrderIndependentOutput or WithVertexMerging
+// are rebuilt from scratch.
+func (d *Diagram) relaxed(ctx context.Context, steps int, cells []int,
+	setters ...RelaxOption) (*Diagram, RelaxResult, error) {
+	if steps < 0 {
+		return nil, RelaxResult{},
+			fmt.Errorf("s2voronoi: relax steps must be non-negative, got %d", steps)
+	}
+
+	opts := &RelaxOptions{
+		Parallelism:      runtime.GOMAXPROCS(0),
+		RelaxationFactor: 1,
+	}
+	for _, set := range setters {
+		err := set(opts)
+		if err != nil {
+			return nil, RelaxResult{}, err
+		}
+	}
+
+	var res RelaxResult
+	cur := d.clone()
+	centroids := make(s2.PointVector, d.NumCells())
+	var builder *s2delaunay.Builder
+	dt := new(s2delaunay.Triangulation)
+	for range steps {
+		if err := ctx.Err(); err != nil {
+			return nil, RelaxResult{}, err
+		}
+
+		cur.computeCentroids(centroids, cells, opts)
+		if opts.RelaxationFactor != 1 {
+			for i, c := range centroids {
+				centroids[i] = s2.Interpolate(opts.RelaxationFactor, cur.Sites[i], c)
+			}
+		}
+		maxChord := 0.0
+		for i, c := range centroids {
+			maxChord = max(maxChord, c.Sub(cur.Sites[i].Vector).Norm2())
+		}
+		if opts.Stats {
+			res.Stats = append(res.Stats, cur.stepStats(centroids))
+		}
+		copy(cur.Sites, centroids)
+		res.Steps++
+		res.MaxDisplacement = s1.ChordAngleFromSquaredLength(maxChord).Angle()
+
+		if cur.weights != nil {
+			nd, err := newPowerDiagram(cur.Sites, cur.weights, &DiagramOptions{Eps: cur.eps})
+			if err != nil {
+				return nil, RelaxResult{}, err
+			}
+			nd.sources = cur.sources
+			nd.siteData = cur.siteData
+			cur = nd
+		} else if cur.orderIndependent || cur.mergeTol != 0 {
+			nd, err := NewDiagram(cur.Sites, cur.options()...)
+			if err != nil {
+				return nil, RelaxResult{}, err
+			}
+			nd.sources = cur.sources
+			nd.siteData = cur.siteData
+			cur = nd
+		} else {
+			if builder == nil {
+				var err error
+				builder, err = s2delaunay.NewBuilder(s2delaunay.WithEps(cur.eps))
+				if err != nil {
+					return nil, RelaxResult{}, err
+				}
+			}
+			if err := builder.TriangulateContext(ctx, dt, cur.Sites); err != nil {
+				return nil, RelaxResult{}, err
+			}
+			if err := cur.setTriangulationContext(ctx, dt, nil); err != nil {
+				return nil, RelaxResult{}, err
+			}
+		}
+
+		if opts.StepCallback != nil {
+			if err := opts.StepCallback(res.Steps, cur); err != nil {
+				return nil, RelaxResult{}, err
+			}
+		}
+		if res.MaxDisplacement < opts.Tolerance {
+			res.Converged = true
+			break
+		}
+	}
+
+	return cur, res, nil
+}
+
+// computeCentroids stores the normalized centroid of every cell listed in cells, or of all
+// cells if cells is nil, in dst, and the site for empty and unlisted cells. The listed cells are
+// split into contiguous chunks processed by opts.Parallelism goroutines.
+func (d *Diagram) computeCentroids(dst s2.PointVector, cells []int, opts *RelaxOptions) {
+	n := d.NumCells()
+	if cells != nil {
+		copy(dst, d.Sites)
+		n = len(cells)
+	}
+	parallelism := opts.Parallelism
+	chunk := (n + parallelism - 1) / max(parallelism, 1)
+	if chunk == 0 {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for start := 0; start < n; start += chunk {
+		end := min(start+chunk, n)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := start; k < end; k++ {
+				i := k
+				if cells != nil {
+					i = cells[k]
+				}
+				c := d.Cell(i)
+				if c.IsEmpty() {
+					dst[i] = c.Site()
+					continue
+				}
+				if opts.Density != nil {
+					dst[i] = c.WeightedCentroid(opts.Density, densitySamples)
+					continue
+				}
+				if opts.ExactCentroids {
+					dst[i] = c.Centroid()
+					continue
+				}
+				dst[i] = s2.Point{Vector: c.centroid().Normalize()}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// stepStats returns the statistics of the step moving every site to the given centroid.
+func (d *Diagram) stepStats(centroids s2.PointVector) RelaxStepStats {
+	var st RelaxStepStats
+	var total, energy compensatedSum
+	for i, c := range centroids {
+		dist := s2.ChordAngleBetweenPoints(d.Sites[i], c).Angle()
+		st.MaxDisplacement = max(st.MaxDisplacement, dist)
+		total.Add(dist.Radians())
+
+		// The squared chord distance is 2 - 2*p·site, whose integral over the cell is
+		// 2*area - 2*site·m with the first moment m of the cell.
+		cell := d.Cell(i)
+		if !cell.IsEmpty() {
+			energy.Add(2*cell.Area() - 2*cell.Site().Dot(cell.firstMoment()))
+		}
+	}
+	if n := len(centroids); n > 0 {
+		st.MeanDisplacement = s1.Angle(total.Value() / float64(n))
+	}
+	st.Energy = energy.Value()
+	return st
+}
@@ -0,0 +1,877 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+	"github.com/google/go-cmp/cmp"
+)
+
+// RelaxOptions
+
+func TestWithParallelism(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       int
+		wantErr bool
+	}{
+		{"positive", 4, false},
+		{"zero", 0, true},
+		{"negative", -1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &RelaxOptions{Parallelism: 1}
+			err := WithParallelism(tt.n)(opts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WithParallelism(%d) error = %v, wantErr %v", tt.n, err, tt.wantErr)
+			}
+			if err == nil && opts.Parallelism != tt.n {
+				t.Errorf("WithParallelism(%d) opts.Parallelism = %d, want %d", tt.n,
+					opts.Parallelism, tt.n)
+			}
+		})
+	}
+}
+
+func TestWithDensity(t *testing.T) {
+	opts := &RelaxOptions{Parallelism: 1}
+	if err := WithDensity(nil)(opts); err == nil {
+		t.Errorf("WithDensity(nil) error = nil, want error")
+	}
+	if err := WithDensity(func(s2.Point) float64 { return 1 })(opts); err != nil {
+		t.Errorf("WithDensity(constant) error = %v, want nil", err)
+	}
+	if opts.Density == nil {
+		t.Errorf("WithDensity(constant) opts.Density = nil, want the density")
+	}
+}
+
+func TestWithExactCentroids(t *testing.T) {
+	opts := &RelaxOptions{Parallelism: 1}
+	if err := WithExactCentroids()(opts); err != nil {
+		t.Errorf("WithExactCentroids() error = %v, want nil", err)
+	}
+	if !opts.ExactCentroids {
+		t.Errorf("WithExactCentroids() opts.ExactCentroids = false, want true")
+	}
+}
+
+func TestWithTolerance(t *testing.T) {
+	tests := []struct {
+		name    string
+		tol     s1.Angle
+		wantErr bool
+	}{
+		{"positive", 1e-3, false},
+		{"zero", 0, false},
+		{"negative", -1e-3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &RelaxOptions{Parallelism: 1}
+			err := WithTolerance(tt.tol)(opts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WithTolerance(%v) error = %v, wantErr %v", tt.tol, err, tt.wantErr)
+			}
+			if err == nil && opts.Tolerance != tt.tol {
+				t.Errorf("WithTolerance(%v) opts.Tolerance = %v, want %v", tt.tol, opts.Tolerance,
+					tt.tol)
+			}
+		})
+	}
+}
+
+func TestWithRelaxationFactor(t *testing.T) {
+	tests := []struct {
+		name    string
+		omega   float64
+		wantErr bool
+	}{
+		{"under", 0.5, false},
+		{"one", 1, false},
+		{"over", 1.5, false},
+		{"two", 2, false},
+		{"zero", 0, true},
+		{"negative", -1, true},
+		{"above two", 2.5, true},
+		{"nan", math.NaN(), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &RelaxOptions{Parallelism: 1, RelaxationFactor: 1}
+			err := WithRelaxationFactor(tt.omega)(opts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WithRelaxationFactor(%v) error = %v, wantErr %v", tt.omega, err,
+					tt.wantErr)
+			}
+			if err == nil && opts.RelaxationFactor != tt.omega {
+				t.Errorf("WithRelaxationFactor(%v) opts.RelaxationFactor = %v, want %v", tt.omega,
+					opts.RelaxationFactor, tt.omega)
+			}
+		})
+	}
+}
+
+func TestWithStats(t *testing.T) {
+	opts := &RelaxOptions{Parallelism: 1}
+	if err := WithStats()(opts); err != nil {
+		t.Errorf("WithStats() error = %v, want nil", err)
+	}
+	if !opts.Stats {
+		t.Errorf("WithStats() opts.Stats = false, want true")
+	}
+}
+
+func TestWithStepCallback(t *testing.T) {
+	opts := &RelaxOptions{Parallelism: 1}
+	if err := WithStepCallback(nil)(opts); err == nil {
+		t.Errorf("WithStepCallback(nil) error = nil, want error")
+	}
+	if err := WithStepCallback(func(int, *Diagram) error { return nil })(opts); err != nil {
+		t.Errorf("WithStepCallback(f) error = %v, want nil", err)
+	}
+	if opts.StepCallback == nil {
+		t.Errorf("WithStepCallback(f) opts.StepCallback = nil, want f")
+	}
+}
+
+// Relax
+
+func TestDiagram_Relax(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps int
+		size  int
+	}{
+		{"zero step", 0, 1000},
+		{"one step", 1, 1000},
+		{"multiple steps", 5, 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd := mustNewDiagram(t, tt.size)
+			vdOld := mustNewDiagram(t, tt.size)
+
+			_, err := vd.Relax(tt.steps)
+			if err != nil {
+				t.Fatalf("vd.Relax(%d) error = %v, want nil", tt.steps, err)
+			}
+
+			if len(vd.Sites) != len(vdOld.Sites) {
+				t.Errorf("vd.Relax(%d) Sites count = %d, want %d", tt.steps,
+					len(vd.Sites), len(vdOld.Sites))
+			}
+			if len(vd.Vertices) != len(vdOld.Vertices) {
+				t.Errorf("vd.Relax(%d) Vertices count = %d, want %d", tt.steps,
+					len(vd.Vertices), len(vdOld.Vertices))
+			}
+			if len(vd.CellNeighbors) != len(vdOld.CellNeighbors) {
+				t.Errorf("vd.Relax(%d) CellNeighbors count = %d, want %d", tt.steps,
+					len(vd.CellNeighbors), len(vdOld.CellNeighbors))
+			}
+			if len(vd.CellVertices) != len(vdOld.CellVertices) {
+				t.Errorf("vd.Relax(%d) CellVertices count = %d, want %d", tt.steps,
+					len(vd.CellVertices), len(vdOld.CellVertices))
+			}
+			if len(vd.CellOffsets) != len(vdOld.CellOffsets) {
+				t.Errorf("vd.Relax(%d) CellOffsets count = %d, want %d", tt.steps,
+					len(vd.CellOffsets), len(vdOld.CellOffsets))
+			}
+
+			expectChange := tt.steps != 0
+			msg := "changed"
+			if expectChange {
+				msg = "not changed"
+			}
+			if cmp.Equal(vd.Sites, vdOld.Sites) == expectChange {
+				t.Errorf("vd.Relax(%d) Sites %s", tt.steps, msg)
+			}
+			if cmp.Equal(vd.Vertices, vdOld.Vertices) == expectChange {
+				t.Errorf("vd.Relax(%d) Vertices %s", tt.steps, msg)
+			}
+			if cmp.Equal(vd.CellNeighbors, vdOld.CellNeighbors) == expectChange {
+				t.Errorf("vd.Relax(%d) CellNeighbors %s", tt.steps, msg)
+			}
+			if cmp.Equal(vd.CellVertices, vdOld.CellVertices) == expectChange {
+				t.Errorf("vd.Relax(%d) CellVertices %s", tt.steps, msg)
+			}
+			if cmp.Equal(vd.CellOffsets, vdOld.CellOffsets) == expectChange {
+				t.Errorf("vd.Relax(%d) CellOffsets %s", tt.steps, msg)
+			}
+		})
+	}
+}
+
+func TestDiagram_Relax_Tolerance(t *testing.T) {
+	const tol = s1.Angle(1e-3)
+	vd := mustNewDiagram(t, 200)
+	res, err := vd.Relax(1000, WithTolerance(tol))
+	if err != nil {
+		t.Fatalf("vd.Relax(1000, WithTolerance(%v)) error = %v, want nil", tol, err)
+	}
+	if !res.Converged || res.Steps >= 1000 {
+		t.Errorf("vd.Relax(1000, WithTolerance(%v)) = %+v, want converged early", tol, res)
+	}
+	if res.MaxDisplacement >= tol {
+		t.Errorf("vd.Relax(1000, WithTolerance(%v)).MaxDisplacement = %v, want less than %v",
+			tol, res.MaxDisplacement, tol)
+	}
+
+	// The same number of steps without a tolerance gives the same diagram, while the step
+	// before the last one still moved a site by at least tol.
+	want := mustNewDiagram(t, 200)
+	prev, err := want.Relax(res.Steps - 1)
+	if err != nil {
+		t.Fatalf("want.Relax(%d) error = %v, want nil", res.Steps-1, err)
+	}
+	if prev.Converged || prev.Steps != res.Steps-1 || prev.MaxDisplacement < tol {
+		t.Errorf("want.Relax(%d) = %+v, want %d steps moving by at least %v", res.Steps-1, prev,
+			res.Steps-1, tol)
+	}
+	last, err := want.Relax(1)
+	if err != nil {
+		t.Fatalf("want.Relax(1) error = %v, want nil", err)
+	}
+	if last.MaxDisplacement != res.MaxDisplacement {
+		t.Errorf("want.Relax(1).MaxDisplacement = %v, want %v", last.MaxDisplacement,
+			res.MaxDisplacement)
+	}
+	if diff := cmp.Diff(want.Sites, vd.Sites); diff != "" {
+		t.Errorf("vd.Sites mismatch (-want +got):\n%s", diff)
+	}
+
+	// A cap that is reached first is reported as such.
+	vd = mustNewDiagram(t, 200)
+	if res, err := vd.Relax(2, WithTolerance(tol)); err != nil || res.Steps != 2 || res.Converged {
+		t.Errorf("vd.Relax(2, WithTolerance(%v)) = %+v, %v, want 2 steps, not converged", tol, res,
+			err)
+	}
+	if res, err := vd.Relax(0); err != nil || !cmp.Equal(res, RelaxResult{}) {
+		t.Errorf("vd.Relax(0) = %+v, %v, want zero result", res, err)
+	}
+}
+
+func TestDiagram_Relax_StepCallback(t *testing.T) {
+	// Every step is reported with the diagram a plain relaxation has after that step.
+	vd := mustNewDiagram(t, 200)
+	want := mustNewDiagram(t, 200)
+	var steps []int
+	callback := func(step int, d *Diagram) error {
+		steps = append(steps, step)
+		if _, err := want.Relax(1); err != nil {
+			return err
+		}
+		if diff := cmp.Diff(want.Sites, d.Sites); diff != "" {
+			t.Errorf("step %d: d.Sites mismatch (-want +got):\n%s", step, diff)
+		}
+		return nil
+	}
+	if _, err := vd.Relax(4, WithStepCallback(callback)); err != nil {
+		t.Fatalf("vd.Relax(4, WithStepCallback(...)) error = %v, want nil", err)
+	}
+	if !slices.Equal(steps, []int{1, 2, 3, 4}) {
+		t.Errorf("vd.Relax(4, WithStepCallback(...)) called back with steps %v, want [1 2 3 4]",
+			steps)
+	}
+
+	// An error stops the relaxation mid-way and leaves the diagram unchanged.
+	vd = mustNewDiagram(t, 200)
+	sites := slices.Clone(vd.Sites)
+	errStop := errors.New("stop")
+	calls := 0
+	stop := func(step int, _ *Diagram) error {
+		calls++
+		if step == 2 {
+			return errStop
+		}
+		return nil
+	}
+	if _, err := vd.Relax(10, WithStepCallback(stop)); !errors.Is(err, errStop) {
+		t.Errorf("vd.Relax(10, WithStepCallback(stop)) error = %v, want %v", err, errStop)
+	}
+	if calls != 2 {
+		t.Errorf("vd.Relax(10, WithStepCallback(stop)) called back %d times, want 2", calls)
+	}
+	if !slices.Equal(vd.Sites, sites) {
+		t.Errorf("vd.Sites changed by stopped Relax, want unchanged")
+	}
+}
+
+func TestDiagram_Relax_Stats(t *testing.T) {
+	const steps = 20
+	vd := mustNewDiagram(t, 500)
+	res, err := vd.Relax(steps, WithStats(), WithExactCentroids())
+	if err != nil {
+		t.Fatalf("vd.Relax(%d, WithStats(), ...) error = %v, want nil", steps, err)
+	}
+	if len(res.Stats) != steps {
+		t.Fatalf("len(vd.Relax(%d, WithStats(), ...).Stats) = %d, want %d", steps, len(res.Stats),
+			steps)
+	}
+
+	for i, st := range res.Stats {
+		if st.MeanDisplacement <= 0 || st.MeanDisplacement > st.MaxDisplacement {
+			t.Errorf("Stats[%d] = %+v, want 0 < MeanDisplacement <= MaxDisplacement", i, st)
+		}
+		if i > 0 && st.Energy > res.Stats[i-1].Energy*(1+1e-12) {
+			t.Errorf("Stats[%d].Energy = %v, want at most Stats[%d].Energy = %v", i, st.Energy,
+				i-1, res.Stats[i-1].Energy)
+		}
+	}
+	first, last := res.Stats[0], res.Stats[steps-1]
+	if last.MaxDisplacement > first.MaxDisplacement/10 {
+		t.Errorf("Stats[%d].MaxDisplacement = %v, want at most a tenth of Stats[0] = %v",
+			steps-1, last.MaxDisplacement, first.MaxDisplacement)
+	}
+	if last.MaxDisplacement != res.MaxDisplacement {
+		t.Errorf("Stats[%d].MaxDisplacement = %v, want res.MaxDisplacement = %v", steps-1,
+			last.MaxDisplacement, res.MaxDisplacement)
+	}
+
+	// The stats do not change the relaxation and are not collected by default.
+	want := mustNewDiagram(t, 500)
+	plain, err := want.Relax(steps, WithExactCentroids())
+	if err != nil {
+		t.Fatalf("want.Relax(%d, ...) error = %v, want nil", steps, err)
+	}
+	if plain.Stats != nil {
+		t.Errorf("want.Relax(%d, ...).Stats = %v, want nil", steps, plain.Stats)
+	}
+	if diff := cmp.Diff(want.Sites, vd.Sites); diff != "" {
+		t.Errorf("vd.Sites mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDiagram_Relax_RelaxationFactor(t *testing.T) {
+	// A factor of 1 is the default.
+	vd := mustNewDiagram(t, 200)
+	want := mustNewDiagram(t, 200)
+	if _, err := vd.Relax(3, WithRelaxationFactor(1)); err != nil {
+		t.Fatalf("vd.Relax(3, WithRelaxationFactor(1)) error = %v, want nil", err)
+	}
+	if _, err := want.Relax(3); err != nil {
+		t.Fatalf("want.Relax(3) error = %v, want nil", err)
+	}
+	if diff := cmp.Diff(want.Sites, vd.Sites); diff != "" {
+		t.Errorf("vd.Sites mismatch (-want +got):\n%s", diff)
+	}
+
+	// Every site moves the given fraction of the way to its centroid along the great circle.
+	vd = mustNewDiagram(t, 200)
+	full, _, err := vd.Relaxed(1)
+	if err != nil {
+		t.Fatalf("vd.Relaxed(1) error = %v, want nil", err)
+	}
+	for _, omega := range []float64{0.5, 1.5} {
+		got, _, err := vd.Relaxed(1, WithRelaxationFactor(omega))
+		if err != nil {
+			t.Fatalf("vd.Relaxed(1, WithRelaxationFactor(%v)) error = %v, want nil", omega, err)
+		}
+		for i, site := range vd.Sites {
+			moved, toCentroid := site.Distance(got.Sites[i]), site.Distance(full.Sites[i])
+			if math.Abs(moved.Radians()-omega*toCentroid.Radians()) > 1e-12 {
+				t.Errorf("omega %v: site %d moved by %v, want %v", omega, i, moved,
+					s1.Angle(omega)*toCentroid)
+			}
+			if dist := s2.DistanceFromSegment(got.Sites[i], site, full.Sites[i]); omega < 1 &&
+				dist > 1e-12 {
+				t.Errorf("omega %v: site %d is %v off the arc to its centroid, want 0", omega, i,
+					dist)
+			}
+		}
+	}
+
+	// Over-relaxation converges in fewer steps.
+	const tol = s1.Angle(1e-4)
+	steps := map[float64]int{}
+	for _, omega := range []float64{1, 1.5} {
+		vd := mustNewDiagram(t, 500)
+		res, err := vd.Relax(1000, WithTolerance(tol), WithRelaxationFactor(omega))
+		if err != nil || !res.Converged {
+			t.Fatalf("vd.Relax(1000, WithTolerance(%v), WithRelaxationFactor(%v)) = %+v, %v, "+
+				"want converged", tol, omega, res, err)
+		}
+		steps[omega] = res.Steps
+	}
+	if steps[1.5] >= steps[1] {
+		t.Errorf("omega 1.5 converged in %d steps, want fewer than %d with omega 1", steps[1.5],
+			steps[1])
+	}
+}
+
+func TestDiagram_RelaxSubset(t *testing.T) {
+	const n = 1000
+	center := s2.PointFromCoords(1, 1, 1)
+	patch := mustNewDiagram(t, n).SitesWithinDistance(center, 0.5)
+
+	// The patch converges while all other sites stay fixed.
+	vd := mustNewDiagram(t, n)
+	sites := slices.Clone(vd.Sites)
+	res, err := vd.RelaxSubset(100, patch, WithStats(), WithTolerance(1e-4))
+	if err != nil {
+		t.Fatalf("vd.RelaxSubset(100, patch, ...) error = %v, want nil", err)
+	}
+	if !res.Converged {
+		t.Errorf("vd.RelaxSubset(100, patch, ...) = %+v, want converged", res)
+	}
+	if last := res.Stats[len(res.Stats)-1]; last.MaxDisplacement > res.Stats[0].MaxDisplacement/10 {
+		t.Errorf("last MaxDisplacement = %v, want at most a tenth of the first %v",
+			last.MaxDisplacement, res.Stats[0].MaxDisplacement)
+	}
+	for i := range vd.Sites {
+		moved := vd.Sites[i] != sites[i]
+		if listed := slices.Contains(patch, i); moved != listed {
+			t.Errorf("site %d moved = %v, want %v", i, moved, listed)
+		}
+	}
+	if err := vd.Validate(); err != nil {
+		t.Errorf("vd.Validate() = %v, want nil", err)
+	}
+
+	// Duplicates are ignored and listing all sites is a full relaxation.
+	tests := []struct {
+		name  string
+		cells []int
+		want  func(*Diagram) error
+	}{
+		{
+			name:  "duplicates",
+			cells: append(slices.Clone(patch), patch[0], patch[len(patch)-1], patch[0]),
+			want: func(d *Diagram) error {
+				_, err := d.RelaxSubset(3, patch)
+				return err
+			},
+		},
+		{
+			name: "all sites",
+			cells: func() []int {
+				s := make([]int, n)
+				for i := range s {
+					s[i] = i
+				}
+				return s
+			}(),
+			want: func(d *Diagram) error {
+				_, err := d.Relax(3)
+				return err
+			},
+		},
+		{
+			name:  "no sites",
+			cells: []int{},
+			want:  func(*Diagram) error { return nil },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, want := mustNewDiagram(t, n), mustNewDiagram(t, n)
+			if _, err := got.RelaxSubset(3, tt.cells); err != nil {
+				t.Fatalf("got.RelaxSubset(3, ...) error = %v, want nil", err)
+			}
+			if err := tt.want(want); err != nil {
+				t.Fatalf("tt.want(want) error = %v, want nil", err)
+			}
+			if diff := cmp.Diff(want.Sites, got.Sites); diff != "" {
+				t.Errorf("got.Sites mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	// Out of range indices are rejected and leave the diagram unchanged.
+	for _, i := range []int{-1, n} {
+		vd := mustNewDiagram(t, n)
+		if _, err := vd.RelaxSubset(1, []int{0, i}); err == nil {
+			t.Errorf("vd.RelaxSubset(1, [0 %d]) error = nil, want non-nil", i)
+		}
+		if !slices.Equal(vd.Sites, sites) {
+			t.Errorf("vd.Sites changed by failed RelaxSubset, want unchanged")
+		}
+	}
+}
+
+func TestDiagram_Relax_MatchesRebuild(t *testing.T) {
+	for _, setters := range [][]DiagramOption{nil, {WithOrderIndependentOutput()}} {
+		vd, err := NewDiagram(utils.GenerateRandomPoints(500, 0), setters...)
+		if err != nil {
+			t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+		}
+		want, err := NewDiagram(slices.Clone(vd.Sites), setters...)
+		if err != nil {
+			t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+		}
+
+		// The reference builds a new diagram from the centroids in every step.
+		centroids := make(s2.PointVector, want.NumCells())
+		for range 5 {
+			want.computeCentroids(centroids, nil, &RelaxOptions{Parallelism: 1})
+			if want, err = NewDiagram(slices.Clone(centroids), setters...); err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+		}
+		if _, err := vd.Relax(5); err != nil {
+			t.Fatalf("vd.Relax(5) error = %v, want nil", err)
+		}
+
+		if diff := cmp.Diff(want.Sites, vd.Sites); diff != "" {
+			t.Errorf("vd.Sites mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(want.Vertices, vd.Vertices); diff != "" {
+			t.Errorf("vd.Vertices mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(want.CellVertices, vd.CellVertices); diff != "" {
+			t.Errorf("vd.CellVertices mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(want.CellNeighbors, vd.CellNeighbors); diff != "" {
+			t.Errorf("vd.CellNeighbors mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(want.CellOffsets, vd.CellOffsets); diff != "" {
+			t.Errorf("vd.CellOffsets mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestDiagram_Relaxed(t *testing.T) {
+	for _, steps := range []int{0, 1, 3} {
+		vd := mustNewDiagram(t, 500)
+		sites, vertices := slices.Clone(vd.Sites), slices.Clone(vd.Vertices)
+		cellVertices := slices.Clone(vd.CellVertices)
+
+		got, _, err := vd.Relaxed(steps)
+		if err != nil {
+			t.Fatalf("vd.Relaxed(%d) error = %v, want nil", steps, err)
+		}
+		if !slices.Equal(vd.Sites, sites) || !slices.Equal(vd.Vertices, vertices) ||
+			!slices.Equal(vd.CellVertices, cellVertices) {
+			t.Errorf("vd.Relaxed(%d) modified the receiver", steps)
+		}
+
+		want := mustNewDiagram(t, 500)
+		if _, err := want.Relax(steps); err != nil {
+			t.Fatalf("want.Relax(%d) error = %v, want nil", steps, err)
+		}
+		if diff := cmp.Diff(want.Sites, got.Sites); diff != "" {
+			t.Errorf("vd.Relaxed(%d) Sites mismatch (-Relax +Relaxed):\n%s", steps, diff)
+		}
+
+		// The result shares no slices with the receiver.
+		got.Sites[0] = s2.PointFromCoords(1, 2, 3)
+		got.Vertices[0] = s2.PointFromCoords(1, 2, 3)
+		got.CellVertices[0]++
+		if !slices.Equal(vd.Sites, sites) || !slices.Equal(vd.Vertices, vertices) ||
+			!slices.Equal(vd.CellVertices, cellVertices) {
+			t.Errorf("vd.Relaxed(%d) result shares slices with the receiver", steps)
+		}
+	}
+}
+
+func TestDiagram_Relax_ErrorKeepsDiagram(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	sites := slices.Clone(vd.Sites)
+	if _, err := vd.Relax(1, WithParallelism(0)); err == nil {
+		t.Fatalf("vd.Relax(1, WithParallelism(0)) error = nil, want non-nil")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := vd.RelaxContext(ctx, 1); err == nil {
+		t.Fatalf("vd.RelaxContext(canceled, 1) error = nil, want non-nil")
+	}
+	if !slices.Equal(vd.Sites, sites) {
+		t.Errorf("vd.Sites changed by failed Relax, want unchanged")
+	}
+}
+
+func TestDiagram_Relax_BrokenData(t *testing.T) {
+	tests := []struct {
+		name    string
+		diagram *Diagram
+		steps   int
+	}{
+		{
+			name:    "negative steps",
+			diagram: mustNewDiagram(t, 100),
+			steps:   -1,
+		},
+		{
+			name:    "empty diagram",
+			diagram: &Diagram{},
+			steps:   1,
+		},
+		{
+			name: "diagram with single site",
+			diagram: &Diagram{
+				Sites:         utils.GenerateRandomPoints(1, 0),
+				eps:           0.01,
+				Vertices:      []s2.Point{s2.PointFromCoords(1, 0, 0)},
+				CellNeighbors: []int{},
+				CellVertices:  []int{0},
+				CellOffsets:   []int{0, 1},
+			},
+			steps: 1,
+		},
+		{
+			name: "negative eps",
+			diagram: func() *Diagram {
+				vd := mustNewDiagram(t, 100)
+				vd.eps = -1
+				return vd
+			}(),
+			steps: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.diagram.Relax(tt.steps)
+			if err == nil {
+				t.Errorf("tt.diagram.Relax(%v) error = nil, want non-nil", tt.steps)
+			}
+		})
+	}
+}
+
+func TestDiagram_RelaxContext_Deterministic(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []RelaxOption
+	}{
+		{"vertex average", nil},
+		{"exact centroids", []RelaxOption{WithExactCentroids()}},
+		{"density", []RelaxOption{WithDensity(func(p s2.Point) float64 { return 1 + p.Z })}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serial := mustNewDiagram(t, 1000)
+			opts := append(slices.Clone(tt.opts), WithParallelism(1))
+			if _, err := serial.RelaxContext(context.Background(), 5, opts...); err != nil {
+				t.Fatalf("serial.RelaxContext(..., 5, WithParallelism(1)) error = %v, want nil",
+					err)
+			}
+
+			for _, n := range []int{2, 8, 0} {
+				parallel := mustNewDiagram(t, 1000)
+				opts := slices.Clone(tt.opts)
+				if n > 0 {
+					opts = append(opts, WithParallelism(n))
+				}
+				if _, err := parallel.RelaxContext(context.Background(), 5, opts...); err != nil {
+					t.Fatalf("parallel.RelaxContext(..., 5, ...) error = %v, want nil", err)
+				}
+				if diff := cmp.Diff(serial.Sites, parallel.Sites); diff != "" {
+					t.Errorf("parallelism %d: relaxed Sites mismatch (-serial +parallel):\n%s", n,
+						diff)
+				}
+			}
+		})
+	}
+}
+
+func TestDiagram_RelaxContext_Canceled(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := vd.RelaxContext(ctx, 1)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("vd.RelaxContext(canceled, 1) error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestDiagram_Relax_EmptyCell(t *testing.T) {
+	vd, empty := mustNewEmptyCellDiagram(t)
+	site := vd.Sites[empty]
+
+	centroids := make(s2.PointVector, vd.NumCells())
+	vd.computeCentroids(centroids, nil, &RelaxOptions{Parallelism: 1})
+	if centroids[empty] != site {
+		t.Errorf("vd.computeCentroids(...)[%d] = %v, want site %v", empty, centroids[empty], site)
+	}
+
+	if _, err := vd.Relax(1); err != nil {
+		t.Fatalf("vd.Relax(1) error = %v, want nil", err)
+	}
+	if vd.Sites[empty] != site {
+		t.Errorf("vd.Sites[%d] = %v after Relax, want unchanged %v", empty, vd.Sites[empty], site)
+	}
+}
+
+func TestDiagram_Relax_Density(t *testing.T) {
+	meanZ := func(vd *Diagram) float64 {
+		sum := 0.0
+		for _, s := range vd.Sites {
+			sum += s.Z
+		}
+		return sum / float64(vd.NumCells())
+	}
+
+	// The sites migrate towards the dense northern hemisphere, while the plain relaxation keeps
+	// them in place on average.
+	plain, dense := mustNewDiagram(t, 500), mustNewDiagram(t, 500)
+	before := meanZ(dense)
+	if _, err := plain.Relax(5); err != nil {
+		t.Fatalf("plain.Relax(5) error = %v, want nil", err)
+	}
+	density := func(p s2.Point) float64 { return math.Exp(2 * p.Z) }
+	if _, err := dense.Relax(5, WithDensity(density), WithParallelism(4)); err != nil {
+		t.Fatalf("dense.Relax(5, WithDensity(density)) error = %v, want nil", err)
+	}
+	if got, want := meanZ(dense), max(before, meanZ(plain))+0.01; got < want {
+		t.Errorf("mean site Z after dense.Relax(5) = %v, want at least %v", got, want)
+	}
+
+	// With a constant density a relaxed diagram moves about as with the plain relaxation. The
+	// area centroid differs from the vertex average of the cells, but not by much once they
+	// are regular.
+	plain = mustNewDiagram(t, 500)
+	if _, err := plain.Relax(10); err != nil {
+		t.Fatalf("plain.Relax(10) error = %v, want nil", err)
+	}
+	uniform, err := NewDiagram(slices.Clone(plain.Sites))
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	if _, err := plain.Relax(1); err != nil {
+		t.Fatalf("plain.Relax(1) error = %v, want nil", err)
+	}
+	constant := func(s2.Point) float64 { return 1 }
+	if _, err := uniform.Relax(1, WithDensity(constant)); err != nil {
+		t.Fatalf("uniform.Relax(1, WithDensity(constant)) error = %v, want nil", err)
+	}
+	for i := range plain.NumCells() {
+		limit := plain.Cell(i).CapBound().Radius() / 4
+		if dist := plain.Sites[i].Distance(uniform.Sites[i]); dist > limit {
+			t.Errorf("uniform.Sites[%d] is %v from plain.Sites[%d], want at most %v", i, dist, i,
+				limit)
+		}
+	}
+}
+
+func TestDiagram_Relax_DensityCap(t *testing.T) {
+	// A density 16 times higher in a cap packs smaller cells into it. The optimal planar CVT has
+	// cells 4 times smaller, which the sites approach slowly across the cap boundary.
+	center := s2.PointFromCoords(0, 0, 1)
+	const radius = s1.Angle(0.6)
+	density := func(p s2.Point) float64 {
+		if p.Distance(center) < radius {
+			return 16
+		}
+		return 1
+	}
+	vd := mustNewDiagram(t, 500)
+	if _, err := vd.Relax(20, WithDensity(density)); err != nil {
+		t.Fatalf("vd.Relax(20, WithDensity(density)) error = %v, want nil", err)
+	}
+
+	var inside, outside []float64
+	for i, area := range vd.CellAreas() {
+		if vd.Sites[i].Distance(center) < radius {
+			inside = append(inside, area)
+		} else {
+			outside = append(outside, area)
+		}
+	}
+	mean := func(s []float64) float64 {
+		sum := 0.0
+		for _, v := range s {
+			sum += v
+		}
+		return sum / float64(len(s))
+	}
+	if in, out := mean(inside), mean(outside); in > out/2 {
+		t.Errorf("mean cell area inside the cap = %v, want at most half of %v outside", in, out)
+	}
+
+	// A constant density reproduces the exact centroids up to the quadrature error.
+	if _, err := vd.Relax(10, WithExactCentroids()); err != nil {
+		t.Fatalf("vd.Relax(10, WithExactCentroids()) error = %v, want nil", err)
+	}
+	exact, _, err := vd.Relaxed(1, WithExactCentroids())
+	if err != nil {
+		t.Fatalf("vd.Relaxed(1, WithExactCentroids()) error = %v, want nil", err)
+	}
+	constant, _, err := vd.Relaxed(1, WithDensity(func(s2.Point) float64 { return 2 }))
+	if err != nil {
+		t.Fatalf("vd.Relaxed(1, WithDensity(constant)) error = %v, want nil", err)
+	}
+	for i := range vd.NumCells() {
+		limit := vd.Cell(i).CapBound().Radius() * 1e-4
+		if dist := exact.Sites[i].Distance(constant.Sites[i]); dist > limit {
+			t.Errorf("constant.Sites[%d] is %v from exact.Sites[%d], want at most %v", i, dist, i,
+				limit)
+		}
+	}
+}
+
+func TestDiagram_Relax_ExactCentroids(t *testing.T) {
+	vd := mustNewDiagram(t, 500)
+	want := make(s2.PointVector, vd.NumCells())
+	for i := range vd.NumCells() {
+		want[i] = vd.Cell(i).Centroid()
+	}
+	if _, err := vd.Relax(1, WithExactCentroids()); err != nil {
+		t.Fatalf("vd.Relax(1, WithExactCentroids()) error = %v, want nil", err)
+	}
+	if diff := cmp.Diff(want, vd.Sites); diff != "" {
+		t.Errorf("vd.Sites after Relax(1, WithExactCentroids()) mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDiagram_Relax(b *testing.B) {
+	sizes := []int{1e+2, 1e+3, 1e+4}
+	steps := []int{1, 10}
+	for _, pointsCnt := range sizes {
+		for _, step := range steps {
+			b.Run(fmt.Sprintf("N%d Steps%d", pointsCnt, step), func(b *testing.B) {
+				points := utils.GenerateRandomPoints(pointsCnt, 0)
+
+				b.ReportAllocs()
+				b.ResetTimer()
+				for b.Loop() {
+					b.StopTimer()
+					vd, err := NewDiagram(points)
+					if err != nil {
+						b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+					}
+					b.StartTimer()
+
+					_, err = vd.Relax(step)
+					if err != nil {
+						b.Fatalf("vd.Relax(%d) error = %v, want nil", step, err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkDiagram_computeCentroids(b *testing.B) {
+	points := utils.GenerateRandomPoints(1e+5, 0)
+	vd, err := NewDiagram(points)
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	centroids := make(s2.PointVector, vd.NumCells())
+	for _, n := range slices.Compact([]int{1, runtime.GOMAXPROCS(0)}) {
+		b.Run(fmt.Sprintf("N%d Parallelism%d", len(points), n), func(b *testing.B) {
+			opts := &RelaxOptions{Parallelism: n}
+			b.ReportAllocs()
+			for b.Loop() {
+				vd.computeCentroids(centroids, nil, opts)
+			}
+		})
+	}
+}
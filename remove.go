@@ -0,0 +1,220 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/golang/geo/s2"
+)
+
+// RemoveSite removes the site i from the diagram and grows the cells of its former neighbors to
+// fill its region. The hole left in the Delaunay triangulation is re-triangulated by clipping
+// Delaunay ears off the ring of the neighbors, and only their rings are recomputed.
+//
+// Indices are kept stable by swapping with the last site: the last site, if it is not i, takes
+// the index i and every other site keeps its index. The vertices of the removed cell are
+// replaced by the new ones and the remaining vertices keep their relative order. Diagrams built
+// with WithOrderIndependentOutput or WithVertexMerging are rebuilt from scratch on the remaining
+// sites instead.
+//
+// It returns an error and leaves the diagram unchanged if fewer than 4 sites would remain, if
+// the diagram is a power diagram, or if the hole cannot be re-triangulated, which happens when
+// the remaining sites lie in an open hemisphere or a new circumcenter is degenerate within eps.
+// It panics if i is out of range.
+func (d *Diagram) RemoveSite(i int) error {
+	d.checkCellIndex(i)
+	n := d.NumCells()
+	if n <= 4 {
+		return errorf(ErrInsufficientSites,
+			"s2voronoi: removing a site from %d sites leaves fewer than 4", n)
+	}
+	if d.weights != nil {
+		return errors.New("s2voronoi: sites cannot be removed from power diagrams")
+	}
+
+	sites := slices.Clone(d.Sites)
+	sites[i] = sites[n-1]
+	sites = sites[:n-1]
+	if d.orderIndependent || d.mergeTol != 0 {
+		nd, err := NewDiagram(sites, d.options()...)
+		if err != nil {
+			return err
+		}
+		nd.siteData = removeSiteData(d.siteData, i)
+		*d = *nd
+		return nil
+	}
+
+	hole := d.Cell(i).NeighborIndices()
+	triangles, err := d.fillHole(i, hole)
+	if err != nil {
+		return err
+	}
+
+	// The new vertices are the circumcenters of the triangles, keyed by their corners.
+	centers := make(map[[3]int]int, len(triangles))
+	vertices := slices.Clone(d.Vertices)
+	for _, t := range triangles {
+		centers[sortedTriangle(t)] = len(vertices)
+		cc := triangleCircumcenter(d.Sites[t[0]], d.Sites[t[1]], d.Sites[t[2]])
+		if cc.Norm() <= d.eps {
+			return errorf(ErrDegenerateInput,
+				"s2voronoi: circumcenter of sites %d, %d and %d is degenerate", t[0], t[1], t[2])
+		}
+		vertices = append(vertices, s2.Point{Vector: cc.Normalize()})
+	}
+
+	// In the ring of a neighbor a, the edge to i runs between the vertices of the triangles
+	// (a, x, i) and (a, i, y). Both are replaced by the fan of new triangles around a from x to
+	// y, whose diagonals become the neighbors of a.
+	rings := make(map[int]cellRing, len(hole))
+	for _, a := range hole {
+		c := d.Cell(a)
+		vs, ns := c.VertexIndices(), c.NeighborIndices()
+		num := len(vs)
+		e := slices.Index(ns, i)
+		x, y := ns[(e+num-1)%num], ns[(e+1)%num]
+		var fanVertices, fanNeighbors []int
+		for prev := x; prev != y; {
+			next := -1
+			for _, t := range triangles {
+				if slices.Contains(t[:], a) && slices.Contains(t[:], prev) {
+					if k := 3 - slices.Index(t[:], a) - slices.Index(t[:], prev); t[k] != x &&
+						!slices.Contains(fanNeighbors, t[k]) {
+						next = t[k]
+						fanVertices = append(fanVertices, centers[sortedTriangle(t)])
+						break
+					}
+				}
+			}
+			if next < 0 {
+				return errorf(ErrDegenerateInput,
+					"s2voronoi: cannot re-triangulate the hole of site %d", i)
+			}
+			fanNeighbors = append(fanNeighbors, next)
+			prev = next
+		}
+
+		// Rotate the ring so that the replaced entries e and e+1 are at its end.
+		var r cellRing
+		for k := range num - 2 {
+			r.vertices = append(r.vertices, vs[(e+2+k)%num])
+			r.neighbors = append(r.neighbors, ns[(e+2+k)%num])
+		}
+		r.vertices = append(r.vertices, fanVertices...)
+		r.neighbors = append(r.neighbors, fanNeighbors...)
+		rings[a] = r
+	}
+
+	// The vertices of the removed cell are dropped and the remaining ones renumbered.
+	removed := make(map[int]bool, len(hole))
+	for _, v := range d.Cell(i).VertexIndices() {
+		removed[v] = true
+	}
+	remap := make([]int, len(vertices))
+	compact := vertices[:0]
+	for v, p := range vertices {
+		if removed[v] {
+			continue
+		}
+		remap[v] = len(compact)
+		compact = append(compact, p)
+	}
+	site := func(j int) int {
+		if j == n-1 {
+			return i
+		}
+		return j
+	}
+
+	offsets := make([]int, 1, n)
+	cellVertices := make([]int, 0, len(d.CellVertices))
+	cellNeighbors := make([]int, 0, len(d.CellNeighbors))
+	for j := range n - 1 {
+		src := j
+		if j == i {
+			src = n - 1
+		}
+		r, ok := rings[src]
+		if !ok {
+			c := d.Cell(src)
+			r = cellRing{vertices: c.VertexIndices(), neighbors: c.NeighborIndices()}
+		}
+		for k := range r.vertices {
+			cellVertices = append(cellVertices, remap[r.vertices[k]])
+			cellNeighbors = append(cellNeighbors, site(r.neighbors[k]))
+		}
+		offsets = append(offsets, len(cellVertices))
+	}
+
+	d.Sites = sites
+	d.Vertices = compact
+	d.CellVertices = cellVertices
+	d.CellNeighbors = cellNeighbors
+	d.CellOffsets = offsets
+	d.sources = siteSources{}
+	d.siteData = removeSiteData(d.siteData, i)
+	d.invalidateCaches()
+	return nil
+}
+
+// fillHole returns the Delaunay triangulation of the ring of sites hole left by removing the
+// site i, which is found by repeatedly clipping an ear of the ring whose circumcircle contains
+// no other site of the ring. The ears are oriented like the triangles around i.
+func (d *Diagram) fillHole(i int, hole []int) ([][3]int, error) {
+	ring := slices.Clone(hole)
+	orient := s2.RobustSign(d.Sites[ring[0]], d.Sites[ring[1]], d.Sites[i])
+	triangles := make([][3]int, 0, len(ring)-2)
+	for len(ring) > 3 {
+		ear := -1
+		for k := range ring {
+			a, b, c := ring[k], ring[(k+1)%len(ring)], ring[(k+2)%len(ring)]
+			if d.isDelaunayEar(a, b, c, orient, hole) {
+				ear = k
+				break
+			}
+		}
+		if ear < 0 {
+			return nil, errorf(ErrDegenerateInput,
+				"s2voronoi: cannot re-triangulate the hole of site %d", i)
+		}
+		b := (ear + 1) % len(ring)
+		triangles = append(triangles, [3]int{ring[ear], ring[b], ring[(ear+2)%len(ring)]})
+		ring = slices.Delete(ring, b, b+1)
+	}
+	return append(triangles, [3]int{ring[0], ring[1], ring[2]}), nil
+}
+
+// isDelaunayEar reports whether the sites a, b and c are oriented like orient, have a
+// circumcenter that is not degenerate within eps, and their circumcircle, on the side given by
+// the orientation, contains no other site of the hole.
+func (d *Diagram) isDelaunayEar(a, b, c int, orient s2.Direction, hole []int) bool {
+	pa, pb, pc := d.Sites[a], d.Sites[b], d.Sites[c]
+	if s2.RobustSign(pa, pb, pc) != orient {
+		return false
+	}
+	center := pb.Sub(pa.Vector).Cross(pc.Sub(pa.Vector))
+	if center.Norm() <= d.eps {
+		return false
+	}
+	if orient == s2.Clockwise {
+		center = center.Mul(-1)
+	}
+	bound := pa.Dot(center)
+	for _, j := range hole {
+		if j != a && j != b && j != c && d.Sites[j].Dot(center) > bound {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedTriangle returns the corners of t in increasing order.
+func sortedTriangle(t [3]int) [3]int {
+	slices.Sort(t[:])
+	return t
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiagram_RemoveSite(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       int
+		removed []int
+	}{
+		{"first", 100, []int{0}},
+		{"last", 100, []int{99}},
+		{"middle", 100, []int{42}},
+		{"many", 300, []int{5, 17, 290, 0, 100, 150, 7, 250, 33, 1}},
+		{"down to 4", 0, []int{4, 4, 4, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var vd *Diagram
+			if tt.n > 0 {
+				vd = mustNewDiagram(t, tt.n)
+			} else {
+				// The removed sites are added to a tetrahedron, which remains.
+				var err error
+				vd, err = NewDiagram(append(tetrahedron(), utils.GenerateRandomPoints(4, 0)...))
+				if err != nil {
+					t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+				}
+			}
+			sites := slices.Clone(vd.Sites)
+			for _, i := range tt.removed {
+				if err := vd.RemoveSite(i); err != nil {
+					t.Fatalf("vd.RemoveSite(%d) error = %v, want nil", i, err)
+				}
+				last := len(sites) - 1
+				sites[i] = sites[last]
+				sites = sites[:last]
+				if err := vd.Validate(); err != nil {
+					t.Fatalf("vd.Validate() after removing site %d = %v, want nil", i, err)
+				}
+			}
+			want, err := NewDiagram(slices.Clone(sites))
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			if got, want := len(vd.Vertices), 2*len(sites)-4; got != want {
+				t.Errorf("len(vd.Vertices) = %d, want %d", got, want)
+			}
+			checkSameDiagram(t, vd, want)
+		})
+	}
+}
+
+func TestDiagram_RemoveSite_AddSite(t *testing.T) {
+	// Removing an added site restores the diagram up to the numbering of the vertices.
+	vd := mustNewDiagram(t, 200)
+	want := vd.clone()
+	p := utils.GenerateRandomPoints(1, 1)[0]
+	i, err := vd.AddSite(p)
+	if err != nil {
+		t.Fatalf("vd.AddSite(%v) error = %v, want nil", p, err)
+	}
+	if err := vd.RemoveSite(i); err != nil {
+		t.Fatalf("vd.RemoveSite(%d) error = %v, want nil", i, err)
+	}
+	checkSameDiagram(t, vd, want)
+}
+
+func TestDiagram_RemoveSite_OrderIndependent(t *testing.T) {
+	points := utils.GenerateRandomPoints(100, 0)
+	vd, err := NewDiagram(slices.Clone(points), WithOrderIndependentOutput())
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	if err := vd.RemoveSite(10); err != nil {
+		t.Fatalf("vd.RemoveSite(10) error = %v, want nil", err)
+	}
+	points[10] = points[99]
+	want, err := NewDiagram(points[:99], WithOrderIndependentOutput())
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	if diff := cmp.Diff(want.CellVertices, vd.CellVertices); diff != "" {
+		t.Errorf("vd.CellVertices mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDiagram_RemoveSite_Errors(t *testing.T) {
+	points := utils.GenerateRandomPoints(100, 0)
+	weights := make([]float64, len(points))
+	weights[0] = 0.01
+	power, err := NewPowerDiagram(points, weights)
+	if err != nil {
+		t.Fatalf("NewPowerDiagram(...) error = %v, want nil", err)
+	}
+	// Without the south pole, the remaining sites lie in the northern hemisphere.
+	hemisphere, err := NewDiagram(s2.PointVector{
+		s2.PointFromCoords(0, 0, -1),
+		s2.PointFromCoords(1, 0, 0.5),
+		s2.PointFromCoords(0, 1, 0.5),
+		s2.PointFromCoords(-1, 0, 0.5),
+		s2.PointFromCoords(0, -1.1, 0.5),
+	})
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	// With a coarse eps, every circumcenter of the hole is degenerate.
+	coarse := mustNewDiagram(t, 100)
+	coarse.eps = 1
+	tests := []struct {
+		name    string
+		diagram *Diagram
+	}{
+		{"four sites", mustNewDiagram(t, 4)},
+		{"power diagram", power},
+		{"hemisphere", hemisphere},
+		{"degenerate circumcenters", coarse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := tt.diagram.clone()
+			if err := tt.diagram.RemoveSite(0); err == nil {
+				t.Errorf("tt.diagram.RemoveSite(0) error = nil, want non-nil")
+			}
+			if diff := cmp.Diff(before.Sites, tt.diagram.Sites); diff != "" {
+				t.Errorf("tt.diagram.Sites changed (-before +after):\n%s", diff)
+			}
+			if diff := cmp.Diff(before.CellOffsets, tt.diagram.CellOffsets); diff != "" {
+				t.Errorf("tt.diagram.CellOffsets changed (-before +after):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDiagram_RemoveSite_Panics(t *testing.T) {
+	vd := mustNewDiagram(t, 10)
+	for _, i := range []int{-1, 10} {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("vd.RemoveSite(%d) did not panic", i)
+				}
+			}()
+			_ = vd.RemoveSite(i)
+		})
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDiagram_RemoveSite(b *testing.B) {
+	for _, n := range []int{1e+3, 1e+4} {
+		b.Run(fmt.Sprintf("N%d", n), func(b *testing.B) {
+			vd, err := NewDiagram(utils.GenerateRandomPoints(n, 0))
+			if err != nil {
+				b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			b.ReportAllocs()
+			for b.Loop() {
+				d := *vd
+				if err := d.RemoveSite(n / 2); err != nil {
+					b.Fatalf("d.RemoveSite(...) error = %v, want nil", err)
+				}
+			}
+		})
+	}
+}
+
+// Helpers
+
+// tetrahedron returns the vertices of a regular tetrahedron.
+func tetrahedron() s2.PointVector {
+	return s2.PointVector{
+		s2.PointFromCoords(1, 1, 1),
+		s2.PointFromCoords(1, -1, -1),
+		s2.PointFromCoords(-1, 1, -1),
+		s2.PointFromCoords(-1, -1, 1),
+	}
+}
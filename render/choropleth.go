@@ -0,0 +1,197 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package render
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/2dChan/s2voronoi"
+	svg "github.com/ajstarks/svgo"
+	"github.com/golang/geo/s2"
+)
+
+// Palette is a sequence of colors that a ColorScale interpolates linearly, from the color of
+// the least value to the color of the greatest.
+type Palette []color.Color
+
+// Built-in palettes.
+var (
+	// Viridis is the perceptually uniform palette of matplotlib, from dark blue to yellow.
+	Viridis = Palette{
+		color.RGBA{0x44, 0x01, 0x54, 0xff}, color.RGBA{0x48, 0x28, 0x78, 0xff},
+		color.RGBA{0x3e, 0x49, 0x89, 0xff}, color.RGBA{0x31, 0x68, 0x8e, 0xff},
+		color.RGBA{0x26, 0x82, 0x8e, 0xff}, color.RGBA{0x1f, 0x9e, 0x89, 0xff},
+		color.RGBA{0x35, 0xb7, 0x79, 0xff}, color.RGBA{0x6e, 0xce, 0x58, 0xff},
+		color.RGBA{0xb5, 0xde, 0x2b, 0xff}, color.RGBA{0xfd, 0xe7, 0x25, 0xff},
+	}
+	// Grays runs from black to white.
+	Grays = Palette{color.Black, color.White}
+	// BlueRed is a diverging palette from blue through white to red, for values around a
+	// midpoint such as temperature anomalies.
+	BlueRed = Palette{
+		color.RGBA{0x21, 0x66, 0xac, 0xff}, color.RGBA{0x67, 0xa9, 0xcf, 0xff},
+		color.RGBA{0xd1, 0xe5, 0xf0, 0xff}, color.RGBA{0xf7, 0xf7, 0xf7, 0xff},
+		color.RGBA{0xfd, 0xdb, 0xc7, 0xff}, color.RGBA{0xef, 0x8a, 0x62, 0xff},
+		color.RGBA{0xb2, 0x18, 0x2b, 0xff},
+	}
+)
+
+// ColorScale maps values to the colors of a palette. Values are clamped to [Min, Max], which
+// map to the first and last color of the palette, linearly or, if Log is set, logarithmically.
+// If Min and Max are both zero, Choropleth uses the least and greatest of its values, positive
+// ones only if Log is set.
+type ColorScale struct {
+	Palette  Palette
+	Min, Max float64
+	Log      bool
+}
+
+// Color returns the color of v. Values outside [Min, Max], and values that are not positive on
+// a logarithmic scale, take the color of the nearest bound; all values take the first color if
+// Min is not less than Max. An empty palette has only color.Transparent.
+func (s ColorScale) Color(v float64) color.Color {
+	switch len(s.Palette) {
+	case 0:
+		return color.Transparent
+	case 1:
+		return s.Palette[0]
+	}
+	t := 0.0
+	if s.Max > s.Min {
+		v = min(max(v, s.Min), s.Max)
+		if s.Log {
+			t = math.Log(v/s.Min) / math.Log(s.Max/s.Min)
+		} else {
+			t = (v - s.Min) / (s.Max - s.Min)
+		}
+	}
+	pos := t * float64(len(s.Palette)-1)
+	k := min(int(pos), len(s.Palette)-2)
+	f := pos - float64(k)
+	a := color.NRGBA64Model.Convert(s.Palette[k]).(color.NRGBA64)
+	b := color.NRGBA64Model.Convert(s.Palette[k+1]).(color.NRGBA64)
+	lerp := func(x, y uint16) uint16 {
+		return uint16(math.Round(float64(x) + f*(float64(y)-float64(x))))
+	}
+	return color.NRGBA64{R: lerp(a.R, b.R), G: lerp(a.G, b.G), B: lerp(a.B, b.B),
+		A: lerp(a.A, b.A)}
+}
+
+// Choropleth writes the cells of the diagram to w as an SVG map like DiagramSVG, with each cell
+// filled with the color of its value in the scale. The sites are not drawn unless set with
+// WithSites, and WithLegend adds a legend of the scale; WithFill has no effect.
+// It returns an error if the number of values is not the number of cells, if a value is not
+// finite, if the scale has fewer than two colors, if its bounds are not finite, Min is greater
+// than Max or, on a logarithmic scale, Min is not positive, if an option is invalid or if
+// writing fails.
+func Choropleth(w io.Writer, vd *s2voronoi.Diagram, values []float64, scale ColorScale,
+	setters ...Option) error {
+	if len(values) != vd.NumCells() {
+		return fmt.Errorf("render: got %d values for %d cells", len(values), vd.NumCells())
+	}
+	for i, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("render: value %d is not finite: %v", i, v)
+		}
+	}
+	if len(scale.Palette) < 2 {
+		return fmt.Errorf("render: palette must have at least 2 colors, got %d",
+			len(scale.Palette))
+	}
+	if scale.Min == 0 && scale.Max == 0 {
+		scale.Min, scale.Max = valueRange(values, scale.Log)
+	}
+	switch {
+	case math.IsNaN(scale.Min) || math.IsNaN(scale.Max) || math.IsInf(scale.Min, 0) ||
+		math.IsInf(scale.Max, 0):
+		return fmt.Errorf("render: scale bounds must be finite, got [%v, %v]", scale.Min,
+			scale.Max)
+	case scale.Min > scale.Max:
+		return fmt.Errorf("render: scale min %v is greater than max %v", scale.Min, scale.Max)
+	case scale.Log && !(scale.Min > 0):
+		return errors.New("render: logarithmic scale needs a positive min")
+	}
+
+	polygons := make([]s2.PointVector, 0, vd.NumCells())
+	fills := make([]string, 0, vd.NumCells())
+	for i, c := range vd.Cells() {
+		if c.IsEmpty() {
+			continue
+		}
+		ring := make(s2.PointVector, 0, c.NumVertices())
+		for _, v := range c.VertexPoints() {
+			ring = append(ring, v)
+		}
+		polygons = append(polygons, ring)
+		fills = append(fills, fillStyle(scale.Color(values[i])))
+	}
+	setters = append([]Option{WithSites("rgb(255,0,0)", 0)}, setters...)
+	return render(w, polygons, fills, vd.Sites, &scale, setters)
+}
+
+// valueRange returns the least and greatest of the values, positive ones only if positive is
+// set, or 1 and 1 if there are none.
+func valueRange(values []float64, positive bool) (lo, hi float64) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if !positive || v > 0 {
+			lo, hi = min(lo, v), max(hi, v)
+		}
+	}
+	if lo > hi {
+		return 1, 1
+	}
+	return lo, hi
+}
+
+// legendHeight is the height in pixels of the band below the map with the legend.
+const legendHeight = 60
+
+// drawLegend draws the legend of the scale in the band below a map of the width and height: a
+// bar with the gradient of the palette between labels of the bounds, under the title.
+func drawLegend(canvas *svg.SVG, scale ColorScale, title string, width, height int) {
+	stops := make([]svg.Offcolor, len(scale.Palette))
+	for k, c := range scale.Palette {
+		n := color.NRGBAModel.Convert(c).(color.NRGBA)
+		stops[k] = svg.Offcolor{
+			Offset:  uint8(math.Round(100 * float64(k) / float64(len(scale.Palette)-1))),
+			Color:   fmt.Sprintf("rgb(%d,%d,%d)", n.R, n.G, n.B),
+			Opacity: float64(n.A) / 0xff,
+		}
+	}
+	x, w := width/4, width/2
+	canvas.Gid("legend")
+	canvas.Def()
+	canvas.LinearGradient("legend-gradient", 0, 0, 100, 0, stops)
+	canvas.DefEnd()
+	text := "font-family:sans-serif;font-size:12px;fill:rgb(0,0,0)"
+	if title != "" {
+		canvas.Text(width/2, height+16, title, text+";text-anchor:middle")
+	}
+	canvas.Rect(x, height+24, w, 14, "fill:url(#legend-gradient)")
+	canvas.Text(x, height+54, formatValue(scale.Min), text+";text-anchor:start")
+	canvas.Text(x+w, height+54, formatValue(scale.Max), text+";text-anchor:end")
+	canvas.Gend()
+}
+
+// fillStyle returns the SVG style of the fill of c.
+func fillStyle(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	style := fmt.Sprintf("fill:rgb(%d,%d,%d)", n.R, n.G, n.B)
+	if n.A != 0xff {
+		style += ";fill-opacity:" + strconv.FormatFloat(float64(n.A)/0xff, 'f', 3, 64)
+	}
+	return style
+}
+
+// formatValue returns v formatted for a legend.
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
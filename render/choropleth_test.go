@@ -0,0 +1,168 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package render
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/xml"
+	"image/color"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/2dChan/s2voronoi"
+	"github.com/2dChan/s2voronoi/utils"
+)
+
+func TestColorScale_Color(t *testing.T) {
+	gray := func(y uint16) color.NRGBA64 {
+		return color.NRGBA64{R: y, G: y, B: y, A: 0xffff}
+	}
+	tests := []struct {
+		name  string
+		scale ColorScale
+		v     float64
+		want  color.Color
+	}{
+		{"min", ColorScale{Grays, 0, 10, false}, 0, gray(0)},
+		{"max", ColorScale{Grays, 0, 10, false}, 10, gray(0xffff)},
+		{"linear", ColorScale{Grays, 0, 10, false}, 2.5, gray(0x4000)},
+		{"below min", ColorScale{Grays, 0, 10, false}, -5, gray(0)},
+		{"above max", ColorScale{Grays, 0, 10, false}, 50, gray(0xffff)},
+		{"log", ColorScale{Grays, 1, 100, true}, 10, gray(0x8000)},
+		{"log not positive", ColorScale{Grays, 1, 100, true}, -1, gray(0)},
+		{"empty domain", ColorScale{Grays, 3, 3, false}, 3, gray(0)},
+		{"stops", ColorScale{Palette{color.Black, color.White, color.Black}, 0, 1, false}, 0.75,
+			gray(0x8000)},
+		{"single color", ColorScale{Palette{color.White}, 0, 1, false}, 0.5, color.White},
+		{"empty palette", ColorScale{nil, 0, 1, false}, 0.5, color.Transparent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.scale.Color(tt.v)
+			r, g, b, a := got.RGBA()
+			wr, wg, wb, wa := tt.want.RGBA()
+			if absDiff(r, wr) > 1 || absDiff(g, wg) > 1 || absDiff(b, wb) > 1 || a != wa {
+				t.Errorf("Color(%v) = %v, want %v", tt.v, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChoropleth(t *testing.T) {
+	const numSites = 50
+	vd, err := s2voronoi.NewDiagram(utils.GenerateRandomPoints(numSites, 0))
+	if err != nil {
+		t.Fatalf("s2voronoi.NewDiagram(...) error = %v, want nil", err)
+	}
+	// A gradient field from the south pole to the north pole, with the sites ranked by height
+	// so that the values are evenly spaced.
+	order := make([]int, vd.NumCells())
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(i, j int) int {
+		return cmp.Compare(vd.Sites[i].Z, vd.Sites[j].Z)
+	})
+	values := make([]float64, vd.NumCells())
+	for rank, i := range order {
+		values[i] = float64(rank)
+	}
+	var buf bytes.Buffer
+	err = Choropleth(&buf, vd, values, ColorScale{Palette: Viridis}, WithWidth(720),
+		WithLegend("height"))
+	if err != nil {
+		t.Fatalf("Choropleth(...) error = %v, want nil", err)
+	}
+
+	var doc struct {
+		Height int `xml:"height,attr"`
+		Groups []struct {
+			ID    string `xml:"id,attr"`
+			Paths []struct {
+				Style string `xml:"style,attr"`
+			} `xml:"path"`
+			Texts []string `xml:"text"`
+		} `xml:"g"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("xml.Unmarshal(svg) error = %v, want nil", err)
+	}
+	if doc.Height != 360+legendHeight {
+		t.Errorf("svg height = %d, want %d", doc.Height, 360+legendHeight)
+	}
+	groups := make(map[string]int)
+	for i, g := range doc.Groups {
+		groups[g.ID] = i
+	}
+	if _, ok := groups["sites"]; ok {
+		t.Errorf("svg has sites, want none by default")
+	}
+	cells := doc.Groups[groups["cells"]]
+	fills := make(map[string]bool)
+	for _, p := range cells.Paths {
+		fills[p.Style] = true
+	}
+	if len(cells.Paths) != numSites || len(fills) != numSites {
+		t.Errorf("svg has %d cells with %d distinct fills, want %d and %d", len(cells.Paths),
+			len(fills), numSites, numSites)
+	}
+	lo, hi := valueRange(values, false)
+	legend, ok := groups["legend"]
+	if !ok {
+		t.Fatalf("svg has no legend")
+	}
+	want := []string{"height", formatValue(lo), formatValue(hi)}
+	if texts := doc.Groups[legend].Texts; strings.Join(texts, ",") != strings.Join(want, ",") {
+		t.Errorf("legend texts = %q, want %q", texts, want)
+	}
+	if !strings.Contains(buf.String(), "<linearGradient") {
+		t.Errorf("legend has no gradient")
+	}
+}
+
+func TestChoropleth_Errors(t *testing.T) {
+	vd, err := s2voronoi.NewDiagram(utils.GenerateRandomPoints(10, 0))
+	if err != nil {
+		t.Fatalf("s2voronoi.NewDiagram(...) error = %v, want nil", err)
+	}
+	values := make([]float64, vd.NumCells())
+	nan := make([]float64, vd.NumCells())
+	nan[3] = math.NaN()
+	tests := []struct {
+		name    string
+		values  []float64
+		scale   ColorScale
+		setters []Option
+	}{
+		{"values", values[1:], ColorScale{Palette: Viridis}, nil},
+		{"nan value", nan, ColorScale{Palette: Viridis}, nil},
+		{"palette", values, ColorScale{Palette: Palette{color.Black}}, nil},
+		{"infinite bound", values, ColorScale{Viridis, 0, math.Inf(1), false}, nil},
+		{"min above max", values, ColorScale{Viridis, 2, 1, false}, nil},
+		{"log min", values, ColorScale{Viridis, 0, 1, true}, nil},
+		{"option", values, ColorScale{Palette: Viridis}, []Option{WithWidth(0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Choropleth(io.Discard, vd, tt.values, tt.scale, tt.setters...); err == nil {
+				t.Errorf("Choropleth(..., %s) error = nil, want error", tt.name)
+			}
+		})
+	}
+}
+
+// Helpers
+
+// absDiff returns the absolute difference of a and b.
+func absDiff(a, b uint32) uint32 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
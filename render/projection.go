@@ -0,0 +1,180 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package render
+
+import (
+	"math"
+
+	"github.com/golang/geo/r2"
+	"github.com/golang/geo/r3"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// Projection maps points on the sphere to a plane.
+type Projection interface {
+	// Project returns the position of p on the plane, x to the right and y upwards, and whether
+	// p is visible. The position must be returned for invisible points as well. The visible
+	// points must be the whole sphere or a hemisphere; polygons and edges are clipped to them.
+	Project(p s2.Point) (x, y float64, visible bool)
+	// Bounds returns the rectangle of the plane that the map shows.
+	Bounds() r2.Rect
+}
+
+// Unprojector is implemented by projections that can be inverted, as DiagramImage requires.
+type Unprojector interface {
+	// Unproject returns the visible point at the position x, y on the plane, and false if no
+	// point is there.
+	Unproject(x, y float64) (p s2.Point, ok bool)
+}
+
+// InvertibleProjection is a Projection that implements Unprojector.
+type InvertibleProjection interface {
+	Projection
+	Unprojector
+}
+
+// Outliner is implemented by projections that draw an outline around their map.
+type Outliner interface {
+	// Outline returns the outline of the map on the plane as a ring of positions.
+	Outline() []r2.Point
+}
+
+// numOutline is the number of positions of curved outlines.
+const numOutline = 360
+
+// PlateCarree is the equirectangular projection with longitude and latitude in radians as x
+// and y, interrupted at the antimeridian.
+type PlateCarree struct{}
+
+// Project implements Projection.
+func (PlateCarree) Project(p s2.Point) (x, y float64, visible bool) {
+	ll := s2.LatLngFromPoint(p)
+	return ll.Lng.Radians(), ll.Lat.Radians(), true
+}
+
+// Bounds implements Projection.
+func (PlateCarree) Bounds() r2.Rect {
+	return r2.RectFromPoints(r2.Point{X: -math.Pi, Y: -math.Pi / 2},
+		r2.Point{X: math.Pi, Y: math.Pi / 2})
+}
+
+// Unproject implements Unprojector.
+func (PlateCarree) Unproject(x, y float64) (s2.Point, bool) {
+	if math.Abs(x) > math.Pi || math.Abs(y) > math.Pi/2 {
+		return s2.Point{}, false
+	}
+	return s2.PointFromLatLng(s2.LatLng{Lat: s1.Angle(y), Lng: s1.Angle(x)}), true
+}
+
+// Outline implements Outliner.
+func (p PlateCarree) Outline() []r2.Point {
+	v := p.Bounds().Vertices()
+	return v[:]
+}
+
+// Mollweide is the equal-area pseudocylindrical projection of the sphere onto an ellipse twice
+// as wide as high, interrupted at the antimeridian.
+type Mollweide struct{}
+
+// Project implements Projection.
+func (Mollweide) Project(p s2.Point) (x, y float64, visible bool) {
+	ll := s2.LatLngFromPoint(p)
+	lat := ll.Lat.Radians()
+	// Solve 2θ + sin 2θ = π sin lat for the auxiliary angle θ by Newton's method, which
+	// converges slowly near the poles, where θ = lat.
+	theta := lat
+	if math.Abs(lat) < math.Pi/2-1e-9 {
+		target := math.Pi * math.Sin(lat)
+		for range 50 {
+			delta := (2*theta + math.Sin(2*theta) - target) / (2 + 2*math.Cos(2*theta))
+			theta -= delta
+			if math.Abs(delta) < 1e-15 {
+				break
+			}
+		}
+	}
+	return 2 * math.Sqrt2 / math.Pi * ll.Lng.Radians() * math.Cos(theta),
+		math.Sqrt2 * math.Sin(theta), true
+}
+
+// Bounds implements Projection.
+func (Mollweide) Bounds() r2.Rect {
+	return r2.RectFromPoints(r2.Point{X: -2 * math.Sqrt2, Y: -math.Sqrt2},
+		r2.Point{X: 2 * math.Sqrt2, Y: math.Sqrt2})
+}
+
+// Unproject implements Unprojector.
+func (Mollweide) Unproject(x, y float64) (s2.Point, bool) {
+	if math.Abs(y) > math.Sqrt2 {
+		return s2.Point{}, false
+	}
+	theta := math.Asin(y / math.Sqrt2)
+	lng := 0.0
+	if c := math.Cos(theta); c > 0 {
+		lng = math.Pi * x / (2 * math.Sqrt2 * c)
+	}
+	if math.Abs(lng) > math.Pi {
+		return s2.Point{}, false
+	}
+	lat := math.Asin(min(max((2*theta+math.Sin(2*theta))/math.Pi, -1), 1))
+	return s2.PointFromLatLng(s2.LatLng{Lat: s1.Angle(lat), Lng: s1.Angle(lng)}), true
+}
+
+// Outline implements Outliner.
+func (Mollweide) Outline() []r2.Point {
+	return ellipse(2*math.Sqrt2, math.Sqrt2)
+}
+
+// Orthographic is the perspective projection of the hemisphere around Center as seen from
+// infinitely far away, with north upwards. The other hemisphere is not visible.
+type Orthographic struct {
+	Center s2.LatLng
+}
+
+// Project implements Projection.
+func (o Orthographic) Project(p s2.Point) (x, y float64, visible bool) {
+	c, east, north := o.frame()
+	return p.Dot(east), p.Dot(north), p.Dot(c) >= 0
+}
+
+// Unproject implements Unprojector.
+func (o Orthographic) Unproject(x, y float64) (s2.Point, bool) {
+	rho2 := x*x + y*y
+	if rho2 > 1 {
+		return s2.Point{}, false
+	}
+	c, east, north := o.frame()
+	v := east.Mul(x).Add(north.Mul(y)).Add(c.Mul(math.Sqrt(1 - rho2)))
+	return s2.Point{Vector: v.Normalize()}, true
+}
+
+// frame returns the unit vectors towards the center, east and north of the view.
+func (o Orthographic) frame() (c, east, north r3.Vector) {
+	c = s2.PointFromLatLng(o.Center).Vector
+	lng := o.Center.Lng.Radians()
+	east = r3.Vector{X: -math.Sin(lng), Y: math.Cos(lng), Z: 0}
+	return c, east, c.Cross(east)
+}
+
+// Bounds implements Projection.
+func (Orthographic) Bounds() r2.Rect {
+	return r2.RectFromPoints(r2.Point{X: -1, Y: -1}, r2.Point{X: 1, Y: 1})
+}
+
+// Outline implements Outliner.
+func (Orthographic) Outline() []r2.Point {
+	return ellipse(1, 1)
+}
+
+// ellipse returns numOutline positions on the ellipse around the origin with the semi-axes.
+func ellipse(a, b float64) []r2.Point {
+	out := make([]r2.Point, numOutline)
+	for k := range out {
+		angle := 2 * math.Pi * float64(k) / numOutline
+		out[k] = r2.Point{X: a * math.Cos(angle), Y: b * math.Sin(angle)}
+	}
+	return out
+}
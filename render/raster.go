@@ -0,0 +1,75 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package render
+
+import (
+	"image"
+	"image/color"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/2dChan/s2voronoi"
+	"github.com/golang/geo/s2"
+)
+
+// rasterChunk is the number of scanlines that DiagramImage hands to a goroutine at a time.
+const rasterChunk = 16
+
+// DiagramImage returns an image of the diagram of the given size in pixels, with the bounds of
+// the projection stretched over it. Every pixel is unprojected at its center and takes the
+// color of the cell containing that point, as found by AssignPoints, so the image is exact at
+// pixel level for any number of cells; pixels without a point, such as the corners of a
+// Mollweide map, are transparent. colorFn is called once per non-empty cell, in order. The
+// scanlines are processed in chunks by runtime.GOMAXPROCS(0) goroutines. A width or height less
+// than 1 gives an empty image.
+func DiagramImage(vd *s2voronoi.Diagram, width, height int, proj InvertibleProjection,
+	colorFn func(site int) color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, max(width, 0), max(height, 0)))
+	if width < 1 || height < 1 {
+		return img
+	}
+	colors := make([]color.RGBA, vd.NumCells())
+	for i, c := range vd.Cells() {
+		if !c.IsEmpty() {
+			colors[i] = color.RGBAModel.Convert(colorFn(i)).(color.RGBA)
+		}
+	}
+
+	b := proj.Bounds()
+	sx, sy := b.X.Length()/float64(width), b.Y.Length()/float64(height)
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for range runtime.GOMAXPROCS(0) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			points := make([]s2.Point, 0, width)
+			pixels := make([]int, 0, width)
+			for {
+				start := int(next.Add(rasterChunk)) - rasterChunk
+				if start >= height {
+					return
+				}
+				for y := start; y < min(start+rasterChunk, height); y++ {
+					points, pixels = points[:0], pixels[:0]
+					py := b.Y.Hi - (float64(y)+0.5)*sy
+					for x := range width {
+						p, ok := proj.Unproject(b.X.Lo+(float64(x)+0.5)*sx, py)
+						if ok {
+							points = append(points, p)
+							pixels = append(pixels, x)
+						}
+					}
+					for k, i := range vd.AssignPoints(points) {
+						img.SetRGBA(pixels[k], y, colors[i])
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return img
+}
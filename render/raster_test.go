@@ -0,0 +1,137 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package render
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/2dChan/s2voronoi"
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+func TestDiagramImage(t *testing.T) {
+	vd, err := s2voronoi.NewDiagram(utils.GenerateRandomPoints(200, 0))
+	if err != nil {
+		t.Fatalf("s2voronoi.NewDiagram(...) error = %v, want nil", err)
+	}
+	for _, proj := range testProjections {
+		t.Run(proj.name, func(t *testing.T) {
+			b := proj.p.Bounds()
+			width := 720
+			height := int(math.Round(float64(width) * b.Y.Length() / b.X.Length()))
+			img := DiagramImage(vd, width, height, proj.p.(InvertibleProjection), siteColor)
+			if got := img.Bounds().Size(); got.X != width || got.Y != height {
+				t.Fatalf("DiagramImage(...) size = %v, want %dx%d", got, width, height)
+			}
+			for i, p := range vd.Sites {
+				// The center of the pixel of a site is within a pixel of it, so the pixel lies in
+				// the cell unless another site is about as near or the pixel is stretched by
+				// the projection near a pole.
+				x, y, visible := proj.p.Project(p)
+				if !visible || math.Abs(s2.LatLngFromPoint(p).Lat.Degrees()) > 60 ||
+					nearestNeighbor(vd, i) < 2*s1.Degree {
+					continue
+				}
+				px := int((x - b.X.Lo) / b.X.Length() * float64(width))
+				py := int((b.Y.Hi - y) / b.Y.Length() * float64(height))
+				if got, want := img.RGBAAt(px, py), siteColor(i); got != want {
+					t.Errorf("DiagramImage(...) pixel %d,%d of site %d = %v, want %v", px, py, i,
+						got, want)
+				}
+			}
+			// The pixels outside the visible part of the sphere are transparent, and the others
+			// cover it.
+			covered := 0
+			for y := range height {
+				for x := range width {
+					if img.RGBAAt(x, y).A != 0 {
+						covered++
+					}
+				}
+			}
+			if want := proj.area; math.Abs(float64(covered)-want) > 5e-3*want {
+				t.Errorf("DiagramImage(...) covers %d pixels, want %v", covered, want)
+			}
+		})
+	}
+}
+
+func TestDiagramImage_Empty(t *testing.T) {
+	vd, err := s2voronoi.NewDiagram(utils.GenerateRandomPoints(10, 0))
+	if err != nil {
+		t.Fatalf("s2voronoi.NewDiagram(...) error = %v, want nil", err)
+	}
+	if img := DiagramImage(vd, 0, -1, PlateCarree{}, siteColor); !img.Bounds().Empty() {
+		t.Errorf("DiagramImage(..., 0, -1, ...) bounds = %v, want empty", img.Bounds())
+	}
+}
+
+func TestProjection_Unproject(t *testing.T) {
+	points := utils.GenerateRandomPoints(1000, 0)
+	for _, proj := range testProjections {
+		t.Run(proj.name, func(t *testing.T) {
+			for _, p := range points {
+				x, y, visible := proj.p.Project(p)
+				if !visible {
+					continue
+				}
+				got, ok := proj.p.(Unprojector).Unproject(x, y)
+				if !ok || got.Distance(p) > 1e-9 {
+					t.Errorf("Unproject(Project(%v)) = %v, %v, want %v, true", p, got, ok, p)
+				}
+			}
+		})
+	}
+	tests := []struct {
+		name string
+		p    Unprojector
+		x, y float64
+	}{
+		{"plate carree", PlateCarree{}, 4, 0},
+		{"mollweide corner", Mollweide{}, 2.8, 1.4},
+		{"mollweide above", Mollweide{}, 0, 1.5},
+		{"orthographic corner", Orthographic{}, 0.8, 0.8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if p, ok := tt.p.Unproject(tt.x, tt.y); ok {
+				t.Errorf("Unproject(%v, %v) = %v, true, want false", tt.x, tt.y, p)
+			}
+		})
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDiagramImage(b *testing.B) {
+	vd, err := s2voronoi.NewDiagram(utils.GenerateRandomPoints(100000, 0))
+	if err != nil {
+		b.Fatalf("s2voronoi.NewDiagram(...) error = %v, want nil", err)
+	}
+	for b.Loop() {
+		DiagramImage(vd, 2048, 1024, PlateCarree{}, siteColor)
+	}
+}
+
+// Helpers
+
+// nearestNeighbor returns the distance from site i to the nearest other site, which is one of
+// its neighbors.
+func nearestNeighbor(vd *s2voronoi.Diagram, i int) s1.Angle {
+	nearest := s1.InfAngle()
+	for _, j := range vd.Cell(i).NeighborIndices() {
+		nearest = min(nearest, vd.Sites[i].Distance(vd.Sites[j]))
+	}
+	return nearest
+}
+
+// siteColor returns an opaque color encoding the index of the site.
+func siteColor(site int) color.Color {
+	return color.RGBA{R: uint8(site), G: uint8(site >> 8), B: uint8(site >> 16), A: 0xff}
+}
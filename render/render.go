@@ -0,0 +1,537 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+// Package render draws Voronoi diagrams and Delaunay triangulations on the S2 sphere as SVG maps
+// in a map projection.
+
+package render
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/2dChan/s2voronoi"
+	"github.com/2dChan/s2voronoi/internal/lnglat"
+	"github.com/2dChan/s2voronoi/s2delaunay"
+	svg "github.com/ajstarks/svgo"
+	"github.com/golang/geo/r2"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// Options holds configuration options for DiagramSVG and TriangulationSVG.
+type Options struct {
+	// Width is the width of the map in pixels; its height follows from the bounds of the
+	// projection.
+	Width       int
+	Projection  Projection
+	Background  string
+	Fill        string
+	Stroke      string
+	StrokeWidth float64
+	SiteFill    string
+	SiteRadius  int
+	MaxSegment  s1.Angle
+
+	OutlineStroke   string
+	OutlineWidth    float64
+	GraticuleStroke string
+	GraticuleWidth  float64
+	GraticuleStep   s1.Angle
+
+	Legend      bool
+	LegendTitle string
+}
+
+// Option is a functional option type for rendering configuration.
+type Option func(*Options) error
+
+// WithWidth sets the width of the map in pixels, 1500 by default. It must be at least 2.
+func WithWidth(width int) Option {
+	return func(o *Options) error {
+		if width < 2 {
+			return fmt.Errorf("render: width must be at least 2, got %d", width)
+		}
+		o.Width = width
+		return nil
+	}
+}
+
+// WithProjection sets the projection of the map, PlateCarree by default. The map shows the
+// bounds of the projection scaled to the width, with the height rounded to whole pixels.
+func WithProjection(p Projection) Option {
+	return func(o *Options) error {
+		if p == nil {
+			return errors.New("render: projection must not be nil")
+		}
+		b := p.Bounds()
+		if !(b.X.Length() > 0) || !(b.Y.Length() > 0) || math.IsInf(b.X.Length(), 1) ||
+			math.IsInf(b.Y.Length(), 1) {
+			return fmt.Errorf("render: projection bounds must be finite and not empty, got %v", b)
+		}
+		o.Projection = p
+		return nil
+	}
+}
+
+// WithBackground sets the SVG paint of the background, white by default, or omits the
+// background if it is empty.
+func WithBackground(fill string) Option {
+	return func(o *Options) error {
+		o.Background = fill
+		return nil
+	}
+}
+
+// WithFill sets the SVG paint of the polygons, white by default, e.g. "none" or "rgb(0,0,255)".
+func WithFill(fill string) Option {
+	return func(o *Options) error {
+		o.Fill = fill
+		return nil
+	}
+}
+
+// WithStroke sets the SVG paint and width in pixels of the edges of the polygons, gray of
+// width 1 by default. The width must not be negative and finite; 0 omits the edges.
+func WithStroke(stroke string, width float64) Option {
+	return func(o *Options) error {
+		if !(width >= 0) || math.IsInf(width, 1) {
+			return fmt.Errorf("render: stroke width must not be negative and finite, got %v",
+				width)
+		}
+		o.Stroke, o.StrokeWidth = stroke, width
+		return nil
+	}
+}
+
+// WithSites sets the SVG paint and radius in pixels of the dots drawn at the sites of a diagram
+// or the vertices of a triangulation, red of radius 3 by default. The radius must not be
+// negative; 0 omits the dots.
+func WithSites(fill string, radius int) Option {
+	return func(o *Options) error {
+		if radius < 0 {
+			return fmt.Errorf("render: site radius must not be negative, got %d", radius)
+		}
+		o.SiteFill, o.SiteRadius = fill, radius
+		return nil
+	}
+}
+
+// WithMaxSegment sets the maximum length of the segments that edges, which are great circle
+// arcs, are subdivided into before projection, 1° by default, so that long edges are drawn
+// curved. It must not be negative; 0 draws every edge as a single straight segment on the map,
+// which collapses polygons along a line of latitude in PlateCarree.
+func WithMaxSegment(maxSegment s1.Angle) Option {
+	return func(o *Options) error {
+		if !(maxSegment >= 0) {
+			return fmt.Errorf("render: max segment must not be negative, got %v", maxSegment)
+		}
+		o.MaxSegment = maxSegment
+		return nil
+	}
+}
+
+// WithOutline sets the SVG paint and width in pixels of the outline of the map, drawn for
+// projections that implement Outliner, gray of width 1 by default. The width must not be
+// negative and finite; 0 omits the outline.
+func WithOutline(stroke string, width float64) Option {
+	return func(o *Options) error {
+		if !(width >= 0) || math.IsInf(width, 1) {
+			return fmt.Errorf("render: outline width must not be negative and finite, got %v",
+				width)
+		}
+		o.OutlineStroke, o.OutlineWidth = stroke, width
+		return nil
+	}
+}
+
+// WithGraticule draws meridians and parallels every step, starting at the antimeridian and the
+// equator, with the SVG paint and width in pixels, over the polygons and under their edges. The
+// step must be positive and at most 90° and the width positive and finite. There is no
+// graticule by default.
+func WithGraticule(stroke string, width float64, step s1.Angle) Option {
+	return func(o *Options) error {
+		if !(step > 0) || step > 90*s1.Degree {
+			return fmt.Errorf("render: graticule step must be in (0°, 90°], got %v", step)
+		}
+		if !(width > 0) || math.IsInf(width, 1) {
+			return fmt.Errorf("render: graticule width must be positive and finite, got %v",
+				width)
+		}
+		o.GraticuleStroke, o.GraticuleWidth, o.GraticuleStep = stroke, width, step
+		return nil
+	}
+}
+
+// WithLegend adds a band with a legend of the color scale, titled title if it is not empty,
+// below the map of Choropleth. The other renderers have no legend.
+func WithLegend(title string) Option {
+	return func(o *Options) error {
+		o.Legend, o.LegendTitle = true, title
+		return nil
+	}
+}
+
+// DiagramSVG writes the cells of the diagram to w as an SVG map: a group "cells" with one
+// filled path per non-empty cell with a visible part in site order, a group "edges" with a
+// path of the edges, a group "outline" with the outline of the map and a group "sites" with a
+// dot per visible site, and a group "graticule" after the cells if set. Cells crossing the
+// antimeridian are cut along it into pieces at both edges of the map, cells containing a pole
+// are bounded by the pole's line of latitude, and cells crossing the boundary of the visible
+// hemisphere are clipped to it, so that the cells cover the map without holes.
+// It returns an error if an option is invalid or if writing fails.
+func DiagramSVG(w io.Writer, vd *s2voronoi.Diagram, setters ...Option) error {
+	polygons := make([]s2.PointVector, 0, vd.NumCells())
+	for _, c := range vd.Cells() {
+		if c.IsEmpty() {
+			continue
+		}
+		ring := make(s2.PointVector, 0, c.NumVertices())
+		for _, v := range c.VertexPoints() {
+			ring = append(ring, v)
+		}
+		polygons = append(polygons, ring)
+	}
+	return render(w, polygons, nil, vd.Sites, nil, setters)
+}
+
+// TriangulationSVG writes the triangles of the triangulation to w as an SVG map like
+// DiagramSVG, one path per triangle with a visible part in order followed by the edges and a
+// dot per visible vertex.
+// It returns an error if an option is invalid or if writing fails.
+func TriangulationSVG(w io.Writer, dt *s2delaunay.Triangulation, setters ...Option) error {
+	// Triangles are CCW when looking at the sphere from outside, the opposite of cells.
+	polygons := make([]s2.PointVector, len(dt.Triangles))
+	for i, tri := range dt.Triangles {
+		polygons[i] = s2.PointVector{dt.Vertices[tri[0]], dt.Vertices[tri[2]],
+			dt.Vertices[tri[1]]}
+	}
+	return render(w, polygons, nil, dt.Vertices, nil, setters)
+}
+
+// render writes the map of the rings, CCW when looking out of the sphere, and the dots to w,
+// with the rings filled with fills if it is not nil and a legend of the scale if it is not nil
+// and set. Every edge of the rings must occur reversed in another ring, as in a tiling of the
+// sphere, and is drawn once.
+func render(w io.Writer, rings []s2.PointVector, fills []string, dots s2.PointVector,
+	scale *ColorScale, setters []Option) error {
+	opts := &Options{
+		Width:       1500,
+		Projection:  PlateCarree{},
+		Background:  "rgb(255,255,255)",
+		Fill:        "rgb(255,255,255)",
+		Stroke:      "rgb(170,170,170)",
+		StrokeWidth: 1,
+		SiteFill:    "rgb(255,0,0)",
+		SiteRadius:  3,
+		MaxSegment:  s1.Degree,
+
+		OutlineStroke: "rgb(170,170,170)",
+		OutlineWidth:  1,
+	}
+	for _, set := range setters {
+		if err := set(opts); err != nil {
+			return err
+		}
+	}
+	m := newMapper(opts)
+	height := m.height
+	if scale != nil && opts.Legend {
+		height += legendHeight
+	}
+
+	ew := &errWriter{w: w}
+	canvas := svg.New(ew)
+	canvas.Start(m.width, height)
+	if opts.Background != "" {
+		canvas.Rect(0, 0, m.width, height, "fill:"+opts.Background)
+	}
+
+	canvas.Gid("cells")
+	var d []byte
+	for i, ring := range rings {
+		fill := "fill:" + opts.Fill
+		if fills != nil {
+			fill = fills[i]
+		}
+		d = d[:0]
+		for _, polygon := range lnglat.Polygons(m.clipRing(ring), opts.MaxSegment) {
+			d = m.appendLine(d, polygon[:len(polygon)-1], true)
+			d = append(d, 'Z')
+		}
+		if len(d) > 0 {
+			canvas.Path(string(d), fill)
+		}
+	}
+	canvas.Gend()
+
+	if opts.GraticuleStep > 0 {
+		canvas.Gid("graticule")
+		canvas.Path(string(m.appendGraticule(d[:0], opts.GraticuleStep)),
+			strokeStyle(opts.GraticuleStroke, opts.GraticuleWidth))
+		canvas.Gend()
+	}
+
+	if opts.StrokeWidth > 0 {
+		canvas.Gid("edges")
+		d = d[:0]
+		for _, ring := range rings {
+			for k, a := range ring {
+				b := ring[(k+1)%len(ring)]
+				if !lessPoint(a, b) {
+					continue
+				}
+				for _, line := range lnglat.Lines(m.clipEdge(a, b), opts.MaxSegment) {
+					d = m.appendLine(d, line, false)
+				}
+			}
+		}
+		if len(d) > 0 {
+			canvas.Path(string(d), strokeStyle(opts.Stroke, opts.StrokeWidth))
+		}
+		canvas.Gend()
+	}
+
+	if o, ok := opts.Projection.(Outliner); ok && opts.OutlineWidth > 0 {
+		canvas.Gid("outline")
+		d = d[:0]
+		for k, q := range o.Outline() {
+			d = m.appendXY(d, q.X, q.Y, k == 0)
+		}
+		canvas.Path(string(append(d, 'Z')), strokeStyle(opts.OutlineStroke, opts.OutlineWidth))
+		canvas.Gend()
+	}
+
+	if opts.SiteRadius > 0 {
+		canvas.Gid("sites")
+		for _, p := range dots {
+			x, y, visible := m.project(p)
+			if visible {
+				canvas.Circle(int(math.Round(x)), int(math.Round(y)), opts.SiteRadius,
+					"fill:"+opts.SiteFill)
+			}
+		}
+		canvas.Gend()
+	}
+	if scale != nil && opts.Legend {
+		drawLegend(canvas, *scale, opts.LegendTitle, m.width, m.height)
+	}
+	canvas.End()
+	return ew.err
+}
+
+// mapper maps points on the sphere to pixels of the map.
+type mapper struct {
+	proj          Projection
+	bounds        r2.Rect
+	scale         float64
+	width, height int
+	maxSegment    s1.Angle
+}
+
+// newMapper returns the mapper of the options.
+func newMapper(opts *Options) *mapper {
+	b := opts.Projection.Bounds()
+	scale := float64(opts.Width) / b.X.Length()
+	return &mapper{
+		proj:       opts.Projection,
+		bounds:     b,
+		scale:      scale,
+		width:      opts.Width,
+		height:     max(1, int(math.Round(b.Y.Length()*scale))),
+		maxSegment: opts.MaxSegment,
+	}
+}
+
+// project returns the position of p in pixels and whether it is visible.
+func (m *mapper) project(p s2.Point) (x, y float64, visible bool) {
+	x, y, visible = m.proj.Project(p)
+	return (x - m.bounds.X.Lo) * m.scale, (m.bounds.Y.Hi - y) * m.scale, visible
+}
+
+// visible reports whether p is visible.
+func (m *mapper) visible(p s2.Point) bool {
+	_, _, visible := m.proj.Project(p)
+	return visible
+}
+
+// appendLine appends the positions as a subpath to d, starting with a move. The segments
+// between them, which are straight in longitude and latitude like the cuts along the
+// antimeridian, are subdivided to the maximum segment in degrees first.
+func (m *mapper) appendLine(d []byte, positions []lnglat.Position, closed bool) []byte {
+	n := len(positions)
+	step := m.maxSegment.Degrees()
+	for k, q := range positions {
+		d = m.appendPosition(d, q, k == 0)
+		if step <= 0 || (!closed && k == n-1) {
+			continue
+		}
+		r := positions[(k+1)%n]
+		num := math.Ceil(max(math.Abs(r[0]-q[0]), math.Abs(r[1]-q[1])) / step)
+		for j := 1.0; j < num; j++ {
+			t := j / num
+			d = m.appendPosition(d, lnglat.Position{q[0] + t*(r[0]-q[0]),
+				q[1] + t*(r[1]-q[1])}, false)
+		}
+	}
+	return d
+}
+
+// appendPosition appends a move to or a line to the position in pixels of q to d.
+func (m *mapper) appendPosition(d []byte, q lnglat.Position, move bool) []byte {
+	x, y, _ := m.proj.Project(positionPoint(q))
+	return m.appendXY(d, x, y, move)
+}
+
+// appendXY appends a move to or a line to the position in pixels of x, y on the plane to d.
+func (m *mapper) appendXY(d []byte, x, y float64, move bool) []byte {
+	if move {
+		d = append(d, 'M')
+	} else {
+		d = append(d, 'L')
+	}
+	d = strconv.AppendFloat(d, (x-m.bounds.X.Lo)*m.scale, 'f', 2, 64)
+	d = append(d, ',')
+	return strconv.AppendFloat(d, (m.bounds.Y.Hi-y)*m.scale, 'f', 2, 64)
+}
+
+// appendGraticule appends the visible parts of the meridians and parallels every step to d,
+// sampled every maximum segment or every degree if there is none.
+func (m *mapper) appendGraticule(d []byte, step s1.Angle) []byte {
+	sample := m.maxSegment.Degrees()
+	if sample <= 0 {
+		sample = 1
+	}
+	appendCurve := func(from, to lnglat.Position) {
+		num := math.Ceil(max(math.Abs(to[0]-from[0]), math.Abs(to[1]-from[1])) / sample)
+		move := true
+		for j := 0.0; j <= num; j++ {
+			t := j / num
+			x, y, visible := m.proj.Project(positionPoint(lnglat.Position{
+				from[0] + t*(to[0]-from[0]), from[1] + t*(to[1]-from[1])}))
+			if !visible {
+				move = true
+				continue
+			}
+			d = m.appendXY(d, x, y, move)
+			move = false
+		}
+	}
+	deg := step.Degrees()
+	for lng := -180.0; lng < 180; lng += deg {
+		appendCurve(lnglat.Position{lng, -90}, lnglat.Position{lng, 90})
+	}
+	for lat := deg; lat < 90; lat += deg {
+		appendCurve(lnglat.Position{-180, lat}, lnglat.Position{180, lat})
+		appendCurve(lnglat.Position{-180, -lat}, lnglat.Position{180, -lat})
+	}
+	appendCurve(lnglat.Position{-180, 0}, lnglat.Position{180, 0})
+	return d
+}
+
+// positionPoint returns the point of q, moved slightly off the antimeridian and the poles
+// towards its side of the map, where the point on the sphere is ambiguous.
+func positionPoint(q lnglat.Position) s2.Point {
+	const eps = 1e-9
+	lng := min(max(q[0], -180+eps), 180-eps)
+	lat := min(max(q[1], -90+eps), 90-eps)
+	return s2.PointFromLatLng(s2.LatLngFromDegrees(lat, lng))
+}
+
+// strokeStyle returns the SVG style of unfilled lines with the paint and width.
+func strokeStyle(stroke string, width float64) string {
+	return "fill:none;stroke:" + stroke + ";stroke-width:" +
+		strconv.FormatFloat(width, 'f', -1, 64)
+}
+
+// clipRing returns the visible part of the ring, or nil if no part of it is visible. Where the
+// ring leaves the visible hemisphere, it continues along its boundary to where it reenters.
+func (m *mapper) clipRing(ring s2.PointVector) s2.PointVector {
+	visible := make([]bool, len(ring))
+	numVisible := 0
+	for k, p := range ring {
+		if visible[k] = m.visible(p); visible[k] {
+			numVisible++
+		}
+	}
+	switch numVisible {
+	case len(ring):
+		return ring
+	case 0:
+		return nil
+	}
+	var out s2.PointVector
+	for k, a := range ring {
+		l := (k + 1) % len(ring)
+		if visible[k] {
+			out = append(out, a)
+		}
+		if visible[k] != visible[l] {
+			out = append(out, m.crossing(a, ring[l], visible[k]))
+		}
+	}
+	return out
+}
+
+// clipEdge returns the visible part of the edge from a to b as a polyline, or nil if no part
+// of it is visible.
+func (m *mapper) clipEdge(a, b s2.Point) s2.PointVector {
+	va, vb := m.visible(a), m.visible(b)
+	switch {
+	case va && vb:
+		return s2.PointVector{a, b}
+	case va:
+		return s2.PointVector{a, m.crossing(a, b, true)}
+	case vb:
+		return s2.PointVector{m.crossing(a, b, false), b}
+	}
+	return nil
+}
+
+// crossing returns the visible point closest to where the edge from a to b crosses the
+// boundary of the visible hemisphere, found by bisection; aVisible is whether a is visible.
+func (m *mapper) crossing(a, b s2.Point, aVisible bool) s2.Point {
+	lo, hi := 0.0, 1.0
+	for range 60 {
+		mid := (lo + hi) / 2
+		if m.visible(s2.Interpolate(mid, a, b)) == aVisible {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	if aVisible {
+		return s2.Interpolate(lo, a, b)
+	}
+	return s2.Interpolate(hi, a, b)
+}
+
+// lessPoint orders points by their coordinates.
+func lessPoint(a, b s2.Point) bool {
+	if a.X != b.X {
+		return a.X < b.X
+	}
+	if a.Y != b.Y {
+		return a.Y < b.Y
+	}
+	return a.Z < b.Z
+}
+
+// errWriter writes to w until the first error, which it keeps.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) Write(p []byte) (int, error) {
+	if ew.err != nil {
+		return 0, ew.err
+	}
+	n, err := ew.w.Write(p)
+	ew.err = err
+	return n, err
+}
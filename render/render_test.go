@@ -0,0 +1,503 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/2dChan/s2voronoi"
+	"github.com/2dChan/s2voronoi/internal/fixtures"
+	"github.com/2dChan/s2voronoi/s2delaunay"
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/r2"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiagramSVG(t *testing.T) {
+	inputs := map[string]s2.PointVector{"random": utils.GenerateRandomPoints(300, 0)}
+	for _, name := range fixtures.Names() {
+		inputs[name] = fixtures.Load(name)
+	}
+	for name, sites := range inputs {
+		vd, err := s2voronoi.NewDiagram(sites)
+		if err != nil {
+			continue
+		}
+		var rings []s2.PointVector
+		for _, c := range vd.Cells() {
+			if c.IsEmpty() {
+				continue
+			}
+			var ring s2.PointVector
+			for _, v := range c.VertexPoints() {
+				ring = append(ring, v)
+			}
+			rings = append(rings, ring)
+		}
+		for _, proj := range testProjections {
+			t.Run(name+"/"+proj.name, func(t *testing.T) {
+				var buf bytes.Buffer
+				if err := DiagramSVG(&buf, vd, WithWidth(720), WithProjection(proj.p)); err != nil {
+					t.Fatalf("DiagramSVG(...) error = %v, want nil", err)
+				}
+				checkSVG(t, buf.Bytes(), proj, rings, vd.Sites)
+			})
+		}
+	}
+}
+
+func TestTriangulationSVG(t *testing.T) {
+	inputs := map[string]s2.PointVector{"random": utils.GenerateRandomPoints(300, 0)}
+	for _, name := range fixtures.Names() {
+		inputs[name] = fixtures.Load(name)
+	}
+	for name, vertices := range inputs {
+		dt, err := s2delaunay.NewTriangulation(vertices)
+		if err != nil {
+			continue
+		}
+		rings := make([]s2.PointVector, len(dt.Triangles))
+		for i, tri := range dt.Triangles {
+			rings[i] = s2.PointVector{dt.Vertices[tri[0]], dt.Vertices[tri[1]], dt.Vertices[tri[2]]}
+		}
+		for _, proj := range testProjections {
+			t.Run(name+"/"+proj.name, func(t *testing.T) {
+				var buf bytes.Buffer
+				err := TriangulationSVG(&buf, dt, WithWidth(720), WithProjection(proj.p))
+				if err != nil {
+					t.Fatalf("TriangulationSVG(...) error = %v, want nil", err)
+				}
+				checkSVG(t, buf.Bytes(), proj, rings, dt.Vertices)
+			})
+		}
+	}
+}
+
+func TestSVG_Golden(t *testing.T) {
+	vd, err := s2voronoi.NewDiagram(fixtures.Load("octahedron"))
+	if err != nil {
+		t.Fatalf("s2voronoi.NewDiagram(...) error = %v, want nil", err)
+	}
+	for _, proj := range testProjections {
+		t.Run(proj.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := DiagramSVG(&buf, vd, WithWidth(200), WithProjection(proj.p),
+				WithMaxSegment(15*s1.Degree), WithGraticule("rgb(221,221,221)", 0.5, 30*s1.Degree))
+			if err != nil {
+				t.Fatalf("DiagramSVG(...) error = %v, want nil", err)
+			}
+			path := filepath.Join("testdata", "octahedron_"+proj.name+".svg")
+			if *update {
+				if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+					t.Fatalf("os.WriteFile(%q) error = %v, want nil", path, err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("os.ReadFile(%q) error = %v, want nil", path, err)
+			}
+			if diff := cmp.Diff(string(want), buf.String()); diff != "" {
+				t.Errorf("DiagramSVG(...) mismatch with %s (-want +got):\n%s", path, diff)
+			}
+		})
+	}
+}
+
+func TestMapper_ClipRing(t *testing.T) {
+	// The cells of the octahedron are the squares around the axes, each covering a sixth of the
+	// sphere; cell 0 is around +x and cell 4 around the north pole.
+	vd, err := s2voronoi.NewDiagram(fixtures.Load("octahedron"))
+	if err != nil {
+		t.Fatalf("s2voronoi.NewDiagram(...) error = %v, want nil", err)
+	}
+	cell := func(i int) s2.PointVector {
+		var ring s2.PointVector
+		for _, v := range vd.Cell(i).VertexPoints() {
+			ring = append(ring, v)
+		}
+		return ring
+	}
+	tests := []struct {
+		name   string
+		center s2.LatLng
+		cell   int
+		// wantArea is the fraction of the area of the cell that is visible.
+		wantArea float64
+	}{
+		{"facing", s2.LatLngFromDegrees(0, 0), 0, 1},
+		{"inside horizon", s2.LatLngFromDegrees(0, 45), 0, 1},
+		{"across meridian horizon", s2.LatLngFromDegrees(0, 90), 0, 0.5},
+		{"across equator horizon", s2.LatLngFromDegrees(90, 0), 0, 0.5},
+		{"across both", s2.LatLngFromDegrees(45, 90), 0, 0.5},
+		{"behind pole", s2.LatLngFromDegrees(-60, 0), 4, 0},
+		{"behind", s2.LatLngFromDegrees(0, 180), 0, 0},
+		{"pole across horizon", s2.LatLngFromDegrees(0, 0), 4, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMapper(&Options{Width: 100, Projection: Orthographic{tt.center}})
+			ring := cell(tt.cell)
+			got := m.clipRing(ring)
+			if tt.wantArea == 0 {
+				if got != nil {
+					t.Errorf("clipRing(...) = %v, want nil", got)
+				}
+				return
+			}
+			c := s2.PointFromLatLng(tt.center)
+			for _, p := range got {
+				if p.Dot(c.Vector) < 0 {
+					t.Errorf("clipRing(...) position %v is behind the horizon", p)
+				}
+			}
+			area := loopArea(got) / loopArea(ring)
+			if math.Abs(area-tt.wantArea) > 1e-9 {
+				t.Errorf("clipRing(...) covers %v of the cell, want %v", area, tt.wantArea)
+			}
+		})
+	}
+}
+
+func TestDiagramSVG_Horizon(t *testing.T) {
+	// Sites on a small circle around a point of the horizon put every cell but two across it.
+	var sites s2.PointVector
+	for k := range 12 {
+		angle := s1.Angle(k) * 30 * s1.Degree
+		sites = append(sites, s2.PointFromLatLng(s2.LatLng{Lat: 20 * s1.Degree * s1.Angle(
+			math.Sin(angle.Radians())), Lng: 90*s1.Degree + 20*s1.Degree*s1.Angle(
+			math.Cos(angle.Radians()))}))
+	}
+	sites = append(sites, s2.PointFromLatLng(s2.LatLngFromDegrees(0, 90)),
+		s2.PointFromLatLng(s2.LatLngFromDegrees(0, -90)))
+	vd, err := s2voronoi.NewDiagram(sites)
+	if err != nil {
+		t.Fatalf("s2voronoi.NewDiagram(...) error = %v, want nil", err)
+	}
+	var rings []s2.PointVector
+	for _, c := range vd.Cells() {
+		var ring s2.PointVector
+		for _, v := range c.VertexPoints() {
+			ring = append(ring, v)
+		}
+		rings = append(rings, ring)
+	}
+	proj := testProjection{"horizon", Orthographic{s2.LatLngFromDegrees(0, 0)},
+		math.Pi * 360 * 360}
+	var buf bytes.Buffer
+	if err := DiagramSVG(&buf, vd, WithWidth(720), WithProjection(proj.p)); err != nil {
+		t.Fatalf("DiagramSVG(...) error = %v, want nil", err)
+	}
+	checkSVG(t, buf.Bytes(), proj, rings, vd.Sites)
+}
+
+func TestProjection(t *testing.T) {
+	sqrt3 := math.Sqrt(3)
+	tests := []struct {
+		name        string
+		p           Projection
+		lat, lng    float64
+		x, y        float64
+		wantVisible bool
+	}{
+		{"plate carree", PlateCarree{}, 45, -90, -math.Pi / 2, math.Pi / 4, true},
+		{"mollweide center", Mollweide{}, 0, 0, 0, 0, true},
+		{"mollweide equator", Mollweide{}, 0, 180, 2 * math.Sqrt2, 0, true},
+		{"mollweide pole", Mollweide{}, 90, 45, 0, math.Sqrt2, true},
+		{"orthographic center", Orthographic{s2.LatLngFromDegrees(30, 60)}, 30, 60, 0, 0, true},
+		{"orthographic east", Orthographic{s2.LatLngFromDegrees(0, 60)}, 0, 120, sqrt3 / 2, 0,
+			true},
+		{"orthographic north", Orthographic{s2.LatLngFromDegrees(-30, 60)}, 30, 60, 0, sqrt3 / 2,
+			true},
+		{"orthographic back", Orthographic{s2.LatLngFromDegrees(30, 60)}, -30, -120, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y, visible := tt.p.Project(s2.PointFromLatLng(s2.LatLngFromDegrees(tt.lat, tt.lng)))
+			if math.Abs(x-tt.x) > 1e-9 || math.Abs(y-tt.y) > 1e-9 || visible != tt.wantVisible {
+				t.Errorf("Project(%v, %v) = %v, %v, %v, want %v, %v, %v", tt.lat, tt.lng, x, y,
+					visible, tt.x, tt.y, tt.wantVisible)
+			}
+			if b := tt.p.Bounds(); !b.ContainsPoint(r2.Point{X: x, Y: y}) && visible {
+				t.Errorf("Bounds() = %v, does not contain %v, %v", b, x, y)
+			}
+		})
+	}
+}
+
+func TestDiagramSVG_Options(t *testing.T) {
+	vd, err := s2voronoi.NewDiagram(utils.GenerateRandomPoints(20, 0))
+	if err != nil {
+		t.Fatalf("s2voronoi.NewDiagram(...) error = %v, want nil", err)
+	}
+	tests := []struct {
+		name    string
+		setters []Option
+		// want and notWant are substrings that the SVG contains and does not contain.
+		want, notWant []string
+	}{
+		{"defaults", nil,
+			[]string{`width="1500" height="750"`, "<rect", "stroke:rgb(170,170,170);stroke-width:1",
+				`r="3" style="fill:rgb(255,0,0)"`},
+			nil},
+		{"styles", []Option{WithFill("none"), WithStroke("black", 0.5),
+			WithSites("blue", 2), WithBackground("")},
+			[]string{"fill:none;stroke:black;stroke-width:0.5", `r="2" style="fill:blue"`},
+			[]string{"<rect"}},
+		{"no stroke and sites", []Option{WithStroke("black", 0), WithSites("blue", 0),
+			WithOutline("black", 0)},
+			nil, []string{"stroke:", "<circle"}},
+		{"outline and graticule", []Option{WithOutline("black", 2),
+			WithGraticule("blue", 0.5, 30*s1.Degree)},
+			[]string{`<g id="outline">`, "stroke:black;stroke-width:2", `<g id="graticule">`,
+				"stroke:blue;stroke-width:0.5"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := DiagramSVG(&buf, vd, tt.setters...); err != nil {
+				t.Fatalf("DiagramSVG(...) error = %v, want nil", err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(buf.String(), s) {
+					t.Errorf("DiagramSVG(...) does not contain %q", s)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(buf.String(), s) {
+					t.Errorf("DiagramSVG(...) contains %q", s)
+				}
+			}
+		})
+	}
+}
+
+func TestDiagramSVG_Errors(t *testing.T) {
+	vd, err := s2voronoi.NewDiagram(utils.GenerateRandomPoints(20, 0))
+	if err != nil {
+		t.Fatalf("s2voronoi.NewDiagram(...) error = %v, want nil", err)
+	}
+	tests := []struct {
+		name   string
+		setter Option
+	}{
+		{"width", WithWidth(1)},
+		{"negative stroke width", WithStroke("black", -1)},
+		{"nan stroke width", WithStroke("black", math.NaN())},
+		{"site radius", WithSites("red", -1)},
+		{"max segment", WithMaxSegment(-s1.Degree)},
+		{"nil projection", WithProjection(nil)},
+		{"outline width", WithOutline("black", -1)},
+		{"zero graticule step", WithGraticule("black", 1, 0)},
+		{"large graticule step", WithGraticule("black", 1, 91*s1.Degree)},
+		{"graticule width", WithGraticule("black", 0, 30*s1.Degree)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := DiagramSVG(io.Discard, vd, tt.setter); err == nil {
+				t.Errorf("DiagramSVG(..., %s) error = nil, want error", tt.name)
+			}
+		})
+	}
+	if err := DiagramSVG(failingWriter{}, vd); err == nil {
+		t.Errorf("DiagramSVG(failingWriter, ...) error = nil, want error")
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDiagramSVG(b *testing.B) {
+	vd, err := s2voronoi.NewDiagram(utils.GenerateRandomPoints(10000, 0))
+	if err != nil {
+		b.Fatalf("s2voronoi.NewDiagram(...) error = %v, want nil", err)
+	}
+	for b.Loop() {
+		if err := DiagramSVG(io.Discard, vd); err != nil {
+			b.Fatalf("DiagramSVG(...) error = %v, want nil", err)
+		}
+	}
+}
+
+// Helpers
+
+// testProjections are the projections that maps are rendered in by the tests.
+var testProjections = []testProjection{
+	{"platecarree", PlateCarree{}, 720 * 360},
+	{"mollweide", Mollweide{}, math.Pi * 360 * 180},
+	{"orthographic", Orthographic{s2.LatLngFromDegrees(50, 10)}, math.Pi * 360 * 360},
+}
+
+// testProjection is a projection with its name and the area of the visible part of the sphere
+// on a map of width 720.
+type testProjection struct {
+	name string
+	p    Projection
+	area float64
+}
+
+// update rewrites the golden files of TestSVG_Golden.
+var update = flag.Bool("update", false, "update golden files")
+
+// checkSVG parses an SVG map of width 720 in the projection and checks that it has a path for
+// every ring with a visible vertex, edges and a dot for every visible dot, all within the map.
+// The rings tile the sphere, so the areas of their paths must add up to the area of the visible
+// part of the sphere: a skipped or misdrawn polygon leaves a hole, and a polygon wrapped the
+// wrong way around overlaps others.
+func checkSVG(t *testing.T, data []byte, proj testProjection, rings []s2.PointVector,
+	dots s2.PointVector) {
+	t.Helper()
+	type path struct {
+		D string `xml:"d,attr"`
+	}
+	var doc struct {
+		XMLName xml.Name `xml:"svg"`
+		Width   int      `xml:"width,attr"`
+		Height  int      `xml:"height,attr"`
+		Groups  []struct {
+			ID      string     `xml:"id,attr"`
+			Paths   []path     `xml:"path"`
+			Circles []struct{} `xml:"circle"`
+		} `xml:"g"`
+	}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("xml.Unmarshal(svg) error = %v, want nil", err)
+	}
+	b := proj.p.Bounds()
+	width, height := 720, int(math.Round(720*b.Y.Length()/b.X.Length()))
+	if doc.Width != width || doc.Height != height {
+		t.Errorf("svg size = %dx%d, want %dx%d", doc.Width, doc.Height, width, height)
+	}
+	var ids []string
+	for _, g := range doc.Groups {
+		ids = append(ids, g.ID)
+	}
+	if want := []string{"cells", "edges", "outline", "sites"}; !slices.Equal(ids, want) {
+		t.Fatalf("svg groups = %v, want %v", ids, want)
+	}
+	visible := func(p s2.Point) bool {
+		_, _, v := proj.p.Project(p)
+		return v
+	}
+	numPaths, numDots := 0, 0
+	for _, ring := range rings {
+		if slices.ContainsFunc(ring, visible) {
+			numPaths++
+		}
+	}
+	for _, p := range dots {
+		if visible(p) {
+			numDots++
+		}
+	}
+	cells, edges, sites := doc.Groups[0], doc.Groups[1], doc.Groups[3]
+	if len(cells.Paths) != numPaths || len(edges.Paths) != 1 || len(sites.Circles) != numDots {
+		t.Fatalf("svg has %d cells, %d edge paths and %d sites, want %d, 1 and %d",
+			len(cells.Paths), len(edges.Paths), len(sites.Circles), numPaths, numDots)
+	}
+
+	inMap := func(a [2]float64) bool {
+		return a[0] >= 0 && a[0] <= float64(width) && a[1] >= 0 && a[1] <= float64(height)
+	}
+	total := 0.0
+	for i, p := range cells.Paths {
+		subpaths, err := parsePath(p.D, true)
+		if err != nil {
+			t.Fatalf("path %d: %v", i, err)
+		}
+		for _, ring := range subpaths {
+			area := 0.0
+			for k, a := range ring {
+				if !inMap(a) {
+					t.Errorf("path %d position %v outside the map", i, a)
+				}
+				b := ring[(k+1)%len(ring)]
+				area += a[0]*b[1] - b[0]*a[1]
+			}
+			// Counterclockwise in longitude and latitude is clockwise on the screen. Slivers
+			// along the antimeridian may round to no area.
+			if area > 0 {
+				t.Errorf("path %d ring %v is not clockwise on the screen", i, ring)
+			}
+			total -= area / 2
+		}
+	}
+	if math.Abs(total-proj.area) > 1e-3*proj.area {
+		t.Errorf("paths cover %v square pixels, want %v", total, proj.area)
+	}
+	lines, err := parsePath(edges.Paths[0].D, false)
+	if err != nil {
+		t.Fatalf("edges: %v", err)
+	}
+	for _, line := range lines {
+		for _, a := range line {
+			if !inMap(a) {
+				t.Errorf("edge position %v outside the map", a)
+			}
+		}
+	}
+}
+
+// parsePath returns the subpaths of an SVG path of "Mx,yLx,y..." subpaths, each followed by Z
+// if closed.
+func parsePath(d string, closed bool) ([][][2]float64, error) {
+	if !strings.HasPrefix(d, "M") {
+		return nil, fmt.Errorf("path %q does not start with M", d)
+	}
+	var subpaths [][][2]float64
+	for _, sub := range strings.Split(d[1:], "M") {
+		var ok bool
+		if sub, ok = strings.CutSuffix(sub, "Z"); ok != closed {
+			return nil, fmt.Errorf("subpath %q: closed = %v, want %v", sub, ok, closed)
+		}
+		var line [][2]float64
+		for _, pos := range strings.Split(sub, "L") {
+			x, y, ok := strings.Cut(pos, ",")
+			if !ok {
+				return nil, fmt.Errorf("position %q is not x,y", pos)
+			}
+			var q [2]float64
+			var err error
+			if q[0], err = strconv.ParseFloat(x, 64); err != nil {
+				return nil, err
+			}
+			if q[1], err = strconv.ParseFloat(y, 64); err != nil {
+				return nil, err
+			}
+			line = append(line, q)
+		}
+		if len(line) < 2 || (closed && len(line) < 3) {
+			return nil, fmt.Errorf("subpath %q has too few positions", sub)
+		}
+		subpaths = append(subpaths, line)
+	}
+	return subpaths, nil
+}
+
+// loopArea returns the area of the ring, CCW when looking out of the sphere.
+func loopArea(ring s2.PointVector) float64 {
+	reversed := slices.Clone(ring)
+	slices.Reverse(reversed)
+	return s2.LoopFromPoints(reversed).Area()
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
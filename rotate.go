@@ -0,0 +1,61 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/s2"
+)
+
+// rotationTolerance is the absolute tolerance of the entries of m·mᵀ - I and of det(m) - 1
+// accepted by Rotate.
+const rotationTolerance = 1e-9
+
+// Rotate applies the rotation matrix m, given in row-major order, to the Sites and Vertices in
+// place, e.g. to move a particular cell to the north pole. A rotation preserves distances and
+// orientation, so the CSR arrays stay valid and are not modified; the rotated points are
+// normalized against rounding, and cached structures are discarded.
+// It returns an error and leaves the diagram unchanged if m is not a proper rotation, i.e. not
+// orthonormal or with a determinant other than +1 within 1e-9, since a reflection would reverse
+// the CCW order of the rings.
+func (d *Diagram) Rotate(m [3][3]float64) error {
+	for i := range 3 {
+		for j := range 3 {
+			dot := m[i][0]*m[j][0] + m[i][1]*m[j][1] + m[i][2]*m[j][2]
+			if i == j {
+				dot--
+			}
+			// The negated comparison also rejects NaN entries.
+			if !(math.Abs(dot) <= rotationTolerance) {
+				return fmt.Errorf("s2voronoi: matrix %v is not orthonormal", m)
+			}
+		}
+	}
+	det := m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+	if !(math.Abs(det-1) <= rotationTolerance) {
+		return fmt.Errorf("s2voronoi: matrix %v has determinant %v, want 1", m, det)
+	}
+
+	for _, ps := range []s2.PointVector{d.Sites, d.Vertices} {
+		for i, p := range ps {
+			ps[i] = rotatePoint(m, p)
+		}
+	}
+	d.invalidateCaches()
+	return nil
+}
+
+// rotatePoint returns the normalized product of the matrix m and p.
+func rotatePoint(m [3][3]float64, p s2.Point) s2.Point {
+	return s2.PointFromCoords(
+		m[0][0]*p.X+m[0][1]*p.Y+m[0][2]*p.Z,
+		m[1][0]*p.X+m[1][1]*p.Y+m[1][2]*p.Z,
+		m[2][0]*p.X+m[2][1]*p.Y+m[2][2]*p.Z,
+	)
+}
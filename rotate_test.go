@@ -0,0 +1,93 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+func TestDiagram_Rotate(t *testing.T) {
+	queries := utils.GenerateRandomPoints(1000, 1)
+	tests := []struct {
+		name string
+		m    [3][3]float64
+	}{
+		{"identity", [3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}},
+		{"axis permutation", [3][3]float64{{0, 0, 1}, {1, 0, 0}, {0, 1, 0}}},
+		{"arbitrary axis", rotationMatrix(s2.PointFromCoords(1, -2, 3), 2)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd := mustNewDiagram(t, 200)
+			want := make([]int, len(queries))
+			for k, q := range queries {
+				want[k] = vd.FindCellIndex(q)
+			}
+			cellVertices := slices.Clone(vd.CellVertices)
+
+			if err := vd.Rotate(tt.m); err != nil {
+				t.Fatalf("vd.Rotate(%v) error = %v, want nil", tt.m, err)
+			}
+			if err := vd.Validate(); err != nil {
+				t.Errorf("vd.Validate() = %v, want nil", err)
+			}
+			if !slices.Equal(vd.CellVertices, cellVertices) {
+				t.Errorf("vd.Rotate(%v) modified CellVertices", tt.m)
+			}
+			for k, q := range queries {
+				if got := vd.FindCellIndex(rotatePoint(tt.m, q)); got != want[k] {
+					t.Errorf("vd.FindCellIndex(rotated query %d) = %d, want %d", k, got, want[k])
+				}
+			}
+		})
+	}
+}
+
+func TestDiagram_Rotate_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		m    [3][3]float64
+	}{
+		{"reflection", [3][3]float64{{-1, 0, 0}, {0, 1, 0}, {0, 0, 1}}},
+		{"scaling", [3][3]float64{{2, 0, 0}, {0, 2, 0}, {0, 0, 2}}},
+		{"shear", [3][3]float64{{1, 0.1, 0}, {0, 1, 0}, {0, 0, 1}}},
+		{"zero", [3][3]float64{}},
+		{"NaN", [3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, math.NaN()}}},
+		{"infinite", [3][3]float64{{1, 0, 0}, {0, math.Inf(1), 0}, {0, 0, 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd := mustNewDiagram(t, 100)
+			sites := slices.Clone(vd.Sites)
+			if err := vd.Rotate(tt.m); err == nil {
+				t.Errorf("vd.Rotate(%v) error = nil, want error", tt.m)
+			}
+			if !slices.Equal(vd.Sites, sites) {
+				t.Errorf("vd.Rotate(%v) modified the sites on error", tt.m)
+			}
+		})
+	}
+}
+
+// Helpers
+
+// rotationMatrix returns the matrix of the rotation by angle around axis, whose columns are the
+// rotated coordinate axes.
+func rotationMatrix(axis s2.Point, angle s1.Angle) [3][3]float64 {
+	var m [3][3]float64
+	for j, e := range []s2.Point{
+		s2.PointFromCoords(1, 0, 0), s2.PointFromCoords(0, 1, 0), s2.PointFromCoords(0, 0, 1),
+	} {
+		r := s2.Rotate(e, axis, angle)
+		m[0][j], m[1][j], m[2][j] = r.X, r.Y, r.Z
+	}
+	return m
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2delaunay
+
+import (
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// boundError is the angle by which bounds are expanded to absorb the rounding error of the
+// computed circumcenters.
+const boundError = s1.Angle(1e-14)
+
+// TriangleCapBounds returns a bounding cap for every triangle. Each cap is the circumcircle of
+// its triangle; since triangles are CCW the circumradius is below π/2, so the cap is convex and
+// also contains the geodesic edges. The caps are computed once on first use and shared by all
+// callers, so the returned slice must not be modified.
+func (t *Triangulation) TriangleCapBounds() []s2.Cap {
+	c := t.caches()
+	c.capBoundsOnce.Do(func() {
+		c.capBounds = make([]s2.Cap, len(t.Triangles))
+		for i := range t.Triangles {
+			a, b, cc := t.TriangleVertices(i)
+			center := s2.Point{Vector: b.Sub(a.Vector).Cross(cc.Sub(a.Vector)).Normalize()}
+			cp := s2.CapFromPoint(center).AddPoint(a).AddPoint(b).AddPoint(cc)
+			c.capBounds[i] = cp.Expanded(boundError)
+		}
+	})
+	return c.capBounds
+}
+
+// TrianglesIntersectingRegion returns the indices of the triangles whose bounding caps
+// intersect the bounding cap of the region, in increasing order. It is a conservative filter:
+// every triangle intersecting the region is reported, but some reported triangles may not
+// intersect it.
+func (t *Triangulation) TrianglesIntersectingRegion(r s2.Region) []int {
+	bound := r.CapBound()
+	var out []int
+	for i, cp := range t.TriangleCapBounds() {
+		if cp.Intersects(bound) {
+			out = append(out, i)
+		}
+	}
+	return out
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2delaunay
+
+import (
+	"context"
+	"math"
+
+	"github.com/2dChan/s2voronoi/internal/progress"
+	"github.com/golang/geo/r3"
+	"github.com/golang/geo/s2"
+	"github.com/markus-wa/quickhull-go/v2"
+)
+
+// Builder triangulates successive vertex sets, e.g. the steps of an iterative relaxation. It
+// keeps the buffers of the convex hull computation between calls and fills the slices of the
+// given triangulation in place, so triangulating vertex sets of the same size allocates little
+// beyond the internal structures of the hull algorithm. The result is identical to that of
+// NewTriangulation. A Builder is not safe for concurrent use.
+type Builder struct {
+	eps       float64
+	progress  progress.Func
+	normalize bool
+	unchecked bool
+	near      bool
+	qh        quickhull.QuickHull
+	points    []r3.Vector
+}
+
+// NewBuilder creates a Builder with the given options, see NewTriangulation.
+func NewBuilder(setters ...TriangulationOption) (*Builder, error) {
+	opts := &TriangulationOptions{
+		Eps: DefaultEps,
+	}
+	for _, set := range setters {
+		err := set(opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &Builder{
+		eps:       opts.Eps,
+		progress:  opts.Progress,
+		normalize: opts.NormalizeInput,
+		unchecked: opts.UncheckedInput,
+		near:      opts.NearDuplicateCheck,
+	}, nil
+}
+
+// SetProgress replaces the progress function set by WithProgress for later calls, e.g. to report
+// every triangulation of a reused Builder to a different progress bar. A nil f disables the
+// reports.
+func (b *Builder) SetProgress(f func(phase string, done, total int)) {
+	b.progress = f
+}
+
+// Triangulate replaces the contents of t by the triangulation of the vertices, see
+// NewTriangulation. The slices of t are reused when their capacity suffices, t takes ownership
+// of the vertices, and all cached structures of t are discarded.
+// It returns an error if the triangulation cannot be constructed, in which case t is left in an
+// unspecified state.
+func (b *Builder) Triangulate(t *Triangulation, vertices s2.PointVector) error {
+	return b.TriangulateContext(context.Background(), t, vertices)
+}
+
+// TriangulateContext is like Triangulate but checks ctx before and after computing the convex
+// hull and before building the incidence arrays, and returns ctx.Err() if it is done.
+func (b *Builder) TriangulateContext(ctx context.Context, t *Triangulation,
+	vertices s2.PointVector) error {
+	if len(vertices) < 4 {
+		return errInsufficientVertices
+	}
+	if !b.unchecked {
+		if err := checkVertices(vertices, b.normalize); err != nil {
+			return err
+		}
+	}
+	if b.normalize {
+		for i, v := range vertices {
+			vertices[i] = s2.Point{Vector: v.Normalize()}
+		}
+	}
+	if err := checkDuplicates(vertices, b.eps, b.near); err != nil {
+		return err
+	}
+	if err := checkCoplanar(vertices, b.eps); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	numVertices := len(vertices)
+	numTriangles := eulerNumTriangles(numVertices)
+	t.Vertices = vertices
+	t.Triangles = resize(t.Triangles, numTriangles)
+	t.IncidentTriangleIndices = resize(t.IncidentTriangleIndices, numTriangles*3)
+	t.IncidentTriangleOffsets = resize(t.IncidentTriangleOffsets, numVertices+1)
+	t.eps = b.eps
+	t.invalidateCaches()
+
+	if b.progress != nil {
+		b.progress(PhaseHull, 0, numVertices)
+	}
+	b.points = resize(b.points, numVertices)
+	for i, p := range vertices {
+		b.points[i] = p.Vector
+	}
+	ch := b.qh.ConvexHull(b.points, true, true, b.eps)
+	if len(ch.Indices) != numTriangles*3 {
+		return hullError(ch.Indices, numVertices)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	hullSign := false
+	for i := range numTriangles {
+		base := i * 3
+		for j := range 3 {
+			t.Triangles[i][j] = ch.Indices[base+j]
+		}
+		o := triangleOrientation(t.Triangles[i], t.Vertices)
+		if math.Abs(o) <= b.eps {
+			return errorf(ErrDegenerateInput,
+				"s2delaunay: triangle %d is degenerate, its plane passes the origin within eps", i)
+		}
+		// The hull faces are wound consistently, so their orientations share one sign unless
+		// the origin is outside of the hull.
+		if i == 0 {
+			hullSign = math.Signbit(o)
+		} else if math.Signbit(o) != hullSign {
+			return errorf(ErrDegenerateInput,
+				"s2delaunay: vertices lie in an open hemisphere, the origin is outside their convex hull")
+		}
+		sortTriangleVerticesCCW(&t.Triangles[i], t.Vertices)
+	}
+	if b.progress != nil {
+		b.progress(PhaseHull, numVertices, numVertices)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	t.buildIncidence(b.progress)
+
+	return nil
+}
+
+// hullError returns the error for a convex hull with the wrong number of indices. Vertices on
+// the sphere are all extreme points, so the hull drops a vertex only if it coincides with
+// another one within the hull tolerance.
+func hullError(indices []int, numVertices int) error {
+	onHull := make([]bool, numVertices)
+	for _, v := range indices {
+		onHull[v] = true
+	}
+	var dropped []int
+	for v, ok := range onHull {
+		if !ok {
+			dropped = append(dropped, v)
+		}
+	}
+	if len(dropped) > 0 {
+		return &DuplicateVerticesError{Indices: dropped}
+	}
+	return errorf(ErrDegenerateInput,
+		"s2delaunay: inconsistent number of indices returned from QuickHull")
+}
+
+// checkVertices returns an error matching ErrNotUnitLength for the first vertex with a NaN or
+// infinite coordinate, naming the coordinate, and then for the first vertex that is not of unit
+// length, or that cannot be normalized if normalize is set.
+func checkVertices(vertices s2.PointVector, normalize bool) error {
+	for i, v := range vertices {
+		for k, x := range [3]float64{v.X, v.Y, v.Z} {
+			if math.IsNaN(x) || math.IsInf(x, 0) {
+				return errorf(ErrNotUnitLength,
+					"s2delaunay: vertex %d has non-finite %c coordinate %v", i, "XYZ"[k], x)
+			}
+		}
+		if !normalize {
+			if !v.IsUnit() {
+				return errorf(ErrNotUnitLength,
+					"s2delaunay: vertex %d is not unit length, norm %v", i, v.Norm())
+			}
+		} else if n := v.Norm(); n == 0 || math.IsInf(n, 0) {
+			return errorf(ErrNotUnitLength, "s2delaunay: vertex %d cannot be normalized, norm %v",
+				i, n)
+		}
+	}
+	return nil
+}
+
+// resize returns a slice of length n that reuses the backing array of s if it is large enough.
+// The contents are not preserved.
+func resize[T any](s []T, n int) []T {
+	if cap(s) < n {
+		return make([]T, n)
+	}
+	return s[:n]
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2delaunay
+
+import (
+	"sync"
+
+	"github.com/golang/geo/s2"
+)
+
+// triangulationCache holds lazily built structures derived from the triangulation data.
+// Every operation that modifies the triangulation must discard it via invalidateCaches.
+type triangulationCache struct {
+	edgesOnce sync.Once
+	edges     map[uint64]struct{}
+
+	capBoundsOnce sync.Once
+	capBounds     []s2.Cap
+}
+
+// caches returns the cache of the triangulation. Triangulations created by the constructors
+// always have one; for triangulations assembled by hand it is allocated on first use, which is
+// not safe for concurrent use.
+func (t *Triangulation) caches() *triangulationCache {
+	if t.cache == nil {
+		t.cache = new(triangulationCache)
+	}
+	return t.cache
+}
+
+// invalidateCaches discards all lazily built structures of the triangulation.
+func (t *Triangulation) invalidateCaches() {
+	t.cache = new(triangulationCache)
+}
+
+// edgeKey returns a key identifying the undirected edge between a and b.
+func edgeKey(a, b int) uint64 {
+	if a > b {
+		a, b = b, a
+	}
+	return uint64(a)<<32 | uint64(uint32(b))
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2delaunay
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/r3"
+	"github.com/golang/geo/s2"
+)
+
+// CoplanarVerticesError is returned when all vertices lie in a common plane, e.g. on one great
+// circle, so that their convex hull is flat and no triangulation exists.
+type CoplanarVerticesError struct {
+	// Normal is the unit normal of the best-fit plane through the vertices.
+	Normal r3.Vector
+	// Offset is the distance of the best-fit plane from the origin along Normal.
+	// It is close to 0 when the vertices lie on a great circle.
+	Offset float64
+	// Deviation is the RMS distance of the vertices from the best-fit plane.
+	Deviation float64
+}
+
+func (e *CoplanarVerticesError) Error() string {
+	return fmt.Sprintf("s2delaunay: vertices are coplanar, plane normal %v offset %v deviation %v",
+		e.Normal, e.Offset, e.Deviation)
+}
+
+// Unwrap returns ErrDegenerateInput, which the error matches.
+func (e *CoplanarVerticesError) Unwrap() error {
+	return ErrDegenerateInput
+}
+
+// checkCoplanar returns a *CoplanarVerticesError if the smallest singular value of the
+// covariance of the vertices is within eps relative to the largest one. The relative test keeps
+// small but genuinely curved clusters of vertices from being rejected.
+func checkCoplanar(vertices s2.PointVector, eps float64) error {
+	n := float64(len(vertices))
+	mean := r3.Vector{}
+	for _, v := range vertices {
+		mean = mean.Add(v.Vector)
+	}
+	mean = mean.Mul(1 / n)
+
+	var cov [3][3]float64
+	for _, v := range vertices {
+		d := v.Sub(mean)
+		dv := [3]float64{d.X, d.Y, d.Z}
+		for i := range 3 {
+			for j := range 3 {
+				cov[i][j] += dv[i] * dv[j] / n
+			}
+		}
+	}
+
+	values, vectors := symmetricEigen3(cov)
+	k := 0
+	for i := 1; i < 3; i++ {
+		if values[i] < values[k] {
+			k = i
+		}
+	}
+	if values[k] > eps*max(values[0], values[1], values[2]) {
+		return nil
+	}
+
+	normal := vectors[k]
+	offset := normal.Dot(mean)
+	if offset < 0 {
+		normal, offset = normal.Mul(-1), -offset
+	}
+	deviation := math.Sqrt(max(values[k], 0))
+	return &CoplanarVerticesError{Normal: normal, Offset: offset, Deviation: deviation}
+}
+
+// symmetricEigen3 returns the eigenvalues and unit eigenvectors of the symmetric matrix m,
+// computed with the cyclic Jacobi method.
+func symmetricEigen3(m [3][3]float64) ([3]float64, [3]r3.Vector) {
+	v := [3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	for range 50 {
+		off := m[0][1]*m[0][1] + m[0][2]*m[0][2] + m[1][2]*m[1][2]
+		if off == 0 {
+			break
+		}
+		for p := range 2 {
+			for q := p + 1; q < 3; q++ {
+				if m[p][q] == 0 {
+					continue
+				}
+				theta := (m[q][q] - m[p][p]) / (2 * m[p][q])
+				t := math.Copysign(1, theta) / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for k := range 3 {
+					mkp, mkq := m[k][p], m[k][q]
+					m[k][p], m[k][q] = c*mkp-s*mkq, s*mkp+c*mkq
+				}
+				for k := range 3 {
+					mpk, mqk := m[p][k], m[q][k]
+					m[p][k], m[q][k] = c*mpk-s*mqk, s*mpk+c*mqk
+				}
+				for k := range 3 {
+					vkp, vkq := v[k][p], v[k][q]
+					v[k][p], v[k][q] = c*vkp-s*vkq, s*vkp+c*vkq
+				}
+			}
+		}
+	}
+
+	var values [3]float64
+	var vectors [3]r3.Vector
+	for i := range 3 {
+		values[i] = m[i][i]
+		vectors[i] = r3.Vector{X: v[0][i], Y: v[1][i], Z: v[2][i]}.Normalize()
+	}
+	return values, vectors
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2delaunay
+
+import (
+	"math"
+	"math/bits"
+	"slices"
+
+	"github.com/golang/geo/s2"
+)
+
+// checkDuplicates returns a *DuplicateVerticesError if vertices are equal, or within a chord
+// distance of eps if near is set, see findDuplicates.
+func checkDuplicates(vertices s2.PointVector, eps float64, near bool) error {
+	var pairs [][2]int
+	if near {
+		pairs = findNearDuplicates(vertices, eps)
+	} else {
+		pairs = findDuplicates(vertices)
+	}
+	if len(pairs) == 0 {
+		return nil
+	}
+	indices := make([]int, 0, len(pairs))
+	for _, p := range pairs {
+		indices = append(indices, p[1])
+	}
+	return &DuplicateVerticesError{Indices: slices.Compact(indices), Pairs: pairs}
+}
+
+// findDuplicates returns the pairs (i, j) of equal vertices with i < j, where i is the first
+// vertex equal to j, in increasing order of j. The vertices are hashed by their coordinates
+// into an open addressing table of at least twice their number, so it takes O(n) time.
+func findDuplicates(vertices s2.PointVector) [][2]int {
+	size := 1 << bits.Len(uint(2*len(vertices)))
+	mask := uint64(size - 1)
+	// Entries are vertex indices plus one, so that zero marks an empty slot.
+	table := make([]int32, size)
+	var pairs [][2]int
+	for j, v := range vertices {
+		for k := hashPoint(v) & mask; ; k = (k + 1) & mask {
+			i := int(table[k]) - 1
+			if i < 0 {
+				table[k] = int32(j + 1)
+				break
+			}
+			if vertices[i] == v {
+				pairs = append(pairs, [2]int{i, j})
+				break
+			}
+		}
+	}
+	return pairs
+}
+
+// hashPoint returns a hash of the coordinates of p in which 0 and -0 are equal.
+func hashPoint(p s2.Point) uint64 {
+	h := math.Float64bits(p.X+0)*0x9e3779b97f4a7c15 ^
+		math.Float64bits(p.Y+0)*0xc2b2ae3d27d4eb4f ^
+		math.Float64bits(p.Z+0)*0x165667b19e3779f9
+	return h ^ h>>29
+}
+
+// findNearDuplicates returns the pairs (i, j) of vertices within a chord distance of eps with
+// i < j, in increasing order of j and then i. The vertices are bucketed by the s2.CellID of the
+// finest level whose cells are at least eps wide, so vertices within eps lie in the same or in
+// adjacent cells and every vertex is compared with the vertices of 9 cells only.
+func findNearDuplicates(vertices s2.PointVector, eps float64) [][2]int {
+	level := s2.MinWidthMetric.MaxLevel(eps)
+	heads := make(map[s2.CellID]int, len(vertices))
+	// next links the vertices of a cell, ending with -1.
+	next := make([]int, len(vertices))
+	var pairs [][2]int
+	for j, v := range vertices {
+		id := s2.CellFromPoint(v).ID().Parent(level)
+		start := len(pairs)
+		for _, c := range append(id.AllNeighbors(level), id) {
+			i, ok := heads[c]
+			for ; ok && i >= 0; i = next[i] {
+				if v.Sub(vertices[i].Vector).Norm() <= eps {
+					pairs = append(pairs, [2]int{i, j})
+				}
+			}
+		}
+		slices.SortFunc(pairs[start:], func(a, b [2]int) int { return a[0] - b[0] })
+
+		next[j] = -1
+		if i, ok := heads[id]; ok {
+			next[j] = i
+		}
+		heads[id] = j
+	}
+	return pairs
+}
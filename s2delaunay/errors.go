@@ -0,0 +1,78 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2delaunay
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The errors of the package match one of these sentinels with errors.Is when they stem from a
+// common failure mode, so callers can tell the modes apart without matching messages. They are
+// shared with s2voronoi, whose errors match them as well.
+var (
+	// ErrInsufficientVertices is matched by errors about fewer than 4 vertices.
+	ErrInsufficientVertices = errors.New("s2delaunay: insufficient vertices")
+	// ErrDegenerateInput is matched by errors about vertices whose triangulation is not defined
+	// within eps, e.g. coplanar vertices, vertices in an open hemisphere or degenerate triangles.
+	ErrDegenerateInput = errors.New("s2delaunay: degenerate input")
+	// ErrDuplicateVertices is matched by errors about vertices that coincide within eps, which
+	// are a *DuplicateVerticesError for NewTriangulation.
+	ErrDuplicateVertices = errors.New("s2delaunay: duplicate vertices")
+	// ErrInvalidOption is matched by errors of invalid options.
+	ErrInvalidOption = errors.New("s2delaunay: invalid option")
+	// ErrNotUnitLength is matched by errors about vertices that are not finite unit vectors,
+	// see WithNormalizeInput.
+	ErrNotUnitLength = errors.New("s2delaunay: vertex not unit length")
+)
+
+// DuplicateVerticesError is returned when vertices coincide with other vertices: when they are
+// equal, or within eps with WithNearDuplicateCheck, which is detected before the convex hull is
+// computed, or when they are within the tolerance of the convex hull, so that the hull drops
+// them. It matches ErrDuplicateVertices.
+type DuplicateVerticesError struct {
+	// Indices are the indices of the duplicate vertices in increasing order, i.e. the later
+	// vertex of every pair or the vertices dropped by the hull.
+	Indices []int
+	// Pairs are the pairs (i, j) of coinciding vertices with i < j found before the hull is
+	// computed, in increasing order of j and then i, or nil for vertices dropped by the hull.
+	Pairs [][2]int
+}
+
+func (e *DuplicateVerticesError) Error() string {
+	if len(e.Pairs) > 0 {
+		return fmt.Sprintf("s2delaunay: vertices %v coincide with other vertices, pairs %v",
+			e.Indices, e.Pairs)
+	}
+	return fmt.Sprintf("s2delaunay: vertices %v coincide with other vertices", e.Indices)
+}
+
+// Unwrap returns ErrDuplicateVertices, which the error matches.
+func (e *DuplicateVerticesError) Unwrap() error {
+	return ErrDuplicateVertices
+}
+
+// errInsufficientVertices is the error for fewer than 4 vertices.
+var errInsufficientVertices = errorf(ErrInsufficientVertices,
+	"s2delaunay: insufficient vertices for triangulation, minimum 4 required")
+
+// kindError is an error with its own message that matches err with errors.Is and errors.As.
+type kindError struct {
+	msg string
+	err error
+}
+
+// errorf returns an error formatted like fmt.Errorf that matches kind with errors.Is.
+func errorf(kind error, format string, args ...any) error {
+	return &kindError{msg: fmt.Sprintf(format, args...), err: kind}
+}
+
+func (e *kindError) Error() string {
+	return e.msg
+}
+
+func (e *kindError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2delaunay
+
+import (
+	"unsafe"
+
+	"github.com/golang/geo/s2"
+)
+
+// MemoryStats holds the number of bytes used by the major arrays of a triangulation. The sizes
+// are computed from the capacities of the slices and the sizes of their elements; maps are
+// counted by their entries only, so the figures are a lower bound of the actual heap usage.
+type MemoryStats struct {
+	Vertices                int
+	Triangles               int
+	IncidentTriangleIndices int
+	IncidentTriangleOffsets int
+	// Caches are the lazily built structures, which count only once materialized.
+	Caches int
+	// Total is the sum of all other fields.
+	Total int
+}
+
+// MemoryFootprint reports the memory used by the triangulation.
+// It must not be called concurrently with methods that build caches.
+func (t *Triangulation) MemoryFootprint() MemoryStats {
+	const (
+		pointSize    = int(unsafe.Sizeof(s2.Point{}))
+		triangleSize = int(unsafe.Sizeof([3]int{}))
+		intSize      = int(unsafe.Sizeof(0))
+		capSize      = int(unsafe.Sizeof(s2.Cap{}))
+		keySize      = int(unsafe.Sizeof(uint64(0)))
+	)
+
+	s := MemoryStats{
+		Vertices:                cap(t.Vertices) * pointSize,
+		Triangles:               cap(t.Triangles) * triangleSize,
+		IncidentTriangleIndices: cap(t.IncidentTriangleIndices) * intSize,
+		IncidentTriangleOffsets: cap(t.IncidentTriangleOffsets) * intSize,
+	}
+	if c := t.cache; c != nil {
+		s.Caches = len(c.edges)*keySize + cap(c.capBounds)*capSize
+	}
+	s.Total = s.Vertices + s.Triangles + s.IncidentTriangleIndices + s.IncidentTriangleOffsets +
+		s.Caches
+	return s
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2delaunay
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/2dChan/s2voronoi/internal/progress"
+	"github.com/golang/geo/s2"
+)
+
+// NewTriangulationFromTriangles creates a triangulation from given vertices and triangles,
+// e.g. the dual of a Voronoi diagram, keeping the triangle indices. The incidence structures
+// are built as by RebuildIncidence, which also sorts the vertex order of every triangle CCW.
+// The Delaunay property is not checked.
+// It returns an error if the triangles are invalid, see RebuildIncidence.
+func NewTriangulationFromTriangles(vertices s2.PointVector, triangles [][3]int,
+	setters ...TriangulationOption) (*Triangulation, error) {
+	opts := &TriangulationOptions{
+		Eps: DefaultEps,
+	}
+	for _, set := range setters {
+		err := set(opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	t := &Triangulation{
+		Vertices:  vertices,
+		Triangles: triangles,
+		eps:       opts.Eps,
+		cache:     new(triangulationCache),
+	}
+	if err := t.RebuildIncidence(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// RebuildIncidence recomputes the incidence structures of the triangulation from Vertices and
+// Triangles, e.g. after manual edits or deserialization. The vertex order of every triangle is
+// sorted CCW, and IncidentTriangleIndices and IncidentTriangleOffsets are regenerated and
+// sorted CCW around their vertex. All cached structures are discarded.
+// It returns an error and leaves the triangulation unchanged if a triangle has a vertex index
+// out of range or is degenerate within eps, or if the triangles do not form a closed manifold.
+func (t *Triangulation) RebuildIncidence() error {
+	numVertices := t.NumVertices()
+	if want := eulerNumTriangles(numVertices); t.NumTriangles() != want {
+		return fmt.Errorf("s2delaunay: %d triangles for %d vertices, want %d",
+			t.NumTriangles(), numVertices, want)
+	}
+
+	triangles := make([][3]int, t.NumTriangles())
+	copy(triangles, t.Triangles)
+	for i := range triangles {
+		for _, v := range triangles[i] {
+			if v < 0 || v >= numVertices {
+				return fmt.Errorf("s2delaunay: triangle %d vertex %d out of range [0 %d)",
+					i, v, numVertices)
+			}
+		}
+		if math.Abs(triangleOrientation(triangles[i], t.Vertices)) <= t.eps {
+			return fmt.Errorf(
+				"s2delaunay: triangle %d is degenerate, its plane passes the origin within eps", i)
+		}
+		sortTriangleVerticesCCW(&triangles[i], t.Vertices)
+	}
+	if _, err := checkManifold(triangles); err != nil {
+		return err
+	}
+
+	rebuilt := &Triangulation{
+		Vertices:                t.Vertices,
+		Triangles:               triangles,
+		IncidentTriangleIndices: make([]int, len(triangles)*3),
+		IncidentTriangleOffsets: make([]int, numVertices+1),
+		eps:                     t.eps,
+		cache:                   new(triangulationCache),
+	}
+	rebuilt.buildIncidence(nil)
+	if err := rebuilt.checkIncidentFans(); err != nil {
+		return err
+	}
+
+	*t = *rebuilt
+	return nil
+}
+
+// buildIncidence fills IncidentTriangleIndices and IncidentTriangleOffsets from Triangles and
+// sorts every incident list CCW, reporting PhaseSorting to report if it is not nil. Both
+// slices must already have their final length.
+func (t *Triangulation) buildIncidence(report progress.Func) {
+	numVertices := t.NumVertices()
+	clear(t.IncidentTriangleOffsets)
+	for _, tri := range t.Triangles {
+		for _, v := range tri {
+			t.IncidentTriangleOffsets[v+1]++
+		}
+	}
+	for i := range numVertices {
+		t.IncidentTriangleOffsets[i+1] += t.IncidentTriangleOffsets[i]
+	}
+	nxt := make([]int, numVertices)
+	copy(nxt, t.IncidentTriangleOffsets[:numVertices])
+	for i, tri := range t.Triangles {
+		for _, v := range tri {
+			t.IncidentTriangleIndices[nxt[v]] = i
+			nxt[v]++
+		}
+	}
+	_ = progress.ForEachChunk(numVertices, report, PhaseSorting, func(start, end int) error {
+		for i := start; i < end; i++ {
+			incidentTriangles := t.IncidentTriangles(i)
+			sortIncidentTriangleIndicesCCW(i, incidentTriangles, t.Triangles)
+		}
+		return nil
+	})
+}
+
+// checkManifold checks that every directed edge of the triangles occurs exactly once and that
+// its reverse occurs as well, so that the triangles form a closed, consistently oriented
+// manifold. It returns the triangle index of every directed edge.
+func checkManifold(triangles [][3]int) (map[[2]int]int, error) {
+	edges := make(map[[2]int]int, len(triangles)*3)
+	for i, tri := range triangles {
+		for j := range 3 {
+			e := [2]int{tri[j], tri[(j+1)%3]}
+			if other, ok := edges[e]; ok {
+				return nil, fmt.Errorf("s2delaunay: edge %v is traversed by triangles %d and %d "+
+					"in the same direction", e, other, i)
+			}
+			edges[e] = i
+		}
+	}
+	for i, tri := range triangles {
+		for j := range 3 {
+			e := [2]int{tri[j], tri[(j+1)%3]}
+			if _, ok := edges[[2]int{e[1], e[0]}]; !ok {
+				return nil, fmt.Errorf("s2delaunay: edge %v of triangle %d has no opposite triangle",
+					e, i)
+			}
+		}
+	}
+	return edges, nil
+}
+
+// checkIncidentFans checks that the incident triangles of every vertex form a single closed
+// fan sorted CCW around it.
+func (t *Triangulation) checkIncidentFans() error {
+	for v := range t.NumVertices() {
+		it := t.IncidentTriangles(v)
+		if len(it) < 3 {
+			return fmt.Errorf("s2delaunay: vertex %d has %d incident triangles, need at least 3",
+				v, len(it))
+		}
+		for k, tIdx := range it {
+			if tIdx < 0 || tIdx >= len(t.Triangles) || !triangleHasVertex(t.Triangles[tIdx], v) {
+				return fmt.Errorf("s2delaunay: vertex %d lists triangle %d that does not contain it",
+					v, tIdx)
+			}
+			next := it[(k+1)%len(it)]
+			if next < 0 || next >= len(t.Triangles) || !triangleHasVertex(t.Triangles[next], v) ||
+				NextVertex(t.Triangles[tIdx], v) != PrevVertex(t.Triangles[next], v) {
+				return fmt.Errorf("s2delaunay: incident triangles of vertex %d are not sorted CCW", v)
+			}
+		}
+	}
+	return nil
+}
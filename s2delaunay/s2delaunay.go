@@ -9,15 +9,13 @@ package s2delaunay
 import (
 	"errors"
 	"fmt"
+	"math"
 
-	"github.com/golang/geo/r3"
 	"github.com/golang/geo/s2"
-	"github.com/markus-wa/quickhull-go/v2"
 )
 
-const (
-	defaultEps = 1e-12
-)
+// DefaultEps is the numerical precision epsilon used when WithEps is not given.
+const DefaultEps = 1e-12
 
 // Triangulation represents a Delaunay triangulation on the S2 sphere.
 type Triangulation struct {
@@ -31,11 +29,20 @@ type Triangulation struct {
 	IncidentTriangleIndices []int
 	// IncidentTriangleOffsets contains offsets for slicing incident triangle data in a CSR-like format.
 	IncidentTriangleOffsets []int
+
+	// eps is the numerical precision epsilon used in triangulation computations.
+	eps float64
+	// cache holds lazily built acceleration structures.
+	cache *triangulationCache
 }
 
 // TriangulationOptions holds configuration options for Delaunay triangulation.
 type TriangulationOptions struct {
-	Eps float64
+	Eps                float64
+	Progress           func(phase string, done, total int)
+	NormalizeInput     bool
+	UncheckedInput     bool
+	NearDuplicateCheck bool
 }
 
 // TriangulationOption is a functional option type for triangulation configuration.
@@ -46,77 +53,123 @@ type TriangulationOption func(*TriangulationOptions) error
 func WithEps(eps float64) TriangulationOption {
 	return func(o *TriangulationOptions) error {
 		if eps <= 0 {
-			return fmt.Errorf("s2delaunay: eps must be positive got %v", eps)
+			return errorf(ErrInvalidOption, "s2delaunay: eps must be positive, got %v", eps)
 		}
 		o.Eps = eps
 		return nil
 	}
 }
 
-// NewTriangulation creates a Delaunay triangulation from the given vertices.
-// The vertices must lie on the unit sphere, there must be at least 4 vertices, and they must not be coplanar.
-// It returns an error if the triangulation cannot be constructed.
-func NewTriangulation(vertices s2.PointVector, setters ...TriangulationOption) (*Triangulation, error) {
-	if len(vertices) < 4 {
-		return nil,
-			errors.New("s2delaunay: insufficient vertices for triangulation, minimum 4 required")
+// WithNormalizeInput normalizes every vertex to unit length in place before the triangulation
+// is built, for vertices that are slightly off the unit sphere, e.g. from geodetic
+// computations. Zero and non-finite vectors are rejected with an error matching
+// ErrNotUnitLength. Without the option such vertices are rejected as well, together with every
+// vertex that is not of unit length, see s2.Point.IsUnit.
+func WithNormalizeInput() TriangulationOption {
+	return func(o *TriangulationOptions) error {
+		o.NormalizeInput = true
+		return nil
 	}
+}
 
-	opts := &TriangulationOptions{
-		Eps: defaultEps,
-	}
-	for _, set := range setters {
-		err := set(opts)
-		if err != nil {
-			return nil, err
-		}
+// WithUncheckedInput skips the linear scan that rejects vertices with NaN or infinite
+// coordinates and vertices that are not of unit length, for hot paths whose input is known to
+// be valid. Invalid vertices then yield a garbage triangulation, an unrelated error or a panic.
+func WithUncheckedInput() TriangulationOption {
+	return func(o *TriangulationOptions) error {
+		o.UncheckedInput = true
+		return nil
 	}
+}
 
-	numVertices := len(vertices)
-	numTriangles := 2 * (numVertices - 2)
-	t := &Triangulation{
-		Vertices:                vertices,
-		Triangles:               make([][3]int, numTriangles),
-		IncidentTriangleIndices: make([]int, numTriangles*3),
-		IncidentTriangleOffsets: make([]int, numVertices+1),
+// WithNearDuplicateCheck extends the check for equal vertices, which runs before the convex
+// hull is computed, to vertices within a chord distance of eps, so that all offending pairs are
+// reported by the *DuplicateVerticesError. It buckets the vertices by s2.CellID in O(n) time,
+// which adds about 15% to the triangulation, while the check for equal vertices adds well under
+// 1%. Without it, vertices within the tolerance of the hull but not equal are only reported when
+// the hull drops them, without pairs.
+func WithNearDuplicateCheck() TriangulationOption {
+	return func(o *TriangulationOptions) error {
+		o.NearDuplicateCheck = true
+		return nil
 	}
+}
 
-	r3vertices := make([]r3.Vector, numVertices)
-	for i, p := range vertices {
-		r3vertices[i] = p.Vector
-	}
-	qh := new(quickhull.QuickHull)
-	ch := qh.ConvexHull(r3vertices, true, true, opts.Eps)
-	if len(ch.Indices) != numTriangles*3 {
-		return nil, errors.New("s2delaunay: inconsistent number of indices returned from QuickHull")
-	}
+// The phases of a triangulation reported by WithProgress, in this order.
+const (
+	// PhaseHull is the convex hull of the vertices, counted in vertices. The hull is computed
+	// in one step, so only its start and end are reported. It takes about 90% of the time of
+	// the triangulation.
+	PhaseHull = "hull"
+	// PhaseSorting is the CCW sorting of the incident triangles of every vertex, counted in
+	// vertices. It takes about 10% of the time of the triangulation.
+	PhaseSorting = "sorting"
+)
 
-	for _, idx := range ch.Indices {
-		t.IncidentTriangleOffsets[idx+1]++
-	}
-	for i := range numVertices {
-		t.IncidentTriangleOffsets[i+1] += t.IncidentTriangleOffsets[i]
-	}
-	nxt := make([]int, numVertices)
-	copy(nxt, t.IncidentTriangleOffsets[:numVertices])
-	for i := range numTriangles {
-		base := i * 3
-		for j := range 3 {
-			v := ch.Indices[base+j]
-			t.Triangles[i][j] = v
-			t.IncidentTriangleIndices[nxt[v]] = i
-			nxt[v]++
+// WithProgress sets a function that NewTriangulation and Builder.Triangulate call during the
+// phases PhaseHull and PhaseSorting with the number of items done out of the total of the
+// phase. Every phase is reported about 100 times, from 0 up to its total, from the calling
+// goroutine. It must not be nil.
+func WithProgress(f func(phase string, done, total int)) TriangulationOption {
+	return func(o *TriangulationOptions) error {
+		if f == nil {
+			return errorf(ErrInvalidOption, "s2delaunay: progress function must not be nil")
 		}
-		sortTriangleVerticesCCW(&t.Triangles[i], t.Vertices)
+		o.Progress = f
+		return nil
 	}
-	for i := range numVertices {
-		incidentTriangles := t.IncidentTriangles(i)
-		sortIncidentTriangleIndicesCCW(i, incidentTriangles, t.Triangles)
+}
+
+// NewTriangulation creates a Delaunay triangulation from the given vertices.
+// The vertices must lie on the unit sphere, there must be at least 4 vertices, they must not be coplanar,
+// and they must not lie in an open hemisphere.
+// It returns an error if the triangulation cannot be constructed, and a *CoplanarVerticesError if
+// the vertices lie in a common plane within eps. The errors match ErrInsufficientVertices,
+// ErrDegenerateInput, ErrDuplicateVertices or ErrInvalidOption, see errors.Is; vertices dropped
+// as duplicates are reported by a *DuplicateVerticesError. Vertices with NaN or infinite
+// coordinates, and vertices that are not of unit length unless WithNormalizeInput is given,
+// yield an error matching ErrNotUnitLength that names the first of them.
+func NewTriangulation(vertices s2.PointVector, setters ...TriangulationOption) (*Triangulation, error) {
+	if len(vertices) < 4 {
+		return nil, errInsufficientVertices
 	}
 
+	b, err := NewBuilder(setters...)
+	if err != nil {
+		return nil, err
+	}
+	t := new(Triangulation)
+	if err := b.Triangulate(t, vertices); err != nil {
+		return nil, err
+	}
 	return t, nil
 }
 
+// Eps returns the numerical precision epsilon the triangulation was built with.
+// It is the distance tolerance of the convex hull, the relative tolerance of the coplanarity
+// check, and the minimum magnitude of triangleOrientation below which a triangle is rejected as
+// degenerate because its CCW orientation cannot be decided.
+func (t *Triangulation) Eps() float64 {
+	return t.eps
+}
+
+// NumVertices returns the number of vertices of the triangulation.
+func (t *Triangulation) NumVertices() int {
+	return len(t.Vertices)
+}
+
+// NumTriangles returns the number of triangles of the triangulation.
+func (t *Triangulation) NumTriangles() int {
+	return len(t.Triangles)
+}
+
+// NumEdges returns the number of distinct edges of the triangulation. It counts the edges of
+// the current Triangles on every call rather than relying on Euler's formula or the cached
+// edge set of HasEdge, so it stays correct for triangulations that have been edited.
+func (t *Triangulation) NumEdges() int {
+	return len(edgeKeys(t.Triangles))
+}
+
 // IncidentTriangles returns the indices of triangles incident to the vertex at the given index,
 // sorted in CCW order when looking out of the sphere.
 // It panics if the vertex index is out of range.
@@ -140,15 +193,97 @@ func (t *Triangulation) TriangleVertices(tIdx int) (s2.Point, s2.Point, s2.Point
 	return t.Vertices[tri[0]], t.Vertices[tri[1]], t.Vertices[tri[2]]
 }
 
+// HasEdge reports whether the vertices a and b are connected by a triangulation edge.
+// The lookup uses a hash set of all edges that is built on first use.
+// It panics if either vertex index is out of range.
+func (t *Triangulation) HasEdge(a, b int) bool {
+	for _, v := range [2]int{a, b} {
+		if v < 0 || v >= t.NumVertices() {
+			panic(fmt.Sprintf("s2delaunay: vIdx %d out of range [0 %d)", v, t.NumVertices()))
+		}
+	}
+
+	_, ok := t.edgeSet()[edgeKey(a, b)]
+	return ok
+}
+
+// edgeSet returns the set of edgeKey values of all triangulation edges, building it on first
+// use.
+func (t *Triangulation) edgeSet() map[uint64]struct{} {
+	c := t.caches()
+	c.edgesOnce.Do(func() {
+		c.edges = edgeKeys(t.Triangles)
+	})
+	return c.edges
+}
+
+// edgeKeys returns the set of edgeKey values of the edges of the triangles.
+func edgeKeys(triangles [][3]int) map[uint64]struct{} {
+	edges := make(map[uint64]struct{}, len(triangles)*3/2)
+	for _, tri := range triangles {
+		for j := range 3 {
+			edges[edgeKey(tri[j], tri[(j+1)%3])] = struct{}{}
+		}
+	}
+	return edges
+}
+
+// PaddedNeighbors returns the one-ring neighbors of all vertices as a dense row-major
+// n×maxDegree matrix, where row i holds the vertices adjacent to vertex i in the order of
+// IncidentTriangles followed by padValue. It also returns the actual maximum vertex degree.
+// If maxDegree is 0, the actual maximum is used as the row width. It returns an error naming
+// the first offending vertex if maxDegree is smaller than the degree of some vertex.
+func (t *Triangulation) PaddedNeighbors(maxDegree int, padValue int) ([]int32, int, error) {
+	if maxDegree < 0 {
+		return nil, 0, fmt.Errorf("s2delaunay: maxDegree must be non-negative, got %d", maxDegree)
+	}
+	numVertices := t.NumVertices()
+	if numVertices > math.MaxInt32 || padValue < math.MinInt32 || padValue > math.MaxInt32 {
+		return nil, 0, errors.New("s2delaunay: indices do not fit into int32")
+	}
+
+	degree := 0
+	for i := range numVertices {
+		degree = max(degree, len(t.IncidentTriangles(i)))
+	}
+	if maxDegree == 0 {
+		maxDegree = degree
+	}
+
+	out := make([]int32, numVertices*maxDegree)
+	for i := range numVertices {
+		it := t.IncidentTriangles(i)
+		if len(it) > maxDegree {
+			return nil, degree, fmt.Errorf("s2delaunay: vertex %d has degree %d, exceeds maxDegree %d",
+				i, len(it), maxDegree)
+		}
+		row := out[i*maxDegree : (i+1)*maxDegree]
+		for k, tIdx := range it {
+			row[k] = int32(NextVertex(t.Triangles[tIdx], i))
+		}
+		for k := len(it); k < maxDegree; k++ {
+			row[k] = int32(padValue)
+		}
+	}
+	return out, degree, nil
+}
+
 // sortTriangleVerticesCCW sorts triangle vertices in CCW order.
 func sortTriangleVerticesCCW(t *[3]int, v s2.PointVector) {
-	a, b, c := v[t[0]], v[t[1]], v[t[2]]
-	norm := b.Sub(a.Vector).Cross(c.Sub(a.Vector))
-	if norm.Dot(a.Vector) < 0 {
+	if triangleOrientation(*t, v) < 0 {
 		t[1], t[2] = t[2], t[1]
 	}
 }
 
+// triangleOrientation returns the determinant of the triangle vertices, which is positive for
+// CCW triangles and whose magnitude is the distance of the triangle plane from the origin scaled
+// by twice the triangle area.
+func triangleOrientation(t [3]int, v s2.PointVector) float64 {
+	a, b, c := v[t[0]], v[t[1]], v[t[2]]
+	norm := b.Sub(a.Vector).Cross(c.Sub(a.Vector))
+	return norm.Dot(a.Vector)
+}
+
 // sortIncidentTriangleIndicesCCW sorts incident triangle indices in CCW order.
 func sortIncidentTriangleIndicesCCW(vIdx int, incidentTris []int, tris [][3]int) {
 	n := len(incidentTris)
@@ -5,12 +5,20 @@
 package s2delaunay
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"math"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/2dChan/s2voronoi/internal/fixtures"
 	"github.com/2dChan/s2voronoi/utils"
 	"github.com/golang/geo/r3"
+	"github.com/golang/geo/s1"
 	"github.com/golang/geo/s2"
 	"github.com/google/go-cmp/cmp"
 	"github.com/markus-wa/quickhull-go/v2"
@@ -30,7 +38,7 @@ func TestWithEps(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			opts := &TriangulationOptions{Eps: defaultEps}
+			opts := &TriangulationOptions{Eps: DefaultEps}
 			opt := WithEps(tt.eps)
 			err := opt(opts)
 			if (err != nil) != tt.wantErr {
@@ -56,7 +64,7 @@ func TestNewTriangulation_WithEps(t *testing.T) {
 		eps     float64
 		wantErr bool
 	}{
-		{"eps default", defaultEps, false},
+		{"eps default", DefaultEps, false},
 		{"eps positive", 0.01, false},
 		{"eps large", 1, true},
 		{"eps zero", 0, true},
@@ -76,6 +84,132 @@ func TestNewTriangulation_WithEps(t *testing.T) {
 	}
 }
 
+func TestNewTriangulation_WithProgress(t *testing.T) {
+	const n = 1000
+	var phases []string
+	last := map[string]int{}
+	progress := func(phase string, done, total int) {
+		if len(phases) == 0 || phases[len(phases)-1] != phase {
+			phases = append(phases, phase)
+			last[phase] = -1
+		}
+		if done <= last[phase] || done > total || total != n {
+			t.Errorf("progress(%s, %d, %d) after %d, want increasing up to %d", phase, done, total,
+				last[phase], n)
+		}
+		last[phase] = done
+	}
+	if _, err := NewTriangulation(utils.GenerateRandomPoints(n, 0),
+		WithProgress(progress)); err != nil {
+		t.Fatalf("NewTriangulation(...) error = %v, want nil", err)
+	}
+	if want := []string{PhaseHull, PhaseSorting}; !slices.Equal(phases, want) {
+		t.Errorf("phases = %v, want %v", phases, want)
+	}
+	for phase, done := range last {
+		if done != n {
+			t.Errorf("phase %s ends at %d, want %d", phase, done, n)
+		}
+	}
+
+	if _, err := NewBuilder(WithProgress(nil)); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("NewBuilder(WithProgress(nil)) error = %v, want %v", err, ErrInvalidOption)
+	}
+}
+
+func TestNewTriangulation_NormalizeInput(t *testing.T) {
+	points := utils.GenerateRandomPoints(100, 0)
+	want, err := NewTriangulation(slices.Clone(points))
+	if err != nil {
+		t.Fatalf("NewTriangulation(...) error = %v, want nil", err)
+	}
+	scaled := slices.Clone(points)
+	for i := 5; i < len(scaled); i += 2 {
+		scaled[i] = s2.Point{Vector: scaled[i].Mul(1 + 1e-6)}
+	}
+
+	_, err = NewTriangulation(slices.Clone(scaled))
+	if !errors.Is(err, ErrNotUnitLength) || !strings.Contains(err.Error(), "vertex 5 ") {
+		t.Errorf("NewTriangulation(scaled) error = %v, want %v for vertex 5", err, ErrNotUnitLength)
+	}
+
+	dt, err := NewTriangulation(scaled, WithNormalizeInput())
+	if err != nil {
+		t.Fatalf("NewTriangulation(scaled, WithNormalizeInput()) error = %v, want nil", err)
+	}
+	for i, v := range dt.Vertices {
+		if !v.IsUnit() || v.Distance(points[i]) > 1e-15 {
+			t.Errorf("dt.Vertices[%d] = %v, want %v", i, v, points[i])
+		}
+	}
+	if diff := cmp.Diff(want.Triangles, dt.Triangles); diff != "" {
+		t.Errorf("dt.Triangles mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, v := range []r3.Vector{{}, {X: math.Inf(1)}, {X: math.NaN()}} {
+		invalid := slices.Clone(points)
+		invalid[7] = s2.Point{Vector: v}
+		if _, err := NewTriangulation(invalid, WithNormalizeInput()); !errors.Is(err,
+			ErrNotUnitLength) {
+			t.Errorf("NewTriangulation(%v, WithNormalizeInput()) error = %v, want %v", v, err,
+				ErrNotUnitLength)
+		}
+		if invalid[0] != points[0] {
+			t.Errorf("NewTriangulation(%v, WithNormalizeInput()) modified the vertices on error", v)
+		}
+	}
+}
+
+func TestNewTriangulation_NonFinite(t *testing.T) {
+	tests := []struct {
+		name   string
+		vertex r3.Vector
+		want   string
+	}{
+		{"NaN X", r3.Vector{X: math.NaN(), Z: 1}, "vertex 7 has non-finite X coordinate NaN"},
+		{"NaN Y", r3.Vector{Y: math.NaN(), Z: 1}, "vertex 7 has non-finite Y coordinate NaN"},
+		{"NaN Z", r3.Vector{Y: 1, Z: math.NaN()}, "vertex 7 has non-finite Z coordinate NaN"},
+		{"+Inf", r3.Vector{Y: math.Inf(1)}, "vertex 7 has non-finite Y coordinate +Inf"},
+		{"-Inf", r3.Vector{Z: math.Inf(-1)}, "vertex 7 has non-finite Z coordinate -Inf"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, opts := range [][]TriangulationOption{nil, {WithNormalizeInput()}} {
+				vertices := utils.GenerateRandomPoints(100, 0)
+				vertices[7] = s2.Point{Vector: tt.vertex}
+				_, err := NewTriangulation(vertices, opts...)
+				if !errors.Is(err, ErrNotUnitLength) || !strings.Contains(err.Error(), tt.want) {
+					t.Errorf("NewTriangulation(...) error = %v, want %v naming %q", err,
+						ErrNotUnitLength, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewTriangulation_UncheckedInput(t *testing.T) {
+	points := utils.GenerateRandomPoints(100, 0)
+	want, err := NewTriangulation(slices.Clone(points))
+	if err != nil {
+		t.Fatalf("NewTriangulation(...) error = %v, want nil", err)
+	}
+	dt, err := NewTriangulation(slices.Clone(points), WithUncheckedInput())
+	if err != nil {
+		t.Fatalf("NewTriangulation(..., WithUncheckedInput()) error = %v, want nil", err)
+	}
+	if diff := cmp.Diff(want.Triangles, dt.Triangles); diff != "" {
+		t.Errorf("dt.Triangles mismatch (-want +got):\n%s", diff)
+	}
+
+	// Slightly scaled vertices are not rejected without the check.
+	points[3] = s2.Point{Vector: points[3].Mul(1 + 1e-6)}
+	if _, err := NewTriangulation(points, WithUncheckedInput()); errors.Is(err,
+		ErrNotUnitLength) {
+		t.Errorf("NewTriangulation(..., WithUncheckedInput()) error = %v, want no %v", err,
+			ErrNotUnitLength)
+	}
+}
+
 func TestNewTriangulation_DegenerateInput(t *testing.T) {
 	vertices := s2.PointVector{
 		s2.PointFromCoords(1, 0, 0),
@@ -87,12 +221,76 @@ func TestNewTriangulation_DegenerateInput(t *testing.T) {
 	}
 }
 
+func TestNewTriangulation_Coplanar(t *testing.T) {
+	tilt := r3.Vector{X: 1, Y: -2, Z: 3}.Normalize()
+	u := tilt.Ortho()
+	w := tilt.Cross(u)
+	tests := []struct {
+		name   string
+		normal r3.Vector
+		point  func(theta float64) s2.Point
+	}{
+		{
+			name:   "equator",
+			normal: r3.Vector{X: 0, Y: 0, Z: 1},
+			point: func(theta float64) s2.Point {
+				return s2.PointFromLatLng(s2.LatLng{Lng: s1.Angle(theta)})
+			},
+		},
+		{
+			name:   "tilted great circle",
+			normal: tilt,
+			point: func(theta float64) s2.Point {
+				return s2.Point{Vector: u.Mul(math.Cos(theta)).Add(w.Mul(math.Sin(theta)))}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			random := rand.New(rand.NewSource(0))
+			vertices := make(s2.PointVector, 20)
+			for i := range vertices {
+				vertices[i] = tt.point(random.Float64() * 2 * math.Pi)
+			}
+
+			_, err := NewTriangulation(vertices)
+			var cerr *CoplanarVerticesError
+			if !errors.As(err, &cerr) {
+				t.Fatalf("NewTriangulation(...) error = %v, want *CoplanarVerticesError", err)
+			}
+			if math.Abs(math.Abs(cerr.Normal.Dot(tt.normal))-1) > 1e-9 {
+				t.Errorf("NewTriangulation(...) error Normal = %v, want ±%v", cerr.Normal, tt.normal)
+			}
+			if cerr.Offset > 1e-9 {
+				t.Errorf("NewTriangulation(...) error Offset = %v, want 0", cerr.Offset)
+			}
+		})
+	}
+}
+
+func TestNewTriangulation_DegenerateTriangle(t *testing.T) {
+	// The face spanned by the three equatorial vertices passes the origin at about 1e-8.
+	vertices := s2.PointVector{
+		s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)),
+		s2.PointFromLatLng(s2.LatLngFromDegrees(-1e-6, 120)),
+		s2.PointFromLatLng(s2.LatLngFromDegrees(0, 240)),
+		s2.PointFromCoords(0, 0, 1),
+	}
+
+	if _, err := NewTriangulation(vertices); err != nil {
+		t.Errorf("NewTriangulation(...) error = %v, want nil", err)
+	}
+	if _, err := NewTriangulation(vertices, WithEps(1e-6)); err == nil {
+		t.Errorf("NewTriangulation(..., WithEps(1e-6)) error = nil, want non-nil")
+	}
+}
+
 func TestNewTriangulation_VerticesOnSphere(t *testing.T) {
 	dt := mustNewTriangulation(t, 100)
 
 	for i, p := range dt.Vertices {
 		norm := p.Norm()
-		if math.Abs(norm-1.0) > defaultEps {
+		if math.Abs(norm-1.0) > DefaultEps {
 			t.Errorf(
 				"dt.Vertices[%d] norm = %v, want ~1.0", i,
 				norm)
@@ -131,6 +329,19 @@ func TestNewTriangulation_VerifyIncidentTrianglesSorted(t *testing.T) {
 	}
 }
 
+func TestTriangulation_Eps(t *testing.T) {
+	vertices := utils.GenerateRandomPoints(10, 0)
+	for _, eps := range []float64{DefaultEps, 1e-9} {
+		dt, err := NewTriangulation(vertices, WithEps(eps))
+		if err != nil {
+			t.Fatalf("NewTriangulation(..., WithEps(%v)) error = %v, want nil", eps, err)
+		}
+		if got := dt.Eps(); got != eps {
+			t.Errorf("dt.Eps() = %v, want %v", got, eps)
+		}
+	}
+}
+
 func TestTriangulation_IncidentTriangles(t *testing.T) {
 	assertPanic := func(dt *Triangulation, in int) {
 		defer func() {
@@ -298,6 +509,678 @@ func TestNextVertex(t *testing.T) {
 	assertPanic(tri, 4)
 }
 
+func TestTriangulation_HasEdge(t *testing.T) {
+	dt := mustNewTriangulation(t, 100)
+	for a := range dt.Vertices {
+		for b := range dt.Vertices {
+			want := false
+			for _, tIdx := range dt.IncidentTriangles(a) {
+				tri := dt.Triangles[tIdx]
+				want = want || (a != b && (tri[0] == b || tri[1] == b || tri[2] == b))
+			}
+			if got := dt.HasEdge(a, b); got != want {
+				t.Errorf("dt.HasEdge(%d, %d) = %v, want %v", a, b, got, want)
+			}
+		}
+	}
+}
+
+func TestTriangulation_HasEdge_Panic(t *testing.T) {
+	dt := mustNewTriangulation(t, 10)
+	for _, in := range []int{-1, len(dt.Vertices)} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("dt.HasEdge(0, %d) did not panic, want panic", in)
+				}
+			}()
+			dt.HasEdge(0, in)
+		}()
+	}
+}
+
+func TestTriangulation_PaddedNeighbors(t *testing.T) {
+	dt := mustNewTriangulation(t, 100)
+	got, degree, err := dt.PaddedNeighbors(0, -1)
+	if err != nil {
+		t.Fatalf("dt.PaddedNeighbors(0, -1) error = %v, want nil", err)
+	}
+
+	wantDegree := 0
+	for i := range dt.Vertices {
+		it := dt.IncidentTriangles(i)
+		wantDegree = max(wantDegree, len(it))
+		row := got[i*degree : (i+1)*degree]
+		for k := range row {
+			want := int32(-1)
+			if k < len(it) {
+				want = int32(NextVertex(dt.Triangles[it[k]], i))
+			}
+			if row[k] != want {
+				t.Errorf("dt.PaddedNeighbors(0, -1) row %d[%d] = %d, want %d", i, k, row[k], want)
+			}
+		}
+	}
+	if degree != wantDegree {
+		t.Errorf("dt.PaddedNeighbors(0, -1) degree = %d, want %d", degree, wantDegree)
+	}
+
+	if _, _, err := dt.PaddedNeighbors(degree-1, -1); err == nil {
+		t.Errorf("dt.PaddedNeighbors(%d, -1) error = nil, want non-nil", degree-1)
+	}
+}
+
+func TestTriangulation_TriangleCapBounds(t *testing.T) {
+	dt := mustNewTriangulation(t, 100)
+	caps := dt.TriangleCapBounds()
+	if len(caps) != len(dt.Triangles) {
+		t.Fatalf("len(dt.TriangleCapBounds()) = %v, want %v", len(caps), len(dt.Triangles))
+	}
+	for i, cp := range caps {
+		if cp.Radius() >= s1.Angle(math.Pi/2) {
+			t.Errorf("dt.TriangleCapBounds()[%d].Radius() = %v, want < π/2", i, cp.Radius())
+		}
+		a, b, c := dt.TriangleVertices(i)
+		for _, e := range [][2]s2.Point{{a, b}, {b, c}, {c, a}} {
+			for k := range 17 {
+				p := s2.Interpolate(float64(k)/16, e[0], e[1])
+				if !cp.ContainsPoint(p) {
+					t.Errorf("dt.TriangleCapBounds()[%d] does not contain %v", i, p)
+				}
+			}
+		}
+	}
+}
+
+func TestTriangulation_TrianglesIntersectingRegion(t *testing.T) {
+	dt := mustNewTriangulation(t, 200)
+	for _, p := range utils.GenerateRandomPoints(100, 1) {
+		got := dt.TrianglesIntersectingRegion(p)
+		found := false
+		for _, tIdx := range got {
+			a, b, c := dt.TriangleVertices(tIdx)
+			found = found || (s2.Sign(a, b, p) && s2.Sign(b, c, p) && s2.Sign(c, a, p))
+		}
+		if !found {
+			t.Errorf("dt.TrianglesIntersectingRegion(%v) = %v, missing containing triangle", p, got)
+		}
+	}
+}
+
+func TestTriangulation_Validate(t *testing.T) {
+	dt := mustNewTriangulation(t, 100)
+	if err := dt.Validate(); err != nil {
+		t.Fatalf("dt.Validate() error = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name    string
+		corrupt func(dt *Triangulation)
+	}{
+		{"vertex out of range", func(dt *Triangulation) { dt.Triangles[0][0] = len(dt.Vertices) }},
+		{"reversed triangle", func(dt *Triangulation) {
+			dt.Triangles[0][1], dt.Triangles[0][2] = dt.Triangles[0][2], dt.Triangles[0][1]
+		}},
+		{"missing triangle", func(dt *Triangulation) { dt.Triangles = dt.Triangles[1:] }},
+		{"unsorted incident triangles", func(dt *Triangulation) {
+			it := dt.IncidentTriangles(0)
+			it[0], it[1] = it[1], it[0]
+		}},
+		{"wrong offsets", func(dt *Triangulation) { dt.IncidentTriangleOffsets[1]++ }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dt := mustNewTriangulation(t, 100)
+			tt.corrupt(dt)
+			if err := dt.Validate(); err == nil {
+				t.Errorf("dt.Validate() error = nil, want non-nil")
+			}
+		})
+	}
+}
+
+func TestNewTriangulation_Fixtures(t *testing.T) {
+	// wantErr is the sentinel of the error of every fixture, nil for a valid triangulation.
+	wantErr := map[string]error{
+		"antimeridian":        nil,
+		"antipodal-pairs":     nil,
+		"cocircular-rings":    nil,
+		"equator-and-pole":    ErrDegenerateInput,
+		"equatorial-coplanar": ErrDegenerateInput,
+		"five-points":         nil,
+		"hemispheric-cluster": ErrDegenerateInput,
+		"near-duplicates":     ErrDuplicateVertices,
+		"octahedron":          nil,
+	}
+	for _, name := range fixtures.Names() {
+		t.Run(name, func(t *testing.T) {
+			want, ok := wantErr[name]
+			if !ok {
+				t.Fatalf("fixture %q has no expected result", name)
+			}
+			dt, err := NewTriangulation(fixtures.Load(name))
+			if !errors.Is(err, want) || (want == nil) != (err == nil) {
+				t.Fatalf("NewTriangulation(...) error = %v, want %v", err, want)
+			}
+			if err != nil {
+				if dt != nil {
+					t.Errorf("NewTriangulation(...) = %v, %v, want nil result with error", dt, err)
+				}
+				return
+			}
+			if err := dt.Validate(); err != nil {
+				t.Errorf("NewTriangulation(...).Validate() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestNewTriangulation_Hemisphere(t *testing.T) {
+	vertices := fixtures.Load("hemispheric-cluster")
+	if _, err := NewTriangulation(vertices); err == nil {
+		t.Errorf("NewTriangulation(...) error = nil, want non-nil for hemispheric vertices")
+	}
+}
+
+func TestNewTriangulation_ErrorKinds(t *testing.T) {
+	duplicated := append(fixtures.Load("octahedron"), fixtures.Load("octahedron")[2])
+	tests := []struct {
+		name     string
+		vertices s2.PointVector
+		opts     []TriangulationOption
+		want     error
+	}{
+		{"insufficient vertices", fixtures.Load("octahedron")[:3], nil, ErrInsufficientVertices},
+		{"invalid eps", fixtures.Load("octahedron"), []TriangulationOption{WithEps(-1)},
+			ErrInvalidOption},
+		{"coplanar", fixtures.Load("equatorial-coplanar"), nil, ErrDegenerateInput},
+		{"hemisphere", fixtures.Load("hemispheric-cluster"), nil, ErrDegenerateInput},
+		{"degenerate triangle", fixtures.Load("equator-and-pole"), nil, ErrDegenerateInput},
+		{"near duplicates", fixtures.Load("near-duplicates"), nil, ErrDuplicateVertices},
+		{"duplicate", duplicated, nil, ErrDuplicateVertices},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTriangulation(tt.vertices, tt.opts...)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("NewTriangulation(...) error = %v, want errors.Is(err, %v)", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTriangulation_DuplicateVerticesError(t *testing.T) {
+	vertices := append(fixtures.Load("octahedron"), fixtures.Load("octahedron")[2])
+	_, err := NewTriangulation(vertices)
+	var derr *DuplicateVerticesError
+	if !errors.As(err, &derr) {
+		t.Fatalf("NewTriangulation(...) error = %v, want *DuplicateVerticesError", err)
+	}
+	if want := []int{6}; !slices.Equal(derr.Indices, want) {
+		t.Errorf("NewTriangulation(...) error Indices = %v, want %v", derr.Indices, want)
+	}
+	if want := [][2]int{{2, 6}}; !cmp.Equal(derr.Pairs, want) {
+		t.Errorf("NewTriangulation(...) error Pairs = %v, want %v", derr.Pairs, want)
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	a, b := s2.PointFromCoords(1, 2, 3), s2.PointFromCoords(-3, 1, 2)
+	c := s2.PointFromCoords(0, 0, 1)
+	tests := []struct {
+		name     string
+		vertices s2.PointVector
+		want     [][2]int
+	}{
+		{"none", s2.PointVector{a, b, c}, nil},
+		{"pair", s2.PointVector{a, b, c, b}, [][2]int{{1, 3}}},
+		{"triple", s2.PointVector{a, b, a, c, a}, [][2]int{{0, 2}, {0, 4}}},
+		{"two pairs", s2.PointVector{c, a, b, b, a}, [][2]int{{2, 3}, {1, 4}}},
+		{"negative zero", s2.PointVector{c, {Vector: r3.Vector{X: math.Copysign(0, -1), Z: 1}}},
+			[][2]int{{0, 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findDuplicates(tt.vertices); !cmp.Equal(got, tt.want) {
+				t.Errorf("findDuplicates(...) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	points := utils.GenerateRandomPoints(10000, 0)
+	if got := findDuplicates(points); got != nil {
+		t.Errorf("findDuplicates(random) = %v, want nil", got)
+	}
+}
+
+func TestFindNearDuplicates(t *testing.T) {
+	const eps = 1e-9
+	random := rand.New(rand.NewSource(0))
+	points := utils.GenerateRandomPoints(2000, 0)
+	// Cell corners and face edges, whose neighbors lie on other faces.
+	points = append(points, s2.PointFromCoords(1, 1, 1), s2.PointFromCoords(1, 0, 1),
+		s2.PointFromCoords(-1, 1, -1))
+	for k := range 500 {
+		p := points[random.Intn(len(points))]
+		offset := s2.Ortho(p).Mul(eps * (0.2 + 1.6*random.Float64()))
+		if k%2 == 0 {
+			offset = p.Cross(offset)
+		}
+		points = append(points, s2.Point{Vector: p.Add(offset).Normalize()})
+	}
+	random.Shuffle(len(points), func(i, j int) { points[i], points[j] = points[j], points[i] })
+
+	var want [][2]int
+	for j := range points {
+		for i := range j {
+			if points[j].Sub(points[i].Vector).Norm() <= eps {
+				want = append(want, [2]int{i, j})
+			}
+		}
+	}
+	if len(want) == 0 {
+		t.Fatalf("no near duplicates generated")
+	}
+	if got := findNearDuplicates(points, eps); !cmp.Equal(got, want) {
+		t.Errorf("findNearDuplicates(...) = %v, want %v", got, want)
+	}
+}
+
+func TestNewTriangulation_NearDuplicateCheck(t *testing.T) {
+	vertices := fixtures.Load("near-duplicates")
+	// Without the check, the copies are only dropped by the hull.
+	_, err := NewTriangulation(vertices)
+	var derr *DuplicateVerticesError
+	if !errors.As(err, &derr) || derr.Pairs != nil {
+		t.Errorf("NewTriangulation(...) error = %v, want *DuplicateVerticesError without pairs",
+			err)
+	}
+
+	_, err = NewTriangulation(vertices, WithNearDuplicateCheck())
+	if !errors.As(err, &derr) {
+		t.Fatalf("NewTriangulation(..., WithNearDuplicateCheck()) error = %v, want "+
+			"*DuplicateVerticesError", err)
+	}
+	// Every point of the fixture is followed by its perturbed copy.
+	var want [][2]int
+	var wantIndices []int
+	for i := 0; i < len(vertices); i += 2 {
+		want = append(want, [2]int{i, i + 1})
+		wantIndices = append(wantIndices, i+1)
+	}
+	if !cmp.Equal(derr.Pairs, want) {
+		t.Errorf("NewTriangulation(...) error Pairs = %v, want %v", derr.Pairs, want)
+	}
+	if !slices.Equal(derr.Indices, wantIndices) {
+		t.Errorf("NewTriangulation(...) error Indices = %v, want %v", derr.Indices, wantIndices)
+	}
+
+	if _, err := NewTriangulation(utils.GenerateRandomPoints(1000, 0),
+		WithNearDuplicateCheck()); err != nil {
+		t.Errorf("NewTriangulation(random, WithNearDuplicateCheck()) error = %v, want nil", err)
+	}
+}
+
+func TestTriangulation_RebuildIncidence(t *testing.T) {
+	want := mustNewTriangulation(t, 100)
+	dt := mustNewTriangulation(t, 100)
+	for i := range dt.Triangles {
+		if i%2 == 0 {
+			dt.Triangles[i][1], dt.Triangles[i][2] = dt.Triangles[i][2], dt.Triangles[i][1]
+		}
+	}
+	random := rand.New(rand.NewSource(0))
+	random.Shuffle(len(dt.IncidentTriangleIndices), func(i, j int) {
+		idx := dt.IncidentTriangleIndices
+		idx[i], idx[j] = idx[j], idx[i]
+	})
+	dt.IncidentTriangleOffsets = nil
+
+	if err := dt.RebuildIncidence(); err != nil {
+		t.Fatalf("dt.RebuildIncidence() error = %v, want nil", err)
+	}
+	if err := dt.Validate(); err != nil {
+		t.Errorf("dt.Validate() error = %v, want nil", err)
+	}
+	if diff := cmp.Diff(want.Triangles, dt.Triangles); diff != "" {
+		t.Errorf("dt.Triangles mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want.IncidentTriangleIndices, dt.IncidentTriangleIndices); diff != "" {
+		t.Errorf("dt.IncidentTriangleIndices mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want.IncidentTriangleOffsets, dt.IncidentTriangleOffsets); diff != "" {
+		t.Errorf("dt.IncidentTriangleOffsets mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTriangulation_RebuildIncidence_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(dt *Triangulation)
+	}{
+		{"vertex out of range", func(dt *Triangulation) { dt.Triangles[0][0] = -1 }},
+		{"missing triangle", func(dt *Triangulation) { dt.Triangles = dt.Triangles[1:] }},
+		{"duplicate triangle", func(dt *Triangulation) { dt.Triangles[0] = dt.Triangles[1] }},
+		{"degenerate triangle", func(dt *Triangulation) { dt.Triangles[0][1] = dt.Triangles[0][0] }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dt := mustNewTriangulation(t, 100)
+			tt.corrupt(dt)
+			offsets := dt.IncidentTriangleOffsets
+			if err := dt.RebuildIncidence(); err == nil {
+				t.Errorf("dt.RebuildIncidence() error = nil, want non-nil")
+			}
+			if &dt.IncidentTriangleOffsets[0] != &offsets[0] {
+				t.Errorf("dt.RebuildIncidence() modified the triangulation on error")
+			}
+		})
+	}
+}
+
+func TestNewTriangulationFromTriangles(t *testing.T) {
+	want := mustNewTriangulation(t, 100)
+	triangles := make([][3]int, want.NumTriangles())
+	for i, tri := range want.Triangles {
+		// Reflected triangles are sorted CCW again.
+		triangles[i] = [3]int{tri[0], tri[2], tri[1]}
+	}
+
+	dt, err := NewTriangulationFromTriangles(want.Vertices, triangles, WithEps(1e-10))
+	if err != nil {
+		t.Fatalf("NewTriangulationFromTriangles(...) error = %v, want nil", err)
+	}
+	if err := dt.Validate(); err != nil {
+		t.Errorf("dt.Validate() error = %v, want nil", err)
+	}
+	if got := dt.Eps(); got != 1e-10 {
+		t.Errorf("dt.Eps() = %v, want %v", got, 1e-10)
+	}
+	if diff := cmp.Diff(want.Triangles, dt.Triangles); diff != "" {
+		t.Errorf("dt.Triangles mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want.IncidentTriangleIndices, dt.IncidentTriangleIndices); diff != "" {
+		t.Errorf("dt.IncidentTriangleIndices mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := NewTriangulationFromTriangles(want.Vertices, triangles[1:]); err == nil {
+		t.Errorf("NewTriangulationFromTriangles(...) with a missing triangle error = nil, want non-nil")
+	}
+	if _, err := NewTriangulationFromTriangles(want.Vertices, triangles, WithEps(0)); err == nil {
+		t.Errorf("NewTriangulationFromTriangles(..., WithEps(0)) error = nil, want non-nil")
+	}
+}
+
+func TestBuilder_Triangulate(t *testing.T) {
+	b, err := NewBuilder(WithEps(1e-10))
+	if err != nil {
+		t.Fatalf("NewBuilder(WithEps(1e-10)) error = %v, want nil", err)
+	}
+	dt := new(Triangulation)
+	var triangles [][3]int
+	for k, n := range []int{100, 100, 300, 50} {
+		points := utils.GenerateRandomPoints(n, int64(k))
+		if err := b.Triangulate(dt, points); err != nil {
+			t.Fatalf("b.Triangulate(dt, %d points) error = %v, want nil", n, err)
+		}
+		want, err := NewTriangulation(points, WithEps(1e-10))
+		if err != nil {
+			t.Fatalf("NewTriangulation(...) error = %v, want nil", err)
+		}
+		if diff := cmp.Diff(want.Triangles, dt.Triangles); diff != "" {
+			t.Errorf("%d points: dt.Triangles mismatch (-want +got):\n%s", n, diff)
+		}
+		if diff := cmp.Diff(want.IncidentTriangleIndices, dt.IncidentTriangleIndices); diff != "" {
+			t.Errorf("%d points: dt.IncidentTriangleIndices mismatch (-want +got):\n%s", n, diff)
+		}
+		if diff := cmp.Diff(want.IncidentTriangleOffsets, dt.IncidentTriangleOffsets); diff != "" {
+			t.Errorf("%d points: dt.IncidentTriangleOffsets mismatch (-want +got):\n%s", n, diff)
+		}
+		if got := dt.Eps(); got != 1e-10 {
+			t.Errorf("dt.Eps() = %v, want %v", got, 1e-10)
+		}
+		// The second set has the same size, so the triangles are stored in place.
+		if k == 1 && &dt.Triangles[0] != &triangles[0] {
+			t.Errorf("b.Triangulate(dt, ...) reallocated dt.Triangles, want reused")
+		}
+		triangles = dt.Triangles
+	}
+
+	if err := b.Triangulate(dt, utils.GenerateRandomPoints(3, 0)); err == nil {
+		t.Errorf("b.Triangulate(dt, 3 points) error = nil, want non-nil")
+	}
+	if _, err := NewBuilder(WithEps(0)); err == nil {
+		t.Errorf("NewBuilder(WithEps(0)) error = nil, want non-nil")
+	}
+}
+
+func TestBuilder_TriangulateContext(t *testing.T) {
+	b, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder() error = %v, want nil", err)
+	}
+	points := utils.GenerateRandomPoints(100, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.TriangulateContext(ctx, new(Triangulation), points); !errors.Is(err,
+		context.Canceled) {
+		t.Errorf("b.TriangulateContext(canceled, ...) error = %v, want %v", err, context.Canceled)
+	}
+
+	dt := new(Triangulation)
+	if err := b.TriangulateContext(context.Background(), dt, points); err != nil {
+		t.Fatalf("b.TriangulateContext(...) error = %v, want nil", err)
+	}
+	if err := dt.Validate(); err != nil {
+		t.Errorf("dt.Validate() error = %v, want nil", err)
+	}
+}
+
+func TestBuilder_SetProgress(t *testing.T) {
+	var first, second int
+	b, err := NewBuilder(WithProgress(func(string, int, int) { first++ }))
+	if err != nil {
+		t.Fatalf("NewBuilder(WithProgress(...)) error = %v, want nil", err)
+	}
+	dt := new(Triangulation)
+	points := utils.GenerateRandomPoints(100, 0)
+	tests := []struct {
+		name                  string
+		progress              func(phase string, done, total int)
+		wantFirst, wantSecond bool
+	}{
+		{"replaced", func(string, int, int) { second++ }, false, true},
+		{"disabled", nil, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, second = 0, 0
+			b.SetProgress(tt.progress)
+			if err := b.Triangulate(dt, points); err != nil {
+				t.Fatalf("b.Triangulate(dt, ...) error = %v, want nil", err)
+			}
+			if (first > 0) != tt.wantFirst || (second > 0) != tt.wantSecond {
+				t.Errorf("b.Triangulate(dt, ...) made %d and %d calls, want calls %v and %v",
+					first, second, tt.wantFirst, tt.wantSecond)
+			}
+		})
+	}
+}
+
+func TestTriangulation_CompareTopology(t *testing.T) {
+	dt := mustNewTriangulation(t, 200)
+	if diff := dt.CompareTopology(mustNewTriangulation(t, 200)); diff.NumEdges() != 0 {
+		t.Errorf("dt.CompareTopology(same) = %v, want empty", diff)
+	}
+
+	// Move vertex 0 most of the way towards one of its neighbors.
+	const moved = 0
+	ring := map[int]bool{moved: true}
+	for _, tIdx := range dt.IncidentTriangles(moved) {
+		ring[NextVertex(dt.Triangles[tIdx], moved)] = true
+	}
+	target := dt.Vertices[NextVertex(dt.Triangles[dt.IncidentTriangles(moved)[0]], moved)]
+	vertices := slices.Clone(dt.Vertices)
+	vertices[moved] = s2.Interpolate(0.9, vertices[moved], target)
+	perturbed, err := NewTriangulation(vertices)
+	if err != nil {
+		t.Fatalf("NewTriangulation(...) error = %v, want nil", err)
+	}
+
+	diff := dt.CompareTopology(perturbed)
+	if diff.NumEdges() == 0 {
+		t.Fatalf("dt.CompareTopology(perturbed) = %v, want differing edges", diff)
+	}
+	if len(diff.Removed) != len(diff.Added) {
+		t.Errorf("dt.CompareTopology(perturbed) removed %d edges, added %d, want equal",
+			len(diff.Removed), len(diff.Added))
+	}
+	for _, e := range slices.Concat(diff.Removed, diff.Added) {
+		if e[0] >= e[1] {
+			t.Errorf("dt.CompareTopology(perturbed) edge %v is not canonical", e)
+		}
+		if !ring[e[0]] && !ring[e[1]] {
+			t.Errorf("dt.CompareTopology(perturbed) edge %v is not local to vertex %d", e, moved)
+		}
+	}
+	for _, e := range diff.Removed {
+		if !dt.HasEdge(e[0], e[1]) || perturbed.HasEdge(e[0], e[1]) {
+			t.Errorf("dt.CompareTopology(perturbed) removed edge %v is wrong", e)
+		}
+	}
+	for _, e := range diff.Added {
+		if dt.HasEdge(e[0], e[1]) || !perturbed.HasEdge(e[0], e[1]) {
+			t.Errorf("dt.CompareTopology(perturbed) added edge %v is wrong", e)
+		}
+	}
+}
+
+func TestTriangulation_Canonicalize(t *testing.T) {
+	want := mustNewTriangulation(t, 100)
+	want.Canonicalize()
+	if err := want.Validate(); err != nil {
+		t.Fatalf("want.Validate() error = %v, want nil", err)
+	}
+	for i, tri := range want.Triangles {
+		if tri[0] != slices.Min(tri[:]) {
+			t.Errorf("want.Triangles[%d] = %v, want to start with the smallest index", i, tri)
+		}
+	}
+	for v := range want.NumVertices() {
+		if inc := want.IncidentTriangles(v); inc[0] != slices.Min(inc) {
+			t.Errorf("want.IncidentTriangles(%d) = %v, want to start with the smallest index", v,
+				inc)
+		}
+	}
+
+	dt := mustNewTriangulation(t, 100)
+	for i, tri := range dt.Triangles {
+		k := i % 3
+		dt.Triangles[i] = [3]int{tri[k], tri[(k+1)%3], tri[(k+2)%3]}
+	}
+	for v := range dt.NumVertices() {
+		inc := dt.IncidentTriangles(v)
+		k := v % len(inc)
+		slices.Reverse(inc[:k])
+		slices.Reverse(inc[k:])
+		slices.Reverse(inc)
+	}
+	dt.Canonicalize()
+	if diff := cmp.Diff(want.Triangles, dt.Triangles); diff != "" {
+		t.Errorf("dt.Triangles mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want.IncidentTriangleIndices, dt.IncidentTriangleIndices); diff != "" {
+		t.Errorf("dt.IncidentTriangleIndices mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTriangulation_CompareTopology_Panic(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("dt.CompareTopology(...) did not panic, want panic")
+		}
+	}()
+	mustNewTriangulation(t, 100).CompareTopology(mustNewTriangulation(t, 50))
+}
+
+func TestTriangulation_MemoryFootprint(t *testing.T) {
+	if strconv.IntSize != 64 {
+		t.Skip("sizes are computed for 64-bit ints")
+	}
+
+	// 100 vertices give 196 triangles.
+	dt := mustNewTriangulation(t, 100)
+	want := MemoryStats{
+		Vertices:                100 * 24,
+		Triangles:               196 * 24,
+		IncidentTriangleIndices: 588 * 8,
+		IncidentTriangleOffsets: 101 * 8,
+	}
+	want.Total = want.Vertices + want.Triangles + want.IncidentTriangleIndices +
+		want.IncidentTriangleOffsets
+	if diff := cmp.Diff(want, dt.MemoryFootprint()); diff != "" {
+		t.Errorf("dt.MemoryFootprint() mismatch (-want +got):\n%s", diff)
+	}
+
+	dt.HasEdge(0, 1)
+	want.Caches = 294 * 8
+	want.Total += want.Caches
+	if diff := cmp.Diff(want, dt.MemoryFootprint()); diff != "" {
+		t.Errorf("dt.MemoryFootprint() after HasEdge mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTriangulation_Counts(t *testing.T) {
+	for _, n := range []int{4, 10, 100} {
+		dt := mustNewTriangulation(t, n)
+		if got := dt.NumVertices(); got != n {
+			t.Errorf("dt.NumVertices() = %v, want %v", got, n)
+		}
+		if got, want := dt.NumTriangles(), 2*(n-2); got != want {
+			t.Errorf("dt.NumTriangles() = %v, want %v", got, want)
+		}
+		if got, want := dt.NumEdges(), 3*(n-2); got != want {
+			t.Errorf("dt.NumEdges() = %v, want %v", got, want)
+		}
+		if err := dt.CheckEulerFormula(); err != nil {
+			t.Errorf("dt.CheckEulerFormula() error = %v, want nil", err)
+		}
+	}
+}
+
+func TestTriangulation_Counts_Mutated(t *testing.T) {
+	// The edits are not followed by RebuildIncidence, and the edge set is cached beforehand.
+	dt := mustNewTriangulation(t, 100)
+	dt.HasEdge(0, 1)
+	dt.Triangles = dt.Triangles[:len(dt.Triangles)-1]
+
+	if got, want := dt.NumTriangles(), 195; got != want {
+		t.Errorf("dt.NumTriangles() = %v, want %v", got, want)
+	}
+	// Removing a triangle keeps all of its edges, which are shared with its neighbors.
+	if got, want := dt.NumEdges(), 294; got != want {
+		t.Errorf("dt.NumEdges() = %v, want %v", got, want)
+	}
+	if err := dt.CheckEulerFormula(); err == nil {
+		t.Errorf("dt.CheckEulerFormula() error = nil, want non-nil")
+	}
+
+	// Removing all triangles around a vertex drops the edges between it and its neighbors.
+	dt = mustNewTriangulation(t, 100)
+	dt.HasEdge(0, 1)
+	it := dt.IncidentTriangles(0)
+	var kept [][3]int
+	for i, tri := range dt.Triangles {
+		if !slices.Contains(it, i) {
+			kept = append(kept, tri)
+		}
+	}
+	dt.Triangles = kept
+	if got, want := dt.NumEdges(), 294-len(it); got != want {
+		t.Errorf("dt.NumEdges() = %v, want %v", got, want)
+	}
+}
+
 // Benchmarks
 
 func BenchmarkConvexHull(b *testing.B) {
@@ -339,6 +1222,90 @@ func BenchmarkNewTriangulation(b *testing.B) {
 	}
 }
 
+func BenchmarkFindDuplicates(b *testing.B) {
+	points := utils.GenerateRandomPoints(1e+5, 0)
+	b.Run("Exact", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			findDuplicates(points)
+		}
+	})
+	b.Run("Near", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			findNearDuplicates(points, DefaultEps)
+		}
+	})
+}
+
+func BenchmarkBuilder_Triangulate(b *testing.B) {
+	points := utils.GenerateRandomPoints(10000, 0)
+	builder, err := NewBuilder()
+	if err != nil {
+		b.Fatalf("NewBuilder() error = %v, want nil", err)
+	}
+	dt := new(Triangulation)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := builder.Triangulate(dt, points); err != nil {
+			b.Fatalf("builder.Triangulate(...) error = %v, want nil", err)
+		}
+	}
+}
+
+func BenchmarkTriangulation_HasEdge(b *testing.B) {
+	points := utils.GenerateRandomPoints(1e+4, 0)
+	dt, err := NewTriangulation(points)
+	if err != nil {
+		b.Fatalf("NewTriangulation(...) error = %v, want nil", err)
+	}
+
+	b.Run("HashSet", func(b *testing.B) {
+		dt.HasEdge(0, 1)
+		i := 0
+		for b.Loop() {
+			dt.HasEdge(i%len(points), (i*7919)%len(points))
+			i++
+		}
+	})
+	b.Run("LinearScan", func(b *testing.B) {
+		i := 0
+		for b.Loop() {
+			a, c := i%len(points), (i*7919)%len(points)
+			for _, tIdx := range dt.IncidentTriangles(a) {
+				if NextVertex(dt.Triangles[tIdx], a) == c {
+					break
+				}
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkTriangulation_TrianglesIntersectingRegion(b *testing.B) {
+	points := utils.GenerateRandomPoints(1e+4, 0)
+	dt, err := NewTriangulation(points)
+	if err != nil {
+		b.Fatalf("NewTriangulation(...) error = %v, want nil", err)
+	}
+	cp := s2.CapFromCenterAngle(points[0], s1.Angle(0.05))
+
+	b.Run("Cached", func(b *testing.B) {
+		dt.TriangleCapBounds()
+		b.ResetTimer()
+		for b.Loop() {
+			dt.TrianglesIntersectingRegion(cp)
+		}
+	})
+	b.Run("Uncached", func(b *testing.B) {
+		for b.Loop() {
+			dt.invalidateCaches()
+			dt.TrianglesIntersectingRegion(cp)
+		}
+	})
+}
+
 // Helpers
 
 func mustNewTriangulation(t *testing.T, n int) *Triangulation {
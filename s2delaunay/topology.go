@@ -0,0 +1,94 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2delaunay
+
+import (
+	"fmt"
+	"slices"
+)
+
+// TopologyDiff describes the edges that differ between two triangulations of the same number
+// of vertices. Every edge is given as a pair of vertex indices with the smaller index first, and
+// both lists are sorted.
+type TopologyDiff struct {
+	// Removed are the edges of the receiver that are missing from the other triangulation.
+	Removed [][2]int
+	// Added are the edges of the other triangulation that are missing from the receiver.
+	Added [][2]int
+}
+
+// NumEdges returns the number of edges present in one triangulation but not the other.
+// Every edge flip contributes two edges.
+func (d TopologyDiff) NumEdges() int {
+	return len(d.Removed) + len(d.Added)
+}
+
+// CompareTopology compares the edge sets of the triangulation and other, which is cheap
+// compared to diffing all triangles and insensitive to triangle and vertex order within
+// triangles. Vertices are identified by index, so the triangulations are expected to be built
+// from corresponding vertices, e.g. before and after perturbing some of them.
+// It panics if the triangulations have different numbers of vertices.
+func (t *Triangulation) CompareTopology(other *Triangulation) TopologyDiff {
+	if t.NumVertices() != other.NumVertices() {
+		panic(fmt.Sprintf("s2delaunay: vertex count mismatch %d != %d",
+			t.NumVertices(), other.NumVertices()))
+	}
+
+	edges, otherEdges := t.edgeSet(), other.edgeSet()
+	return TopologyDiff{
+		Removed: edgeDifference(edges, otherEdges),
+		Added:   edgeDifference(otherEdges, edges),
+	}
+}
+
+// edgeDifference returns the sorted edges of a that are not in b.
+func edgeDifference(a, b map[uint64]struct{}) [][2]int {
+	var out [][2]int
+	for key := range a {
+		if _, ok := b[key]; !ok {
+			out = append(out, [2]int{int(key >> 32), int(uint32(key))})
+		}
+	}
+	slices.SortFunc(out, func(x, y [2]int) int {
+		if x[0] != y[0] {
+			return x[0] - y[0]
+		}
+		return x[1] - y[1]
+	})
+	return out
+}
+
+// Canonicalize rotates every triangle to start with its smallest vertex index and the incident
+// triangles of every vertex to start with the smallest triangle index, preserving the CCW order
+// of both. Triangulations of the same vertices with the same triangle numbering then have
+// identical Triangles and incidence arrays, whatever the starting points chosen when they were
+// built, e.g. for golden files or hashing.
+func (t *Triangulation) Canonicalize() {
+	for i, tri := range t.Triangles {
+		k := 0
+		if tri[1] < tri[k] {
+			k = 1
+		}
+		if tri[2] < tri[k] {
+			k = 2
+		}
+		t.Triangles[i] = [3]int{tri[k], tri[(k+1)%3], tri[(k+2)%3]}
+	}
+	for v := range len(t.IncidentTriangleOffsets) - 1 {
+		rotateToMin(t.IncidentTriangles(v))
+	}
+	t.invalidateCaches()
+}
+
+// rotateToMin rotates s in place so that it starts with its smallest element.
+func rotateToMin(s []int) {
+	if len(s) == 0 {
+		return
+	}
+	k := slices.Index(s, slices.Min(s))
+	slices.Reverse(s[:k])
+	slices.Reverse(s[k:])
+	slices.Reverse(s)
+}
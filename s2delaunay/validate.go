@@ -0,0 +1,89 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2delaunay
+
+import (
+	"fmt"
+)
+
+// Validate checks the structural invariants of the triangulation and returns an error
+// describing the first violation, or nil if the triangulation is consistent. It verifies that
+// there are 2(n-2) CCW triangles with vertex indices in range, that every edge is shared by
+// exactly two triangles traversing it in opposite directions, that every edge is locally
+// Delaunay within eps, and that the incident triangle lists match the triangles and are sorted
+// CCW around their vertex.
+func (t *Triangulation) Validate() error {
+	numVertices := t.NumVertices()
+	if want := eulerNumTriangles(numVertices); t.NumTriangles() != want {
+		return fmt.Errorf("s2delaunay: %d triangles for %d vertices, want %d",
+			t.NumTriangles(), numVertices, want)
+	}
+
+	for i, tri := range t.Triangles {
+		for _, v := range tri {
+			if v < 0 || v >= numVertices {
+				return fmt.Errorf("s2delaunay: triangle %d vertex %d out of range [0 %d)",
+					i, v, numVertices)
+			}
+		}
+		if triangleOrientation(tri, t.Vertices) <= 0 {
+			return fmt.Errorf("s2delaunay: triangle %d is not CCW", i)
+		}
+	}
+	edges, err := checkManifold(t.Triangles)
+	if err != nil {
+		return err
+	}
+	for i, tri := range t.Triangles {
+		for j := range 3 {
+			e := [2]int{tri[j], tri[(j+1)%3]}
+			other := edges[[2]int{e[1], e[0]}]
+			a, b, c := t.Vertices[e[0]], t.Vertices[e[1]], t.Vertices[tri[(j+2)%3]]
+			d := t.Vertices[NextVertex(t.Triangles[other], e[0])]
+			// d lies inside the circumcircle of abc iff it is above the plane of abc.
+			n := b.Sub(a.Vector).Cross(c.Sub(a.Vector))
+			if n.Dot(d.Sub(a.Vector)) > t.eps*n.Norm() {
+				return fmt.Errorf("s2delaunay: edge %v between triangles %d and %d is not Delaunay",
+					e, i, other)
+			}
+		}
+	}
+
+	offsets := t.IncidentTriangleOffsets
+	if len(offsets) != numVertices+1 || offsets[0] != 0 ||
+		offsets[numVertices] != len(t.IncidentTriangleIndices) ||
+		len(t.IncidentTriangleIndices) != 3*t.NumTriangles() {
+		return fmt.Errorf("s2delaunay: incident triangle offsets are inconsistent")
+	}
+	for v := range numVertices {
+		if offsets[v] > offsets[v+1] {
+			return fmt.Errorf("s2delaunay: incident triangle offsets of vertex %d decrease", v)
+		}
+	}
+	return t.checkIncidentFans()
+}
+
+// CheckEulerFormula checks that the vertex, edge and triangle counts satisfy Euler's formula
+// V - E + F = 2 for a triangulation of the sphere. The counts are taken from the current
+// Vertices and Triangles, see NumEdges, so a violation reveals missing, extra or non-manifold
+// triangles after edits, without calling RebuildIncidence first.
+func (t *Triangulation) CheckEulerFormula() error {
+	v, e, f := t.NumVertices(), t.NumEdges(), t.NumTriangles()
+	if v-e+f != 2 {
+		return fmt.Errorf("s2delaunay: V - E + F = %d - %d + %d = %d, want 2", v, e, f, v-e+f)
+	}
+	return nil
+}
+
+// eulerNumTriangles returns the number of triangles of a triangulation of the sphere with the
+// given number of vertices. By Euler's formula and 3F = 2E it is 2(V - 2), with 3(V - 2) edges.
+func eulerNumTriangles(numVertices int) int {
+	return 2 * (numVertices - 2)
+}
+
+// triangleHasVertex reports whether the triangle contains the vertex index.
+func triangleHasVertex(t [3]int, vIdx int) bool {
+	return t[0] == vIdx || t[1] == vIdx || t[2] == vIdx
+}
@@ -7,22 +7,28 @@
 package s2voronoi
 
 import (
-	"errors"
+	"context"
 	"fmt"
+	"iter"
+	"math"
+	"slices"
 
+	"github.com/2dChan/s2voronoi/internal/progress"
 	"github.com/2dChan/s2voronoi/s2delaunay"
+	"github.com/golang/geo/s1"
 	"github.com/golang/geo/s2"
 )
 
-const (
-	defaultEps = 1e-12
-)
+// DefaultEps is the numerical precision epsilon used when WithEps is not given.
+const DefaultEps = s2delaunay.DefaultEps
 
 // Diagram represents a Voronoi diagram on the S2 sphere.
 type Diagram struct {
 	// Sites are the input points on the unit sphere.
 	Sites s2.PointVector
-	// Vertices are the Voronoi vertices on the unit sphere.
+	// Vertices are the Voronoi vertices on the unit sphere. Vertex v is the circumcenter of
+	// triangle v of the dual Delaunay triangulation, see Triangulation, unless the diagram was
+	// built with WithVertexMerging.
 	Vertices s2.PointVector
 
 	// CellVertices contains indices of vertices for each cell, sorted in CCW order,
@@ -36,11 +42,44 @@ type Diagram struct {
 
 	// eps is the numerical precision epsilon used in Voronoi diagram computations.
 	eps float64
+	// orderIndependent reports whether the diagram was built with WithOrderIndependentOutput.
+	orderIndependent bool
+	// weights are the site weights of a power diagram, see NewPowerDiagram, or nil.
+	weights []float64
+	// maxWeight is the largest of the weights, which generator subtracts from them, or 0.
+	maxWeight float64
+	// sources maps the cells to the input sites merged into them, see SourceIndex.
+	sources siteSources
+	// mergeTol is the tolerance of WithVertexMerging, or 0.
+	mergeTol s1.Angle
+	// siteData holds the values of SetSiteData, or nil.
+	siteData []any
+	// cache holds lazily built acceleration structures.
+	cache *diagramCache
+	// buffers holds the triangulation state kept by Rebuild, or nil.
+	buffers *rebuildBuffers
 }
 
+// CoplanarSitesError is returned when all sites lie in a common plane, e.g. on one great circle.
+// It reports the best-fit plane so that callers can decide how to recover, for example by
+// jittering the sites or by ordering them along the circle instead.
+type CoplanarSitesError = s2delaunay.CoplanarVerticesError
+
 // DiagramOptions holds configuration options for Voronoi diagram creation.
 type DiagramOptions struct {
-	Eps float64
+	Eps                 float64
+	OrderIndependent    bool
+	Validation          ValidationLevel
+	RetainTriangulation bool
+	Progress            func(phase string, done, total int)
+	NormalizeInput      bool
+	UncheckedInput      bool
+	NearDuplicateCheck  bool
+	Deduplication       s1.Angle
+	Jitter              s1.Angle
+	JitterSeed          int64
+	VertexMerging       s1.Angle
+	SkipNonPoints       bool
 }
 
 // DiagramOption is a functional option type for Voronoi diagram configuration.
@@ -51,7 +90,7 @@ type DiagramOption func(*DiagramOptions) error
 func WithEps(eps float64) DiagramOption {
 	return func(o *DiagramOptions) error {
 		if eps <= 0 {
-			return fmt.Errorf("s2voronoi: eps must be positive got %v", eps)
+			return errorf(ErrInvalidOption, "s2voronoi: eps must be positive, got %v", eps)
 
 		}
 		o.Eps = eps
@@ -59,56 +98,280 @@ func WithEps(eps float64) DiagramOption {
 	}
 }
 
+// WithNormalizeInput normalizes every site to unit length in place before the diagram is built,
+// for sites that are slightly off the unit sphere, e.g. from geodetic computations. Zero and
+// non-finite vectors are rejected with an error matching ErrNotUnitLength. Without the option
+// such sites are rejected as well, together with every site that is not of unit length, see
+// s2.Point.IsUnit, since they would skew the circumcenters.
+func WithNormalizeInput() DiagramOption {
+	return func(o *DiagramOptions) error {
+		o.NormalizeInput = true
+		return nil
+	}
+}
+
+// WithUncheckedInput skips the linear scan that rejects sites with NaN or infinite coordinates
+// and sites that are not of unit length, for hot paths whose input is known to be valid.
+// Invalid sites then yield a garbage diagram, an unrelated error or a panic.
+func WithUncheckedInput() DiagramOption {
+	return func(o *DiagramOptions) error {
+		o.UncheckedInput = true
+		return nil
+	}
+}
+
+// WithNearDuplicateCheck extends the check for equal sites, which runs before the convex hull
+// is computed, to sites within a chord distance of eps, so that all offending pairs are reported
+// by the *DuplicateSitesError, see s2delaunay.WithNearDuplicateCheck. It does not apply to power
+// diagrams, in which coinciding sites of different weights are valid.
+func WithNearDuplicateCheck() DiagramOption {
+	return func(o *DiagramOptions) error {
+		o.NearDuplicateCheck = true
+		return nil
+	}
+}
+
+// The phases of the construction of a diagram reported by WithProgress, in this order. The
+// first two are those of the Delaunay triangulation and share about 95% of the time as given in
+// s2delaunay; PhaseCircumcenters takes the remaining 5%.
+const (
+	// PhaseHull is the convex hull of the sites, counted in sites, see s2delaunay.PhaseHull.
+	// Only its start and end are reported.
+	PhaseHull = s2delaunay.PhaseHull
+	// PhaseSorting is the CCW sorting of the cells, counted in sites, see
+	// s2delaunay.PhaseSorting.
+	PhaseSorting = s2delaunay.PhaseSorting
+	// PhaseCircumcenters is the computation of the vertices, counted in vertices.
+	PhaseCircumcenters = "circumcenters"
+)
+
+// WithProgress sets a function that NewDiagram, NewDiagramContext and Rebuild call during the
+// phases PhaseHull, PhaseSorting and PhaseCircumcenters with the number of items done out of the
+// total of the phase, e.g. to render a progress bar. Every phase is reported about 100 times,
+// from 0 up to its total, from the calling goroutine. Without the option no progress is tracked.
+// It must not be nil.
+func WithProgress(f func(phase string, done, total int)) DiagramOption {
+	return func(o *DiagramOptions) error {
+		if f == nil {
+			return errorf(ErrInvalidOption, "s2voronoi: progress function must not be nil")
+		}
+		o.Progress = f
+		return nil
+	}
+}
+
+// triangulationOptions returns the options of the triangulation of a diagram built with o,
+// whose sites have already been prepared by prepareSites.
+func (o *DiagramOptions) triangulationOptions() []s2delaunay.TriangulationOption {
+	opts := []s2delaunay.TriangulationOption{
+		s2delaunay.WithEps(o.Eps),
+		s2delaunay.WithUncheckedInput(),
+	}
+	if o.Progress != nil {
+		opts = append(opts, s2delaunay.WithProgress(o.Progress))
+	}
+	if o.NearDuplicateCheck {
+		opts = append(opts, s2delaunay.WithNearDuplicateCheck())
+	}
+	return opts
+}
+
 // NewDiagram creates a new Voronoi diagram from the given sites.
-// The sites must lie on the unit sphere, there must be at least 4 sites, and they must not be coplanar.
-// It returns an error if the diagram cannot be constructed.
+// The sites must lie on the unit sphere, there must be at least 4 sites, they must not be coplanar,
+// and they must not lie in an open hemisphere.
+// It returns an error if the diagram cannot be constructed, and a *CoplanarSitesError if the
+// sites lie in a common plane within eps. The errors match ErrInsufficientSites,
+// ErrDegenerateInput, ErrDuplicateSites or ErrInvalidOption, see errors.Is; sites dropped as
+// duplicates are reported by a *DuplicateSitesError. Sites with NaN or infinite coordinates, and
+// sites that are not of unit length unless WithNormalizeInput is given, yield an error matching
+// ErrNotUnitLength that names the first of them.
 func NewDiagram(sites s2.PointVector, setters ...DiagramOption) (*Diagram, error) {
-	if len(sites) < 4 {
-		return nil, errors.New("s2voronoi: insufficient sites for diagram, minimum 4 required")
+	return NewDiagramContext(context.Background(), sites, setters...)
+}
+
+// NewDiagramContext is like NewDiagram but checks ctx between the phases of the construction,
+// i.e. before and after the convex hull, before sorting the incidence arrays and between the
+// circumcenters and the cell neighbors, and returns ctx.Err() if it is done. The convex hull
+// itself is not interrupted. On error no diagram is returned.
+func NewDiagramContext(ctx context.Context, sites s2.PointVector,
+	setters ...DiagramOption) (*Diagram, error) {
+	d := new(Diagram)
+	if err := d.rebuild(ctx, sites, setters...); err != nil {
+		return nil, err
 	}
+	return d, nil
+}
 
-	opts := &DiagramOptions{
-		Eps: defaultEps,
+// newDiagram creates a Voronoi diagram from the given sites in their given order.
+func newDiagram(ctx context.Context, sites s2.PointVector,
+	opts *DiagramOptions) (*Diagram, error) {
+	b, err := s2delaunay.NewBuilder(opts.triangulationOptions()...)
+	if err != nil {
+		return nil, err
 	}
-	for _, set := range setters {
-		err := set(opts)
-		if err != nil {
+	dt := new(s2delaunay.Triangulation)
+	if err := opts.triangulate(ctx, b, dt, sites); err != nil {
+		return nil, err
+	}
+
+	d := &Diagram{eps: opts.Eps}
+	if err := d.setTriangulationContext(ctx, dt, opts.Progress); err != nil {
+		return nil, err
+	}
+	if opts.VertexMerging != 0 {
+		if err := d.mergeVertices(opts.VertexMerging); err != nil {
 			return nil, err
 		}
 	}
+	return d, nil
+}
+
+// setTriangulation replaces the contents of the diagram by the dual of dt. The diagram shares
+// the vertices and the incidence arrays of dt, reuses its Vertices and CellNeighbors when their
+// capacity suffices, and discards all cached structures.
+func (d *Diagram) setTriangulation(dt *s2delaunay.Triangulation) error {
+	return d.setTriangulationContext(context.Background(), dt, nil)
+}
 
-	dt, err := s2delaunay.NewTriangulation(sites, s2delaunay.WithEps(opts.Eps))
+// setTriangulationContext is like setTriangulation but checks ctx between the circumcenters and
+// the cell neighbors, and reports PhaseCircumcenters to report if it is not nil.
+func (d *Diagram) setTriangulationContext(ctx context.Context, dt *s2delaunay.Triangulation,
+	report progress.Func) error {
+	numTriangles := dt.NumTriangles()
+	d.Sites = dt.Vertices
+	d.Vertices = resize(d.Vertices, numTriangles)
+	d.CellVertices = dt.IncidentTriangleIndices
+	d.CellNeighbors = resize(d.CellNeighbors, len(dt.IncidentTriangleIndices))
+	d.CellOffsets = dt.IncidentTriangleOffsets
+	d.invalidateCaches()
+
+	circumcenters := func(start, end int) error {
+		for i := start; i < end; i++ {
+			a, b, c := dt.TriangleVertices(i)
+			cc := triangleCircumcenter(a, b, c)
+			if cc.Norm() <= d.eps {
+				return errorf(ErrDegenerateInput,
+					"s2voronoi: circumcenter of triangle %d is degenerate", i)
+			}
+			d.Vertices[i] = s2.Point{Vector: cc.Normalize()}
+		}
+		return nil
+	}
+	err := progress.ForEachChunk(numTriangles, report, PhaseCircumcenters, circumcenters)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	numTriangles := len(dt.Triangles)
-	numNeighbors := len(dt.IncidentTriangleIndices)
-	d := &Diagram{
-		Sites:         dt.Vertices,
-		Vertices:      make(s2.PointVector, numTriangles),
-		CellVertices:  dt.IncidentTriangleIndices,
-		CellNeighbors: make([]int, numNeighbors),
-		CellOffsets:   dt.IncidentTriangleOffsets,
+	fillCellNeighbors(d.CellNeighbors, dt)
+
+	return nil
+}
 
-		eps: opts.Eps,
+// prepareSites checks the sites with checkSites unless opts.UncheckedInput is set, and then
+// normalizes them in place if opts.NormalizeInput is set.
+func prepareSites(sites s2.PointVector, opts *DiagramOptions) error {
+	if !opts.UncheckedInput {
+		if err := checkSites(sites, opts.NormalizeInput); err != nil {
+			return err
+		}
+	}
+	if opts.NormalizeInput {
+		for i, s := range sites {
+			sites[i] = s2.Point{Vector: s.Normalize()}
+		}
 	}
+	return nil
+}
 
-	for i := range numTriangles {
-		a, b, c := dt.TriangleVertices(i)
-		d.Vertices[i] = s2.Point{Vector: triangleCircumcenter(a, b, c).Normalize()}
+// checkSites returns an error matching ErrNotUnitLength for the first site with a NaN or
+// infinite coordinate, naming the coordinate, and then for the first site that is not of unit
+// length, or that cannot be normalized if normalize is set.
+func checkSites(sites s2.PointVector, normalize bool) error {
+	for i, s := range sites {
+		for k, x := range [3]float64{s.X, s.Y, s.Z} {
+			if math.IsNaN(x) || math.IsInf(x, 0) {
+				return errorf(ErrNotUnitLength,
+					"s2voronoi: site %d has non-finite %c coordinate %v", i, "XYZ"[k], x)
+			}
+		}
+		if !normalize {
+			if !s.IsUnit() {
+				return errorf(ErrNotUnitLength,
+					"s2voronoi: site %d is not unit length, norm %v", i, s.Norm())
+			}
+		} else if n := s.Norm(); n == 0 || math.IsInf(n, 0) {
+			return errorf(ErrNotUnitLength, "s2voronoi: site %d cannot be normalized, norm %v",
+				i, n)
+		}
 	}
+	return nil
+}
 
+// resize returns a slice of length n that reuses the backing array of s if it is large enough.
+// The contents are not preserved.
+func resize[T any](s []T, n int) []T {
+	if cap(s) < n {
+		return make([]T, n)
+	}
+	return s[:n]
+}
+
+// fillCellNeighbors stores in dst the neighbor across every cell edge, which is the Delaunay
+// vertex following the cell site in the corresponding incident triangle.
+func fillCellNeighbors(dst []int, dt *s2delaunay.Triangulation) {
 	for vIdx := range dt.Vertices {
 		offset := dt.IncidentTriangleOffsets[vIdx]
 		it := dt.IncidentTriangles(vIdx)
 		for i, tIdx := range it {
 			nxt := s2delaunay.NextVertex(dt.Triangles[tIdx], vIdx)
-			d.CellNeighbors[offset+i] = nxt
+			dst[offset+i] = nxt
 		}
 	}
+}
 
-	return d, nil
+// Eps returns the numerical precision epsilon the diagram was built with.
+// It is passed on to the underlying triangulation (see s2delaunay.Triangulation.Eps), it is the
+// minimum norm of the unnormalized circumcenter below which a triangle is rejected as
+// degenerate, and it is the chord distance under which two sites or vertices are considered
+// coincident or sites tied in point location. The tolerances of Validate, of the cell bounds and
+// of the check of the rotation matrix of Rotate are fixed and do not depend on it.
+func (d *Diagram) Eps() float64 {
+	return d.eps
+}
+
+// options returns the options that rebuild a diagram with the same configuration.
+func (d *Diagram) options() []DiagramOption {
+	opts := []DiagramOption{WithEps(d.eps)}
+	if d.orderIndependent {
+		opts = append(opts, WithOrderIndependentOutput())
+	}
+	if d.mergeTol != 0 {
+		opts = append(opts, WithVertexMerging(d.mergeTol))
+	}
+	return opts
+}
+
+// clone returns a copy of the diagram that shares no slices with it and has empty caches.
+func (d *Diagram) clone() *Diagram {
+	return &Diagram{
+		Sites:         slices.Clone(d.Sites),
+		Vertices:      slices.Clone(d.Vertices),
+		CellVertices:  slices.Clone(d.CellVertices),
+		CellNeighbors: slices.Clone(d.CellNeighbors),
+		CellOffsets:   slices.Clone(d.CellOffsets),
+
+		eps:              d.eps,
+		orderIndependent: d.orderIndependent,
+		weights:          slices.Clone(d.weights),
+		maxWeight:        d.maxWeight,
+		sources:          d.sources.clone(),
+		mergeTol:         d.mergeTol,
+		siteData:         slices.Clone(d.siteData),
+		cache:            new(diagramCache),
+	}
 }
 
 // NumCells returns the number of cells in the diagram.
@@ -119,36 +382,36 @@ func (d *Diagram) NumCells() int {
 // Cell returns the Voronoi cell at the specified index.
 // It panics if the index is out of range.
 func (d *Diagram) Cell(i int) Cell {
-	if i < 0 || i >= len(d.Sites) {
-		panic(fmt.Sprintf("s2voronoi: cell index %d out of range [0, %d)", i, len(d.Sites)))
-	}
+	d.checkCellIndex(i)
 
 	return Cell{idx: i, d: d}
 }
 
-// Relax performs Lloyd's relaxation by moving sites to centroids and recomputing the diagram.
-// NOTE: Allocates excessive memory by creating new Diagram per step
-func (d *Diagram) Relax(steps int) error {
-	if steps < 0 {
-		return fmt.Errorf("s2voronoi: relax steps must be non-negative, got %d", steps)
+// CellAt returns the Voronoi cell at the specified index, like Cell, or an error if the index
+// is out of range, for indices that come from data rather than from the diagram.
+func (d *Diagram) CellAt(i int) (Cell, error) {
+	if i < 0 || i >= len(d.Sites) {
+		return Cell{}, fmt.Errorf("s2voronoi: cell index %d out of range [0 %d)", i, len(d.Sites))
 	}
+	return Cell{idx: i, d: d}, nil
+}
 
-	for range steps {
-		for i := range d.NumCells() {
-			cell := d.Cell(i)
-			d.Sites[i] = s2.Point{Vector: cell.centroid().Normalize()}
-		}
-
-		// TODO: Optimize for reuse memory
-		nd, err := NewDiagram(d.Sites, WithEps(d.eps))
-		if err != nil {
-			return err
+// Cells returns an iterator over the cells of the diagram and their indices, in index order.
+func (d *Diagram) Cells() iter.Seq2[int, Cell] {
+	return func(yield func(int, Cell) bool) {
+		for i := range d.Sites {
+			if !yield(i, Cell{idx: i, d: d}) {
+				return
+			}
 		}
-
-		*d = *nd
 	}
+}
 
-	return nil
+// checkCellIndex panics if i is not a valid cell index.
+func (d *Diagram) checkCellIndex(i int) {
+	if i < 0 || i >= len(d.Sites) {
+		panic(fmt.Sprintf("s2voronoi: cell index %d out of range [0 %d)", i, len(d.Sites)))
+	}
 }
 
 // triangleCircumcenter computes the circumcenter of a triangle on the sphere.
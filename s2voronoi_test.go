@@ -5,11 +5,17 @@
 package s2voronoi
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"math"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/r3"
 	"github.com/golang/geo/s2"
 	"github.com/google/go-cmp/cmp"
 )
@@ -28,7 +34,7 @@ func TestWithEps(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			opts := &DiagramOptions{Eps: defaultEps}
+			opts := &DiagramOptions{Eps: DefaultEps}
 			opt := WithEps(tt.eps)
 			err := opt(opts)
 			if (err != nil) != tt.wantErr {
@@ -54,7 +60,7 @@ func TestNewDiagram_WithEps(t *testing.T) {
 		eps     float64
 		wantErr bool
 	}{
-		{"eps default", defaultEps, false},
+		{"eps default", DefaultEps, false},
 		{"eps positive", 0.01, false},
 		{"eps large", 1, true},
 		{"eps zero", 0, true},
@@ -78,6 +84,232 @@ func TestNewDiagram_WithEps(t *testing.T) {
 	}
 }
 
+func TestNewDiagramContext(t *testing.T) {
+	sites := utils.GenerateRandomPoints(1000, 0)
+	want, err := NewDiagram(slices.Clone(sites))
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	for _, opts := range [][]DiagramOption{nil, {WithOrderIndependentOutput()}} {
+		wantOpts, err := NewDiagram(slices.Clone(sites), opts...)
+		if err != nil {
+			t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+		}
+		got, err := NewDiagramContext(context.Background(), slices.Clone(sites), opts...)
+		if err != nil {
+			t.Fatalf("NewDiagramContext(...) error = %v, want nil", err)
+		}
+		if !got.Equal(wantOpts) {
+			t.Errorf("NewDiagramContext(...) differs from NewDiagram(...)")
+		}
+	}
+
+	// The build checks ctx at every phase, so a context done at the k-th check trips it there.
+	for k := 0; ; k++ {
+		ctx := &countdownContext{Context: context.Background(), n: k}
+		got, err := NewDiagramContext(ctx, slices.Clone(sites))
+		if err == nil {
+			if k == 0 {
+				t.Fatalf("NewDiagramContext(...) error = nil, want context.Canceled")
+			}
+			if !got.Equal(want) {
+				t.Errorf("NewDiagramContext(...) after %d checks differs from NewDiagram(...)", k)
+			}
+			break
+		}
+		if !errors.Is(err, context.Canceled) || got != nil {
+			t.Fatalf("NewDiagramContext(...) after %d checks = %v, %v, want nil, %v", k, got, err,
+				context.Canceled)
+		}
+	}
+}
+
+func TestNewDiagramContext_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, opts := range [][]DiagramOption{nil, {WithOrderIndependentOutput()}} {
+		got, err := NewDiagramContext(ctx, utils.GenerateRandomPoints(100, 0), opts...)
+		if !errors.Is(err, context.Canceled) || got != nil {
+			t.Errorf("NewDiagramContext(...) = %v, %v, want nil, %v", got, err, context.Canceled)
+		}
+	}
+}
+
+func TestNewDiagramContext_Deadline(t *testing.T) {
+	sites := utils.GenerateRandomPoints(200000, 0)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	got, err := NewDiagramContext(ctx, sites)
+	if !errors.Is(err, context.DeadlineExceeded) || got != nil {
+		t.Errorf("NewDiagramContext(...) = %v, %v, want nil, %v", got, err,
+			context.DeadlineExceeded)
+	}
+}
+
+func TestNewDiagram_WithProgress(t *testing.T) {
+	const n = 1000
+	tests := []struct {
+		name string
+		opts []DiagramOption
+	}{
+		{"default", nil},
+		{"order independent", []DiagramOption{WithOrderIndependentOutput()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []progressCall
+			progress := WithProgress(func(phase string, done, total int) {
+				calls = append(calls, progressCall{phase, done, total})
+			})
+			vd := new(Diagram)
+			// The second build keeps its buffers, and the third and fourth reuse them.
+			var buf *rebuildBuffers
+			for k := range 3 {
+				calls = nil
+				sites := utils.GenerateRandomPoints(n, int64(k))
+				if err := vd.Rebuild(sites, append(tt.opts, progress)...); err != nil {
+					t.Fatalf("vd.Rebuild(...) error = %v, want nil", err)
+				}
+				checkProgress(t, calls, []string{PhaseHull, PhaseSorting, PhaseCircumcenters},
+					[]int{n, n, 2*n - 4})
+				if k == 2 && vd.buffers != buf {
+					t.Errorf("vd.Rebuild(...) with WithProgress replaced the buffers, want reused")
+				}
+				buf = vd.buffers
+			}
+
+			calls = nil
+			if err := vd.Rebuild(utils.GenerateRandomPoints(n, 2), tt.opts...); err != nil {
+				t.Fatalf("vd.Rebuild(...) error = %v, want nil", err)
+			}
+			if len(calls) != 0 {
+				t.Errorf("vd.Rebuild(...) without WithProgress made %d calls, want 0", len(calls))
+			}
+			if vd.buffers != buf {
+				t.Errorf("vd.Rebuild(...) without WithProgress replaced the buffers, want reused")
+			}
+		})
+	}
+
+	if _, err := NewDiagram(utils.GenerateRandomPoints(n, 0), WithProgress(nil)); !errors.Is(err,
+		ErrInvalidOption) {
+		t.Errorf("NewDiagram(..., WithProgress(nil)) error = %v, want %v", err, ErrInvalidOption)
+	}
+}
+
+func TestNewDiagram_NormalizeInput(t *testing.T) {
+	weights := make([]float64, 100)
+	weights[0] = 0.01
+	tests := []struct {
+		name  string
+		build func(sites s2.PointVector, setters ...DiagramOption) (*Diagram, error)
+	}{
+		{"default", NewDiagram},
+		{
+			name: "order independent",
+			build: func(sites s2.PointVector, setters ...DiagramOption) (*Diagram, error) {
+				return NewDiagram(sites, append(setters, WithOrderIndependentOutput())...)
+			},
+		},
+		{
+			name: "power",
+			build: func(sites s2.PointVector, setters ...DiagramOption) (*Diagram, error) {
+				return NewPowerDiagram(sites, weights, setters...)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points := utils.GenerateRandomPoints(100, 0)
+			want, err := tt.build(slices.Clone(points))
+			if err != nil {
+				t.Fatalf("tt.build(...) error = %v, want nil", err)
+			}
+			scaled := slices.Clone(points)
+			for i := 5; i < len(scaled); i += 2 {
+				scaled[i] = s2.Point{Vector: scaled[i].Mul(1 - 1e-6)}
+			}
+
+			_, err = tt.build(slices.Clone(scaled))
+			if !errors.Is(err, ErrNotUnitLength) || !strings.Contains(err.Error(), "site 5 ") {
+				t.Errorf("tt.build(scaled) error = %v, want %v for site 5", err, ErrNotUnitLength)
+			}
+
+			vd, err := tt.build(scaled, WithNormalizeInput())
+			if err != nil {
+				t.Fatalf("tt.build(scaled, WithNormalizeInput()) error = %v, want nil", err)
+			}
+			if !vd.ApproxEqual(want, 1e-12) {
+				t.Errorf("tt.build(scaled, WithNormalizeInput()) differs from the unit sites")
+			}
+
+			zero := slices.Clone(points)
+			zero[7] = s2.Point{}
+			if _, err := tt.build(zero, WithNormalizeInput()); !errors.Is(err, ErrNotUnitLength) {
+				t.Errorf("tt.build(zero, WithNormalizeInput()) error = %v, want %v", err,
+					ErrNotUnitLength)
+			}
+		})
+	}
+}
+
+func TestNewDiagram_NonFinite(t *testing.T) {
+	weights := make([]float64, 100)
+	weights[0] = 0.01
+	tests := []struct {
+		name string
+		site r3.Vector
+		want string
+	}{
+		{"NaN X", r3.Vector{X: math.NaN(), Z: 1}, "site 7 has non-finite X coordinate NaN"},
+		{"NaN Y", r3.Vector{Y: math.NaN(), Z: 1}, "site 7 has non-finite Y coordinate NaN"},
+		{"NaN Z", r3.Vector{Y: 1, Z: math.NaN()}, "site 7 has non-finite Z coordinate NaN"},
+		{"+Inf", r3.Vector{Y: math.Inf(1)}, "site 7 has non-finite Y coordinate +Inf"},
+		{"-Inf", r3.Vector{Z: math.Inf(-1)}, "site 7 has non-finite Z coordinate -Inf"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sites := utils.GenerateRandomPoints(100, 0)
+			sites[7] = s2.Point{Vector: tt.site}
+			var errs []error
+			for _, opts := range [][]DiagramOption{nil, {WithNormalizeInput()},
+				{WithOrderIndependentOutput()}} {
+				_, err := NewDiagram(slices.Clone(sites), opts...)
+				errs = append(errs, err)
+			}
+			_, err := NewPowerDiagram(sites, weights)
+			errs = append(errs, err)
+			for _, err := range errs {
+				if !errors.Is(err, ErrNotUnitLength) || !strings.Contains(err.Error(), tt.want) {
+					t.Errorf("error = %v, want %v naming %q", err, ErrNotUnitLength, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewDiagram_UncheckedInput(t *testing.T) {
+	sites := utils.GenerateRandomPoints(100, 0)
+	want, err := NewDiagram(slices.Clone(sites))
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	vd, err := NewDiagram(slices.Clone(sites), WithUncheckedInput())
+	if err != nil {
+		t.Fatalf("NewDiagram(..., WithUncheckedInput()) error = %v, want nil", err)
+	}
+	if !vd.Equal(want) {
+		t.Errorf("NewDiagram(..., WithUncheckedInput()) differs from NewDiagram(...)")
+	}
+
+	// Slightly scaled sites are not rejected without the check.
+	sites[3] = s2.Point{Vector: sites[3].Mul(1 + 1e-6)}
+	if _, err := NewDiagram(sites, WithUncheckedInput()); errors.Is(err, ErrNotUnitLength) {
+		t.Errorf("NewDiagram(..., WithUncheckedInput()) error = %v, want no %v", err,
+			ErrNotUnitLength)
+	}
+}
+
 func TestDiagram_Invariants(t *testing.T) {
 	tests := []struct {
 		name string
@@ -123,19 +355,52 @@ func TestNewDiagram_DegenerateInput(t *testing.T) {
 	}
 }
 
+func TestNewDiagram_Coplanar(t *testing.T) {
+	sites := make(s2.PointVector, 10)
+	for i := range sites {
+		sites[i] = s2.PointFromLatLng(s2.LatLngFromDegrees(0, float64(i)*36))
+	}
+
+	_, err := NewDiagram(sites)
+	var cerr *CoplanarSitesError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("NewDiagram(...) error = %v, want *CoplanarSitesError", err)
+	}
+	if math.Abs(math.Abs(cerr.Normal.Z)-1) > 1e-9 {
+		t.Errorf("NewDiagram(...) error Normal = %v, want ±Z", cerr.Normal)
+	}
+}
+
+func TestNewDiagram_DegenerateTriangle(t *testing.T) {
+	// One Delaunay triangle is nearly flat against the origin.
+	sites := s2.PointVector{
+		s2.PointFromLatLng(s2.LatLngFromDegrees(0, 0)),
+		s2.PointFromLatLng(s2.LatLngFromDegrees(-1e-6, 120)),
+		s2.PointFromLatLng(s2.LatLngFromDegrees(0, 240)),
+		s2.PointFromCoords(0, 0, 1),
+	}
+
+	if _, err := NewDiagram(sites); err != nil {
+		t.Errorf("NewDiagram(...) error = %v, want nil", err)
+	}
+	if _, err := NewDiagram(sites, WithEps(1e-6)); err == nil {
+		t.Errorf("NewDiagram(..., WithEps(1e-6)) error = nil, want non-nil")
+	}
+}
+
 func TestNewDiagram_OnSphere(t *testing.T) {
 	vd := mustNewDiagram(t, 100)
 
 	for i, v := range vd.Vertices {
 		n := v.Norm()
-		if math.Abs(n-1.0) > defaultEps {
+		if math.Abs(n-1.0) > DefaultEps {
 			t.Errorf("vd.Vertices[%d] norm = %v, want ~1.0", i, n)
 		}
 	}
 
 	for i, s := range vd.Sites {
 		n := s.Norm()
-		if math.Abs(n-1.0) > defaultEps {
+		if math.Abs(n-1.0) > DefaultEps {
 			t.Errorf("vd.Sites[%d] norm = %v, want ~1.0", i, n)
 		}
 	}
@@ -178,6 +443,19 @@ func TestNewDiagram_VerifyCCW(t *testing.T) {
 	}
 }
 
+func TestDiagram_Eps(t *testing.T) {
+	points := utils.GenerateRandomPoints(10, 0)
+	for _, eps := range []float64{DefaultEps, 1e-9} {
+		vd, err := NewDiagram(points, WithEps(eps))
+		if err != nil {
+			t.Fatalf("NewDiagram(..., WithEps(%v)) error = %v, want nil", eps, err)
+		}
+		if got := vd.Eps(); got != eps {
+			t.Errorf("vd.Eps() = %v, want %v", got, eps)
+		}
+	}
+}
+
 func TestDiagram_NumCells(t *testing.T) {
 	vd := mustNewDiagram(t, 10)
 	want := len(vd.Sites)
@@ -192,145 +470,86 @@ func TestDiagram_Cell(t *testing.T) {
 	for i := range vd.NumCells() {
 		c := vd.Cell(i)
 		want := Cell{i, vd}
-		if diff := cmp.Diff(want, c, cmp.AllowUnexported(Cell{}, Diagram{})); diff != "" {
+		// The cache holds sync.Once values, which cannot be compared field by field.
+		sameCache := cmp.Comparer(func(a, b *diagramCache) bool { return a == b })
+		diff := cmp.Diff(want, c, cmp.AllowUnexported(Cell{}, Diagram{}), sameCache)
+		if diff != "" {
 			t.Errorf("vd.Cell(%d) mismatch (-want +got):\n%s", i, diff)
 		}
 	}
 }
 
-func TestDiagram_Cell_Panic(t *testing.T) {
+func TestDiagram_CellAt(t *testing.T) {
 	vd := mustNewDiagram(t, 10)
-
 	tests := []struct {
-		name  string
-		index int
+		name    string
+		index   int
+		wantErr bool
 	}{
-		{"negative index", -1},
-		{"out of range", vd.NumCells()},
+		{"first", 0, false},
+		{"last", 9, false},
+		{"negative index", -1, true},
+		{"out of range", 10, true},
 	}
-
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			defer func() {
-				if r := recover(); r == nil {
-					t.Errorf("vd.Cell(%d) did not panic, want panic", tt.index)
-				}
-			}()
-			vd.Cell(tt.index)
+			c, err := vd.CellAt(tt.index)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("vd.CellAt(%d) error = %v, want error %v", tt.index, err, tt.wantErr)
+			}
+			if !tt.wantErr && c != vd.Cell(tt.index) {
+				t.Errorf("vd.CellAt(%d) = %v, want %v", tt.index, c, vd.Cell(tt.index))
+			}
 		})
 	}
 }
 
-func TestDiagram_Relax(t *testing.T) {
-	tests := []struct {
-		name  string
-		steps int
-		size  int
-	}{
-		{"zero step", 0, 1000},
-		{"one step", 1, 1000},
-		{"multiple steps", 5, 1000},
+func TestDiagram_Cells(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	next := 0
+	for i, c := range vd.Cells() {
+		if i != next || c != vd.Cell(i) {
+			t.Errorf("vd.Cells() yielded %d, %v, want %d, %v", i, c, next, vd.Cell(next))
+		}
+		next++
+	}
+	if next != vd.NumCells() {
+		t.Errorf("vd.Cells() yielded %d cells, want %d", next, vd.NumCells())
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			vd := mustNewDiagram(t, tt.size)
-			vdOld := mustNewDiagram(t, tt.size)
-
-			err := vd.Relax(tt.steps)
-			if err != nil {
-				t.Fatalf("vd.Relax(%d) error = %v, want nil", tt.steps, err)
-			}
-
-			if len(vd.Sites) != len(vdOld.Sites) {
-				t.Errorf("vd.Relax(%d) Sites count = %d, want %d", tt.steps,
-					len(vd.Sites), len(vdOld.Sites))
-			}
-			if len(vd.Vertices) != len(vdOld.Vertices) {
-				t.Errorf("vd.Relax(%d) Vertices count = %d, want %d", tt.steps,
-					len(vd.Vertices), len(vdOld.Vertices))
-			}
-			if len(vd.CellNeighbors) != len(vdOld.CellNeighbors) {
-				t.Errorf("vd.Relax(%d) CellNeighbors count = %d, want %d", tt.steps,
-					len(vd.CellNeighbors), len(vdOld.CellNeighbors))
-			}
-			if len(vd.CellVertices) != len(vdOld.CellVertices) {
-				t.Errorf("vd.Relax(%d) CellVertices count = %d, want %d", tt.steps,
-					len(vd.CellVertices), len(vdOld.CellVertices))
-			}
-			if len(vd.CellOffsets) != len(vdOld.CellOffsets) {
-				t.Errorf("vd.Relax(%d) CellOffsets count = %d, want %d", tt.steps,
-					len(vd.CellOffsets), len(vdOld.CellOffsets))
-			}
-
-			expectChange := tt.steps != 0
-			msg := "changed"
-			if expectChange {
-				msg = "not changed"
-			}
-			if cmp.Equal(vd.Sites, vdOld.Sites) == expectChange {
-				t.Errorf("vd.Relax(%d) Sites %s", tt.steps, msg)
-			}
-			if cmp.Equal(vd.Vertices, vdOld.Vertices) == expectChange {
-				t.Errorf("vd.Relax(%d) Vertices %s", tt.steps, msg)
-			}
-			if cmp.Equal(vd.CellNeighbors, vdOld.CellNeighbors) == expectChange {
-				t.Errorf("vd.Relax(%d) CellNeighbors %s", tt.steps, msg)
-			}
-			if cmp.Equal(vd.CellVertices, vdOld.CellVertices) == expectChange {
-				t.Errorf("vd.Relax(%d) CellVertices %s", tt.steps, msg)
-			}
-			if cmp.Equal(vd.CellOffsets, vdOld.CellOffsets) == expectChange {
-				t.Errorf("vd.Relax(%d) CellOffsets %s", tt.steps, msg)
-			}
-		})
+	for i := range vd.Cells() {
+		if i == 3 {
+			break
+		}
+	}
+	allocs := testing.AllocsPerRun(10, func() {
+		for range vd.Cells() {
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("ranging over vd.Cells() allocates %v times, want 0", allocs)
 	}
 }
 
-func TestDiagram_Relax_BrokenData(t *testing.T) {
+func TestDiagram_Cell_Panic(t *testing.T) {
+	vd := mustNewDiagram(t, 10)
+
 	tests := []struct {
-		name    string
-		diagram *Diagram
-		steps   int
+		name  string
+		index int
 	}{
-		{
-			name:    "negative steps",
-			diagram: mustNewDiagram(t, 100),
-			steps:   -1,
-		},
-		{
-			name:    "empty diagram",
-			diagram: &Diagram{},
-			steps:   1,
-		},
-		{
-			name: "diagram with single site",
-			diagram: &Diagram{
-				Sites:         utils.GenerateRandomPoints(1, 0),
-				eps:           0.01,
-				Vertices:      []s2.Point{s2.PointFromCoords(1, 0, 0)},
-				CellNeighbors: []int{},
-				CellVertices:  []int{0},
-				CellOffsets:   []int{0, 1},
-			},
-			steps: 1,
-		},
-		{
-			name: "negative eps",
-			diagram: func() *Diagram {
-				vd := mustNewDiagram(t, 100)
-				vd.eps = -1
-				return vd
-			}(),
-			steps: 1,
-		},
+		{"negative index", -1},
+		{"out of range", vd.NumCells()},
 	}
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := tt.diagram.Relax(tt.steps)
-			if err == nil {
-				t.Errorf("tt.diagram.Relax(%v) error = nil, want non-nil", tt.steps)
-			}
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("vd.Cell(%d) did not panic, want panic", tt.index)
+				}
+			}()
+			vd.Cell(tt.index)
 		})
 	}
 }
@@ -360,7 +579,7 @@ func TestTriangleCircumcenter(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got := triangleCircumcenter(tt.a, tt.b, tt.c)
-			if got.Distance(tt.want) > defaultEps {
+			if got.Distance(tt.want) > DefaultEps {
 				t.Errorf("triangleCircumcenter(...) = %v, want %v", got, tt.want)
 			}
 		})
@@ -387,34 +606,6 @@ func BenchmarkNewDiagram(b *testing.B) {
 	}
 }
 
-func BenchmarkDiagram_Relax(b *testing.B) {
-	sizes := []int{1e+2, 1e+3, 1e+4}
-	steps := []int{1, 10}
-	for _, pointsCnt := range sizes {
-		for _, step := range steps {
-			b.Run(fmt.Sprintf("N%d Steps%d", pointsCnt, step), func(b *testing.B) {
-				points := utils.GenerateRandomPoints(pointsCnt, 0)
-
-				b.ReportAllocs()
-				b.ResetTimer()
-				for b.Loop() {
-					b.StopTimer()
-					vd, err := NewDiagram(points)
-					if err != nil {
-						b.Fatalf("NewDiagram(...) error = %v, want nil", err)
-					}
-					b.StartTimer()
-
-					err = vd.Relax(step)
-					if err != nil {
-						b.Fatalf("vd.Relax(%d) error = %v, want nil", step, err)
-					}
-				}
-			})
-		}
-	}
-}
-
 // Helpers
 
 func mustNewDiagram(t *testing.T, n int) *Diagram {
@@ -438,3 +629,52 @@ func computeAngleCCW(refVec, vec, normal s2.Point) float64 {
 	}
 	return angle
 }
+
+// countdownContext is a context that is canceled once Err has been called n times.
+type countdownContext struct {
+	context.Context
+	n int
+}
+
+func (c *countdownContext) Err() error {
+	if c.n <= 0 {
+		return context.Canceled
+	}
+	c.n--
+	return nil
+}
+
+// progressCall is a call of a progress function, see WithProgress.
+type progressCall struct {
+	phase       string
+	done, total int
+}
+
+// checkProgress checks that the calls report the phases in order, every phase with the given
+// total and with done increasing from 0 to it.
+func checkProgress(t *testing.T, calls []progressCall, phases []string, totals []int) {
+	t.Helper()
+	var got []string
+	for k, c := range calls {
+		if k == 0 || c.phase != calls[k-1].phase {
+			got = append(got, c.phase)
+			if c.done != 0 {
+				t.Errorf("phase %s starts at %d, want 0", c.phase, c.done)
+			}
+		} else if c.done <= calls[k-1].done {
+			t.Errorf("phase %s reports %d after %d, want increasing", c.phase, c.done,
+				calls[k-1].done)
+		}
+		if p := len(got) - 1; p < len(totals) && c.total != totals[p] {
+			t.Errorf("phase %s total = %d, want %d", c.phase, c.total, totals[p])
+		}
+		if k == len(calls)-1 || calls[k+1].phase != c.phase {
+			if c.done != c.total {
+				t.Errorf("phase %s ends at %d, want %d", c.phase, c.done, c.total)
+			}
+		}
+	}
+	if !slices.Equal(got, phases) {
+		t.Errorf("phases = %v, want %v", got, phases)
+	}
+}
@@ -0,0 +1,297 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+// Package s2voronoipb implements the message Diagram described by s2voronoi.proto, the schema
+// of Voronoi diagrams exchanged between services, see s2voronoi.Diagram.ToProto and
+// s2voronoi.DiagramFromProto.
+//
+// The message is encoded by hand following the protocol buffer wire format, so that the module
+// does not depend on a protocol buffer runtime. Diagram is not a proto.Message; its encoding is
+// tested against golden bytes worked out from the wire format, with the fields in the order of
+// their numbers as written by generated code.
+package s2voronoipb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Version is the version of the message layout written by s2voronoi.Diagram.ToProto.
+const Version = 1
+
+// The field numbers of s2voronoi.proto.
+const (
+	fieldVersion       = 1
+	fieldEps           = 2
+	fieldSites         = 3
+	fieldVertices      = 4
+	fieldCellVertices  = 5
+	fieldCellNeighbors = 6
+	fieldCellOffsets   = 7
+	fieldWeights       = 8
+	fieldMergeTol      = 9
+)
+
+// maxField is the largest field number of the protocol buffer encoding.
+const maxField = 1<<29 - 1
+
+// The wire types of the protocol buffer encoding.
+const (
+	wireVarint = 0
+	wireI64    = 1
+	wireLen    = 2
+	wireI32    = 5
+)
+
+// Diagram is the message Diagram of s2voronoi.proto, a Voronoi diagram on the unit sphere.
+type Diagram struct {
+	// Version is the version of the message layout.
+	Version uint32
+	// Eps is the numerical precision of the diagram.
+	Eps float64
+	// Sites are the x, y, z coordinates of the sites, three per site.
+	Sites []float64
+	// Vertices are the x, y, z coordinates of the Voronoi vertices, three per vertex.
+	Vertices []float64
+	// CellVertices are the vertex indices of the rings of all cells.
+	CellVertices []uint32
+	// CellNeighbors are the indices of the cells across the edges of the rings.
+	CellNeighbors []uint32
+	// CellOffsets delimit the ring of each cell, one more than the number of sites.
+	CellOffsets []uint32
+	// Weights are the weights of the sites of a power diagram, empty otherwise.
+	Weights []float64
+	// MergeTol is the tolerance in radians of the merged vertices of the diagram, 0 if no
+	// vertices were merged.
+	MergeTol float64
+}
+
+// Marshal returns the wire encoding of the message. Repeated fields are packed and fields
+// with default values are omitted, following the proto3 encoding rules.
+func (m *Diagram) Marshal() ([]byte, error) {
+	var b []byte
+	if m.Version != 0 {
+		b = binary.AppendUvarint(b, fieldVersion<<3|wireVarint)
+		b = binary.AppendUvarint(b, uint64(m.Version))
+	}
+	if m.Eps != 0 {
+		b = binary.AppendUvarint(b, fieldEps<<3|wireI64)
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(m.Eps))
+	}
+	b = appendDoubles(b, fieldSites, m.Sites)
+	b = appendDoubles(b, fieldVertices, m.Vertices)
+	b = appendUint32s(b, fieldCellVertices, m.CellVertices)
+	b = appendUint32s(b, fieldCellNeighbors, m.CellNeighbors)
+	b = appendUint32s(b, fieldCellOffsets, m.CellOffsets)
+	b = appendDoubles(b, fieldWeights, m.Weights)
+	if m.MergeTol != 0 {
+		b = binary.AppendUvarint(b, fieldMergeTol<<3|wireI64)
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(m.MergeTol))
+	}
+	return b, nil
+}
+
+// Unmarshal replaces the message with the wire encoding b. Repeated fields are accepted both
+// packed and unpacked, and unknown fields are skipped.
+// It returns an error if b is truncated or malformed.
+func (m *Diagram) Unmarshal(b []byte) error {
+	*m = Diagram{}
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 || tag>>3 == 0 || tag>>3 > maxField {
+			return errors.New("s2voronoipb: invalid field tag")
+		}
+		b = b[n:]
+		field, wire := tag>>3, int(tag&7)
+
+		var err error
+		switch field {
+		case fieldVersion:
+			var v uint64
+			if v, b, err = consumeVarint(b, wire); err == nil {
+				m.Version = uint32(v)
+			}
+		case fieldEps:
+			var v uint64
+			if v, b, err = consumeFixed64(b, wire, field); err == nil {
+				m.Eps = math.Float64frombits(v)
+			}
+		case fieldMergeTol:
+			var v uint64
+			if v, b, err = consumeFixed64(b, wire, field); err == nil {
+				m.MergeTol = math.Float64frombits(v)
+			}
+		case fieldSites:
+			m.Sites, b, err = appendConsumedDoubles(m.Sites, b, wire, field)
+		case fieldVertices:
+			m.Vertices, b, err = appendConsumedDoubles(m.Vertices, b, wire, field)
+		case fieldWeights:
+			m.Weights, b, err = appendConsumedDoubles(m.Weights, b, wire, field)
+		case fieldCellVertices:
+			m.CellVertices, b, err = appendConsumedUint32s(m.CellVertices, b, wire, field)
+		case fieldCellNeighbors:
+			m.CellNeighbors, b, err = appendConsumedUint32s(m.CellNeighbors, b, wire, field)
+		case fieldCellOffsets:
+			m.CellOffsets, b, err = appendConsumedUint32s(m.CellOffsets, b, wire, field)
+		default:
+			b, err = skipField(b, wire, field)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendDoubles appends the packed field of xs to b, or nothing if xs is empty.
+func appendDoubles(b []byte, field uint64, xs []float64) []byte {
+	if len(xs) == 0 {
+		return b
+	}
+	b = binary.AppendUvarint(b, field<<3|wireLen)
+	b = binary.AppendUvarint(b, uint64(8*len(xs)))
+	for _, x := range xs {
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(x))
+	}
+	return b
+}
+
+// appendUint32s appends the packed field of xs to b, or nothing if xs is empty.
+func appendUint32s(b []byte, field uint64, xs []uint32) []byte {
+	if len(xs) == 0 {
+		return b
+	}
+	size := 0
+	for _, x := range xs {
+		size += uvarintLen(uint64(x))
+	}
+	b = binary.AppendUvarint(b, field<<3|wireLen)
+	b = binary.AppendUvarint(b, uint64(size))
+	for _, x := range xs {
+		b = binary.AppendUvarint(b, uint64(x))
+	}
+	return b
+}
+
+// uvarintLen returns the length of the varint encoding of x.
+func uvarintLen(x uint64) int {
+	n := 1
+	for ; x >= 0x80; x >>= 7 {
+		n++
+	}
+	return n
+}
+
+// consumeVarint reads a varint of wire type wire from b and returns it with the rest of b.
+func consumeVarint(b []byte, wire int) (uint64, []byte, error) {
+	if wire != wireVarint {
+		return 0, nil, fmt.Errorf("s2voronoipb: wire type %d of varint field", wire)
+	}
+	v, n := binary.Uvarint(b)
+	if n <= 0 {
+		return 0, nil, errors.New("s2voronoipb: invalid varint")
+	}
+	return v, b[n:], nil
+}
+
+// consumeBytes reads a length-delimited value from b and returns it with the rest of b.
+func consumeBytes(b []byte) ([]byte, []byte, error) {
+	size, n := binary.Uvarint(b)
+	if n <= 0 || size > uint64(len(b)-n) {
+		return nil, nil, errors.New("s2voronoipb: truncated length-delimited field")
+	}
+	return b[n : n+int(size)], b[n+int(size):], nil
+}
+
+// consumeFixed64 reads a 64-bit value of the field of wire type wire from b and returns it
+// with the rest of b.
+func consumeFixed64(b []byte, wire int, field uint64) (uint64, []byte, error) {
+	if wire != wireI64 {
+		return 0, nil, fmt.Errorf("s2voronoipb: wire type %d of double field %d", wire, field)
+	}
+	if len(b) < 8 {
+		return 0, nil, fmt.Errorf("s2voronoipb: truncated field %d", field)
+	}
+	return binary.LittleEndian.Uint64(b), b[8:], nil
+}
+
+// consumeDoubles reads the doubles of the field, packed or a single one depending on wire,
+// from b and returns them with the rest of b.
+func consumeDoubles(b []byte, wire int, field uint64) ([]float64, []byte, error) {
+	if wire != wireLen {
+		v, b, err := consumeFixed64(b, wire, field)
+		return []float64{math.Float64frombits(v)}, b, err
+	}
+	data, b, err := consumeBytes(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(data)%8 != 0 {
+		return nil, nil, fmt.Errorf("s2voronoipb: packed field %d of %d bytes", field, len(data))
+	}
+	xs := make([]float64, len(data)/8)
+	for k := range xs {
+		xs[k] = math.Float64frombits(binary.LittleEndian.Uint64(data[8*k:]))
+	}
+	return xs, b, nil
+}
+
+// appendConsumedDoubles appends the doubles of the field read from b to xs and returns them
+// with the rest of b.
+func appendConsumedDoubles(xs []float64, b []byte, wire int, field uint64) ([]float64, []byte,
+	error) {
+	v, b, err := consumeDoubles(b, wire, field)
+	return append(xs, v...), b, err
+}
+
+// appendConsumedUint32s appends the uint32 values of the field, packed or a single one
+// depending on wire, read from b to xs and returns them with the rest of b.
+func appendConsumedUint32s(xs []uint32, b []byte, wire int, field uint64) ([]uint32, []byte,
+	error) {
+	if wire == wireVarint {
+		v, b, err := consumeVarint(b, wire)
+		return append(xs, uint32(v)), b, err
+	}
+	if wire != wireLen {
+		return nil, nil, fmt.Errorf("s2voronoipb: wire type %d of uint32 field %d", wire, field)
+	}
+	data, b, err := consumeBytes(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	for len(data) > 0 {
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, nil, fmt.Errorf("s2voronoipb: invalid varint in packed field %d", field)
+		}
+		xs = append(xs, uint32(v))
+		data = data[n:]
+	}
+	return xs, b, nil
+}
+
+// skipField skips the value of an unknown field of wire type wire in b and returns the rest.
+func skipField(b []byte, wire int, field uint64) ([]byte, error) {
+	switch wire {
+	case wireVarint:
+		_, b, err := consumeVarint(b, wire)
+		return b, err
+	case wireI64, wireI32:
+		n := 8
+		if wire == wireI32 {
+			n = 4
+		}
+		if len(b) < n {
+			return nil, fmt.Errorf("s2voronoipb: truncated field %d", field)
+		}
+		return b[n:], nil
+	case wireLen:
+		_, b, err := consumeBytes(b)
+		return b, err
+	default:
+		return nil, fmt.Errorf("s2voronoipb: unsupported wire type %d of field %d", wire, field)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoipb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiagram_Marshal(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *Diagram
+	}{
+		{"zero", &Diagram{}},
+		{"scalars", &Diagram{Version: Version, Eps: 1e-12}},
+		{"full", &Diagram{
+			Version:       Version,
+			Eps:           1e-12,
+			Sites:         []float64{1, 0, 0, -1, 0, 0, 0, 1, 0, 0, -1, math.Copysign(0, -1), 0.5},
+			Vertices:      []float64{math.SmallestNonzeroFloat64, math.MaxFloat64, -2},
+			CellVertices:  []uint32{0, 1, 127, 128, 16383, 16384, math.MaxUint32},
+			CellNeighbors: []uint32{3, 2, 1},
+			CellOffsets:   []uint32{0, 3, 3, 7},
+			Weights:       []float64{0.25, -1},
+			MergeTol:      1e-6,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.m.Marshal()
+			if err != nil {
+				t.Fatalf("m.Marshal() error = %v, want nil", err)
+			}
+			var got Diagram
+			if err := got.Unmarshal(data); err != nil {
+				t.Fatalf("Unmarshal(m.Marshal()) error = %v, want nil", err)
+			}
+			if diff := cmp.Diff(tt.m, &got, cmp.Comparer(sameFloat)); diff != "" {
+				t.Errorf("Unmarshal(m.Marshal()) mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDiagram_Marshal_Golden(t *testing.T) {
+	// The bytes follow the protocol buffer wire format for s2voronoi.proto, with the fields in
+	// increasing order of their numbers as generated code writes them.
+	m := &Diagram{
+		Version:       Version,
+		Eps:           0.5,
+		Sites:         []float64{1, 2},
+		CellVertices:  []uint32{1, 300},
+		CellNeighbors: []uint32{2},
+		CellOffsets:   []uint32{0},
+		Weights:       []float64{-1},
+		MergeTol:      0.25,
+	}
+	golden := []byte{
+		0x08, 0x01, // version = 1
+		0x11, 0, 0, 0, 0, 0, 0, 0xe0, 0x3f, // eps = 0.5
+		0x1a, 0x10, // sites, 16 bytes
+		0, 0, 0, 0, 0, 0, 0xf0, 0x3f, // 1.0
+		0, 0, 0, 0, 0, 0, 0, 0x40, // 2.0
+		0x2a, 0x03, 0x01, 0xac, 0x02, // cell_vertices = [1, 300]
+		0x32, 0x01, 0x02, // cell_neighbors = [2]
+		0x3a, 0x01, 0x00, // cell_offsets = [0]
+		0x42, 0x08, 0, 0, 0, 0, 0, 0, 0xf0, 0xbf, // weights = [-1.0]
+		0x49, 0, 0, 0, 0, 0, 0, 0xd0, 0x3f, // merge_tol = 0.25
+	}
+	got, err := m.Marshal()
+	if err != nil {
+		t.Fatalf("m.Marshal() error = %v, want nil", err)
+	}
+	if !bytes.Equal(got, golden) {
+		t.Errorf("m.Marshal() = %x, want %x", got, golden)
+	}
+	var decoded Diagram
+	if err := decoded.Unmarshal(golden); err != nil {
+		t.Fatalf("Unmarshal(golden) error = %v, want nil", err)
+	}
+	if diff := cmp.Diff(m, &decoded); diff != "" {
+		t.Errorf("Unmarshal(golden) mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDiagram_Unmarshal_Wire(t *testing.T) {
+	// Encodings the wire format allows besides those of Marshal:
+	// unpacked repeated fields, split packed fields, repeated scalars and unknown fields.
+	var b []byte
+	b = appendTag(b, fieldVersion, wireVarint)
+	b = binary.AppendUvarint(b, 7)
+	b = appendTag(b, fieldSites, wireI64)
+	b = binary.LittleEndian.AppendUint64(b, math.Float64bits(1.5))
+	b = appendDoubles(b, fieldSites, []float64{2, 3})
+	b = appendTag(b, fieldCellOffsets, wireVarint)
+	b = binary.AppendUvarint(b, 300)
+	b = appendUint32s(b, fieldCellOffsets, []uint32{1, 2})
+	b = appendTag(b, 100, wireLen)
+	b = binary.AppendUvarint(b, 3)
+	b = append(b, "abc"...)
+	b = appendTag(b, 101, wireI32)
+	b = append(b, 1, 2, 3, 4)
+	b = appendTag(b, 102, wireI64)
+	b = binary.LittleEndian.AppendUint64(b, 5)
+	b = appendTag(b, 103, wireVarint)
+	b = binary.AppendUvarint(b, 1<<40)
+	b = appendTag(b, fieldVersion, wireVarint)
+	b = binary.AppendUvarint(b, Version)
+
+	want := &Diagram{
+		Version:     Version,
+		Sites:       []float64{1.5, 2, 3},
+		CellOffsets: []uint32{300, 1, 2},
+	}
+	got := &Diagram{Eps: 1, Weights: []float64{1}}
+	if err := got.Unmarshal(b); err != nil {
+		t.Fatalf("Unmarshal(...) error = %v, want nil", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unmarshal(...) mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDiagram_Unmarshal_Errors(t *testing.T) {
+	full := &Diagram{
+		Version:      Version,
+		Eps:          1e-12,
+		Sites:        []float64{1, 2, 3},
+		CellVertices: []uint32{1, 1000, 100000},
+	}
+	data, err := full.Marshal()
+	if err != nil {
+		t.Fatalf("m.Marshal() error = %v, want nil", err)
+	}
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"field zero", appendTag(nil, 0, wireVarint)},
+		{"invalid tag", []byte{0x80}},
+		{"version wire type", appendTag(nil, fieldVersion, wireI64)},
+		{"eps wire type", appendTag(nil, fieldEps, wireVarint)},
+		{"merge tol wire type", appendTag(nil, fieldMergeTol, wireLen)},
+		{"sites wire type", appendTag(nil, fieldSites, wireVarint)},
+		{"offsets wire type", appendTag(nil, fieldCellOffsets, wireI64)},
+		{"packed doubles", append(appendTag(nil, fieldSites, wireLen), 3, 0, 0, 0)},
+		{"packed varint", append(appendTag(nil, fieldCellOffsets, wireLen), 1, 0x80)},
+		{"length", append(appendTag(nil, fieldSites, wireLen), 0xff, 0xff, 0xff, 0xff, 0x0f)},
+		{"group", appendTag(nil, 100, 3)},
+	}
+	// Every proper prefix that does not end at a field boundary is truncated.
+	for _, k := range []int{1, 3, 10, 12, len(data) - 1} {
+		tests = append(tests, struct {
+			name string
+			data []byte
+		}{"truncated", data[:k]})
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Diagram
+			if err := m.Unmarshal(tt.data); err == nil {
+				t.Errorf("Unmarshal(%x) error = nil, want error", tt.data)
+			}
+		})
+	}
+}
+
+// Helpers
+
+// appendTag appends the tag of the field with the wire type to b.
+func appendTag(b []byte, field uint64, wire int) []byte {
+	return binary.AppendUvarint(b, field<<3|uint64(wire))
+}
+
+// sameFloat reports whether a and b have the same bits.
+func sameFloat(a, b float64) bool {
+	return math.Float64bits(a) == math.Float64bits(b)
+}
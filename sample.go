@@ -0,0 +1,84 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/golang/geo/r3"
+	"github.com/golang/geo/s2"
+)
+
+// maxSampleAttempts bounds the number of samples RandomPoint draws before it gives up and
+// returns the center of the fan. A sample is only rejected when rounding puts it on the wrong
+// side of an edge, so the bound is never reached in practice.
+const maxSampleAttempts = 64
+
+// RandomPoint returns a point distributed uniformly by area within the cell, drawing its
+// randomness from r. The cell is split into the fan triangles from the site to its edges, or
+// from the vertex average in a power diagram, whose sites may lie outside their cells. One of
+// them is picked with probability proportional to its area, and the point is sampled within it
+// with Arvo's area-preserving map. The returned point always satisfies ContainsPoint: the rare
+// samples rounded onto the wrong side of an edge are redrawn, and a cell of zero area yields the
+// center of its fan. It panics if the cell is empty.
+func (c Cell) RandomPoint(r *rand.Rand) s2.Point {
+	num := c.NumVertices()
+	if num == 0 {
+		panic("s2voronoi: RandomPoint: cell has no vertices")
+	}
+
+	center := c.fanCenter()
+	areas := make([]float64, num)
+	total := 0.0
+	for i := range num {
+		areas[i] = s2.PointArea(center, c.Vertex(i), c.Vertex((i+1)%num))
+		total += areas[i]
+	}
+	if total == 0 {
+		return center
+	}
+
+	for range maxSampleAttempts {
+		pick := r.Float64() * total
+		i := 0
+		for i < num-1 && pick >= areas[i] {
+			pick -= areas[i]
+			i++
+		}
+		p := sampleTriangle(center, c.Vertex(i), c.Vertex((i+1)%num), areas[i], r.Float64(),
+			r.Float64())
+		if c.ContainsPoint(p) {
+			return p
+		}
+	}
+	return center
+}
+
+// sampleTriangle maps u1 and u2, uniform in [0, 1), to a point uniform by area in the spherical
+// triangle ABC of the given area, see J. Arvo, "Stratified Sampling of Spherical Triangles",
+// SIGGRAPH 1995. u1 selects the point C' on the arc AC such that the triangle ABC' has u1 times
+// the area, and u2 selects the point on the arc BC'.
+func sampleTriangle(a, b, c s2.Point, area, u1, u2 float64) s2.Point {
+	alpha := s2.Angle(b, a, c).Radians()
+	sinAlpha, cosAlpha := math.Sincos(alpha)
+	s, t := math.Sincos(u1*area - alpha)
+	u := t - cosAlpha
+	v := s + sinAlpha*a.Dot(b.Vector)
+	q := ((v*t-u*s)*cosAlpha - v) / ((v*s + u*t) * sinAlpha)
+	q = math.Max(-1, math.Min(1, q))
+	cp := a.Mul(q).Add(tangentTowards(a, c.Vector).Mul(math.Sqrt((1 - q) * (1 + q))))
+
+	// 1 - cos|BC'| is taken from the chord, which keeps it accurate for small triangles.
+	h := u2 * cp.Sub(b.Vector).Norm2() / 2
+	p := b.Mul(1 - h).Add(tangentTowards(b, cp).Mul(math.Sqrt(h * (2 - h))))
+	return s2.Point{Vector: p.Normalize()}
+}
+
+// tangentTowards returns the unit tangent at the unit vector a pointing along the great circle
+// towards v, or the zero vector if v is parallel to a.
+func tangentTowards(a s2.Point, v r3.Vector) r3.Vector {
+	return v.Sub(a.Mul(v.Dot(a.Vector))).Normalize()
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/internal/fixtures"
+	"github.com/golang/geo/s2"
+)
+
+func TestCell_RandomPoint(t *testing.T) {
+	octahedron, err := NewDiagram(fixtures.Load("octahedron"))
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	tests := []struct {
+		name string
+		vd   *Diagram
+	}{
+		{"octahedron", octahedron},
+		{"random", mustNewDiagram(t, 100)},
+		{"stretched", mustNewStretchedDiagram(t)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(0))
+			for i := range tt.vd.NumCells() {
+				c := tt.vd.Cell(i)
+				for range 100 {
+					if p := c.RandomPoint(r); !c.ContainsPoint(p) {
+						t.Errorf("vd.Cell(%d).ContainsPoint(vd.Cell(%d).RandomPoint(r)) = false, "+
+							"want true", i, i)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCell_RandomPoint_Uniform(t *testing.T) {
+	octahedron, err := NewDiagram(fixtures.Load("octahedron"))
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	tests := []struct {
+		name string
+		c    Cell
+	}{
+		{"octahedron", octahedron.Cell(0)},
+		{"random", mustNewDiagram(t, 100).Cell(0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The bins split every fan triangle of the cell into four triangles at the
+			// midpoints of its edges, so they also probe the distribution within a triangle.
+			bins := uniformityBins(tt.c)
+			const samples = 20000
+			counts := make([]int, len(bins))
+			r := rand.New(rand.NewSource(1))
+			for range samples {
+				p := tt.c.RandomPoint(r)
+				for k, b := range bins {
+					if s2.Sign(b[0], b[1], p) && s2.Sign(b[1], b[2], p) && s2.Sign(b[2], b[0], p) {
+						counts[k]++
+						break
+					}
+				}
+			}
+
+			area := tt.c.Area()
+			chi2 := 0.0
+			for k, b := range bins {
+				want := samples * s2.PointArea(b[0], b[1], b[2]) / area
+				chi2 += (float64(counts[k]) - want) * (float64(counts[k]) - want) / want
+			}
+			// The statistic has len(bins)-1 degrees of freedom, so its mean is about
+			// len(bins) and its standard deviation about sqrt(2*len(bins)).
+			df := float64(len(bins) - 1)
+			if limit := df + 5*math.Sqrt(2*df); chi2 > limit {
+				t.Errorf("chi-square of %d samples over %d bins = %v, want at most %v", samples,
+					len(bins), chi2, limit)
+			}
+		})
+	}
+}
+
+func TestCell_RandomPoint_Empty(t *testing.T) {
+	vd, empty := mustNewEmptyCellDiagram(t)
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("vd.Cell(%d).RandomPoint(r) did not panic, want panic", empty)
+		}
+	}()
+	vd.Cell(empty).RandomPoint(rand.New(rand.NewSource(0)))
+}
+
+// Benchmarks
+
+func BenchmarkCell_RandomPoint(b *testing.B) {
+	vd, err := NewDiagram(fixtures.Load("octahedron"))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	c := vd.Cell(0)
+	r := rand.New(rand.NewSource(0))
+	for b.Loop() {
+		c.RandomPoint(r)
+	}
+}
+
+// Helpers
+
+// uniformityBins returns the triangles, CCW in the s2 convention, that split every fan
+// triangle of the cell into four at the midpoints of its edges.
+func uniformityBins(c Cell) [][3]s2.Point {
+	mid := func(a, b s2.Point) s2.Point { return s2.Point{Vector: a.Add(b.Vector).Normalize()} }
+	var bins [][3]s2.Point
+	num := c.NumVertices()
+	for i := range num {
+		// The ring is clockwise in the s2 convention, so the fan triangles are reversed.
+		s, a, b := c.Site(), c.Vertex((i+1)%num), c.Vertex(i)
+		sa, ab, bs := mid(s, a), mid(a, b), mid(b, s)
+		bins = append(bins, [3]s2.Point{s, sa, bs}, [3]s2.Point{sa, a, ab},
+			[3]s2.Point{bs, ab, b}, [3]s2.Point{sa, ab, bs})
+	}
+	return bins
+}
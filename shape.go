@@ -0,0 +1,85 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import "github.com/golang/geo/s2"
+
+// DiagramEdgesShape is an s2.Shape of dimension 1 whose edges are the Voronoi edges of a
+// diagram, see EdgesShape. Every edge is a chain of its own, so the edge with ID i is chain i
+// at offset 0 and is DiagramEdge(i) in the diagram. The endpoints are read from the Vertices
+// of the diagram, so the shape must be rebuilt after the diagram is modified.
+type DiagramEdgesShape struct {
+	// Shape is always nil. It is embedded only for the unexported methods of s2.Shape, which
+	// cannot be implemented outside package s2 and are not called by it; all the other methods
+	// are implemented by DiagramEdgesShape.
+	s2.Shape
+
+	vertices s2.PointVector
+	edges    []Edge
+}
+
+// EdgesShape returns the unique Voronoi edges of the diagram, in the order of Edges, as an
+// s2.Shape for use in an s2.ShapeIndex, e.g. to find the edges closest to a point with an
+// s2.EdgeQuery or the edges crossing other geometry. The vertex points are not copied.
+func (d *Diagram) EdgesShape() *DiagramEdgesShape {
+	return &DiagramEdgesShape{vertices: d.Vertices, edges: d.Edges()}
+}
+
+// DiagramEdge returns the Voronoi edge with the given edge ID, with the cells on both sides.
+// It panics if id is out of range.
+func (s *DiagramEdgesShape) DiagramEdge(id int) Edge {
+	return s.edges[id]
+}
+
+// NumEdges implements s2.Shape.
+func (s *DiagramEdgesShape) NumEdges() int {
+	return len(s.edges)
+}
+
+// Edge implements s2.Shape.
+func (s *DiagramEdgesShape) Edge(id int) s2.Edge {
+	e := s.edges[id].Vertices
+	return s2.Edge{V0: s.vertices[e[0]], V1: s.vertices[e[1]]}
+}
+
+// ReferencePoint implements s2.Shape. The shape has no interior.
+func (s *DiagramEdgesShape) ReferencePoint() s2.ReferencePoint {
+	return s2.OriginReferencePoint(false)
+}
+
+// NumChains implements s2.Shape.
+func (s *DiagramEdgesShape) NumChains() int {
+	return len(s.edges)
+}
+
+// Chain implements s2.Shape.
+func (s *DiagramEdgesShape) Chain(chainID int) s2.Chain {
+	return s2.Chain{Start: chainID, Length: 1}
+}
+
+// ChainEdge implements s2.Shape.
+func (s *DiagramEdgesShape) ChainEdge(chainID, offset int) s2.Edge {
+	return s.Edge(chainID + offset)
+}
+
+// ChainPosition implements s2.Shape.
+func (s *DiagramEdgesShape) ChainPosition(edgeID int) s2.ChainPosition {
+	return s2.ChainPosition{ChainID: edgeID, Offset: 0}
+}
+
+// Dimension implements s2.Shape.
+func (s *DiagramEdgesShape) Dimension() int {
+	return 1
+}
+
+// IsEmpty implements s2.Shape.
+func (s *DiagramEdgesShape) IsEmpty() bool {
+	return len(s.edges) == 0
+}
+
+// IsFull implements s2.Shape.
+func (s *DiagramEdgesShape) IsFull() bool {
+	return false
+}
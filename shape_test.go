@@ -0,0 +1,95 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"testing"
+
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+func TestDiagram_EdgesShape(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	s := vd.EdgesShape()
+	edges := vd.Edges()
+	if s.NumEdges() != len(edges) || s.NumChains() != len(edges) {
+		t.Fatalf("NumEdges(), NumChains() = %d, %d, want %d", s.NumEdges(), s.NumChains(),
+			len(edges))
+	}
+	if s.Dimension() != 1 || s.IsEmpty() || s.IsFull() || s.ReferencePoint().Contained {
+		t.Errorf("EdgesShape() = dimension %d, empty %v, full %v, contains %v, want 1 and false",
+			s.Dimension(), s.IsEmpty(), s.IsFull(), s.ReferencePoint().Contained)
+	}
+	for id, e := range edges {
+		want := s2.Edge{V0: vd.Vertices[e.Vertices[0]], V1: vd.Vertices[e.Vertices[1]]}
+		if got := s.Edge(id); got != want {
+			t.Errorf("Edge(%d) = %v, want %v", id, got, want)
+		}
+		pos := s.ChainPosition(id)
+		if c := s.Chain(pos.ChainID); c.Start+pos.Offset != id || c.Length != 1 {
+			t.Errorf("Chain(ChainPosition(%d)) = %v at %v, want edge %d", id, c, pos, id)
+		}
+		if got := s.ChainEdge(pos.ChainID, pos.Offset); got != want {
+			t.Errorf("ChainEdge(%v) = %v, want %v", pos, got, want)
+		}
+		if got := s.DiagramEdge(id); got != e {
+			t.Errorf("DiagramEdge(%d) = %v, want %v", id, got, e)
+		}
+	}
+}
+
+func TestDiagram_EdgesShape_ClosestEdge(t *testing.T) {
+	vd := mustNewDiagram(t, 1000)
+	s := vd.EdgesShape()
+	index := s2.NewShapeIndex()
+	id := index.Add(s)
+	query := s2.NewClosestEdgeQuery(index, s2.NewClosestEdgeQueryOptions().MaxResults(1))
+	for _, p := range utils.GenerateRandomPoints(1000, 1) {
+		results := query.FindEdges(s2.NewMinDistanceToPointTarget(p))
+		if len(results) != 1 || results[0].ShapeID() != id {
+			t.Fatalf("FindEdges(%v) = %v, want 1 edge of the shape", p, results)
+		}
+		want := s1.InfChordAngle()
+		for k := range s.NumEdges() {
+			e := s.Edge(k)
+			want = min(want, s2.ChordAngleBetweenPoints(p, s2.Project(p, e.V0, e.V1)))
+		}
+		e := s.Edge(int(results[0].EdgeID()))
+		got := s2.ChordAngleBetweenPoints(p, s2.Project(p, e.V0, e.V1))
+		if float64(got-want) > 1e-15 || float64(results[0].Distance()-want) > 1e-15 {
+			t.Errorf("FindEdges(%v) = edge %d at %v, want distance %v", p, results[0].EdgeID(),
+				got, want)
+		}
+	}
+}
+
+func TestDiagram_EdgesShape_NoCopy(t *testing.T) {
+	vd := mustNewDiagram(t, 10)
+	s := vd.EdgesShape()
+	e := s.DiagramEdge(0)
+	vd.Vertices[e.Vertices[0]] = s2.PointFromCoords(0, 0, 1)
+	if got := s.Edge(0).V0; got != vd.Vertices[e.Vertices[0]] {
+		t.Errorf("Edge(0).V0 = %v, want the vertex of the diagram %v", got,
+			vd.Vertices[e.Vertices[0]])
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDiagram_EdgesShape_ClosestEdge(b *testing.B) {
+	vd, err := NewDiagram(utils.GenerateRandomPoints(100000, 0))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	index := s2.NewShapeIndex()
+	index.Add(vd.EdgesShape())
+	query := s2.NewClosestEdgeQuery(index, s2.NewClosestEdgeQueryOptions().MaxResults(1))
+	queries := utils.GenerateRandomPoints(1024, 1)
+	for i := 0; b.Loop(); i++ {
+		query.FindEdges(s2.NewMinDistanceToPointTarget(queries[i%len(queries)]))
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// CellShape is the 2-dimensional shape of a cell in the index returned by ShapeIndex: the
+// loop of the cell, see Cell.Loop, and the index of its site.
+type CellShape struct {
+	*s2.Loop
+	Site int
+}
+
+// ShapeIndex returns an s2.ShapeIndex holding every cell as a *CellShape, for containment and
+// intersection queries against arbitrary s2 geometry, e.g. with s2.ContainsPointQuery or
+// s2.CrossingEdgeQuery. The shape ID of a cell is its site index, and empty cells hold an
+// empty loop. Vertices joined by edges of at most eps, e.g. of cocircular sites, are replaced
+// by one of them, so that no loop has a degenerate edge. Adjacent cells then still share their
+// vertices exactly, and with the semi-open vertex model every point is contained in exactly one
+// cell, which is an exact alternative to FindCell away from ties.
+//
+// The index is built on first use and shared by later calls until the diagram is modified,
+// e.g. by Relax or AddSite. It must not be modified by the caller.
+func (d *Diagram) ShapeIndex() *s2.ShapeIndex {
+	c := d.caches()
+	c.shapeIndexOnce.Do(func() {
+		root, _ := d.vertexGroups(s1.ChordAngleFromSquaredLength(d.eps * d.eps))
+		index := s2.NewShapeIndex()
+		for i, cell := range d.Cells() {
+			index.Add(&CellShape{Loop: cell.shapeLoop(root), Site: i})
+		}
+		index.Build()
+		c.shapeIndex = index
+	})
+	return c.shapeIndex
+}
+
+// shapeLoop returns the loop of the cell like Loop, with every vertex replaced by the vertex
+// root[v] of its group and without repeated consecutive vertices, or an empty loop if fewer
+// than 3 remain. Unlike in LoopValidated, the replacement is the same for all cells, so that
+// the loops of neighbors share their edges.
+func (c Cell) shapeLoop(root []int) *s2.Loop {
+	ring := c.VertexIndices()
+	pts := make([]s2.Point, 0, len(ring))
+	for i := len(ring) - 1; i >= 0; i-- {
+		if v := c.d.Vertices[root[ring[i]]]; len(pts) == 0 || v != pts[len(pts)-1] {
+			pts = append(pts, v)
+		}
+	}
+	for len(pts) > 1 && pts[0] == pts[len(pts)-1] {
+		pts = pts[:len(pts)-1]
+	}
+	if len(pts) < 3 {
+		return s2.EmptyLoop()
+	}
+	return s2.LoopFromPoints(pts)
+}
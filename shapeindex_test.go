@@ -0,0 +1,103 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"testing"
+
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s2"
+)
+
+func TestDiagram_ShapeIndex(t *testing.T) {
+	empty, _ := mustNewEmptyCellDiagram(t)
+	tests := []struct {
+		name string
+		vd   *Diagram
+	}{
+		{"random", mustNewDiagram(t, 1000)},
+		// Neighbors of the same radius have coincident vertices, and their points are tied.
+		{"cocircular", mustNewFixtureDiagram(t, "cocircular-rings")},
+		{"octahedron", mustNewFixtureDiagram(t, "octahedron")},
+		{"empty cell", empty},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd := tt.vd
+			index := vd.ShapeIndex()
+			if index.Len() != vd.NumCells() {
+				t.Fatalf("ShapeIndex().Len() = %d, want %d", index.Len(), vd.NumCells())
+			}
+			for i := range vd.NumCells() {
+				if s, ok := index.Shape(int32(i)).(*CellShape); !ok || s.Site != i {
+					t.Errorf("ShapeIndex().Shape(%d) = %v, want the cell shape of site %d",
+						i, index.Shape(int32(i)), i)
+				}
+			}
+
+			// Every point is in exactly one cell, whose site is nearest to it.
+			q := s2.NewContainsPointQuery(index, s2.VertexModelSemiOpen)
+			for _, p := range utils.GenerateRandomPoints(10000, 1) {
+				shapes := q.ContainingShapes(p)
+				if len(shapes) != 1 {
+					t.Errorf("ContainingShapes(%v) = %d shapes, want 1", p, len(shapes))
+					continue
+				}
+				// A cell of a site tied with the nearest one is also right.
+				got, want := shapes[0].(*CellShape).Site, vd.FindCellIndex(p)
+				if got != want && vd.smallestTied(p, got) != want {
+					t.Errorf("ContainingShapes(%v) = cell %d, want %d", p, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDiagram_ShapeIndex_Invalidate(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	index := vd.ShapeIndex()
+	if vd.ShapeIndex() != index {
+		t.Errorf("ShapeIndex() = new index, want the index of the first call")
+	}
+
+	p := s2.PointFromCoords(0.3, -0.2, 0.9)
+	i, err := vd.AddSite(p)
+	if err != nil {
+		t.Fatalf("AddSite(%v) error = %v, want nil", p, err)
+	}
+	index = vd.ShapeIndex()
+	if index.Len() != vd.NumCells() {
+		t.Fatalf("after AddSite, ShapeIndex().Len() = %d, want %d", index.Len(), vd.NumCells())
+	}
+	q := s2.NewContainsPointQuery(index, s2.VertexModelSemiOpen)
+	if shapes := q.ContainingShapes(p); len(shapes) != 1 || shapes[0].(*CellShape).Site != i {
+		t.Errorf("after AddSite, ContainingShapes(%v) = %v, want the cell of site %d", p,
+			shapes, i)
+	}
+
+	if _, err := vd.Relax(1); err != nil {
+		t.Fatalf("Relax(1) error = %v, want nil", err)
+	}
+	q = s2.NewContainsPointQuery(vd.ShapeIndex(), s2.VertexModelSemiOpen)
+	for j, s := range vd.Sites {
+		if shapes := q.ContainingShapes(s); len(shapes) != 1 || shapes[0].(*CellShape).Site != j {
+			t.Errorf("after Relax, ContainingShapes(Sites[%d]) = %v, want the cell of site %d",
+				j, shapes, j)
+		}
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDiagram_ShapeIndex(b *testing.B) {
+	vd, err := NewDiagram(utils.GenerateRandomPoints(10000, 0))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	for b.Loop() {
+		vd.invalidateCaches()
+		vd.ShapeIndex()
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+// SetSiteData attaches the value v to the site i, e.g. a biome or a color, so that it follows
+// the site when the diagram changes: Relax and MoveSite keep it, AddSite and AddSites give the
+// new sites nil, and RemoveSite moves the value of the last site to the index it takes over.
+// Rebuild and the constructors start without values. The values are copied shallowly by
+// Relaxed and ignored by Equal.
+// It panics if i is out of range.
+func (d *Diagram) SetSiteData(i int, v any) {
+	d.checkCellIndex(i)
+	if d.siteData == nil {
+		d.siteData = make([]any, d.NumCells())
+	}
+	d.siteData[i] = v
+}
+
+// SiteData returns the value attached to the site i by SetSiteData, or nil.
+// It panics if i is out of range.
+func (d *Diagram) SiteData(i int) any {
+	d.checkCellIndex(i)
+	if d.siteData == nil {
+		return nil
+	}
+	return d.siteData[i]
+}
+
+// extendSiteData returns the site values data extended with nil up to n sites, or nil if data
+// is nil.
+func extendSiteData(data []any, n int) []any {
+	if data == nil {
+		return nil
+	}
+	return append(data, make([]any, n-len(data))...)
+}
+
+// removeSiteData returns the site values data without the value of site i, whose index is
+// taken over by the last site like in RemoveSite, or nil if data is nil. It modifies data.
+func removeSiteData(data []any, i int) []any {
+	if data == nil {
+		return nil
+	}
+	n := len(data)
+	data[i] = data[n-1]
+	data[n-1] = nil
+	return data[:n-1]
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/golang/geo/s2"
+)
+
+func TestDiagram_SiteData(t *testing.T) {
+	fresh := s2.PointFromLatLng(s2.LatLngFromDegrees(10, 10))
+	tests := []struct {
+		name string
+		opts []DiagramOption
+	}{
+		{"default", nil},
+		{"order independent", []DiagramOption{WithOrderIndependentOutput()}},
+		{"vertex merging", []DiagramOption{WithVertexMerging(1e-9)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd, err := NewDiagram(latLngGrid(5, 8), tt.opts...)
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			// Every site carries its original index, want[i] is the index expected at site i.
+			orig := slices.Clone(vd.Sites)
+			want := make([]any, vd.NumCells())
+			for i := range vd.NumCells() {
+				vd.SetSiteData(i, i)
+				want[i] = i
+			}
+			check := func(op string) {
+				t.Helper()
+				for i := range vd.NumCells() {
+					if got := vd.SiteData(i); got != want[i] {
+						t.Errorf("after %s vd.SiteData(%d) = %v, want %v", op, i, got, want[i])
+					}
+				}
+			}
+
+			if _, err := vd.Relax(3); err != nil {
+				t.Fatalf("vd.Relax(3) error = %v, want nil", err)
+			}
+			check("Relax")
+			for i, p := range vd.Sites {
+				if d := p.Distance(orig[i]); d > 0.3 {
+					t.Errorf("after Relax site %d moved by %v, want at most 0.3", i, d)
+				}
+			}
+
+			if err := vd.RemoveSite(3); err != nil {
+				t.Fatalf("vd.RemoveSite(3) error = %v, want nil", err)
+			}
+			want[3] = want[len(want)-1]
+			want = want[:len(want)-1]
+			check("RemoveSite")
+
+			i, err := vd.AddSite(fresh)
+			if err != nil {
+				t.Fatalf("vd.AddSite(...) error = %v, want nil", err)
+			}
+			want = append(want, nil)
+			check("AddSite")
+			vd.SetSiteData(i, "fresh")
+			want[i] = "fresh"
+
+			if _, err := vd.AddSites(s2.PointVector{s2.PointFromCoords(1, 2, 3)}); err != nil {
+				t.Fatalf("vd.AddSites(...) error = %v, want nil", err)
+			}
+			want = append(want, nil)
+			check("AddSites")
+
+			moved := s2.PointFromLatLng(s2.LatLngFromDegrees(12, 11))
+			if _, err := vd.MoveSite(i, moved); err != nil {
+				t.Fatalf("vd.MoveSite(...) error = %v, want nil", err)
+			}
+			check("MoveSite")
+
+			rd, _, err := vd.Relaxed(1)
+			if err != nil {
+				t.Fatalf("vd.Relaxed(1) error = %v, want nil", err)
+			}
+			rd.SetSiteData(0, "changed")
+			check("Relaxed")
+		})
+	}
+}
+
+func TestDiagram_SiteData_Unset(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	if got := vd.SiteData(5); got != nil {
+		t.Errorf("vd.SiteData(5) = %v, want nil", got)
+	}
+	vd.SetSiteData(5, "x")
+	if err := vd.Rebuild(slices.Clone(vd.Sites)); err != nil {
+		t.Fatalf("vd.Rebuild(...) error = %v, want nil", err)
+	}
+	if got := vd.SiteData(5); got != nil {
+		t.Errorf("after Rebuild vd.SiteData(5) = %v, want nil", got)
+	}
+}
+
+func TestDiagram_SiteData_Panic(t *testing.T) {
+	vd := mustNewDiagram(t, 10)
+	for _, i := range []int{-1, vd.NumCells()} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("vd.SetSiteData(%d, ...) did not panic, want panic", i)
+				}
+			}()
+			vd.SetSiteData(i, 0)
+		}()
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("vd.SiteData(%d) did not panic, want panic", i)
+				}
+			}()
+			vd.SiteData(i)
+		}()
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// tinyCellRatio is the fraction of the mean cell area below which Stats counts a cell as tiny.
+const tinyCellRatio = 1e-6
+
+// DiagramStats holds summary quality metrics of a Voronoi diagram.
+type DiagramStats struct {
+	// MinArea, MeanArea and MaxArea are the smallest, the mean and the largest cell area in
+	// steradians.
+	MinArea, MeanArea, MaxArea float64
+	// MinNeighbors, MeanNeighbors and MaxNeighbors are the smallest, the mean and the largest
+	// number of neighbors of a cell.
+	MinNeighbors  int
+	MeanNeighbors float64
+	MaxNeighbors  int
+	// MinEdgeLength and MaxEdgeLength are the lengths of the shortest and the longest edge.
+	MinEdgeLength, MaxEdgeLength s1.Angle
+	// NumTinyCells is the number of near-degenerate cells, whose area is below a millionth of
+	// the mean area, including empty cells.
+	NumTinyCells int
+	// MaxAnisotropy is the largest Anisotropy of all cell moments.
+	MaxAnisotropy float64
+}
+
+// String returns a one-line summary of the metrics for logging.
+func (s DiagramStats) String() string {
+	return fmt.Sprintf("area [%.6g %.6g %.6g] neighbors [%d %.4g %d] edge length [%v %v] "+
+		"tiny cells %d max anisotropy %.4g", s.MinArea, s.MeanArea, s.MaxArea, s.MinNeighbors,
+		s.MeanNeighbors, s.MaxNeighbors, s.MinEdgeLength, s.MaxEdgeLength, s.NumTinyCells,
+		s.MaxAnisotropy)
+}
+
+// Stats computes summary quality metrics of the diagram, e.g. to compare a diagram before and
+// after Relax. The areas are those of CellAreas.
+func (d *Diagram) Stats() DiagramStats {
+	n := d.NumCells()
+	if n == 0 {
+		return DiagramStats{}
+	}
+	areas := d.CellAreas()
+	s := DiagramStats{
+		MinArea:       math.Inf(1),
+		MinNeighbors:  math.MaxInt,
+		MinEdgeLength: s1.InfAngle(),
+	}
+	var totalArea compensatedSum
+	totalNeighbors := 0
+	for i, c := range d.Cells() {
+		s.MinArea = min(s.MinArea, areas[i])
+		s.MaxArea = max(s.MaxArea, areas[i])
+		totalArea.Add(areas[i])
+
+		num := c.NumNeighbors()
+		s.MinNeighbors = min(s.MinNeighbors, num)
+		s.MaxNeighbors = max(s.MaxNeighbors, num)
+		totalNeighbors += num
+
+		for k := range c.NumVertices() {
+			e := c.Edge(k)
+			l := s2.ChordAngleBetweenPoints(e.V0, e.V1).Angle()
+			s.MinEdgeLength = min(s.MinEdgeLength, l)
+			s.MaxEdgeLength = max(s.MaxEdgeLength, l)
+		}
+		s.MaxAnisotropy = max(s.MaxAnisotropy, c.Moments().Anisotropy)
+	}
+	s.MeanArea = totalArea.Value() / float64(n)
+	s.MeanNeighbors = float64(totalNeighbors) / float64(n)
+	if math.IsInf(float64(s.MinEdgeLength), 1) {
+		s.MinEdgeLength = 0
+	}
+	for _, a := range areas {
+		if a < tinyCellRatio*s.MeanArea {
+			s.NumTinyCells++
+		}
+	}
+	return s
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/internal/fixtures"
+	"github.com/golang/geo/s1"
+)
+
+func TestDiagram_Stats(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	want := DiagramStats{
+		MinArea:       math.Inf(1),
+		MinNeighbors:  math.MaxInt,
+		MinEdgeLength: s1.InfAngle(),
+	}
+	neighbors := 0
+	for i := range vd.NumCells() {
+		c := vd.Cell(i)
+		want.MaxAnisotropy = max(want.MaxAnisotropy, c.Moments().Anisotropy)
+		want.MinArea = min(want.MinArea, c.Area())
+		want.MaxArea = max(want.MaxArea, c.Area())
+		want.MinNeighbors = min(want.MinNeighbors, c.NumNeighbors())
+		want.MaxNeighbors = max(want.MaxNeighbors, c.NumNeighbors())
+		neighbors += c.NumNeighbors()
+		for k := range c.NumVertices() {
+			l := c.Vertex(k).Distance(c.Vertex((k + 1) % c.NumVertices()))
+			want.MinEdgeLength = min(want.MinEdgeLength, l)
+			want.MaxEdgeLength = max(want.MaxEdgeLength, l)
+		}
+	}
+	want.MeanNeighbors = float64(neighbors) / float64(vd.NumCells())
+
+	got := vd.Stats()
+	if got.MaxAnisotropy != want.MaxAnisotropy {
+		t.Errorf("vd.Stats().MaxAnisotropy = %v, want %v", got.MaxAnisotropy, want.MaxAnisotropy)
+	}
+	for _, f := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"MinArea", got.MinArea, want.MinArea},
+		{"MeanArea", got.MeanArea, 4 * math.Pi / float64(vd.NumCells())},
+		{"MaxArea", got.MaxArea, want.MaxArea},
+		{"MinEdgeLength", got.MinEdgeLength.Radians(), want.MinEdgeLength.Radians()},
+		{"MaxEdgeLength", got.MaxEdgeLength.Radians(), want.MaxEdgeLength.Radians()},
+	} {
+		if math.Abs(f.got-f.want) > 1e-12 {
+			t.Errorf("vd.Stats().%s = %v, want %v", f.name, f.got, f.want)
+		}
+	}
+	if got.MinNeighbors != want.MinNeighbors || got.MaxNeighbors != want.MaxNeighbors ||
+		got.MeanNeighbors != want.MeanNeighbors {
+		t.Errorf("vd.Stats() neighbors = [%d %v %d], want [%d %v %d]", got.MinNeighbors,
+			got.MeanNeighbors, got.MaxNeighbors, want.MinNeighbors, want.MeanNeighbors,
+			want.MaxNeighbors)
+	}
+	if got.NumTinyCells != 0 {
+		t.Errorf("vd.Stats().NumTinyCells = %d, want 0", got.NumTinyCells)
+	}
+}
+
+func TestDiagram_Stats_Octahedron(t *testing.T) {
+	vd, err := NewDiagram(fixtures.Load("octahedron"))
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	// The cells are the faces of a cube projected onto the sphere.
+	area := 4 * math.Pi / 6
+	edge := math.Acos(1.0 / 3)
+	got := vd.Stats()
+	for _, f := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"MinArea", got.MinArea, area},
+		{"MeanArea", got.MeanArea, area},
+		{"MaxArea", got.MaxArea, area},
+		{"MeanNeighbors", got.MeanNeighbors, 4},
+		{"MinEdgeLength", got.MinEdgeLength.Radians(), edge},
+		{"MaxEdgeLength", got.MaxEdgeLength.Radians(), edge},
+	} {
+		if math.Abs(f.got-f.want) > 1e-12 {
+			t.Errorf("vd.Stats().%s = %v, want %v", f.name, f.got, f.want)
+		}
+	}
+	if got.MinNeighbors != 4 || got.MaxNeighbors != 4 || got.NumTinyCells != 0 {
+		t.Errorf("vd.Stats() neighbors [%d %d], tiny cells %d, want [4 4], 0", got.MinNeighbors,
+			got.MaxNeighbors, got.NumTinyCells)
+	}
+	if s := got.String(); !strings.Contains(s, "neighbors [4 4 4]") {
+		t.Errorf("vd.Stats().String() = %q, want it to contain %q", s, "neighbors [4 4 4]")
+	}
+}
+
+func TestDiagram_Stats_EmptyCell(t *testing.T) {
+	vd, _ := mustNewEmptyCellDiagram(t)
+	got := vd.Stats()
+	if got.NumTinyCells != 1 || got.MinArea != 0 || got.MinNeighbors != 0 {
+		t.Errorf("vd.Stats() tiny cells %d, min area %v, min neighbors %d, want 1, 0, 0",
+			got.NumTinyCells, got.MinArea, got.MinNeighbors)
+	}
+}
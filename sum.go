@@ -0,0 +1,50 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"math"
+
+	"github.com/golang/geo/r3"
+)
+
+// compensatedSum accumulates float64 values using Neumaier's variant of Kahan summation,
+// which keeps the rounding error independent of the number of terms.
+type compensatedSum struct {
+	sum, c float64
+}
+
+// Add adds x to the sum.
+func (s *compensatedSum) Add(x float64) {
+	t := s.sum + x
+	if math.Abs(s.sum) >= math.Abs(x) {
+		s.c += (s.sum - t) + x
+	} else {
+		s.c += (x - t) + s.sum
+	}
+	s.sum = t
+}
+
+// Value returns the accumulated sum.
+func (s compensatedSum) Value() float64 {
+	return s.sum + s.c
+}
+
+// compensatedVector accumulates r3.Vector values component-wise with compensatedSum.
+type compensatedVector struct {
+	x, y, z compensatedSum
+}
+
+// Add adds v to the sum.
+func (s *compensatedVector) Add(v r3.Vector) {
+	s.x.Add(v.X)
+	s.y.Add(v.Y)
+	s.z.Add(v.Z)
+}
+
+// Value returns the accumulated sum.
+func (s compensatedVector) Value() r3.Vector {
+	return r3.Vector{X: s.x.Value(), Y: s.y.Value(), Z: s.z.Value()}
+}
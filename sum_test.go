@@ -0,0 +1,89 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/golang/geo/s2"
+)
+
+func TestCompensatedSum(t *testing.T) {
+	random := rand.New(rand.NewSource(0))
+	values := make([]float64, 10000)
+	for i := range values {
+		values[i] = math.Pow(10, float64(random.Intn(20)-10)) * (random.Float64() - 0.5)
+	}
+	// Cancel the large terms so that the total is tiny compared to the summands.
+	values = append(values, values...)
+	for i := len(values) / 2; i < len(values); i++ {
+		values[i] = -values[i] * (1 + 1e-9)
+	}
+
+	ref := new(big.Float).SetPrec(2048)
+	naive := 0.0
+	var comp compensatedSum
+	for _, v := range values {
+		ref.Add(ref, new(big.Float).SetFloat64(v))
+		naive += v
+		comp.Add(v)
+	}
+	want, _ := ref.Float64()
+
+	if d := ulpDistance(comp.Value(), want); d > 4 {
+		t.Errorf("compensatedSum = %v, want %v (%v ulps apart)", comp.Value(), want, d)
+	}
+	if d := ulpDistance(naive, want); d <= 4 {
+		t.Errorf("naive sum = %v is within %v ulps of %v, want a less accurate fixture", naive,
+			d, want)
+	}
+}
+
+func TestCell_centroid_Compensated(t *testing.T) {
+	// The vertices of a dense ring nearly cancel, leaving a tiny horizontal component.
+	const n = 10000
+	vertices := make(s2.PointVector, n)
+	indices := make([]int, n)
+	for i := range n {
+		theta := 2 * math.Pi * float64(i) / n
+		vertices[i] = s2.PointFromCoords(math.Cos(theta)+1e-9, math.Sin(theta), 0.1)
+		indices[i] = i
+	}
+	d := &Diagram{
+		Sites:         s2.PointVector{s2.PointFromCoords(0, 0, 1)},
+		Vertices:      vertices,
+		CellVertices:  indices,
+		CellNeighbors: make([]int, n),
+		CellOffsets:   []int{0, n},
+		eps:           DefaultEps,
+	}
+
+	ref := new(big.Float).SetPrec(2048)
+	naive := 0.0
+	for _, v := range vertices {
+		ref.Add(ref, new(big.Float).SetFloat64(v.X))
+		naive += v.X
+	}
+	sum, _ := ref.Float64()
+	want := sum / n
+
+	got := d.Cell(0).centroid().X
+	if dist := ulpDistance(got, want); dist > 4 {
+		t.Errorf("c.centroid().X = %v, want %v (%v ulps apart)", got, want, dist)
+	}
+	if dist := ulpDistance(naive/n, want); dist <= 4 {
+		t.Errorf("naive centroid X = %v is within %v ulps of %v, want a less accurate fixture",
+			naive/n, dist, want)
+	}
+}
+
+// ulpDistance returns the distance between a and b in units in the last place of b.
+func ulpDistance(a, b float64) float64 {
+	ulp := math.Nextafter(math.Abs(b), math.Inf(1)) - math.Abs(b)
+	return math.Abs(a-b) / ulp
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/golang/geo/s2"
+)
+
+type topoJSONTopology struct {
+	Type      string                    `json:"type"`
+	Transform *topoJSONTransform        `json:"transform,omitempty"`
+	Objects   map[string]topoJSONObject `json:"objects"`
+	Arcs      any                       `json:"arcs"`
+}
+
+type topoJSONTransform struct {
+	Scale     [2]float64 `json:"scale"`
+	Translate [2]float64 `json:"translate"`
+}
+
+type topoJSONObject struct {
+	Type       string             `json:"type"`
+	Geometries []topoJSONGeometry `json:"geometries"`
+}
+
+type topoJSONGeometry struct {
+	// Type is nil for an empty cell.
+	Type       *string           `json:"type"`
+	Arcs       [][]int           `json:"arcs,omitempty"`
+	Properties geoJSONProperties `json:"properties"`
+}
+
+// ToTopoJSON writes the cells of the diagram to w as a TopoJSON topology whose object "cells"
+// is a GeometryCollection with one Polygon per cell in site order, with the properties of
+// ToGeoJSON. Every Voronoi edge of Edges is written once, as the arc of the same index from its
+// first to its second vertex, and each cell is the ring of the arcs of its edges, a reference
+// ~a, that is -a-1, denoting arc a reversed. The positions are [lng, lat] in degrees. Unlike
+// ToGeoJSON the edges are not cut at the antimeridian, and the rings are clockwise, so that
+// consumers that read edges as great circle arcs, such as d3-geo, render the cells and their
+// shared borders without gaps. Empty cells are geometries of type null.
+// If quantization is at least 2 the positions are quantized to that many values along each
+// axis of the bounding box of the vertices and the arcs are delta-encoded, as the TopoJSON
+// transform describes; if it is 0 they are written unquantized.
+// It returns an error if quantization is negative or 1, or if writing fails.
+func (d *Diagram) ToTopoJSON(w io.Writer, quantization int) error {
+	if quantization < 0 || quantization == 1 {
+		return fmt.Errorf("s2voronoi: quantization must be 0 or at least 2, got %d", quantization)
+	}
+
+	edges := d.Edges()
+	positions := make([][2]float64, len(d.Vertices))
+	for i, v := range d.Vertices {
+		ll := s2.LatLngFromPoint(v)
+		positions[i] = [2]float64{ll.Lng.Degrees(), ll.Lat.Degrees()}
+	}
+	topology := topoJSONTopology{Type: "Topology"}
+	if quantization == 0 {
+		arcs := make([][2][2]float64, len(edges))
+		for a, e := range edges {
+			arcs[a] = [2][2]float64{positions[e.Vertices[0]], positions[e.Vertices[1]]}
+		}
+		topology.Arcs = arcs
+	} else {
+		t := quantizationTransform(positions, quantization)
+		quantized := make([][2]int, len(positions))
+		for i, p := range positions {
+			for k := range 2 {
+				quantized[i][k] = int(math.Round((p[k] - t.Translate[k]) / t.Scale[k]))
+			}
+		}
+		arcs := make([][2][2]int, len(edges))
+		for a, e := range edges {
+			p, q := quantized[e.Vertices[0]], quantized[e.Vertices[1]]
+			arcs[a] = [2][2]int{p, {q[0] - p[0], q[1] - p[1]}}
+		}
+		topology.Transform = &t
+		topology.Arcs = arcs
+	}
+
+	// arcOf[k] is the arc reference of the entry k of the rings.
+	arcOf := make([]int, len(d.CellNeighbors))
+	a := 0
+	for i := range d.NumCells() {
+		start := d.CellOffsets[i]
+		for k, j := range d.Cell(i).NeighborIndices() {
+			if j >= i {
+				arcOf[start+k] = a
+				a++
+				continue
+			}
+			// The edge is an arc of cell j, which runs along it in the opposite direction.
+			arcOf[start+k] = ^arcOf[d.sharedEntry(j, i, d.CellVertices[start+k])]
+		}
+	}
+
+	polygon := "Polygon"
+	object := topoJSONObject{
+		Type:       "GeometryCollection",
+		Geometries: make([]topoJSONGeometry, d.NumCells()),
+	}
+	for i, c := range d.Cells() {
+		ll := s2.LatLngFromPoint(c.Site())
+		g := topoJSONGeometry{
+			Properties: geoJSONProperties{Index: i, Lat: ll.Lat.Degrees(), Lng: ll.Lng.Degrees()},
+		}
+		if !c.IsEmpty() {
+			g.Type = &polygon
+			g.Arcs = [][]int{arcOf[d.CellOffsets[i]:d.CellOffsets[i+1]]}
+		}
+		object.Geometries[i] = g
+	}
+	topology.Objects = map[string]topoJSONObject{"cells": object}
+	return json.NewEncoder(w).Encode(topology)
+}
+
+// sharedEntry returns the entry of the ring of cell j whose edge separates it from cell i and
+// ends at the vertex v, the start of the same edge in the ring of cell i.
+func (d *Diagram) sharedEntry(j, i, v int) int {
+	start, end := d.CellOffsets[j], d.CellOffsets[j+1]
+	for k := start; k < end; k++ {
+		next := k + 1
+		if next == end {
+			next = start
+		}
+		if d.CellNeighbors[k] == i && d.CellVertices[next] == v {
+			return k
+		}
+	}
+	panic(fmt.Sprintf("s2voronoi: cells %d and %d do not share an edge ending at vertex %d",
+		j, i, v))
+}
+
+// quantizationTransform returns the TopoJSON transform that maps the bounding box of positions
+// onto quantization values along each axis.
+func quantizationTransform(positions [][2]float64, quantization int) topoJSONTransform {
+	var t topoJSONTransform
+	for k := range 2 {
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, p := range positions {
+			lo, hi = min(lo, p[k]), max(hi, p[k])
+		}
+		t.Translate[k], t.Scale[k] = lo, (hi-lo)/float64(quantization-1)
+		if !(t.Scale[k] > 0) {
+			t.Translate[k], t.Scale[k] = 0, 1
+		}
+	}
+	return t
+}
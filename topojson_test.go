@@ -0,0 +1,199 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/internal/fixtures"
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s2"
+)
+
+func TestDiagram_ToTopoJSON(t *testing.T) {
+	tests := []struct {
+		name         string
+		vd           func(t *testing.T) *Diagram
+		quantization int
+		// tol is the largest expected error of a position in degrees.
+		tol float64
+	}{
+		{"unquantized", func(t *testing.T) *Diagram { return mustNewDiagram(t, 200) }, 0, 0},
+		{"quantized", func(t *testing.T) *Diagram { return mustNewDiagram(t, 200) }, 1e6, 4e-4},
+		{"coarse", func(t *testing.T) *Diagram { return mustNewDiagram(t, 200) }, 2, 360},
+		{"octahedron", func(t *testing.T) *Diagram {
+			vd, err := NewDiagram(fixtures.Load("octahedron"))
+			if err != nil {
+				t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+			}
+			return vd
+		}, 0, 0},
+		{"empty cell", func(t *testing.T) *Diagram {
+			vd, _ := mustNewEmptyCellDiagram(t)
+			return vd
+		}, 1e4, 4e-2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd := tt.vd(t)
+			var buf bytes.Buffer
+			if err := vd.ToTopoJSON(&buf, tt.quantization); err != nil {
+				t.Fatalf("vd.ToTopoJSON(..., %d) error = %v, want nil", tt.quantization, err)
+			}
+			rings, numArcs := assembleTopoJSON(t, buf.Bytes())
+			if numArcs != len(vd.Edges()) {
+				t.Errorf("topology has %d arcs, want %d edges", numArcs, len(vd.Edges()))
+			}
+			if len(rings) != vd.NumCells() {
+				t.Fatalf("topology has %d geometries, want %d", len(rings), vd.NumCells())
+			}
+			for i, c := range vd.Cells() {
+				if len(rings[i]) != c.NumVertices() {
+					t.Errorf("cell %d ring has %d positions, want %d", i, len(rings[i]),
+						c.NumVertices())
+					continue
+				}
+				for k, p := range c.VertexPoints() {
+					ll := s2.LatLngFromPoint(p)
+					got := rings[i][k]
+					if math.Abs(got[0]-ll.Lng.Degrees()) > tt.tol ||
+						math.Abs(got[1]-ll.Lat.Degrees()) > tt.tol {
+						t.Errorf("cell %d position %d = %v, want [%v %v]", i, k, got,
+							ll.Lng.Degrees(), ll.Lat.Degrees())
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDiagram_ToTopoJSON_Errors(t *testing.T) {
+	vd := mustNewDiagram(t, 10)
+	for _, q := range []int{-1, 1} {
+		if err := vd.ToTopoJSON(io.Discard, q); err == nil {
+			t.Errorf("vd.ToTopoJSON(..., %d) error = nil, want error", q)
+		}
+	}
+	if err := vd.ToTopoJSON(&limitedWriter{n: 10}, 0); err == nil {
+		t.Errorf("vd.ToTopoJSON(limitedWriter, 0) error = nil, want error")
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDiagram_ToTopoJSON(b *testing.B) {
+	vd, err := NewDiagram(utils.GenerateRandomPoints(10000, 0))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	for b.Loop() {
+		if err := vd.ToTopoJSON(io.Discard, 1e6); err != nil {
+			b.Fatalf("vd.ToTopoJSON(...) error = %v, want nil", err)
+		}
+	}
+}
+
+// Helpers
+
+// assembleTopoJSON decodes a topology written by ToTopoJSON and reassembles the rings of its
+// cells from their arcs, checking that consecutive arcs meet and that every arc is used once in
+// each direction. It returns the positions of every ring, without the closing one, and the
+// number of arcs.
+func assembleTopoJSON(t *testing.T, data []byte) ([][][2]float64, int) {
+	t.Helper()
+	var topology struct {
+		Type      string
+		Transform *struct {
+			Scale, Translate [2]float64
+		}
+		Objects map[string]struct {
+			Type       string
+			Geometries []struct {
+				Type       *string
+				Arcs       [][]int
+				Properties struct{ Index int }
+			}
+		}
+		Arcs [][][2]float64
+	}
+	if err := json.Unmarshal(data, &topology); err != nil {
+		t.Fatalf("json.Unmarshal(vd.ToTopoJSON()) error = %v, want nil", err)
+	}
+	cells, ok := topology.Objects["cells"]
+	if topology.Type != "Topology" || !ok || cells.Type != "GeometryCollection" {
+		t.Fatalf("vd.ToTopoJSON() = %s with objects %v, want Topology with GeometryCollection "+
+			"cells", topology.Type, topology.Objects)
+	}
+
+	// Decode the arcs into absolute positions.
+	arcs := make([][][2]float64, len(topology.Arcs))
+	for a, arc := range topology.Arcs {
+		var x, y float64
+		for _, p := range arc {
+			if tr := topology.Transform; tr != nil {
+				x, y = x+p[0], y+p[1]
+				arcs[a] = append(arcs[a], [2]float64{x*tr.Scale[0] + tr.Translate[0],
+					y*tr.Scale[1] + tr.Translate[1]})
+			} else {
+				arcs[a] = append(arcs[a], p)
+			}
+		}
+	}
+
+	uses := make([][2]int, len(arcs))
+	rings := make([][][2]float64, len(cells.Geometries))
+	for i, g := range cells.Geometries {
+		if g.Properties.Index != i {
+			t.Errorf("geometry %d has index %d", i, g.Properties.Index)
+		}
+		if g.Type == nil {
+			continue
+		}
+		if *g.Type != "Polygon" || len(g.Arcs) != 1 {
+			t.Fatalf("geometry %d is a %s with %d rings, want Polygon with 1", i, *g.Type,
+				len(g.Arcs))
+		}
+		var ring [][2]float64
+		for _, ref := range g.Arcs[0] {
+			a, reversed := ref, false
+			if ref < 0 {
+				a, reversed = ^ref, true
+			}
+			if a >= len(arcs) {
+				t.Fatalf("geometry %d references arc %d of %d", i, a, len(arcs))
+			}
+			arc := arcs[a]
+			if reversed {
+				uses[a][1]++
+				arc = [][2]float64{arc[1], arc[0]}
+			} else {
+				uses[a][0]++
+			}
+			if len(ring) > 0 && ring[len(ring)-1] != arc[0] {
+				t.Errorf("geometry %d arc %d starts at %v, want %v", i, ref, arc[0],
+					ring[len(ring)-1])
+			}
+			if len(ring) == 0 {
+				ring = append(ring, arc[0])
+			}
+			ring = append(ring, arc[1])
+		}
+		if ring[0] != ring[len(ring)-1] {
+			t.Errorf("geometry %d ring %v is not closed", i, ring)
+		}
+		rings[i] = ring[:len(ring)-1]
+	}
+	for a, u := range uses {
+		if u != [2]int{1, 1} {
+			t.Errorf("arc %d is used %d times forward and %d times reversed, want once each", a,
+				u[0], u[1])
+		}
+	}
+	return rings, len(arcs)
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/golang/geo/s2"
+)
+
+//go:embed data/airports.csv
+var airportsCSV []byte
+
+// LoadEmbeddedAirports returns the locations of 451 major airports around the world, no two
+// within 100 km of each other, followed by 56 secondary airports within 100 km of one of them,
+// in the order of the embedded data/airports.csv file. The coordinates are accurate to about
+// 0.1°, which makes the set a realistic, strongly clustered input with near-duplicates for tests
+// and examples.
+func LoadEmbeddedAirports() s2.PointVector {
+	var points s2.PointVector
+	sc := bufio.NewScanner(bytes.NewReader(airportsCSV))
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, ",")
+		if len(fields) != 3 {
+			panic(fmt.Sprintf("utils: airports.csv:%d: want 3 fields, got %d", line, len(fields)))
+		}
+		lat, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			panic(fmt.Sprintf("utils: airports.csv:%d: %v", line, err))
+		}
+		lng, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			panic(fmt.Sprintf("utils: airports.csv:%d: %v", line, err))
+		}
+		points = append(points, s2.PointFromLatLng(s2.LatLngFromDegrees(lat, lng)))
+	}
+	return points
+}
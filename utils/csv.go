@@ -0,0 +1,278 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package utils
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// CSVOptions holds configuration options for ReadPointsCSV.
+type CSVOptions struct {
+	LatColumn, LngColumn int
+	LatName, LngName     string
+	Header               bool
+	Radians              bool
+	Comma, Comment       rune
+	SkipInvalidRows      bool
+}
+
+// CSVOption is a functional option type for CSV reading configuration.
+type CSVOption func(*CSVOptions) error
+
+// WithColumns reads the latitude and longitude from the zero-based columns lat and lng, which
+// must be distinct and not negative. By default they are the columns 0 and 1.
+func WithColumns(lat, lng int) CSVOption {
+	return func(o *CSVOptions) error {
+		if lat < 0 || lng < 0 || lat == lng {
+			return fmt.Errorf("utils: columns must be distinct and not negative, got %d and %d",
+				lat, lng)
+		}
+		o.LatColumn, o.LngColumn = lat, lng
+		return nil
+	}
+}
+
+// WithColumnNames reads the latitude and longitude from the columns whose header fields are
+// lat and lng, compared case-insensitively, and implies WithHeader. The names must be distinct
+// and not empty.
+func WithColumnNames(lat, lng string) CSVOption {
+	return func(o *CSVOptions) error {
+		lat, lng = strings.TrimSpace(lat), strings.TrimSpace(lng)
+		if lat == "" || lng == "" || strings.EqualFold(lat, lng) {
+			return fmt.Errorf("utils: column names must be distinct and not empty, got %q and %q",
+				lat, lng)
+		}
+		o.LatName, o.LngName, o.Header = lat, lng, true
+		return nil
+	}
+}
+
+// WithHeader skips the first record, which is a header.
+func WithHeader() CSVOption {
+	return func(o *CSVOptions) error {
+		o.Header = true
+		return nil
+	}
+}
+
+// WithRadians reads the coordinates in radians rather than degrees.
+func WithRadians() CSVOption {
+	return func(o *CSVOptions) error {
+		o.Radians = true
+		return nil
+	}
+}
+
+// WithComma sets the field delimiter, ',' by default, see csv.Reader.Comma.
+func WithComma(comma rune) CSVOption {
+	return func(o *CSVOptions) error {
+		if !validCSVDelimiter(comma) || comma == o.Comment {
+			return fmt.Errorf("utils: invalid field delimiter %q", comma)
+		}
+		o.Comma = comma
+		return nil
+	}
+}
+
+// WithComment sets the character that starts a comment line, '#' by default, or disables
+// comments if it is 0, see csv.Reader.Comment.
+func WithComment(comment rune) CSVOption {
+	return func(o *CSVOptions) error {
+		if comment != 0 && (!validCSVDelimiter(comment) || comment == o.Comma) {
+			return fmt.Errorf("utils: invalid comment character %q", comment)
+		}
+		o.Comment = comment
+		return nil
+	}
+}
+
+// WithSkipInvalidRows skips invalid rows instead of failing at the first one. ReadPointsCSV
+// then returns the points of the valid rows together with a RowErrors of the invalid ones.
+func WithSkipInvalidRows() CSVOption {
+	return func(o *CSVOptions) error {
+		o.SkipInvalidRows = true
+		return nil
+	}
+}
+
+// RowError is the error of an invalid row of a CSV file.
+type RowError struct {
+	// Line is the line number of the row, starting at 1.
+	Line int
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("utils: line %d: %v", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// RowErrors are the errors of the invalid rows of a CSV file in line order, returned by
+// ReadPointsCSV with WithSkipInvalidRows.
+type RowErrors []*RowError
+
+func (e RowErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%v (and %d more invalid rows)", e[0], len(e)-1)
+}
+
+// ReadPointsCSV reads points from the CSV rows of r, one per record, as latitude and longitude
+// in degrees, or in radians with WithRadians. Blank lines, comment lines and surrounding
+// whitespace of fields are ignored; columns other than those of the coordinates are ignored.
+// A row is invalid if it lacks a coordinate column, a coordinate is not a number, or the
+// latitude is outside [-90°, 90°] or the longitude outside [-180°, 180°]. The first invalid
+// row fails reading with a *RowError carrying its line number, unless WithSkipInvalidRows is
+// set. It returns an error if an option is invalid, reading fails, or the header lacks a
+// column of WithColumnNames.
+func ReadPointsCSV(r io.Reader, setters ...CSVOption) (s2.PointVector, error) {
+	opts := &CSVOptions{LngColumn: 1, Comma: ',', Comment: '#'}
+	for _, set := range setters {
+		if err := set(opts); err != nil {
+			return nil, err
+		}
+	}
+
+	cr := csv.NewReader(r)
+	cr.Comma, cr.Comment = opts.Comma, opts.Comment
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	cr.ReuseRecord = true
+
+	var points s2.PointVector
+	var rowErrs RowErrors
+	header := opts.Header
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rowErr := &RowError{Line: parseErr.Line, Err: parseErr.Err}
+			if !opts.SkipInvalidRows {
+				return nil, rowErr
+			}
+			rowErrs = append(rowErrs, rowErr)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		line, _ := cr.FieldPos(0)
+		if header {
+			header = false
+			if opts.LatName != "" {
+				if err := opts.resolveColumns(record); err != nil {
+					return nil, &RowError{Line: line, Err: err}
+				}
+			}
+			continue
+		}
+
+		p, err := opts.parsePoint(record)
+		if err != nil {
+			rowErr := &RowError{Line: line, Err: err}
+			if !opts.SkipInvalidRows {
+				return nil, rowErr
+			}
+			rowErrs = append(rowErrs, rowErr)
+			continue
+		}
+		points = append(points, p)
+	}
+	if rowErrs != nil {
+		return points, rowErrs
+	}
+	return points, nil
+}
+
+// WritePointsCSV writes the points to w as CSV with a "lat,lng" header and one row per point
+// of its latitude and longitude in degrees, in the shortest form that round-trips. The result
+// is read back by ReadPointsCSV with WithHeader.
+func WritePointsCSV(w io.Writer, points s2.PointVector) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"lat", "lng"}); err != nil {
+		return err
+	}
+	for _, p := range points {
+		ll := s2.LatLngFromPoint(p)
+		err := cw.Write([]string{
+			strconv.FormatFloat(ll.Lat.Degrees(), 'f', -1, 64),
+			strconv.FormatFloat(ll.Lng.Degrees(), 'f', -1, 64),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// resolveColumns sets the coordinate columns to those of the header fields LatName and
+// LngName.
+func (o *CSVOptions) resolveColumns(header []string) error {
+	lat, lng := -1, -1
+	for k, field := range header {
+		field = strings.TrimSpace(field)
+		if lat < 0 && strings.EqualFold(field, o.LatName) {
+			lat = k
+		} else if lng < 0 && strings.EqualFold(field, o.LngName) {
+			lng = k
+		}
+	}
+	if lat < 0 || lng < 0 {
+		return fmt.Errorf("header %q lacks column %q or %q", header, o.LatName, o.LngName)
+	}
+	o.LatColumn, o.LngColumn = lat, lng
+	return nil
+}
+
+// parsePoint returns the point of the coordinates of record.
+func (o *CSVOptions) parsePoint(record []string) (s2.Point, error) {
+	if n := max(o.LatColumn, o.LngColumn) + 1; len(record) < n {
+		return s2.Point{}, fmt.Errorf("want at least %d fields, got %d", n, len(record))
+	}
+	unit, limits := s1.Degree, [2]float64{90, 180}
+	if o.Radians {
+		unit, limits = s1.Radian, [2]float64{math.Pi / 2, math.Pi}
+	}
+	var coords [2]s1.Angle
+	for k, column := range []int{o.LatColumn, o.LngColumn} {
+		field := strings.TrimSpace(record[column])
+		x, err := strconv.ParseFloat(field, 64)
+		if err != nil || math.IsNaN(x) || math.IsInf(x, 0) {
+			return s2.Point{}, fmt.Errorf("column %d: invalid coordinate %q", column, field)
+		}
+		if math.Abs(x) > limits[k] {
+			return s2.Point{}, fmt.Errorf("column %d: %s %s out of range [%v, %v]", column,
+				[2]string{"latitude", "longitude"}[k], field, -limits[k], limits[k])
+		}
+		coords[k] = s1.Angle(x) * unit
+	}
+	lat, lng := coords[0], coords[1]
+	return s2.PointFromLatLng(s2.LatLng{Lat: lat, Lng: lng}), nil
+}
+
+// validCSVDelimiter reports whether r can delimit fields or start comments in encoding/csv.
+func validCSVDelimiter(r rune) bool {
+	return r != 0 && r != '"' && r != '\r' && r != '\n' && r != 0xFFFD
+}
@@ -0,0 +1,206 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/golang/geo/s2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadPointsCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		setters []CSVOption
+		// want are the latitudes and longitudes of the points in degrees.
+		want [][2]float64
+	}{
+		{"plain", "1,2\n3,4\n", nil, [][2]float64{{1, 2}, {3, 4}}},
+		{"no trailing newline", "1,2\n3,4", nil, [][2]float64{{1, 2}, {3, 4}}},
+		{"empty", "", nil, nil},
+		{"blank lines and comments", "\n# lat,lng\n1,2\n\n  \n#3,4\n5,6\n", nil,
+			[][2]float64{{1, 2}, {5, 6}}},
+		{"whitespace and crlf", " 1 ,\t2 \r\n3,4\r\n", nil, [][2]float64{{1, 2}, {3, 4}}},
+		{"header", "lat,lng\n1,2\n", []CSVOption{WithHeader()}, [][2]float64{{1, 2}}},
+		{"columns", "a,10,b,20\n", []CSVOption{WithColumns(3, 1)}, [][2]float64{{20, 10}}},
+		{"column names", "lng,id,LAT\n2,x,1\n", []CSVOption{WithColumnNames("lat", "lng")},
+			[][2]float64{{1, 2}}},
+		{"radians", "0.5,-1\n", []CSVOption{WithRadians()},
+			[][2]float64{{0.5 * 180 / math.Pi, -180 / math.Pi}}},
+		{"radians bounds", "-1.5707963267948966,3.141592653589793\n", []CSVOption{WithRadians()},
+			[][2]float64{{-90, 180}}},
+		{"bounds", "90,-180\n-90,180\n", nil, [][2]float64{{90, -180}, {-90, 180}}},
+		{"semicolons", "1;2\n", []CSVOption{WithComma(';')}, [][2]float64{{1, 2}}},
+		{"comment", "%1,2\n3,4\n", []CSVOption{WithComment('%')}, [][2]float64{{3, 4}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadPointsCSV(strings.NewReader(tt.input), tt.setters...)
+			if err != nil {
+				t.Fatalf("ReadPointsCSV(%q) error = %v, want nil", tt.input, err)
+			}
+			if diff := cmp.Diff(tt.want, degrees(got), approx); diff != "" {
+				t.Errorf("ReadPointsCSV(%q) mismatch (-want +got):\n%s", tt.input, diff)
+			}
+		})
+	}
+}
+
+func TestReadPointsCSV_InvalidRows(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		setters  []CSVOption
+		wantLine int
+	}{
+		{"latitude out of range", "1,2\n\n91,0\n", nil, 3},
+		{"longitude out of range", "0,-180.5\n", nil, 1},
+		{"radians out of range", "0,3.2\n", []CSVOption{WithRadians()}, 1},
+		{"swapped columns", "lng,lat\n10,20\n150,-30\n", []CSVOption{WithHeader()}, 3},
+		{"not a number", "# comment\nx,2\n", nil, 2},
+		{"empty field", "1,\n", nil, 1},
+		{"nan", "NaN,0\n", nil, 1},
+		{"infinity", "0,+Inf\n", nil, 1},
+		{"missing field", "1,2\n3\n", nil, 2},
+		{"missing column", "1,2,3\n4,5\n", []CSVOption{WithColumns(0, 2)}, 2},
+		{"bare quote", "1,2\n3\"4,5\n", nil, 2},
+		{"header lacks column", "# sites\nlatitude,lng\n1,2\n",
+			[]CSVOption{WithColumnNames("lat", "lng")}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadPointsCSV(strings.NewReader(tt.input), tt.setters...)
+			var rowErr *RowError
+			if !errors.As(err, &rowErr) || rowErr.Line != tt.wantLine {
+				t.Fatalf("ReadPointsCSV(%q) error = %v, want RowError at line %d", tt.input, err,
+					tt.wantLine)
+			}
+			if got != nil {
+				t.Errorf("ReadPointsCSV(%q) = %v, want nil", tt.input, got)
+			}
+		})
+	}
+}
+
+func TestReadPointsCSV_SkipInvalidRows(t *testing.T) {
+	f, err := os.Open("testdata/messy.csv")
+	if err != nil {
+		t.Fatalf("os.Open(...) error = %v, want nil", err)
+	}
+	defer f.Close()
+	got, err := ReadPointsCSV(f, WithColumnNames("latitude", "longitude"),
+		WithSkipInvalidRows())
+
+	want := [][2]float64{{48.86, 2.35}, {35.69, 139.69}, {-33.87, 151.21}, {64.15, -21.94}}
+	if diff := cmp.Diff(want, degrees(got), approx); diff != "" {
+		t.Errorf("ReadPointsCSV(messy.csv) mismatch (-want +got):\n%s", diff)
+	}
+	var rowErrs RowErrors
+	if !errors.As(err, &rowErrs) {
+		t.Fatalf("ReadPointsCSV(messy.csv) error = %v, want RowErrors", err)
+	}
+	var lines []int
+	for _, e := range rowErrs {
+		lines = append(lines, e.Line)
+	}
+	if diff := cmp.Diff([]int{8, 9, 11, 12}, lines); diff != "" {
+		t.Errorf("ReadPointsCSV(messy.csv) error lines mismatch (-want +got):\n%s", diff)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "line 8") ||
+		!strings.Contains(msg, "3 more") {
+		t.Errorf("ReadPointsCSV(messy.csv) error = %q, want line 8 and 3 more", msg)
+	}
+
+	// Without WithSkipInvalidRows the first invalid row fails reading.
+	if _, err := f.Seek(0, 0); err != nil {
+		t.Fatalf("f.Seek(...) error = %v, want nil", err)
+	}
+	_, err = ReadPointsCSV(f, WithColumnNames("latitude", "longitude"))
+	var rowErr *RowError
+	if !errors.As(err, &rowErr) || rowErr.Line != 8 {
+		t.Errorf("ReadPointsCSV(messy.csv) error = %v, want RowError at line 8", err)
+	}
+}
+
+func TestReadPointsCSV_InvalidOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		setter CSVOption
+	}{
+		{"negative column", WithColumns(-1, 1)},
+		{"equal columns", WithColumns(2, 2)},
+		{"empty column name", WithColumnNames("lat", " ")},
+		{"equal column names", WithColumnNames("lat", "LAT")},
+		{"quote delimiter", WithComma('"')},
+		{"newline delimiter", WithComma('\n')},
+		{"delimiter is comment", WithComma('#')},
+		{"comment is delimiter", WithComment(',')},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadPointsCSV(strings.NewReader("1,2\n"), tt.setter); err == nil {
+				t.Errorf("ReadPointsCSV(..., %s) error = nil, want error", tt.name)
+			}
+		})
+	}
+}
+
+func TestWritePointsCSV(t *testing.T) {
+	points := append(GenerateRandomPoints(100, 0), LoadEmbeddedAirports()...)
+	var buf bytes.Buffer
+	if err := WritePointsCSV(&buf, points); err != nil {
+		t.Fatalf("WritePointsCSV(...) error = %v, want nil", err)
+	}
+	if !strings.HasPrefix(buf.String(), "lat,lng\n") {
+		t.Errorf("WritePointsCSV(...) = %q..., want header lat,lng", buf.String()[:20])
+	}
+	got, err := ReadPointsCSV(&buf, WithHeader())
+	if err != nil {
+		t.Fatalf("ReadPointsCSV(WritePointsCSV(...)) error = %v, want nil", err)
+	}
+	if len(got) != len(points) {
+		t.Fatalf("ReadPointsCSV(WritePointsCSV(...)) len = %d, want %d", len(got), len(points))
+	}
+	for i, p := range got {
+		if d := p.Distance(points[i]); d > 1e-15 {
+			t.Errorf("point %d round-trips to %v away, want ≈0", i, d)
+		}
+	}
+}
+
+func TestWritePointsCSV_Error(t *testing.T) {
+	if err := WritePointsCSV(failingWriter{}, GenerateRandomPoints(10, 0)); err == nil {
+		t.Errorf("WritePointsCSV(failingWriter, ...) error = nil, want error")
+	}
+}
+
+// Helpers
+
+// approx compares coordinates in degrees up to rounding.
+var approx = cmp.Comparer(func(a, b float64) bool { return math.Abs(a-b) <= 1e-12 })
+
+// degrees returns the latitudes and longitudes of the points in degrees.
+func degrees(points s2.PointVector) [][2]float64 {
+	var coords [][2]float64
+	for _, p := range points {
+		ll := s2.LatLngFromPoint(p)
+		coords = append(coords, [2]float64{ll.Lat.Degrees(), ll.Lng.Degrees()})
+	}
+	return coords
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
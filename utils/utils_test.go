@@ -59,3 +59,21 @@ func TestGenerateRandomPoints_Determinism(t *testing.T) {
 		t.Errorf("GenerateRandomPoints(%v, %v) mismatch (-want +got):\n%s", cnt, seed, diff)
 	}
 }
+
+func TestLoadEmbeddedAirports(t *testing.T) {
+	points := LoadEmbeddedAirports()
+	if len(points) != 507 {
+		t.Errorf("LoadEmbeddedAirports() len = %v, want 507", len(points))
+	}
+	for i, p := range points {
+		if math.Abs(p.Norm()-1) > 1e-12 {
+			t.Errorf("LoadEmbeddedAirports()[%d] point norm = %v, want ≈1", i, p.Norm())
+		}
+	}
+
+	// The first airport is ATL.
+	want := s2.PointFromLatLng(s2.LatLngFromDegrees(33.64, -84.43))
+	if got := points[0]; got.Distance(want) > 1e-12 {
+		t.Errorf("LoadEmbeddedAirports()[0] = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"fmt"
+	"math"
+)
+
+// validateTolerance is the absolute tolerance of the dot products compared by Validate. It is
+// looser than eps because circumcenters of nearly degenerate triangles lose precision.
+const validateTolerance = 1e-9
+
+// Validate checks the structural invariants of the diagram and returns an error describing
+// the first violation, or nil if the diagram is consistent. It verifies that the CSR arrays
+// are consistent and in range, that every non-empty cell has at least 3 vertices, that all
+// vertices have unit length, that every ring winds once around its site in CCW order when
+// looking out of the sphere, that every edge is shared with the neighbor listed for it and
+// traversed by it in the opposite direction, and that every vertex is equidistant from the
+// sites of the cells sharing it and lies less than π/2 away from them. For power diagrams, the
+// rings must wind around their vertex average instead, and the vertices must be equidistant in
+// terms of the power, see NewPowerDiagram. For diagrams built with WithVertexMerging, a merged
+// vertex may be off by up to the merge tolerance per merged edge, which widens the equidistance
+// check accordingly.
+func (d *Diagram) Validate() error {
+	return d.validate(ValidationFull)
+}
+
+// validate checks the invariants of the diagram selected by level, see Validate.
+func (d *Diagram) validate(level ValidationLevel) error {
+	numCells := d.NumCells()
+	offsets := d.CellOffsets
+	if len(offsets) != numCells+1 || offsets[0] != 0 ||
+		offsets[numCells] != len(d.CellVertices) || len(d.CellVertices) != len(d.CellNeighbors) {
+		return fmt.Errorf("s2voronoi: cell offsets are inconsistent")
+	}
+	for i := range numCells {
+		if n := offsets[i+1] - offsets[i]; n != 0 && n < 3 {
+			return fmt.Errorf("s2voronoi: cell %d has %d vertices, need 0 or at least 3", i, n)
+		}
+	}
+	for k, v := range d.CellVertices {
+		if v < 0 || v >= len(d.Vertices) {
+			return fmt.Errorf("s2voronoi: cell vertex %d out of range [0 %d)", v, len(d.Vertices))
+		}
+		if n := d.CellNeighbors[k]; n < 0 || n >= numCells {
+			return fmt.Errorf("s2voronoi: cell neighbor %d out of range [0 %d)", n, numCells)
+		}
+	}
+	for i, v := range d.Vertices {
+		if !v.IsUnit() {
+			return fmt.Errorf("s2voronoi: vertex %d is not unit length", i)
+		}
+	}
+	// A vertex merged from m vertices of degree 3 along m-1 edges has degree m+2, so the
+	// vertices of degree k > 3 may lie up to (k-3)·mergeTol away from those they replace.
+	var degree []int
+	if d.mergeTol != 0 && level == ValidationFull {
+		degree = make([]int, len(d.Vertices))
+		for _, v := range d.CellVertices {
+			degree[v]++
+		}
+	}
+
+	for i := range numCells {
+		c := d.Cell(i)
+		num := c.NumVertices()
+		if turns := ringTurns(c); num > 0 && turns != -1 {
+			return fmt.Errorf("s2voronoi: cell %d ring winds %v times around the site, want -1",
+				i, turns)
+		}
+		for k, j := range c.NeighborIndices() {
+			if j == i {
+				return fmt.Errorf("s2voronoi: cell %d is its own neighbor", i)
+			}
+			a, b := c.VertexIndices()[k], c.VertexIndices()[(k+1)%num]
+			if !hasReversedEdge(d.Cell(j), i, a, b) {
+				return fmt.Errorf("s2voronoi: edge %d of cell %d is not shared with cell %d", k, i, j)
+			}
+			if level == ValidationFull {
+				slack := 0.0
+				if degree != nil {
+					slack = 2 * float64(d.mergeTol) * float64(max(degree[a]-3, 0))
+				}
+				if err := d.checkEquidistant(a, i, j, slack); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkEuler checks the number of ring entries against the Euler relation of the sphere: a
+// diagram of F non-empty cells with V vertices has E = V + F - 2 edges, each in two rings. It
+// detects vertices outside every ring, which Validate accepts.
+func (d *Diagram) checkEuler() error {
+	nonEmpty := 0
+	for _, c := range d.Cells() {
+		if !c.IsEmpty() {
+			nonEmpty++
+		}
+	}
+	if numEntries := len(d.CellVertices); numEntries != 2*(len(d.Vertices)+nonEmpty-2) {
+		return fmt.Errorf("s2voronoi: %d ring entries for %d vertices and %d non-empty "+
+			"cells, want %d", numEntries, len(d.Vertices), nonEmpty,
+			2*(len(d.Vertices)+nonEmpty-2))
+	}
+	return nil
+}
+
+// checkEquidistant checks that the vertex is equidistant from the sites i and j, up to slack on
+// top of validateTolerance, and lies in the open hemisphere around them. Moving a vertex by an
+// angle δ changes the difference of its dot products with two sites by at most 2δ.
+func (d *Diagram) checkEquidistant(vIdx, i, j int, slack float64) error {
+	v := d.Vertices[vIdx]
+	di, dj := v.Dot(d.generator(i)), v.Dot(d.generator(j))
+	if di <= 0 {
+		return fmt.Errorf("s2voronoi: vertex %d is not in the hemisphere around site %d", vIdx, i)
+	}
+	if math.Abs(di-dj) > validateTolerance*max(1, di)+slack {
+		return fmt.Errorf("s2voronoi: vertex %d is not equidistant from sites %d and %d",
+			vIdx, i, j)
+	}
+	return nil
+}
+
+// ringTurns returns the number of times the ring of the cell winds around its fan center,
+// counted positive CCW in the s2 convention. The ring of a valid non-empty cell winds -1 times,
+// since it is CCW when looking out of the sphere.
+func ringTurns(c Cell) float64 {
+	site := c.fanCenter()
+	num := c.NumVertices()
+	winding := 0.0
+	for k := range num {
+		winding += subtendedAngle(site, c.Vertex(k), c.Vertex((k+1)%num))
+	}
+	return math.Round(winding / (2 * math.Pi))
+}
+
+// hasReversedEdge reports whether the cell has the edge from b to a and lists nIdx as the
+// neighbor across it.
+func hasReversedEdge(c Cell, nIdx, a, b int) bool {
+	vertices := c.VertexIndices()
+	for k, m := range c.NeighborIndices() {
+		if m == nIdx && vertices[k] == b && vertices[(k+1)%len(vertices)] == a {
+			return true
+		}
+	}
+	return false
+}
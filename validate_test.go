@@ -0,0 +1,85 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/internal/fixtures"
+	"github.com/golang/geo/s2"
+)
+
+func TestDiagram_Validate(t *testing.T) {
+	vd := mustNewDiagram(t, 100)
+	if err := vd.Validate(); err != nil {
+		t.Fatalf("vd.Validate() error = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name    string
+		corrupt func(vd *Diagram)
+	}{
+		{"vertex out of range", func(vd *Diagram) { vd.CellVertices[0] = len(vd.Vertices) }},
+		{"neighbor out of range", func(vd *Diagram) { vd.CellNeighbors[0] = -1 }},
+		{"wrong offsets", func(vd *Diagram) { vd.CellOffsets = vd.CellOffsets[1:] }},
+		{"asymmetric neighbor", func(vd *Diagram) { vd.CellNeighbors[0] = vd.CellNeighbors[1] }},
+		{"moved vertex", func(vd *Diagram) {
+			vd.Vertices[0] = s2.Point{Vector: vd.Vertices[0].Add(vd.Sites[0].Vector).Normalize()}
+		}},
+		{"reversed ring", func(vd *Diagram) {
+			slices.Reverse(vd.CellVertices[vd.CellOffsets[0]:vd.CellOffsets[1]])
+		}},
+		{"antipodal vertex", func(vd *Diagram) {
+			vd.Vertices[0] = s2.Point{Vector: vd.Vertices[0].Mul(-1)}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vd := mustNewDiagram(t, 100)
+			tt.corrupt(vd)
+			if err := vd.Validate(); err == nil {
+				t.Errorf("vd.Validate() error = nil, want non-nil")
+			}
+		})
+	}
+}
+
+func TestNewDiagram_Fixtures(t *testing.T) {
+	// wantErr is the sentinel of the error of every fixture, nil for a valid diagram.
+	wantErr := map[string]error{
+		"antimeridian":        nil,
+		"antipodal-pairs":     nil,
+		"cocircular-rings":    nil,
+		"equator-and-pole":    ErrDegenerateInput,
+		"equatorial-coplanar": ErrDegenerateInput,
+		"five-points":         nil,
+		"hemispheric-cluster": ErrDegenerateInput,
+		"near-duplicates":     ErrDuplicateSites,
+		"octahedron":          nil,
+	}
+	for _, name := range fixtures.Names() {
+		t.Run(name, func(t *testing.T) {
+			want, ok := wantErr[name]
+			if !ok {
+				t.Fatalf("fixture %q has no expected result", name)
+			}
+			vd, err := NewDiagram(fixtures.Load(name))
+			if !errors.Is(err, want) || (want == nil) != (err == nil) {
+				t.Fatalf("NewDiagram(...) error = %v, want %v", err, want)
+			}
+			if err != nil {
+				if vd != nil {
+					t.Errorf("NewDiagram(...) = %v, %v, want nil result with error", vd, err)
+				}
+				return
+			}
+			if err := vd.Validate(); err != nil {
+				t.Errorf("NewDiagram(...).Validate() error = %v, want nil", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"fmt"
+	"slices"
+)
+
+// VertexCells returns the indices of the cells sharing the Voronoi vertex, sorted in CCW order
+// when looking out of the sphere and starting with the smallest index. Every vertex of a
+// diagram built by NewDiagram is the circumcenter of one Delaunay triangle and has the three
+// cells of its corners, unless it was merged from several, see WithVertexMerging. The reverse
+// index is built on first use and shared by all callers, so the returned slice must not be
+// modified. The diagram must be consistent, see Validate.
+// It panics if the vertex index is out of range.
+func (d *Diagram) VertexCells(vIdx int) []int {
+	d.checkVertexIndex(vIdx)
+	c := d.vertexCells()
+	return c.vertexCells[c.vertexCellOffsets[vIdx]:c.vertexCellOffsets[vIdx+1]]
+}
+
+// VertexNeighbors returns the indices of the Voronoi vertices connected to the vertex by a
+// Voronoi edge. The k-th of them ends the edge between the cells VertexCells(vIdx)[k] and
+// [k+1], so vertices of a diagram built by NewDiagram have 3 neighbors, the circumcenters of
+// the triangles adjacent to the dual Delaunay triangle. Coincident vertices, e.g. of cocircular
+// sites, are connected by edges of zero length, unless they are merged, see WithVertexMerging.
+// The adjacency is built on first use and shared by all callers, so the returned slice must not
+// be modified. The diagram must be consistent, see Validate.
+// It panics if the vertex index is out of range.
+func (d *Diagram) VertexNeighbors(vIdx int) []int {
+	d.checkVertexIndex(vIdx)
+	c := d.vertexCells()
+	c.vertexNeighborsOnce.Do(func() {
+		c.vertexNeighbors = make([]int, len(c.vertexCells))
+		for v := range d.Vertices {
+			start := c.vertexCellOffsets[v]
+			for k, i := range c.vertexCells[start:c.vertexCellOffsets[v+1]] {
+				cell := d.Cell(i)
+				ring := cell.VertexIndices()
+				c.vertexNeighbors[start+k] = ring[(slices.Index(ring, v)+1)%len(ring)]
+			}
+		}
+	})
+	return c.vertexNeighbors[c.vertexCellOffsets[vIdx]:c.vertexCellOffsets[vIdx+1]]
+}
+
+// checkVertexIndex panics if vIdx is not a valid vertex index.
+func (d *Diagram) checkVertexIndex(vIdx int) {
+	if vIdx < 0 || vIdx >= len(d.Vertices) {
+		panic(fmt.Sprintf("s2voronoi: vertex index %d out of range [0 %d)", vIdx, len(d.Vertices)))
+	}
+}
+
+// vertexCells returns the cache holding the reverse index of VertexCells, building it on first
+// use. Around a vertex, the cell following a cell in CCW order is its neighbor across the edge
+// leaving the vertex. If the cells of a vertex do not form such a cycle, because the diagram is
+// inconsistent, they are left in increasing order.
+func (d *Diagram) vertexCells() *diagramCache {
+	c := d.caches()
+	c.vertexCellsOnce.Do(func() {
+		offsets := make([]int, len(d.Vertices)+1)
+		for _, v := range d.CellVertices {
+			offsets[v+1]++
+		}
+		for v := range d.Vertices {
+			offsets[v+1] += offsets[v]
+		}
+		cells := make([]int, len(d.CellVertices))
+		next := slices.Clone(offsets[:len(d.Vertices)])
+		for i := range d.NumCells() {
+			for _, v := range d.Cell(i).VertexIndices() {
+				cells[next[v]] = i
+				next[v]++
+			}
+		}
+
+		var cycle []int
+		for v := range d.Vertices {
+			incident := cells[offsets[v]:offsets[v+1]]
+			cycle = cycle[:0]
+			for cur := incident[0]; len(cycle) < len(incident); {
+				if slices.Contains(cycle, cur) || !slices.Contains(incident, cur) {
+					break
+				}
+				cycle = append(cycle, cur)
+				cell := d.Cell(cur)
+				k := slices.Index(cell.VertexIndices(), v)
+				if k < 0 {
+					break
+				}
+				cur = cell.NeighborIndices()[k]
+			}
+			if len(cycle) == len(incident) {
+				copy(incident, cycle)
+			}
+		}
+		c.vertexCellOffsets, c.vertexCells = offsets, cells
+	})
+	return c
+}
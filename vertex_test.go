@@ -0,0 +1,128 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/internal/fixtures"
+	"github.com/2dChan/s2voronoi/s2delaunay"
+)
+
+func TestDiagram_VertexCells(t *testing.T) {
+	for _, name := range []string{"octahedron", "cocircular-rings", "five-points", "random"} {
+		t.Run(name, func(t *testing.T) {
+			var vd *Diagram
+			if name == "random" {
+				vd = mustNewDiagram(t, 200)
+			} else {
+				var err error
+				if vd, err = NewDiagram(fixtures.Load(name)); err != nil {
+					t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+				}
+			}
+			dt, err := s2delaunay.NewTriangulation(vd.Sites)
+			if err != nil {
+				t.Fatalf("s2delaunay.NewTriangulation(...) error = %v, want nil", err)
+			}
+
+			total := 0
+			for v := range vd.Vertices {
+				cells := vd.VertexCells(v)
+				total += len(cells)
+				if len(cells) < 3 {
+					t.Errorf("vd.VertexCells(%d) = %v, want at least 3 cells", v, cells)
+				}
+				if slices.Min(cells) != cells[0] {
+					t.Errorf("vd.VertexCells(%d) = %v, want starting with the smallest index", v,
+						cells)
+				}
+				// The vertex is the circumcenter of the triangle with the same index.
+				if !isRotation(cells, dt.Triangles[v][:]) {
+					t.Errorf("vd.VertexCells(%d) = %v, want a rotation of %v", v, cells,
+						dt.Triangles[v])
+				}
+			}
+			if total != len(vd.CellVertices) {
+				t.Errorf("vd.VertexCells lists %d cells in total, want %d", total,
+					len(vd.CellVertices))
+			}
+			for i := range vd.NumCells() {
+				for _, v := range vd.Cell(i).VertexIndices() {
+					if !slices.Contains(vd.VertexCells(v), i) {
+						t.Errorf("vd.VertexCells(%d) = %v, want to contain cell %d", v,
+							vd.VertexCells(v), i)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDiagram_VertexCells_Panic(t *testing.T) {
+	vd := mustNewDiagram(t, 10)
+	for _, in := range []int{-1, len(vd.Vertices)} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("vd.VertexCells(%d) did not panic, want panic", in)
+				}
+			}()
+			vd.VertexCells(in)
+		}()
+	}
+}
+
+func TestDiagram_VertexNeighbors(t *testing.T) {
+	for _, name := range []string{"octahedron", "cocircular-rings", "random"} {
+		t.Run(name, func(t *testing.T) {
+			var vd *Diagram
+			if name == "random" {
+				vd = mustNewDiagram(t, 200)
+			} else {
+				var err error
+				if vd, err = NewDiagram(fixtures.Load(name)); err != nil {
+					t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+				}
+			}
+			for v := range vd.Vertices {
+				neighbors := vd.VertexNeighbors(v)
+				if len(neighbors) != 3 {
+					t.Errorf("vd.VertexNeighbors(%d) = %v, want 3 vertices", v, neighbors)
+				}
+				cells := vd.VertexCells(v)
+				for k, w := range neighbors {
+					if w == v || !slices.Contains(vd.VertexNeighbors(w), v) {
+						t.Errorf("vd.VertexNeighbors(%d) = %v, want symmetric without loops", v,
+							neighbors)
+					}
+					// The edge from v to w separates consecutive cells around v.
+					a, b := cells[k], cells[(k+1)%len(cells)]
+					e, ok := vd.SharedEdge(a, b)
+					if !ok || e.Vertices != [2]int{v, w} {
+						t.Errorf("vd.SharedEdge(%d, %d) = %+v, %v, want edge from %d to %d", a, b,
+							e, ok, v, w)
+					}
+				}
+			}
+		})
+	}
+}
+
+// Helpers
+
+// isRotation reports whether b is a cyclic rotation of a.
+func isRotation(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for s := range b {
+		if slices.Equal(a, append(slices.Clone(b[s:]), b[:s]...)) {
+			return true
+		}
+	}
+	return len(a) == 0
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+
+	"github.com/2dChan/s2voronoi/internal/lnglat"
+)
+
+// The geometry types of WKB.
+const (
+	wkbPolygon            = 3
+	wkbMultiPolygon       = 6
+	wkbGeometryCollection = 7
+)
+
+// WKT returns the cell as OGC Well-Known Text with positions as "lng lat" in degrees in the
+// shortest form that round-trips, see AppendWKT.
+func (c Cell) WKT() string {
+	return string(c.AppendWKT(nil, -1))
+}
+
+// AppendWKT appends the cell as OGC Well-Known Text to dst and returns the extended buffer.
+// The geometry is that of ToGeoJSON: a POLYGON with one closed counterclockwise exterior ring,
+// a MULTIPOLYGON for a cell cut at the antimeridian, and POLYGON EMPTY for an empty cell, all
+// of which PostGIS accepts as valid. The positions are "lng lat" in degrees with precision
+// digits after the decimal point, or in the shortest form that round-trips if precision is
+// negative; positions that coincide after rounding are written once, and a ring that collapses
+// is dropped.
+func (c Cell) AppendWKT(dst []byte, precision int) []byte {
+	polygons := c.wkPolygons(precision)
+	if len(polygons) > 1 {
+		dst = append(dst, "MULTIPOLYGON ("...)
+		for k, p := range polygons {
+			if k > 0 {
+				dst = append(dst, ", "...)
+			}
+			dst = appendWKTPolygon(dst, p, precision)
+		}
+		return append(dst, ')')
+	}
+	dst = append(dst, "POLYGON "...)
+	if len(polygons) == 0 {
+		return append(dst, "EMPTY"...)
+	}
+	return appendWKTPolygon(dst, polygons[0], precision)
+}
+
+// WKTCollection returns the cells of the diagram as an OGC Well-Known Text GEOMETRYCOLLECTION
+// of their geometries in site order, see Cell.AppendWKT.
+func (d *Diagram) WKTCollection(precision int) string {
+	dst := []byte("GEOMETRYCOLLECTION (")
+	for i, c := range d.Cells() {
+		if i > 0 {
+			dst = append(dst, ", "...)
+		}
+		dst = c.AppendWKT(dst, precision)
+	}
+	return string(append(dst, ')'))
+}
+
+// WKB returns the cell as little-endian OGC Well-Known Binary with full precision, see
+// AppendWKB.
+func (c Cell) WKB() []byte {
+	return c.AppendWKB(nil, -1)
+}
+
+// AppendWKB appends the cell as little-endian OGC Well-Known Binary to dst and returns the
+// extended buffer. The geometry is that of AppendWKT with the positions rounded to precision
+// digits after the decimal point, or not rounded if precision is negative; an empty cell is a
+// polygon without rings.
+func (c Cell) AppendWKB(dst []byte, precision int) []byte {
+	polygons := c.wkPolygons(precision)
+	if len(polygons) > 1 {
+		dst = appendWKBHeader(dst, wkbMultiPolygon, len(polygons))
+		for _, p := range polygons {
+			dst = appendWKBPolygon(dst, p)
+		}
+		return dst
+	}
+	var ring []lngLatPosition
+	if len(polygons) == 1 {
+		ring = polygons[0]
+	}
+	return appendWKBPolygon(dst, ring)
+}
+
+// WKBCollection returns the cells of the diagram as a little-endian OGC Well-Known Binary
+// GeometryCollection of their geometries in site order, see Cell.AppendWKB.
+func (d *Diagram) WKBCollection(precision int) []byte {
+	dst := appendWKBHeader(nil, wkbGeometryCollection, d.NumCells())
+	for _, c := range d.Cells() {
+		dst = c.AppendWKB(dst, precision)
+	}
+	return dst
+}
+
+// wkPolygons returns the exterior rings of the polygons of the cell, see lngLatPolygons, with
+// the positions rounded to precision digits if it is not negative.
+func (c Cell) wkPolygons(precision int) [][]lngLatPosition {
+	var rings [][]lngLatPosition
+	for _, p := range c.lngLatPolygons(0) {
+		ring := p[0]
+		if precision >= 0 {
+			scale := math.Pow(10, float64(precision))
+			for k := range ring {
+				ring[k][0] = math.Round(ring[k][0]*scale) / scale
+				ring[k][1] = math.Round(ring[k][1]*scale) / scale
+			}
+			ring = lnglat.CloseRing(ring[:len(ring)-1])
+		}
+		if ring != nil {
+			rings = append(rings, ring)
+		}
+	}
+	return rings
+}
+
+// appendWKTPolygon appends the polygon with the exterior ring to dst.
+func appendWKTPolygon(dst []byte, ring []lngLatPosition, precision int) []byte {
+	dst = append(dst, "(("...)
+	for k, p := range ring {
+		if k > 0 {
+			dst = append(dst, ", "...)
+		}
+		dst = strconv.AppendFloat(dst, p[0], 'f', precision, 64)
+		dst = append(dst, ' ')
+		dst = strconv.AppendFloat(dst, p[1], 'f', precision, 64)
+	}
+	return append(dst, "))"...)
+}
+
+// appendWKBHeader appends the byte order, the geometry type and the number of elements to dst.
+func appendWKBHeader(dst []byte, geometryType uint32, n int) []byte {
+	dst = append(dst, 1)
+	dst = binary.LittleEndian.AppendUint32(dst, geometryType)
+	return binary.LittleEndian.AppendUint32(dst, uint32(n))
+}
+
+// appendWKBPolygon appends the polygon with the exterior ring to dst, without rings if ring is
+// empty.
+func appendWKBPolygon(dst []byte, ring []lngLatPosition) []byte {
+	if len(ring) == 0 {
+		return appendWKBHeader(dst, wkbPolygon, 0)
+	}
+	dst = appendWKBHeader(dst, wkbPolygon, 1)
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(len(ring)))
+	for _, p := range ring {
+		dst = binary.LittleEndian.AppendUint64(dst, math.Float64bits(p[0]))
+		dst = binary.LittleEndian.AppendUint64(dst, math.Float64bits(p[1]))
+	}
+	return dst
+}
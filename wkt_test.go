@@ -0,0 +1,373 @@
+// Copyright (c) 2026 Andrey Kriulin
+// Licensed under the MIT License.
+// See the LICENSE file in the project root for full license text.
+
+package s2voronoi
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/2dChan/s2voronoi/internal/fixtures"
+	"github.com/2dChan/s2voronoi/utils"
+	"github.com/golang/geo/s2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiagram_WKTCollection(t *testing.T) {
+	diagrams := []struct {
+		name  string
+		sites s2.PointVector
+	}{
+		{"random", utils.GenerateRandomPoints(200, 0)},
+		{"octahedron", fixtures.Load("octahedron")},
+		{"antimeridian", fixtures.Load("antimeridian")},
+	}
+	for _, dt := range diagrams {
+		vd, err := NewDiagram(dt.sites)
+		if err != nil {
+			t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+		}
+		for _, precision := range []int{-1, 9, 6, 2} {
+			t.Run(fmt.Sprintf("%s precision %d", dt.name, precision), func(t *testing.T) {
+				got, err := parseWKT(vd.WKTCollection(precision))
+				if err != nil {
+					t.Fatalf("parseWKT(vd.WKTCollection(%d)) error = %v, want nil", precision, err)
+				}
+				if got.Type != "GEOMETRYCOLLECTION" || len(got.Children) != vd.NumCells() {
+					t.Fatalf("vd.WKTCollection(%d) = %s of %d, want GEOMETRYCOLLECTION of %d",
+						precision, got.Type, len(got.Children), vd.NumCells())
+				}
+				// Rounding to 2 digits may drop positions, so only validity is checked.
+				tol := 0.0
+				if precision >= 0 {
+					tol = 0.5 * math.Pow(10, -float64(precision))
+				}
+				for i, c := range vd.Cells() {
+					g := got.Children[i]
+					checkWKTPolygons(t, i, g)
+					if precision == 2 {
+						continue
+					}
+					want := c.lngLatPolygons(0)
+					if len(g.Polygons) != len(want) {
+						t.Errorf("cell %d has %d polygons, want %d", i, len(g.Polygons), len(want))
+						continue
+					}
+					for k, p := range g.Polygons {
+						assertPositionsNear(t, i, p[0], want[k][0], tol)
+					}
+				}
+
+				// WKB holds the same positions as WKT.
+				wkb, err := parseWKB(vd.WKBCollection(precision))
+				if err != nil {
+					t.Fatalf("parseWKB(vd.WKBCollection(%d)) error = %v, want nil", precision, err)
+				}
+				if diff := cmp.Diff(got, wkb); diff != "" {
+					t.Errorf("WKB mismatch with WKT (-wkt +wkb):\n%s", diff)
+				}
+			})
+		}
+	}
+}
+
+func TestCell_WKT(t *testing.T) {
+	vd, err := NewDiagram(fixtures.Load("octahedron"))
+	if err != nil {
+		t.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	tests := []struct {
+		name      string
+		cell      int
+		precision int
+		want      string
+	}{
+		{"polygon", 0, 3,
+			"POLYGON ((45.000 35.264, -45.000 35.264, -45.000 -35.264, 45.000 -35.264, " +
+				"45.000 35.264))"},
+		{"multipolygon", 1, 0,
+			"MULTIPOLYGON (((180 -45, 180 45, 135 35, 135 -35, 180 -45)), " +
+				"((-180 -45, -135 -35, -135 35, -180 45, -180 -45)))"},
+		{"pole", 4, 0,
+			"POLYGON ((180 90, -180 90, -180 45, -135 35, -45 35, 45 35, 135 35, 180 45, " +
+				"180 90))"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(vd.Cell(tt.cell).AppendWKT(nil, tt.precision)); got != tt.want {
+				t.Errorf("Cell(%d).AppendWKT(nil, %d) = %q, want %q", tt.cell, tt.precision, got,
+					tt.want)
+			}
+		})
+	}
+
+	if got, err := parseWKT(vd.Cell(0).WKT()); err != nil || got.Type != "POLYGON" {
+		t.Errorf("parseWKT(Cell(0).WKT()) = %v, %v, want POLYGON", got.Type, err)
+	}
+	if got, err := parseWKB(vd.Cell(1).WKB()); err != nil || got.Type != "MULTIPOLYGON" {
+		t.Errorf("parseWKB(Cell(1).WKB()) = %v, %v, want MULTIPOLYGON", got.Type, err)
+	}
+}
+
+func TestCell_WKT_Empty(t *testing.T) {
+	vd, empty := mustNewEmptyCellDiagram(t)
+	c := vd.Cell(empty)
+	if got := c.WKT(); got != "POLYGON EMPTY" {
+		t.Errorf("Cell(%d).WKT() = %q, want \"POLYGON EMPTY\"", empty, got)
+	}
+	got, err := parseWKB(c.WKB())
+	if err != nil || got.Type != "POLYGON" || len(got.Polygons) != 0 {
+		t.Errorf("parseWKB(Cell(%d).WKB()) = %+v, %v, want empty POLYGON", empty, got, err)
+	}
+}
+
+// Benchmarks
+
+func BenchmarkDiagram_WKTCollection(b *testing.B) {
+	vd, err := NewDiagram(utils.GenerateRandomPoints(10000, 0))
+	if err != nil {
+		b.Fatalf("NewDiagram(...) error = %v, want nil", err)
+	}
+	for b.Loop() {
+		vd.WKTCollection(6)
+	}
+}
+
+// Helpers
+
+// wkGeometry is a geometry parsed by parseWKT or parseWKB.
+type wkGeometry struct {
+	Type string
+	// Polygons are the rings of every polygon of a POLYGON or MULTIPOLYGON.
+	Polygons [][][][2]float64
+	// Children are the geometries of a GEOMETRYCOLLECTION.
+	Children []wkGeometry
+}
+
+// checkWKTPolygons checks that the geometry g of cell i is a POLYGON or MULTIPOLYGON that
+// PostGIS accepts as valid: every ring is closed, has at least 4 positions, is counterclockwise
+// and within the range of longitudes and latitudes.
+func checkWKTPolygons(t *testing.T, i int, g wkGeometry) {
+	t.Helper()
+	if g.Type != "POLYGON" && g.Type != "MULTIPOLYGON" {
+		t.Errorf("cell %d is a %s, want POLYGON or MULTIPOLYGON", i, g.Type)
+	}
+	for _, p := range g.Polygons {
+		for _, ring := range p {
+			if len(ring) < 4 || ring[0] != ring[len(ring)-1] {
+				t.Errorf("cell %d ring %v is not closed", i, ring)
+			}
+			if planarArea(ring) <= 0 {
+				t.Errorf("cell %d ring %v is not counterclockwise", i, ring)
+			}
+			for _, q := range ring {
+				if math.Abs(q[0]) > 180 || math.Abs(q[1]) > 90 {
+					t.Errorf("cell %d position %v out of range", i, q)
+				}
+			}
+		}
+	}
+}
+
+// assertPositionsNear checks that the ring got of cell i matches want within tol. Positions
+// of want that coincide after rounding may appear once in got.
+func assertPositionsNear(t *testing.T, i int, got, want [][2]float64, tol float64) {
+	t.Helper()
+	near := func(p, q [2]float64) bool {
+		return math.Abs(p[0]-q[0]) <= tol && math.Abs(p[1]-q[1]) <= tol
+	}
+	k := 0
+	for _, p := range got {
+		for k < len(want) && !near(p, want[k]) {
+			k++
+		}
+		if k == len(want) {
+			t.Errorf("cell %d position %v, want one of %v within %v", i, p, want, tol)
+			return
+		}
+		k++
+	}
+	if tol == 0 && len(got) != len(want) {
+		t.Errorf("cell %d ring has %d positions, want %d", i, len(got), len(want))
+	}
+}
+
+// parseWKT parses the POLYGON, MULTIPOLYGON and GEOMETRYCOLLECTION geometries of Well-Known
+// Text.
+func parseWKT(s string) (wkGeometry, error) {
+	p := &wktParser{s: s}
+	g, err := p.geometry()
+	if err == nil && strings.TrimSpace(p.s) != "" {
+		err = fmt.Errorf("trailing %q", p.s)
+	}
+	return g, err
+}
+
+// wktParser consumes the text s.
+type wktParser struct {
+	s string
+}
+
+// token consumes and returns the next word or punctuation.
+func (p *wktParser) token() string {
+	p.s = strings.TrimLeft(p.s, " ")
+	if p.s == "" {
+		return ""
+	}
+	n := strings.IndexAny(p.s, " (),")
+	if n == 0 {
+		n = 1
+	} else if n < 0 {
+		n = len(p.s)
+	}
+	tok := p.s[:n]
+	p.s = p.s[n:]
+	return tok
+}
+
+func (p *wktParser) expect(want string) error {
+	if tok := p.token(); tok != want {
+		return fmt.Errorf("got %q, want %q", tok, want)
+	}
+	return nil
+}
+
+// list parses a parenthesized comma-separated list of elements.
+func (p *wktParser) list(element func() error) error {
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	for {
+		if err := element(); err != nil {
+			return err
+		}
+		switch tok := p.token(); tok {
+		case ",":
+		case ")":
+			return nil
+		default:
+			return fmt.Errorf("got %q, want \",\" or \")\"", tok)
+		}
+	}
+}
+
+func (p *wktParser) polygon() ([][][2]float64, error) {
+	var rings [][][2]float64
+	err := p.list(func() error {
+		var ring [][2]float64
+		err := p.list(func() error {
+			var q [2]float64
+			for k := range q {
+				x, err := strconv.ParseFloat(p.token(), 64)
+				if err != nil {
+					return err
+				}
+				q[k] = x
+			}
+			ring = append(ring, q)
+			return nil
+		})
+		rings = append(rings, ring)
+		return err
+	})
+	return rings, err
+}
+
+func (p *wktParser) geometry() (wkGeometry, error) {
+	g := wkGeometry{Type: p.token()}
+	if strings.HasPrefix(strings.TrimLeft(p.s, " "), "EMPTY") {
+		p.token()
+		return g, nil
+	}
+	var err error
+	switch g.Type {
+	case "POLYGON":
+		var rings [][][2]float64
+		rings, err = p.polygon()
+		g.Polygons = [][][][2]float64{rings}
+	case "MULTIPOLYGON":
+		err = p.list(func() error {
+			rings, err := p.polygon()
+			g.Polygons = append(g.Polygons, rings)
+			return err
+		})
+	case "GEOMETRYCOLLECTION":
+		err = p.list(func() error {
+			child, err := p.geometry()
+			g.Children = append(g.Children, child)
+			return err
+		})
+	default:
+		err = fmt.Errorf("unknown geometry %q", g.Type)
+	}
+	return g, err
+}
+
+// parseWKB parses the Polygon, MultiPolygon and GeometryCollection geometries of little-endian
+// Well-Known Binary.
+func parseWKB(b []byte) (wkGeometry, error) {
+	g, rest, err := parseWKBGeometry(b)
+	if err == nil && len(rest) != 0 {
+		err = fmt.Errorf("%d trailing bytes", len(rest))
+	}
+	return g, err
+}
+
+func parseWKBGeometry(b []byte) (wkGeometry, []byte, error) {
+	var g wkGeometry
+	if len(b) < 9 || b[0] != 1 {
+		return g, nil, fmt.Errorf("bad header %x", b[:min(len(b), 9)])
+	}
+	typ, n := binary.LittleEndian.Uint32(b[1:]), int(binary.LittleEndian.Uint32(b[5:]))
+	b = b[9:]
+	switch typ {
+	case wkbPolygon:
+		g.Type = "POLYGON"
+		var rings [][][2]float64
+		for range n {
+			if len(b) < 4 {
+				return g, nil, fmt.Errorf("truncated ring")
+			}
+			m := int(binary.LittleEndian.Uint32(b))
+			b = b[4:]
+			if len(b) < 16*m {
+				return g, nil, fmt.Errorf("truncated positions")
+			}
+			ring := make([][2]float64, m)
+			for k := range ring {
+				ring[k][0] = math.Float64frombits(binary.LittleEndian.Uint64(b[16*k:]))
+				ring[k][1] = math.Float64frombits(binary.LittleEndian.Uint64(b[16*k+8:]))
+			}
+			rings = append(rings, ring)
+			b = b[16*m:]
+		}
+		if rings != nil {
+			g.Polygons = [][][][2]float64{rings}
+		}
+	case wkbMultiPolygon, wkbGeometryCollection:
+		g.Type = "MULTIPOLYGON"
+		if typ == wkbGeometryCollection {
+			g.Type = "GEOMETRYCOLLECTION"
+		}
+		for range n {
+			child, rest, err := parseWKBGeometry(b)
+			if err != nil {
+				return g, nil, err
+			}
+			if typ == wkbMultiPolygon {
+				g.Polygons = append(g.Polygons, child.Polygons...)
+			} else {
+				g.Children = append(g.Children, child)
+			}
+			b = rest
+		}
+	default:
+		return g, nil, fmt.Errorf("unknown geometry type %d", typ)
+	}
+	return g, b, nil
+}